@@ -68,8 +68,13 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta3.CloudStackAffinityGroupStatus)(nil), (*CloudStackAffinityGroupStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta3_CloudStackAffinityGroupStatus_To_v1beta1_CloudStackAffinityGroupStatus(a.(*v1beta3.CloudStackAffinityGroupStatus), b.(*CloudStackAffinityGroupStatus), scope)
+	if err := s.AddGeneratedConversionFunc((*CloudStackIdentityReference)(nil), (*v1beta3.CloudStackIdentityReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta1_CloudStackIdentityReference_To_v1beta3_CloudStackIdentityReference(a.(*CloudStackIdentityReference), b.(*v1beta3.CloudStackIdentityReference), scope)
+	}); err != nil {
+		return err
+	}
+	if err := s.AddGeneratedConversionFunc((*v1beta3.CloudStackIdentityReference)(nil), (*CloudStackIdentityReference)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta3_CloudStackIdentityReference_To_v1beta1_CloudStackIdentityReference(a.(*v1beta3.CloudStackIdentityReference), b.(*CloudStackIdentityReference), scope)
 	}); err != nil {
 		return err
 	}
@@ -263,6 +268,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta3.CloudStackAffinityGroupStatus)(nil), (*CloudStackAffinityGroupStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta3_CloudStackAffinityGroupStatus_To_v1beta1_CloudStackAffinityGroupStatus(a.(*v1beta3.CloudStackAffinityGroupStatus), b.(*CloudStackAffinityGroupStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta3.CloudStackCluster)(nil), (*CloudStackCluster)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta3_CloudStackCluster_To_v1beta1_CloudStackCluster(a.(*v1beta3.CloudStackCluster), b.(*CloudStackCluster), scope)
 	}); err != nil {
@@ -407,12 +417,30 @@ func Convert_v1beta1_CloudStackAffinityGroupStatus_To_v1beta3_CloudStackAffinity
 
 func autoConvert_v1beta3_CloudStackAffinityGroupStatus_To_v1beta1_CloudStackAffinityGroupStatus(in *v1beta3.CloudStackAffinityGroupStatus, out *CloudStackAffinityGroupStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
+	return nil
+}
+
+func autoConvert_v1beta1_CloudStackIdentityReference_To_v1beta3_CloudStackIdentityReference(in *CloudStackIdentityReference, out *v1beta3.CloudStackIdentityReference, s conversion.Scope) error {
+	out.Kind = in.Kind
+	out.Name = in.Name
+	return nil
+}
+
+// Convert_v1beta1_CloudStackIdentityReference_To_v1beta3_CloudStackIdentityReference is an autogenerated conversion function.
+func Convert_v1beta1_CloudStackIdentityReference_To_v1beta3_CloudStackIdentityReference(in *CloudStackIdentityReference, out *v1beta3.CloudStackIdentityReference, s conversion.Scope) error {
+	return autoConvert_v1beta1_CloudStackIdentityReference_To_v1beta3_CloudStackIdentityReference(in, out, s)
+}
+
+func autoConvert_v1beta3_CloudStackIdentityReference_To_v1beta1_CloudStackIdentityReference(in *v1beta3.CloudStackIdentityReference, out *CloudStackIdentityReference, s conversion.Scope) error {
+	out.Kind = in.Kind
+	out.Name = in.Name
 	return nil
 }
 
-// Convert_v1beta3_CloudStackAffinityGroupStatus_To_v1beta1_CloudStackAffinityGroupStatus is an autogenerated conversion function.
-func Convert_v1beta3_CloudStackAffinityGroupStatus_To_v1beta1_CloudStackAffinityGroupStatus(in *v1beta3.CloudStackAffinityGroupStatus, out *CloudStackAffinityGroupStatus, s conversion.Scope) error {
-	return autoConvert_v1beta3_CloudStackAffinityGroupStatus_To_v1beta1_CloudStackAffinityGroupStatus(in, out, s)
+// Convert_v1beta3_CloudStackIdentityReference_To_v1beta1_CloudStackIdentityReference is an autogenerated conversion function.
+func Convert_v1beta3_CloudStackIdentityReference_To_v1beta1_CloudStackIdentityReference(in *v1beta3.CloudStackIdentityReference, out *CloudStackIdentityReference, s conversion.Scope) error {
+	return autoConvert_v1beta3_CloudStackIdentityReference_To_v1beta1_CloudStackIdentityReference(in, out, s)
 }
 
 func autoConvert_v1beta1_CloudStackIsolatedNetwork_To_v1beta3_CloudStackIsolatedNetwork(in *CloudStackIsolatedNetwork, out *v1beta3.CloudStackIsolatedNetwork, s conversion.Scope) error {
@@ -508,8 +536,11 @@ func autoConvert_v1beta3_CloudStackIsolatedNetworkSpec_To_v1beta1_CloudStackIsol
 	// WARNING: in.FailureDomainName requires manual conversion: does not exist in peer-type
 	// WARNING: in.Gateway requires manual conversion: does not exist in peer-type
 	// WARNING: in.Netmask requires manual conversion: does not exist in peer-type
+	// WARNING: in.IP6Gateway requires manual conversion: does not exist in peer-type
+	// WARNING: in.IP6CIDR requires manual conversion: does not exist in peer-type
 	// WARNING: in.Offering requires manual conversion: does not exist in peer-type
 	// WARNING: in.VPC requires manual conversion: does not exist in peer-type
+	// WARNING: in.ACL requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -528,9 +559,14 @@ func Convert_v1beta1_CloudStackIsolatedNetworkStatus_To_v1beta3_CloudStackIsolat
 func autoConvert_v1beta3_CloudStackIsolatedNetworkStatus_To_v1beta1_CloudStackIsolatedNetworkStatus(in *v1beta3.CloudStackIsolatedNetworkStatus, out *CloudStackIsolatedNetworkStatus, s conversion.Scope) error {
 	out.PublicIPID = in.PublicIPID
 	out.LBRuleID = in.LBRuleID
+	// WARNING: in.AdditionalLBRuleIDs requires manual conversion: does not exist in peer-type
+	// WARNING: in.LBHealthCheckPolicyID requires manual conversion: does not exist in peer-type
+	// WARNING: in.LBStickinessPolicyID requires manual conversion: does not exist in peer-type
+	// WARNING: in.NetworkACLListID requires manual conversion: does not exist in peer-type
 	// WARNING: in.RoutingMode requires manual conversion: does not exist in peer-type
 	// WARNING: in.FirewallRulesOpened requires manual conversion: does not exist in peer-type
 	out.Ready = in.Ready
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -642,21 +678,27 @@ func autoConvert_v1beta3_CloudStackMachineSpec_To_v1beta1_CloudStackMachineSpec(
 	out.Name = in.Name
 	out.ID = in.ID
 	out.InstanceID = (*string)(unsafe.Pointer(in.InstanceID))
+	// WARNING: in.Adopt requires manual conversion: does not exist in peer-type
 	if err := Convert_v1beta3_CloudStackResourceIdentifier_To_v1beta1_CloudStackResourceIdentifier(&in.Offering, &out.Offering, s); err != nil {
 		return err
 	}
+	// WARNING: in.InPlaceResize requires manual conversion: does not exist in peer-type
 	if err := Convert_v1beta3_CloudStackResourceIdentifier_To_v1beta1_CloudStackResourceIdentifier(&in.Template, &out.Template, s); err != nil {
 		return err
 	}
+	// WARNING: in.RootDisk requires manual conversion: does not exist in peer-type
 	if err := Convert_v1beta3_CloudStackResourceDiskOffering_To_v1beta1_CloudStackResourceDiskOffering(&in.DiskOffering, &out.DiskOffering, s); err != nil {
 		return err
 	}
+	// WARNING: in.DataDisks requires manual conversion: does not exist in peer-type
 	// WARNING: in.Networks requires manual conversion: does not exist in peer-type
 	out.SSHKey = in.SSHKey
 	out.Details = *(*map[string]string)(unsafe.Pointer(&in.Details))
+	// WARNING: in.AdditionalTags requires manual conversion: does not exist in peer-type
 	out.AffinityGroupIDs = *(*[]string)(unsafe.Pointer(&in.AffinityGroupIDs))
 	out.Affinity = in.Affinity
 	out.AffinityGroupRef = (*corev1.ObjectReference)(unsafe.Pointer(in.AffinityGroupRef))
+	// WARNING: in.Placement requires manual conversion: does not exist in peer-type
 	out.ProviderID = (*string)(unsafe.Pointer(in.ProviderID))
 	// WARNING: in.FailureDomainName requires manual conversion: does not exist in peer-type
 	// WARNING: in.UncompressedUserData requires manual conversion: does not exist in peer-type
@@ -775,9 +817,20 @@ func autoConvert_v1beta3_CloudStackMachineStatus_To_v1beta1_CloudStackMachineSta
 	out.Addresses = *(*[]corev1.NodeAddress)(unsafe.Pointer(&in.Addresses))
 	out.InstanceState = InstanceState(in.InstanceState)
 	out.InstanceStateLastUpdated = in.InstanceStateLastUpdated
+	// WARNING: in.ServiceOfferingID requires manual conversion: does not exist in peer-type
+	// WARNING: in.ServiceOfferingName requires manual conversion: does not exist in peer-type
+	// WARNING: in.Placement requires manual conversion: does not exist in peer-type
+	// WARNING: in.Hypervisor requires manual conversion: does not exist in peer-type
+	// WARNING: in.TemplateID requires manual conversion: does not exist in peer-type
+	// WARNING: in.CPUNumber requires manual conversion: does not exist in peer-type
+	// WARNING: in.MemoryMiB requires manual conversion: does not exist in peer-type
+	// WARNING: in.RootVolumeID requires manual conversion: does not exist in peer-type
 	out.Ready = in.Ready
 	// WARNING: in.Status requires manual conversion: does not exist in peer-type
 	// WARNING: in.Reason requires manual conversion: does not exist in peer-type
+	// WARNING: in.AsyncJob requires manual conversion: does not exist in peer-type
+	// WARNING: in.InsufficientCapacityFailureDomains requires manual conversion: does not exist in peer-type
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -991,8 +1044,11 @@ func autoConvert_v1beta3_Network_To_v1beta1_Network(in *v1beta3.Network, out *Ne
 	out.Name = in.Name
 	// WARNING: in.Gateway requires manual conversion: does not exist in peer-type
 	// WARNING: in.Netmask requires manual conversion: does not exist in peer-type
+	// WARNING: in.IP6Gateway requires manual conversion: does not exist in peer-type
+	// WARNING: in.IP6CIDR requires manual conversion: does not exist in peer-type
 	// WARNING: in.Offering requires manual conversion: does not exist in peer-type
 	// WARNING: in.VPC requires manual conversion: does not exist in peer-type
+	// WARNING: in.ACL requires manual conversion: does not exist in peer-type
 	// WARNING: in.RoutingMode requires manual conversion: does not exist in peer-type
 	return nil
 }
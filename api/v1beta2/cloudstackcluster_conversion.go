@@ -19,17 +19,49 @@ package v1beta2
 import (
 	machineryconversion "k8s.io/apimachinery/pkg/conversion"
 	"sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 )
 
 func (src *CloudStackCluster) ConvertTo(dstRaw conversion.Hub) error { // nolint
 	dst := dstRaw.(*v1beta3.CloudStackCluster)
-	return Convert_v1beta2_CloudStackCluster_To_v1beta3_CloudStackCluster(src, dst, nil)
+	if err := Convert_v1beta2_CloudStackCluster_To_v1beta3_CloudStackCluster(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data
+	restored := &v1beta3.CloudStackCluster{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+
+	dst.Spec.SyncWithACS = restored.Spec.SyncWithACS
+	dst.Spec.APIServerLoadBalancer = restored.Spec.APIServerLoadBalancer
+	dst.Spec.FailureDomainPlacement = restored.Spec.FailureDomainPlacement
+	dst.Spec.AdditionalTags = restored.Spec.AdditionalTags
+	// Failure domains are matched by name, as they may have been reordered, added or removed in v1beta2.
+	for i := range dst.Spec.FailureDomains {
+		for j := range restored.Spec.FailureDomains {
+			if restored.Spec.FailureDomains[j].Name == dst.Spec.FailureDomains[i].Name {
+				restoreFailureDomainSpec(&restored.Spec.FailureDomains[j], &dst.Spec.FailureDomains[i])
+				break
+			}
+		}
+	}
+	dst.Status.CloudStackClusterID = restored.Status.CloudStackClusterID
+	dst.Status.Conditions = restored.Status.Conditions
+
+	return nil
 }
 
 func (dst *CloudStackCluster) ConvertFrom(srcRaw conversion.Hub) error { // nolint
 	src := srcRaw.(*v1beta3.CloudStackCluster)
-	return Convert_v1beta3_CloudStackCluster_To_v1beta2_CloudStackCluster(src, dst, nil)
+	if err := Convert_v1beta3_CloudStackCluster_To_v1beta2_CloudStackCluster(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion
+	return utilconversion.MarshalData(src, dst)
 }
 
 func Convert_v1beta3_CloudStackClusterSpec_To_v1beta2_CloudStackClusterSpec(in *v1beta3.CloudStackClusterSpec, out *CloudStackClusterSpec, s machineryconversion.Scope) error { // nolint
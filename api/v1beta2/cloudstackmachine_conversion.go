@@ -35,9 +35,18 @@ func (src *CloudStackMachine) ConvertTo(dstRaw conversion.Hub) error { // nolint
 		return err
 	}
 
-	if len(restored.Spec.Networks) > 0 {
-		dst.Spec.Networks = restored.Spec.Networks
-	}
+	restoreMachineSpec(&restored.Spec, &dst.Spec)
+	dst.Status.ServiceOfferingID = restored.Status.ServiceOfferingID
+	dst.Status.ServiceOfferingName = restored.Status.ServiceOfferingName
+	dst.Status.Placement = restored.Status.Placement
+	dst.Status.Hypervisor = restored.Status.Hypervisor
+	dst.Status.TemplateID = restored.Status.TemplateID
+	dst.Status.CPUNumber = restored.Status.CPUNumber
+	dst.Status.MemoryMiB = restored.Status.MemoryMiB
+	dst.Status.RootVolumeID = restored.Status.RootVolumeID
+	dst.Status.AsyncJob = restored.Status.AsyncJob
+	dst.Status.InsufficientCapacityFailureDomains = restored.Status.InsufficientCapacityFailureDomains
+	dst.Status.Conditions = restored.Status.Conditions
 
 	return nil
 }
@@ -48,14 +57,20 @@ func (dst *CloudStackMachine) ConvertFrom(srcRaw conversion.Hub) error { // noli
 		return err
 	}
 
-	// Preserve Hub data on down-conversion, including Networks field
+	// Preserve Hub data on down-conversion, including the fields v1beta2 lacks
 	err := utilconversion.MarshalData(src, dst)
 	return err
 }
 
 // Convert_v1beta3_CloudStackMachineSpec_To_v1beta2_CloudStackMachineSpec handles the conversion from v1beta3 to v1beta2,
-// ignoring the Networks field that doesn't exist in v1beta2
+// ignoring the fields that don't exist in v1beta2
 func Convert_v1beta3_CloudStackMachineSpec_To_v1beta2_CloudStackMachineSpec(in *v1beta3.CloudStackMachineSpec, out *CloudStackMachineSpec, s machineryconversion.Scope) error { // nolint
-	// Use the auto-generated conversion function, which will handle all fields except Networks
+	// Use the auto-generated conversion function, which will handle all fields v1beta2 has
 	return autoConvert_v1beta3_CloudStackMachineSpec_To_v1beta2_CloudStackMachineSpec(in, out, s)
 }
+
+// Convert_v1beta3_CloudStackMachineStatus_To_v1beta2_CloudStackMachineStatus handles the conversion from v1beta3 to v1beta2,
+// ignoring the fields that don't exist in v1beta2
+func Convert_v1beta3_CloudStackMachineStatus_To_v1beta2_CloudStackMachineStatus(in *v1beta3.CloudStackMachineStatus, out *CloudStackMachineStatus, s machineryconversion.Scope) error { // nolint
+	return autoConvert_v1beta3_CloudStackMachineStatus_To_v1beta2_CloudStackMachineStatus(in, out, s)
+}
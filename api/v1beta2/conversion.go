@@ -54,3 +54,35 @@ func Convert_v1beta3_CloudStackIsolatedNetworkStatus_To_v1beta2_CloudStackIsolat
 	// RoutingMode field doesn't exist in v1beta2, so we ignore it during conversion
 	return nil
 }
+
+// restoreNetwork restores the fields of a v1beta3.Network that v1beta2 lacks from the copy kept in the
+// conversion data annotation.
+func restoreNetwork(restored, dst *v1beta3.Network) {
+	dst.Gateway = restored.Gateway
+	dst.Netmask = restored.Netmask
+	dst.IP6Gateway = restored.IP6Gateway
+	dst.IP6CIDR = restored.IP6CIDR
+	dst.Offering = restored.Offering
+	dst.VPC = restored.VPC
+	dst.ACL = restored.ACL
+	dst.RoutingMode = restored.RoutingMode
+}
+
+// restoreFailureDomainSpec restores the fields of a v1beta3.CloudStackFailureDomainSpec that v1beta2 lacks.
+func restoreFailureDomainSpec(restored, dst *v1beta3.CloudStackFailureDomainSpec) {
+	dst.Project = restored.Project
+	dst.IdentityRef = restored.IdentityRef
+	dst.Weight = restored.Weight
+	restoreNetwork(&restored.Zone.Network, &dst.Zone.Network)
+}
+
+// restoreMachineSpec restores the fields of a v1beta3.CloudStackMachineSpec that v1beta2 lacks.
+func restoreMachineSpec(restored, dst *v1beta3.CloudStackMachineSpec) {
+	dst.Adopt = restored.Adopt
+	dst.InPlaceResize = restored.InPlaceResize
+	dst.RootDisk = restored.RootDisk
+	dst.DataDisks = restored.DataDisks
+	dst.Networks = restored.Networks
+	dst.AdditionalTags = restored.AdditionalTags
+	dst.Placement = restored.Placement
+}
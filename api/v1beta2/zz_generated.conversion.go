@@ -73,11 +73,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta3.CloudStackAffinityGroupStatus)(nil), (*CloudStackAffinityGroupStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta3_CloudStackAffinityGroupStatus_To_v1beta2_CloudStackAffinityGroupStatus(a.(*v1beta3.CloudStackAffinityGroupStatus), b.(*CloudStackAffinityGroupStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*CloudStackCluster)(nil), (*v1beta3.CloudStackCluster)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_CloudStackCluster_To_v1beta3_CloudStackCluster(a.(*CloudStackCluster), b.(*v1beta3.CloudStackCluster), scope)
 	}); err != nil {
@@ -138,11 +133,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta3.CloudStackFailureDomainStatus)(nil), (*CloudStackFailureDomainStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta3_CloudStackFailureDomainStatus_To_v1beta2_CloudStackFailureDomainStatus(a.(*v1beta3.CloudStackFailureDomainStatus), b.(*CloudStackFailureDomainStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*CloudStackIsolatedNetwork)(nil), (*v1beta3.CloudStackIsolatedNetwork)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_CloudStackIsolatedNetwork_To_v1beta3_CloudStackIsolatedNetwork(a.(*CloudStackIsolatedNetwork), b.(*v1beta3.CloudStackIsolatedNetwork), scope)
 	}); err != nil {
@@ -243,11 +233,6 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
-	if err := s.AddGeneratedConversionFunc((*v1beta3.CloudStackMachineStatus)(nil), (*CloudStackMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
-		return Convert_v1beta3_CloudStackMachineStatus_To_v1beta2_CloudStackMachineStatus(a.(*v1beta3.CloudStackMachineStatus), b.(*CloudStackMachineStatus), scope)
-	}); err != nil {
-		return err
-	}
 	if err := s.AddGeneratedConversionFunc((*CloudStackMachineTemplate)(nil), (*v1beta3.CloudStackMachineTemplate)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta2_CloudStackMachineTemplate_To_v1beta3_CloudStackMachineTemplate(a.(*CloudStackMachineTemplate), b.(*v1beta3.CloudStackMachineTemplate), scope)
 	}); err != nil {
@@ -328,6 +313,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta3.CloudStackAffinityGroupStatus)(nil), (*CloudStackAffinityGroupStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta3_CloudStackAffinityGroupStatus_To_v1beta2_CloudStackAffinityGroupStatus(a.(*v1beta3.CloudStackAffinityGroupStatus), b.(*CloudStackAffinityGroupStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta3.CloudStackClusterSpec)(nil), (*CloudStackClusterSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta3_CloudStackClusterSpec_To_v1beta2_CloudStackClusterSpec(a.(*v1beta3.CloudStackClusterSpec), b.(*CloudStackClusterSpec), scope)
 	}); err != nil {
@@ -343,6 +333,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta3.CloudStackFailureDomainStatus)(nil), (*CloudStackFailureDomainStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta3_CloudStackFailureDomainStatus_To_v1beta2_CloudStackFailureDomainStatus(a.(*v1beta3.CloudStackFailureDomainStatus), b.(*CloudStackFailureDomainStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta3.CloudStackIsolatedNetworkSpec)(nil), (*CloudStackIsolatedNetworkSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta3_CloudStackIsolatedNetworkSpec_To_v1beta2_CloudStackIsolatedNetworkSpec(a.(*v1beta3.CloudStackIsolatedNetworkSpec), b.(*CloudStackIsolatedNetworkSpec), scope)
 	}); err != nil {
@@ -358,6 +353,11 @@ func RegisterConversions(s *runtime.Scheme) error {
 	}); err != nil {
 		return err
 	}
+	if err := s.AddConversionFunc((*v1beta3.CloudStackMachineStatus)(nil), (*CloudStackMachineStatus)(nil), func(a, b interface{}, scope conversion.Scope) error {
+		return Convert_v1beta3_CloudStackMachineStatus_To_v1beta2_CloudStackMachineStatus(a.(*v1beta3.CloudStackMachineStatus), b.(*CloudStackMachineStatus), scope)
+	}); err != nil {
+		return err
+	}
 	if err := s.AddConversionFunc((*v1beta3.CloudStackMachineTemplateSpec)(nil), (*CloudStackMachineTemplateSpec)(nil), func(a, b interface{}, scope conversion.Scope) error {
 		return Convert_v1beta3_CloudStackMachineTemplateSpec_To_v1beta2_CloudStackMachineTemplateSpec(a.(*v1beta3.CloudStackMachineTemplateSpec), b.(*CloudStackMachineTemplateSpec), scope)
 	}); err != nil {
@@ -405,7 +405,17 @@ func Convert_v1beta3_CloudStackAffinityGroup_To_v1beta2_CloudStackAffinityGroup(
 
 func autoConvert_v1beta2_CloudStackAffinityGroupList_To_v1beta3_CloudStackAffinityGroupList(in *CloudStackAffinityGroupList, out *v1beta3.CloudStackAffinityGroupList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]v1beta3.CloudStackAffinityGroup)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]v1beta3.CloudStackAffinityGroup, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta2_CloudStackAffinityGroup_To_v1beta3_CloudStackAffinityGroup(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -416,7 +426,17 @@ func Convert_v1beta2_CloudStackAffinityGroupList_To_v1beta3_CloudStackAffinityGr
 
 func autoConvert_v1beta3_CloudStackAffinityGroupList_To_v1beta2_CloudStackAffinityGroupList(in *v1beta3.CloudStackAffinityGroupList, out *CloudStackAffinityGroupList, s conversion.Scope) error {
 	out.ListMeta = in.ListMeta
-	out.Items = *(*[]CloudStackAffinityGroup)(unsafe.Pointer(&in.Items))
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CloudStackAffinityGroup, len(*in))
+		for i := range *in {
+			if err := Convert_v1beta3_CloudStackAffinityGroup_To_v1beta2_CloudStackAffinityGroup(&(*in)[i], &(*out)[i], s); err != nil {
+				return err
+			}
+		}
+	} else {
+		out.Items = nil
+	}
 	return nil
 }
 
@@ -463,14 +483,10 @@ func Convert_v1beta2_CloudStackAffinityGroupStatus_To_v1beta3_CloudStackAffinity
 
 func autoConvert_v1beta3_CloudStackAffinityGroupStatus_To_v1beta2_CloudStackAffinityGroupStatus(in *v1beta3.CloudStackAffinityGroupStatus, out *CloudStackAffinityGroupStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta3_CloudStackAffinityGroupStatus_To_v1beta2_CloudStackAffinityGroupStatus is an autogenerated conversion function.
-func Convert_v1beta3_CloudStackAffinityGroupStatus_To_v1beta2_CloudStackAffinityGroupStatus(in *v1beta3.CloudStackAffinityGroupStatus, out *CloudStackAffinityGroupStatus, s conversion.Scope) error {
-	return autoConvert_v1beta3_CloudStackAffinityGroupStatus_To_v1beta2_CloudStackAffinityGroupStatus(in, out, s)
-}
-
 func autoConvert_v1beta2_CloudStackCluster_To_v1beta3_CloudStackCluster(in *CloudStackCluster, out *v1beta3.CloudStackCluster, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1beta2_CloudStackClusterSpec_To_v1beta3_CloudStackClusterSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -580,6 +596,9 @@ func autoConvert_v1beta3_CloudStackClusterSpec_To_v1beta2_CloudStackClusterSpec(
 	}
 	out.ControlPlaneEndpoint = in.ControlPlaneEndpoint
 	// WARNING: in.SyncWithACS requires manual conversion: does not exist in peer-type
+	// WARNING: in.APIServerLoadBalancer requires manual conversion: does not exist in peer-type
+	// WARNING: in.FailureDomainPlacement requires manual conversion: does not exist in peer-type
+	// WARNING: in.AdditionalTags requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -598,6 +617,7 @@ func autoConvert_v1beta3_CloudStackClusterStatus_To_v1beta2_CloudStackClusterSta
 	out.FailureDomains = *(*v1beta1.FailureDomains)(unsafe.Pointer(&in.FailureDomains))
 	// WARNING: in.CloudStackClusterID requires manual conversion: does not exist in peer-type
 	out.Ready = in.Ready
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -700,6 +720,8 @@ func autoConvert_v1beta3_CloudStackFailureDomainSpec_To_v1beta2_CloudStackFailur
 	out.Domain = in.Domain
 	// WARNING: in.Project requires manual conversion: does not exist in peer-type
 	out.ACSEndpoint = in.ACSEndpoint
+	// WARNING: in.IdentityRef requires manual conversion: does not exist in peer-type
+	// WARNING: in.Weight requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -715,14 +737,10 @@ func Convert_v1beta2_CloudStackFailureDomainStatus_To_v1beta3_CloudStackFailureD
 
 func autoConvert_v1beta3_CloudStackFailureDomainStatus_To_v1beta2_CloudStackFailureDomainStatus(in *v1beta3.CloudStackFailureDomainStatus, out *CloudStackFailureDomainStatus, s conversion.Scope) error {
 	out.Ready = in.Ready
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta3_CloudStackFailureDomainStatus_To_v1beta2_CloudStackFailureDomainStatus is an autogenerated conversion function.
-func Convert_v1beta3_CloudStackFailureDomainStatus_To_v1beta2_CloudStackFailureDomainStatus(in *v1beta3.CloudStackFailureDomainStatus, out *CloudStackFailureDomainStatus, s conversion.Scope) error {
-	return autoConvert_v1beta3_CloudStackFailureDomainStatus_To_v1beta2_CloudStackFailureDomainStatus(in, out, s)
-}
-
 func autoConvert_v1beta2_CloudStackIsolatedNetwork_To_v1beta3_CloudStackIsolatedNetwork(in *CloudStackIsolatedNetwork, out *v1beta3.CloudStackIsolatedNetwork, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1beta2_CloudStackIsolatedNetworkSpec_To_v1beta3_CloudStackIsolatedNetworkSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -817,8 +835,11 @@ func autoConvert_v1beta3_CloudStackIsolatedNetworkSpec_To_v1beta2_CloudStackIsol
 	out.FailureDomainName = in.FailureDomainName
 	// WARNING: in.Gateway requires manual conversion: does not exist in peer-type
 	// WARNING: in.Netmask requires manual conversion: does not exist in peer-type
+	// WARNING: in.IP6Gateway requires manual conversion: does not exist in peer-type
+	// WARNING: in.IP6CIDR requires manual conversion: does not exist in peer-type
 	// WARNING: in.Offering requires manual conversion: does not exist in peer-type
 	// WARNING: in.VPC requires manual conversion: does not exist in peer-type
+	// WARNING: in.ACL requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -837,9 +858,14 @@ func Convert_v1beta2_CloudStackIsolatedNetworkStatus_To_v1beta3_CloudStackIsolat
 func autoConvert_v1beta3_CloudStackIsolatedNetworkStatus_To_v1beta2_CloudStackIsolatedNetworkStatus(in *v1beta3.CloudStackIsolatedNetworkStatus, out *CloudStackIsolatedNetworkStatus, s conversion.Scope) error {
 	out.PublicIPID = in.PublicIPID
 	out.LBRuleID = in.LBRuleID
+	// WARNING: in.AdditionalLBRuleIDs requires manual conversion: does not exist in peer-type
+	// WARNING: in.LBHealthCheckPolicyID requires manual conversion: does not exist in peer-type
+	// WARNING: in.LBStickinessPolicyID requires manual conversion: does not exist in peer-type
+	// WARNING: in.NetworkACLListID requires manual conversion: does not exist in peer-type
 	// WARNING: in.RoutingMode requires manual conversion: does not exist in peer-type
 	// WARNING: in.FirewallRulesOpened requires manual conversion: does not exist in peer-type
 	out.Ready = in.Ready
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
 	return nil
 }
 
@@ -950,21 +976,27 @@ func autoConvert_v1beta3_CloudStackMachineSpec_To_v1beta2_CloudStackMachineSpec(
 	out.Name = in.Name
 	out.ID = in.ID
 	out.InstanceID = (*string)(unsafe.Pointer(in.InstanceID))
+	// WARNING: in.Adopt requires manual conversion: does not exist in peer-type
 	if err := Convert_v1beta3_CloudStackResourceIdentifier_To_v1beta2_CloudStackResourceIdentifier(&in.Offering, &out.Offering, s); err != nil {
 		return err
 	}
+	// WARNING: in.InPlaceResize requires manual conversion: does not exist in peer-type
 	if err := Convert_v1beta3_CloudStackResourceIdentifier_To_v1beta2_CloudStackResourceIdentifier(&in.Template, &out.Template, s); err != nil {
 		return err
 	}
+	// WARNING: in.RootDisk requires manual conversion: does not exist in peer-type
 	if err := Convert_v1beta3_CloudStackResourceDiskOffering_To_v1beta2_CloudStackResourceDiskOffering(&in.DiskOffering, &out.DiskOffering, s); err != nil {
 		return err
 	}
+	// WARNING: in.DataDisks requires manual conversion: does not exist in peer-type
 	// WARNING: in.Networks requires manual conversion: does not exist in peer-type
 	out.SSHKey = in.SSHKey
 	out.Details = *(*map[string]string)(unsafe.Pointer(&in.Details))
+	// WARNING: in.AdditionalTags requires manual conversion: does not exist in peer-type
 	out.AffinityGroupIDs = *(*[]string)(unsafe.Pointer(&in.AffinityGroupIDs))
 	out.Affinity = in.Affinity
 	out.AffinityGroupRef = (*corev1.ObjectReference)(unsafe.Pointer(in.AffinityGroupRef))
+	// WARNING: in.Placement requires manual conversion: does not exist in peer-type
 	out.ProviderID = (*string)(unsafe.Pointer(in.ProviderID))
 	out.FailureDomainName = in.FailureDomainName
 	out.UncompressedUserData = (*bool)(unsafe.Pointer(in.UncompressedUserData))
@@ -1084,17 +1116,23 @@ func autoConvert_v1beta3_CloudStackMachineStatus_To_v1beta2_CloudStackMachineSta
 	out.Addresses = *(*[]corev1.NodeAddress)(unsafe.Pointer(&in.Addresses))
 	out.InstanceState = in.InstanceState
 	out.InstanceStateLastUpdated = in.InstanceStateLastUpdated
+	// WARNING: in.ServiceOfferingID requires manual conversion: does not exist in peer-type
+	// WARNING: in.ServiceOfferingName requires manual conversion: does not exist in peer-type
+	// WARNING: in.Placement requires manual conversion: does not exist in peer-type
+	// WARNING: in.Hypervisor requires manual conversion: does not exist in peer-type
+	// WARNING: in.TemplateID requires manual conversion: does not exist in peer-type
+	// WARNING: in.CPUNumber requires manual conversion: does not exist in peer-type
+	// WARNING: in.MemoryMiB requires manual conversion: does not exist in peer-type
+	// WARNING: in.RootVolumeID requires manual conversion: does not exist in peer-type
 	out.Ready = in.Ready
 	out.Status = (*string)(unsafe.Pointer(in.Status))
 	out.Reason = (*string)(unsafe.Pointer(in.Reason))
+	// WARNING: in.AsyncJob requires manual conversion: does not exist in peer-type
+	// WARNING: in.InsufficientCapacityFailureDomains requires manual conversion: does not exist in peer-type
+	// WARNING: in.Conditions requires manual conversion: does not exist in peer-type
 	return nil
 }
 
-// Convert_v1beta3_CloudStackMachineStatus_To_v1beta2_CloudStackMachineStatus is an autogenerated conversion function.
-func Convert_v1beta3_CloudStackMachineStatus_To_v1beta2_CloudStackMachineStatus(in *v1beta3.CloudStackMachineStatus, out *CloudStackMachineStatus, s conversion.Scope) error {
-	return autoConvert_v1beta3_CloudStackMachineStatus_To_v1beta2_CloudStackMachineStatus(in, out, s)
-}
-
 func autoConvert_v1beta2_CloudStackMachineTemplate_To_v1beta3_CloudStackMachineTemplate(in *CloudStackMachineTemplate, out *v1beta3.CloudStackMachineTemplate, s conversion.Scope) error {
 	out.ObjectMeta = in.ObjectMeta
 	if err := Convert_v1beta2_CloudStackMachineTemplateSpec_To_v1beta3_CloudStackMachineTemplateSpec(&in.Spec, &out.Spec, s); err != nil {
@@ -1305,8 +1343,11 @@ func autoConvert_v1beta3_Network_To_v1beta2_Network(in *v1beta3.Network, out *Ne
 	out.Name = in.Name
 	// WARNING: in.Gateway requires manual conversion: does not exist in peer-type
 	// WARNING: in.Netmask requires manual conversion: does not exist in peer-type
+	// WARNING: in.IP6Gateway requires manual conversion: does not exist in peer-type
+	// WARNING: in.IP6CIDR requires manual conversion: does not exist in peer-type
 	// WARNING: in.Offering requires manual conversion: does not exist in peer-type
 	// WARNING: in.VPC requires manual conversion: does not exist in peer-type
+	// WARNING: in.ACL requires manual conversion: does not exist in peer-type
 	// WARNING: in.RoutingMode requires manual conversion: does not exist in peer-type
 	return nil
 }
@@ -18,6 +18,7 @@ package v1beta3
 
 import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 const AffinityGroupFinalizer = "affinitygroup.infrastructure.cluster.x-k8s.io"
@@ -25,7 +26,9 @@ const AffinityGroupFinalizer = "affinitygroup.infrastructure.cluster.x-k8s.io"
 // CloudStackAffinityGroupSpec defines the desired state of CloudStackAffinityGroup
 type CloudStackAffinityGroupSpec struct {
 	// Mutually exclusive parameter with AffinityGroupIDs.
-	// Can be "host affinity" or "host anti-affinity". Will create an affinity group per machine set.
+	// Can be "host affinity", "host anti-affinity", "non-strict host affinity" or "non-strict host anti-affinity".
+	// Will create an affinity group per machine set.
+	// +kubebuilder:validation:Enum="host affinity";"host anti-affinity";"non-strict host affinity";"non-strict host anti-affinity"
 	Type string `json:"type,omitempty"`
 
 	// Name.
@@ -44,6 +47,10 @@ type CloudStackAffinityGroupSpec struct {
 type CloudStackAffinityGroupStatus struct {
 	// Reflects the readiness of the CS Affinity Group.
 	Ready bool `json:"ready"`
+
+	// Conditions defines current service state of the CloudStackAffinityGroup.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -68,6 +75,16 @@ type CloudStackAffinityGroupList struct {
 	Items           []CloudStackAffinityGroup `json:"items"`
 }
 
+// GetConditions returns the observations of the operational state of the CloudStackAffinityGroup resource.
+func (r *CloudStackAffinityGroup) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the CloudStackAffinityGroup to the predescribed clusterv1.Conditions.
+func (r *CloudStackAffinityGroup) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
 func init() {
 	objectTypes = append(objectTypes, &CloudStackAffinityGroup{}, &CloudStackAffinityGroupList{})
 }
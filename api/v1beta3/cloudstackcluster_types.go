@@ -26,6 +26,14 @@ const (
 	ClusterFinalizer = "cloudstackcluster.infrastructure.cluster.x-k8s.io"
 )
 
+// Strategies placing machines in failure domains.
+const (
+	SpreadPlacement        = "Spread"
+	WeightedPlacement      = "Weighted"
+	CapacityAwarePlacement = "CapacityAware"
+	RandomPlacement        = "Random"
+)
+
 var K8sClient client.Client
 
 // CloudStackClusterSpec defines the desired state of CloudStackCluster.
@@ -38,6 +46,115 @@ type CloudStackClusterSpec struct {
 	// SyncWithACS determines if an externalManaged CKS cluster should be created on ACS.
 	// +optional
 	SyncWithACS *bool `json:"syncWithACS,omitempty"`
+
+	// APIServerLoadBalancer configures the load balancer rules exposing the control plane on the public IP of
+	// isolated networks. Changes are applied to the existing rules.
+	// +optional
+	APIServerLoadBalancer *APIServerLoadBalancer `json:"apiServerLoadBalancer,omitempty"`
+
+	// FailureDomainPlacement is the strategy placing machines CAPI doesn't assign a failure domain to, such as
+	// workers of MachineDeployments without one.
+	// Spread places machines in the failure domain with the fewest machines of their MachineDeployment.
+	// Weighted places them randomly, in proportion to the weights of the failure domains.
+	// CapacityAware spreads them over the failure domains whose zone has room for them, by the zone's capacity
+	// and the resource limits of the account.
+	// Random places them randomly.
+	// Default is "Spread".
+	// +kubebuilder:validation:Enum=Spread;Weighted;CapacityAware;Random
+	// +optional
+	FailureDomainPlacement string `json:"failureDomainPlacement,omitempty"`
+
+	// AdditionalTags are added to the CloudStack resources CAPC creates for the cluster and its machines, e.g. for
+	// chargeback. Tags of machines take precedence. Tags are only added to resources when they're created.
+	// +optional
+	AdditionalTags map[string]string `json:"additionalTags,omitempty"`
+}
+
+// APIServerLoadBalancer configures the control plane's load balancer rules.
+type APIServerLoadBalancer struct {
+	// Algorithm used to balance connections between control plane machines.
+	// Default is "roundrobin".
+	// +kubebuilder:validation:Enum=roundrobin;leastconn;source
+	// +optional
+	Algorithm string `json:"algorithm,omitempty"`
+
+	// HealthCheck of the control plane machines behind the API server rule.
+	// The network offering's load balancer must support health checks.
+	// +optional
+	HealthCheck *LoadBalancerHealthCheck `json:"healthCheck,omitempty"`
+
+	// Stickiness policy of the API server rule.
+	// +optional
+	Stickiness *LoadBalancerStickiness `json:"stickiness,omitempty"`
+
+	// AdditionalPorts forwarded from the public IP to the control plane machines, for example for konnectivity or
+	// an ingress NodePort.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	AdditionalPorts []LoadBalancerPort `json:"additionalPorts,omitempty"`
+
+	// AllowedCIDRs are the source CIDRs allowed to connect through the load balancer rules.
+	// Enforced with ingress firewall rules on the public IP of isolated networks, and with network ACL items in VPC
+	// tiers, which requires the tier to use a custom ACL list. All sources are allowed if empty.
+	// +optional
+	AllowedCIDRs []string `json:"allowedCIDRs,omitempty"`
+}
+
+// LoadBalancerHealthCheck configures a load balancer health check policy. Unset values use CloudStack's defaults.
+type LoadBalancerHealthCheck struct {
+	// PingPath is the HTTP path requested by the health check.
+	// +optional
+	PingPath string `json:"pingPath,omitempty"`
+
+	// IntervalSeconds between health checks.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	IntervalSeconds int `json:"intervalSeconds,omitempty"`
+
+	// TimeoutSeconds after which a health check fails.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	TimeoutSeconds int `json:"timeoutSeconds,omitempty"`
+
+	// HealthyThreshold is the number of consecutive successful checks before a machine is considered healthy.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	HealthyThreshold int `json:"healthyThreshold,omitempty"`
+
+	// UnhealthyThreshold is the number of consecutive failed checks before a machine is considered unhealthy.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	UnhealthyThreshold int `json:"unhealthyThreshold,omitempty"`
+}
+
+// LoadBalancerStickiness configures a load balancer stickiness policy.
+type LoadBalancerStickiness struct {
+	// Method of the stickiness policy.
+	// +kubebuilder:validation:Enum=LbCookie;AppCookie;SourceBased
+	Method string `json:"method"`
+
+	// Parameters of the method, as accepted by CloudStack's createLBStickinessPolicy API.
+	// +optional
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// LoadBalancerPort is an additional port forwarded to the control plane machines.
+type LoadBalancerPort struct {
+	// Name of the port. Used to name its load balancer rule.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Port on the public IP.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int32 `json:"port"`
+
+	// TargetPort on the control plane machines. Defaults to Port.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	TargetPort int32 `json:"targetPort,omitempty"`
 }
 
 // The status of the CloudStackCluster object.
@@ -53,6 +170,10 @@ type CloudStackClusterStatus struct {
 
 	// Reflects the readiness of the CS cluster.
 	Ready bool `json:"ready"`
+
+	// Conditions defines current service state of the CloudStackCluster.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -79,6 +200,16 @@ type CloudStackClusterList struct {
 	Items           []CloudStackCluster `json:"items"`
 }
 
+// GetConditions returns the observations of the operational state of the CloudStackCluster resource.
+func (r *CloudStackCluster) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the CloudStackCluster to the predescribed clusterv1.Conditions.
+func (r *CloudStackCluster) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
 func init() {
 	objectTypes = append(objectTypes, &CloudStackCluster{}, &CloudStackClusterList{})
 }
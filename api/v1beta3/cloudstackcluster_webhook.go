@@ -17,44 +17,71 @@ limitations under the License.
 package v1beta3
 
 import (
+	"context"
 	"fmt"
+	"net"
+	"slices"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/webhookutil"
+	"sigs.k8s.io/cluster-api/util/topology"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
+// defaultAPIServerPort is the control plane endpoint port used when none is set.
+const defaultAPIServerPort = 6443
+
 // log is for logging in this package.
 var cloudstackclusterlog = logf.Log.WithName("cloudstackcluster-resource")
 
-func (r *CloudStackCluster) SetupWebhookWithManager(mgr ctrl.Manager) error {
+func (r *CloudStackCluster) SetupWebhookWithManager(mgr ctrl.Manager, opts WebhookOptions) error {
+	w := &cloudStackClusterWebhook{WebhookOptions: opts}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
+		WithDefaulter(w).
+		WithValidator(w).
 		Complete()
 }
 
+// cloudStackClusterWebhook implements the CloudStackCluster webhooks. It is a custom defaulter and validator, as
+// validating updates needs the admission request to tell topology controller dry-runs apart.
+type cloudStackClusterWebhook struct {
+	WebhookOptions
+}
+
 //+kubebuilder:webhook:path=/mutate-infrastructure-cluster-x-k8s-io-v1beta3-cloudstackcluster,mutating=true,failurePolicy=fail,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=cloudstackclusters,verbs=create;update,versions=v1beta3,name=mcloudstackcluster.kb.io,admissionReviewVersions=v1;v1beta1
 
-var _ webhook.Defaulter = &CloudStackCluster{}
+var _ webhook.CustomDefaulter = &cloudStackClusterWebhook{}
 
-// Default implements webhook.Defaulter so a webhook will be registered for the type
-func (r *CloudStackCluster) Default() {
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type
+func (*cloudStackClusterWebhook) Default(_ context.Context, obj runtime.Object) error {
+	r, ok := obj.(*CloudStackCluster)
+	if !ok {
+		return errors.NewBadRequest(fmt.Sprintf("expected a CloudStackCluster but got a %T", obj))
+	}
 	cloudstackclusterlog.V(1).Info("entered api default setting webhook", "api resource name", r.Name)
 	// No defaulted values supported yet.
+	return nil
 }
 
 // +kubebuilder:webhook:name=vcloudstackcluster.kb.io,groups=infrastructure.cluster.x-k8s.io,resources=cloudstackclusters,versions=v1beta3,verbs=create;update,path=/validate-infrastructure-cluster-x-k8s-io-v1beta3-cloudstackcluster,mutating=false,failurePolicy=fail,sideEffects=None,admissionReviewVersions=v1;v1beta1
 
-var _ webhook.Validator = &CloudStackCluster{}
+var _ webhook.CustomValidator = &cloudStackClusterWebhook{}
 
-// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
-func (r *CloudStackCluster) ValidateCreate() (admission.Warnings, error) {
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
+func (w *cloudStackClusterWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	r, ok := obj.(*CloudStackCluster)
+	if !ok {
+		return nil, errors.NewBadRequest(fmt.Sprintf("expected a CloudStackCluster but got a %T", obj))
+	}
 	cloudstackclusterlog.V(1).Info("entered validate create webhook", "api resource name", r.Name)
 
 	var errorList field.ErrorList
@@ -63,44 +90,176 @@ func (r *CloudStackCluster) ValidateCreate() (admission.Warnings, error) {
 	if len(r.Spec.FailureDomains) == 0 {
 		errorList = append(errorList, field.Required(field.NewPath("spec", "FailureDomains"), "FailureDomains"))
 	} else {
-		for _, fdSpec := range r.Spec.FailureDomains { // Require failureDomain names meet k8s qualified name spec.
-			for _, errMsg := range validation.IsDNS1123Subdomain(fdSpec.Name) {
-				errorList = append(errorList, field.Invalid(
-					field.NewPath("spec", "failureDomains", "name"), fdSpec.Name, errMsg))
+		errorList = append(errorList, w.validateFailureDomains(
+			field.NewPath("spec", "failureDomains"), r.Namespace, r.Spec.FailureDomains)...)
+	}
+	errorList = append(errorList, validateAPIServerLoadBalancer(
+		field.NewPath("spec", "apiServerLoadBalancer"), r.Spec.APIServerLoadBalancer, r.Spec.ControlPlaneEndpoint.Port)...)
+	errorList = validateAdditionalTags(r.Spec.AdditionalTags, field.NewPath("spec", "additionalTags"), errorList)
+
+	var warnings admission.Warnings
+	if w.Preflight != nil && len(errorList) == 0 {
+		warnings, errorList = w.Preflight.ValidateFailureDomains(
+			ctx, r.Namespace, r.Spec.FailureDomains, field.NewPath("spec", "failureDomains"))
+	}
+
+	return warnings, webhookutil.AggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, errorList)
+}
+
+// validateFailureDomains requires failure domain names meet the k8s qualified name spec, and each failure domain
+// to have a network and either an ACS endpoint, in the cluster's namespace if restricted, or an identity reference.
+func (w *WebhookOptions) validateFailureDomains(
+	fdPath *field.Path, namespace string, fdSpecs []CloudStackFailureDomainSpec,
+) field.ErrorList {
+	var errorList field.ErrorList
+	for _, fdSpec := range fdSpecs {
+		for _, errMsg := range validation.IsDNS1123Subdomain(fdSpec.Name) {
+			errorList = append(errorList, field.Invalid(fdPath.Child("name"), fdSpec.Name, errMsg))
+		}
+		if fdSpec.Zone.Network.Name == "" && fdSpec.Zone.Network.ID == "" {
+			errorList = append(errorList, field.Required(
+				fdPath.Child("Zone", "Network"), "each Zone requires a Network specification"))
+		}
+		errorList = append(errorList, validateNetworkACL(fdPath.Child("Zone", "Network", "ACL"), fdSpec.Zone.Network)...)
+		if fdSpec.IdentityRef != nil {
+			if fdSpec.ACSEndpoint.Name != "" || fdSpec.ACSEndpoint.Namespace != "" {
+				errorList = append(errorList, field.Forbidden(
+					fdPath.Child("ACSEndpoint"), "ACSEndpoint and IdentityRef are mutually exclusive"))
+			}
+			if fdSpec.IdentityRef.Name == "" {
+				errorList = append(errorList, field.Required(fdPath.Child("IdentityRef", "Name"), "Name is required"))
 			}
-			if fdSpec.Zone.Network.Name == "" && fdSpec.Zone.Network.ID == "" {
-				errorList = append(errorList, field.Required(
-					field.NewPath("spec", "failureDomains", "Zone", "Network"),
-					"each Zone requires a Network specification"))
+		} else if fdSpec.ACSEndpoint.Name == "" || fdSpec.ACSEndpoint.Namespace == "" {
+			errorList = append(errorList, field.Required(
+				fdPath.Child("ACSEndpoint"), "Name and Namespace are required"))
+		} else if w.RestrictACSEndpointNamespace && fdSpec.ACSEndpoint.Namespace != namespace {
+			errorList = append(errorList, field.Forbidden(fdPath.Child("ACSEndpoint", "Namespace"),
+				"must be the namespace of the cluster, use a CloudStackClusterIdentity to share credentials across namespaces"))
+		}
+	}
+	return errorList
+}
+
+// validateNetworkACL requires network ACLs to be set on VPC tiers only, and to either reference an existing list or
+// declare rules with valid CIDRs and port ranges.
+func validateNetworkACL(aclPath *field.Path, network Network) field.ErrorList {
+	acl := network.ACL
+	if acl == nil {
+		return nil
+	}
+	var errorList field.ErrorList
+	if network.VPC == nil || (network.VPC.ID == "" && network.VPC.Name == "") {
+		errorList = append(errorList, field.Forbidden(aclPath, "network ACLs only apply to networks in a VPC"))
+	}
+	if (acl.ID != "" || acl.Name != "") && len(acl.Rules) > 0 {
+		errorList = append(errorList, field.Forbidden(aclPath.Child("Rules"),
+			"Rules and a reference to an existing ACL list are mutually exclusive"))
+	} else if acl.ID == "" && acl.Name == "" && len(acl.Rules) == 0 {
+		errorList = append(errorList, field.Required(aclPath, "either an existing ACL list or Rules are required"))
+	}
+
+	for i, rule := range acl.Rules {
+		rulePath := aclPath.Child("Rules").Index(i)
+		for j, cidr := range rule.CIDRList {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				errorList = append(errorList, field.Invalid(rulePath.Child("CIDRList").Index(j), cidr, "must be a valid CIDR"))
 			}
-			if fdSpec.ACSEndpoint.Name == "" || fdSpec.ACSEndpoint.Namespace == "" {
-				errorList = append(errorList, field.Required(
-					field.NewPath("spec", "failureDomains", "ACSEndpoint"),
-					"Name and Namespace are required"))
+		}
+		if rule.Protocol != "tcp" && rule.Protocol != "udp" {
+			if rule.StartPort != 0 || rule.EndPort != 0 {
+				errorList = append(errorList, field.Forbidden(rulePath, "ports only apply to tcp and udp rules"))
 			}
+		} else if rule.EndPort != 0 && rule.EndPort < rule.StartPort {
+			errorList = append(errorList, field.Invalid(rulePath.Child("EndPort"), rule.EndPort,
+				"must not be lower than the start port"))
+		} else if rule.EndPort != 0 && rule.StartPort == 0 {
+			errorList = append(errorList, field.Required(rulePath.Child("StartPort"), "required with an end port"))
 		}
 	}
+	return errorList
+}
 
-	return nil, webhookutil.AggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, errorList)
+// validateAPIServerLoadBalancer requires allowed CIDRs to parse, and additional ports to have unique names and ports
+// other than the control plane endpoint's.
+func validateAPIServerLoadBalancer(lbPath *field.Path, lb *APIServerLoadBalancer, endpointPort int32) field.ErrorList {
+	if lb == nil {
+		return nil
+	}
+	var errorList field.ErrorList
+	for i, cidr := range lb.AllowedCIDRs {
+		if _, _, err := net.ParseCIDR(cidr); err != nil {
+			errorList = append(errorList, field.Invalid(lbPath.Child("allowedCIDRs").Index(i), cidr, "must be a valid CIDR"))
+		}
+	}
+
+	if endpointPort == 0 {
+		endpointPort = defaultAPIServerPort
+	}
+	names := map[string]bool{}
+	ports := map[int32]bool{endpointPort: true}
+	for i, port := range lb.AdditionalPorts {
+		portPath := lbPath.Child("additionalPorts").Index(i)
+		if names[port.Name] {
+			errorList = append(errorList, field.Duplicate(portPath.Child("name"), port.Name))
+		}
+		if ports[port.Port] {
+			errorList = append(errorList, field.Invalid(portPath.Child("port"), port.Port,
+				"must differ from the control plane endpoint port and other additional ports"))
+		}
+		names[port.Name] = true
+		ports[port.Port] = true
+	}
+	return errorList
 }
 
-// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
-func (r *CloudStackCluster) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
+func (w *cloudStackClusterWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	r, ok := newObj.(*CloudStackCluster)
+	if !ok {
+		return nil, errors.NewBadRequest(fmt.Sprintf("expected a CloudStackCluster but got a %T", newObj))
+	}
 	cloudstackclusterlog.V(1).Info("entered validate update webhook", "api resource name", r.Name)
 
 	spec := r.Spec
 
-	oldCluster, ok := old.(*CloudStackCluster)
+	oldCluster, ok := oldObj.(*CloudStackCluster)
 	if !ok {
-		return nil, errors.NewBadRequest(fmt.Sprintf("expected a CloudStackCluster but got a %T", old))
+		return nil, errors.NewBadRequest(fmt.Sprintf("expected a CloudStackCluster but got a %T", oldObj))
 	}
 	oldSpec := oldCluster.Spec
 
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil, errors.NewBadRequest(fmt.Sprintf("expected an admission.Request inside context: %v", err))
+	}
+	// The ClusterClass topology controller dry-runs patches of the whole spec to compute its changes.
+	if topology.ShouldSkipImmutabilityChecks(req, r) {
+		return nil, nil
+	}
+	// Updates of the metadata only, such as the removal of finalizers, must not be held up by newer rules.
+	if equality.Semantic.DeepEqual(oldSpec, spec) {
+		return nil, nil
+	}
+
 	errorList := field.ErrorList(nil)
 
 	if err := ValidateFailureDomainUpdates(oldSpec.FailureDomains, spec.FailureDomains); err != nil {
 		errorList = append(errorList, err)
 	}
+	// Only failure domains being added or changed are validated, so that objects admitted under older rules can
+	// still be updated and deleted.
+	var changedFDs []CloudStackFailureDomainSpec
+	for _, fd := range spec.FailureDomains {
+		if !slices.ContainsFunc(oldSpec.FailureDomains, func(oldFD CloudStackFailureDomainSpec) bool {
+			return FailureDomainsEqual(fd, oldFD)
+		}) {
+			changedFDs = append(changedFDs, fd)
+		}
+	}
+	errorList = append(errorList, w.validateFailureDomains(field.NewPath("spec", "failureDomains"), r.Namespace, changedFDs)...)
+	errorList = append(errorList, validateAPIServerLoadBalancer(
+		field.NewPath("spec", "apiServerLoadBalancer"), spec.APIServerLoadBalancer, spec.ControlPlaneEndpoint.Port)...)
+	errorList = validateAdditionalTags(spec.AdditionalTags, field.NewPath("spec", "additionalTags"), errorList)
 
 	if oldSpec.ControlPlaneEndpoint.Host != "" { // Need to allow one time endpoint setting via CAPC cluster controller.
 		errorList = webhookutil.EnsureEqualStrings(
@@ -110,7 +269,13 @@ func (r *CloudStackCluster) ValidateUpdate(old runtime.Object) (admission.Warnin
 			"controlplaneendpoint.port", errorList)
 	}
 
-	return nil, webhookutil.AggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, errorList)
+	var warnings admission.Warnings
+	if w.Preflight != nil && len(errorList) == 0 && len(changedFDs) > 0 {
+		warnings, errorList = w.Preflight.ValidateFailureDomains(
+			ctx, r.Namespace, changedFDs, field.NewPath("spec", "failureDomains"))
+	}
+
+	return warnings, webhookutil.AggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, errorList)
 }
 
 // ValidateFailureDomainUpdates verifies that at least one failure domain has not been deleted, and
@@ -141,17 +306,23 @@ func ValidateFailureDomainUpdates(oldFDs, newFDs []CloudStackFailureDomainSpec)
 func FailureDomainsEqual(fd1, fd2 CloudStackFailureDomainSpec) bool {
 	return fd1.Name == fd2.Name &&
 		fd1.ACSEndpoint == fd2.ACSEndpoint &&
+		ptr.Equal(fd1.IdentityRef, fd2.IdentityRef) &&
 		fd1.Account == fd2.Account &&
 		fd1.Domain == fd2.Domain &&
 		fd1.Zone.Name == fd2.Zone.Name &&
 		fd1.Zone.ID == fd2.Zone.ID &&
 		fd1.Zone.Network.Name == fd2.Zone.Network.Name &&
 		fd1.Zone.Network.ID == fd2.Zone.Network.ID &&
-		fd1.Zone.Network.Type == fd2.Zone.Network.Type
+		fd1.Zone.Network.Type == fd2.Zone.Network.Type &&
+		equality.Semantic.DeepEqual(fd1.Zone.Network.ACL, fd2.Zone.Network.ACL)
 }
 
-// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
-func (r *CloudStackCluster) ValidateDelete() (admission.Warnings, error) {
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
+func (*cloudStackClusterWebhook) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	r, ok := obj.(*CloudStackCluster)
+	if !ok {
+		return nil, errors.NewBadRequest(fmt.Sprintf("expected a CloudStackCluster but got a %T", obj))
+	}
 	cloudstackclusterlog.V(1).Info("entered validate delete webhook", "api resource name", r.Name)
 	// No deletion validations.  Deletion webhook not enabled.
 	return nil, nil
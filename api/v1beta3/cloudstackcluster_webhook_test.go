@@ -18,11 +18,18 @@ package v1beta3_test
 
 import (
 	"context"
+	"time"
 
 	"github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
 	dummies "sigs.k8s.io/cluster-api-provider-cloudstack/test/dummies/v1beta3"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
 )
 
 var _ = ginkgo.Describe("CloudStackCluster webhooks", func() {
@@ -54,6 +61,97 @@ var _ = ginkgo.Describe("CloudStackCluster webhooks", func() {
 			gomega.Expect(k8sClient.Create(ctx, dummies.CSCluster)).Should(gomega.MatchError(gomega.MatchRegexp(requiredRegex,
 				"each Zone requires a Network specification")))
 		})
+
+		ginkgo.It("Should accept a CloudStackCluster whose failure domain references an identity", func() {
+			dummies.CSCluster.Spec.FailureDomains[0].ACSEndpoint = corev1.SecretReference{}
+			dummies.CSCluster.Spec.FailureDomains[0].IdentityRef = &infrav1.CloudStackIdentityReference{Name: "team-a"}
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSCluster)).Should(gomega.Succeed())
+		})
+
+		ginkgo.It("Should reject a CloudStackCluster with both an ACSEndpoint and an identity", func() {
+			dummies.CSCluster.Spec.FailureDomains[0].IdentityRef = &infrav1.CloudStackIdentityReference{Name: "team-a"}
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSCluster)).Should(gomega.MatchError(gomega.MatchRegexp(forbiddenRegex,
+				"ACSEndpoint and IdentityRef are mutually exclusive")))
+		})
+
+		ginkgo.It("Should accept a CloudStackCluster whose ACSEndpoint is in another namespace", func() {
+			dummies.CSCluster.Spec.FailureDomains[0].ACSEndpoint.Namespace = "capc-system"
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSCluster)).Should(gomega.Succeed())
+		})
+
+		ginkgo.It("Should reject a CloudStackCluster whose ACSEndpoint is in another namespace if the manager restricts it", func() {
+			validator := infrav1.NewCloudStackClusterValidator(infrav1.WebhookOptions{RestrictACSEndpointNamespace: true})
+			dummies.CSCluster.Spec.FailureDomains[0].ACSEndpoint.Namespace = "capc-system"
+			_, err := validator.ValidateCreate(ctx, dummies.CSCluster)
+			gomega.Expect(err).Should(gomega.MatchError(gomega.ContainSubstring("must be the namespace of the cluster")))
+		})
+
+		ginkgo.It("Should reject a CloudStackCluster with neither an ACSEndpoint nor an identity", func() {
+			dummies.CSCluster.Spec.FailureDomains[0].ACSEndpoint = corev1.SecretReference{}
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSCluster)).Should(gomega.MatchError(gomega.MatchRegexp(requiredRegex,
+				"Name and Namespace are required")))
+		})
+	})
+
+	ginkgo.Context("When configuring the API server load balancer", func() {
+		ginkgo.It("Should accept a load balancer with additional ports and allowed CIDRs", func() {
+			dummies.CSCluster.Spec.APIServerLoadBalancer = &infrav1.APIServerLoadBalancer{
+				Algorithm:       "leastconn",
+				AdditionalPorts: []infrav1.LoadBalancerPort{{Name: "konnectivity", Port: 8132}},
+				AllowedCIDRs:    []string{"10.0.0.0/8"},
+			}
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSCluster)).Should(gomega.Succeed())
+		})
+
+		ginkgo.It("Should reject an invalid allowed CIDR", func() {
+			dummies.CSCluster.Spec.APIServerLoadBalancer = &infrav1.APIServerLoadBalancer{AllowedCIDRs: []string{"10.0.0.0"}}
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSCluster)).Should(
+				gomega.MatchError(gomega.ContainSubstring("must be a valid CIDR")))
+		})
+
+		ginkgo.It("Should reject an additional port on the control plane endpoint port", func() {
+			dummies.CSCluster.Spec.APIServerLoadBalancer = &infrav1.APIServerLoadBalancer{
+				AdditionalPorts: []infrav1.LoadBalancerPort{{Name: "api", Port: dummies.CSCluster.Spec.ControlPlaneEndpoint.Port}},
+			}
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSCluster)).Should(
+				gomega.MatchError(gomega.ContainSubstring("must differ from the control plane endpoint port")))
+		})
+	})
+
+	ginkgo.Context("When configuring a network ACL", func() {
+		ginkgo.It("Should accept ACL rules for a network in a VPC", func() {
+			dummies.CSCluster.Spec.FailureDomains[0].Zone.Network.VPC = &infrav1.VPC{Name: "vpc1"}
+			dummies.CSCluster.Spec.FailureDomains[0].Zone.Network.ACL = &infrav1.NetworkACL{Rules: []infrav1.NetworkACLRule{
+				{Action: "Allow", Protocol: "tcp", StartPort: 6443, CIDRList: []string{"10.0.0.0/8"}},
+				{Action: "Deny", Protocol: "all"},
+			}}
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSCluster)).Should(gomega.Succeed())
+		})
+
+		ginkgo.It("Should reject an ACL for a network outside of a VPC", func() {
+			dummies.CSCluster.Spec.FailureDomains[0].Zone.Network.ACL = &infrav1.NetworkACL{Name: "acl1"}
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSCluster)).Should(gomega.MatchError(gomega.MatchRegexp(forbiddenRegex,
+				"network ACLs only apply to networks in a VPC")))
+		})
+
+		ginkgo.It("Should reject ACL rules along with a reference to an existing list", func() {
+			dummies.CSCluster.Spec.FailureDomains[0].Zone.Network.VPC = &infrav1.VPC{Name: "vpc1"}
+			dummies.CSCluster.Spec.FailureDomains[0].Zone.Network.ACL = &infrav1.NetworkACL{
+				Name:  "acl1",
+				Rules: []infrav1.NetworkACLRule{{Action: "Allow", Protocol: "all"}},
+			}
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSCluster)).Should(gomega.MatchError(gomega.MatchRegexp(forbiddenRegex,
+				"Rules and a reference to an existing ACL list are mutually exclusive")))
+		})
+
+		ginkgo.It("Should reject ports on an icmp rule", func() {
+			dummies.CSCluster.Spec.FailureDomains[0].Zone.Network.VPC = &infrav1.VPC{Name: "vpc1"}
+			dummies.CSCluster.Spec.FailureDomains[0].Zone.Network.ACL = &infrav1.NetworkACL{
+				Rules: []infrav1.NetworkACLRule{{Action: "Allow", Protocol: "icmp", StartPort: 22}},
+			}
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSCluster)).Should(gomega.MatchError(gomega.MatchRegexp(forbiddenRegex,
+				"ports only apply to tcp and udp rules")))
+		})
 	})
 
 	ginkgo.Context("When updating a CloudStackCluster", func() {
@@ -75,10 +173,52 @@ var _ = ginkgo.Describe("CloudStackCluster webhooks", func() {
 				Should(gomega.MatchError(gomega.MatchRegexp(forbiddenRegex, "controlplaneendpoint\\.host")))
 		})
 
+		ginkgo.It("Should allow dry-run updates of the topology controller to CloudStackCluster FailureDomains", func() {
+			dummies.CSCluster.Annotations = map[string]string{clusterv1.TopologyDryRunAnnotation: ""}
+			dummies.CSCluster.Spec.FailureDomains[0].Zone.Name = "SomeRandomUpdate"
+			gomega.Expect(k8sClient.Update(ctx, dummies.CSCluster, client.DryRunAll)).Should(gomega.Succeed())
+		})
+
 		ginkgo.It("Should reject updates to CloudStackCluster controlplaneendpoint.port", func() {
 			dummies.CSCluster.Spec.ControlPlaneEndpoint.Port = int32(1234)
 			gomega.Expect(k8sClient.Update(ctx, dummies.CSCluster)).
 				Should(gomega.MatchError(gomega.MatchRegexp(forbiddenRegex, "controlplaneendpoint\\.port")))
 		})
 	})
+
+	ginkgo.Context("When updating a CloudStackCluster admitted under older rules", func() {
+		var (
+			validator  webhook.CustomValidator
+			oldCluster *infrav1.CloudStackCluster
+		)
+
+		ginkgo.BeforeEach(func() {
+			validator = infrav1.NewCloudStackClusterValidator(infrav1.WebhookOptions{RestrictACSEndpointNamespace: true})
+			ctx = admission.NewContextWithRequest(ctx, admission.Request{})
+			dummies.CSCluster.Spec.FailureDomains[0].ACSEndpoint.Namespace = "capc-system"
+			oldCluster = dummies.CSCluster.DeepCopy()
+		})
+
+		ginkgo.It("Should accept updates that leave its failure domains unchanged", func() {
+			dummies.CSCluster.Labels = map[string]string{"updated": "true"}
+			_, err := validator.ValidateUpdate(ctx, oldCluster, dummies.CSCluster)
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("Should accept the removal of its finalizers", func() {
+			oldCluster.Finalizers = []string{infrav1.ClusterFinalizer}
+			oldCluster.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+			dummies.CSCluster.DeletionTimestamp = oldCluster.DeletionTimestamp
+			_, err := validator.ValidateUpdate(ctx, oldCluster, dummies.CSCluster)
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+		})
+
+		ginkgo.It("Should still validate the failure domains being added", func() {
+			addedFD := dummies.CSCluster.Spec.FailureDomains[0].DeepCopy()
+			addedFD.Name = "added-fd"
+			dummies.CSCluster.Spec.FailureDomains = append(dummies.CSCluster.Spec.FailureDomains, *addedFD)
+			_, err := validator.ValidateUpdate(ctx, oldCluster, dummies.CSCluster)
+			gomega.Expect(err).Should(gomega.MatchError(gomega.ContainSubstring("must be the namespace of the cluster")))
+		})
+	})
 })
@@ -0,0 +1,88 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta3
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CloudStackClusterIdentityKind is the only kind of identity a failure domain can reference.
+const CloudStackClusterIdentityKind = "CloudStackClusterIdentity"
+
+// CloudStackIdentityReference references the identity whose credentials a failure domain uses.
+type CloudStackIdentityReference struct {
+	// Kind of the identity.
+	// +kubebuilder:validation:Enum=CloudStackClusterIdentity
+	// +kubebuilder:default=CloudStackClusterIdentity
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Name of the identity.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+}
+
+// AllowedNamespaces selects the namespaces of clusters that may use an identity. The union of both fields is allowed.
+type AllowedNamespaces struct {
+	// List of namespaces allowed to use the identity.
+	// +optional
+	// +nullable
+	NamespaceList []string `json:"list,omitempty"`
+
+	// Selector of the namespaces allowed to use the identity.
+	// An empty selector selects no namespaces.
+	// +optional
+	Selector metav1.LabelSelector `json:"selector,omitempty"`
+}
+
+// CloudStackClusterIdentitySpec defines the desired state of CloudStackClusterIdentity.
+type CloudStackClusterIdentitySpec struct {
+	// Apache CloudStack Endpoint secret reference, in the same format as a failure domain's acsEndpoint.
+	SecretRef corev1.SecretReference `json:"secretRef"`
+
+	// AllowedNamespaces restricts which namespaces' clusters may use the identity.
+	// If nil, no namespace is allowed. If empty (`{}`), all namespaces are allowed.
+	// +optional
+	AllowedNamespaces *AllowedNamespaces `json:"allowedNamespaces,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:path=cloudstackclusteridentities,scope=Cluster,categories=cluster-api,shortName=csci
+//+kubebuilder:storageversion
+
+// CloudStackClusterIdentity is the Schema for the cloudstackclusteridentities API.
+// It makes CloudStack credentials available to clusters in the namespaces it allows.
+type CloudStackClusterIdentity struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CloudStackClusterIdentitySpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CloudStackClusterIdentityList contains a list of CloudStackClusterIdentity
+type CloudStackClusterIdentityList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CloudStackClusterIdentity `json:"items"`
+}
+
+func init() {
+	objectTypes = append(objectTypes, &CloudStackClusterIdentity{}, &CloudStackClusterIdentityList{})
+}
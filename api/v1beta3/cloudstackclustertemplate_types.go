@@ -0,0 +1,63 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta3
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// CloudStackClusterTemplateResource defines the data needed to create a CloudStackCluster from a template.
+type CloudStackClusterTemplateResource struct {
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	ObjectMeta clusterv1.ObjectMeta `json:"metadata,omitempty"`
+
+	// Spec is the specification of the desired state of the cluster.
+	Spec CloudStackClusterSpec `json:"spec"`
+}
+
+// CloudStackClusterTemplateSpec defines the desired state of CloudStackClusterTemplate.
+type CloudStackClusterTemplateSpec struct {
+	Template CloudStackClusterTemplateResource `json:"template"`
+}
+
+//+kubebuilder:object:root=true
+//+kubebuilder:resource:path=cloudstackclustertemplates,scope=Namespaced,categories=cluster-api,shortName=csct
+//+kubebuilder:storageversion
+
+// CloudStackClusterTemplate is the Schema for the cloudstackclustertemplates API
+type CloudStackClusterTemplate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec CloudStackClusterTemplateSpec `json:"spec,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CloudStackClusterTemplateList contains a list of CloudStackClusterTemplate
+type CloudStackClusterTemplateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CloudStackClusterTemplate `json:"items"`
+}
+
+func init() {
+	objectTypes = append(objectTypes, &CloudStackClusterTemplate{}, &CloudStackClusterTemplateList{})
+}
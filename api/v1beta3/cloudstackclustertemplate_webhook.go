@@ -0,0 +1,126 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta3
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/webhookutil"
+	"sigs.k8s.io/cluster-api/util/topology"
+	ctrl "sigs.k8s.io/controller-runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// log is for logging in this package.
+var cloudstackclustertemplatelog = logf.Log.WithName("cloudstackclustertemplate-resource")
+
+func (r *CloudStackClusterTemplate) SetupWebhookWithManager(mgr ctrl.Manager, opts WebhookOptions) error {
+	w := &cloudStackClusterTemplateWebhook{WebhookOptions: opts}
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(r).
+		WithDefaulter(w).
+		WithValidator(w).
+		Complete()
+}
+
+// cloudStackClusterTemplateWebhook implements the CloudStackClusterTemplate webhooks.
+type cloudStackClusterTemplateWebhook struct {
+	WebhookOptions
+}
+
+//+kubebuilder:webhook:path=/mutate-infrastructure-cluster-x-k8s-io-v1beta3-cloudstackclustertemplate,mutating=true,failurePolicy=fail,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=cloudstackclustertemplates,verbs=create;update,versions=v1beta3,name=mcloudstackclustertemplate.kb.io,admissionReviewVersions=v1;v1beta1
+
+var _ webhook.CustomDefaulter = &cloudStackClusterTemplateWebhook{}
+
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type
+func (*cloudStackClusterTemplateWebhook) Default(_ context.Context, obj runtime.Object) error {
+	r, ok := obj.(*CloudStackClusterTemplate)
+	if !ok {
+		return errors.NewBadRequest(fmt.Sprintf("expected a CloudStackClusterTemplate but got a %T", obj))
+	}
+	cloudstackclustertemplatelog.V(1).Info("entered default setting webhook", "api resource name", r.Name)
+	// No defaulted values supported yet.
+	return nil
+}
+
+//+kubebuilder:webhook:path=/validate-infrastructure-cluster-x-k8s-io-v1beta3-cloudstackclustertemplate,mutating=false,failurePolicy=fail,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=cloudstackclustertemplates,verbs=create;update,versions=v1beta3,name=vcloudstackclustertemplate.kb.io,admissionReviewVersions=v1;v1beta1
+
+var _ webhook.CustomValidator = &cloudStackClusterTemplateWebhook{}
+
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
+func (w *cloudStackClusterTemplateWebhook) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	r, ok := obj.(*CloudStackClusterTemplate)
+	if !ok {
+		return nil, errors.NewBadRequest(fmt.Sprintf("expected a CloudStackClusterTemplate but got a %T", obj))
+	}
+	cloudstackclustertemplatelog.V(1).Info("entered validate create webhook", "api resource name", r.Name)
+
+	// Failure domains may be left to ClusterClass patches, but those given have to be valid. Clusters are created in
+	// the namespace of their templates.
+	errorList := w.validateFailureDomains(
+		field.NewPath("spec", "template", "spec", "failureDomains"), r.Namespace, r.Spec.Template.Spec.FailureDomains)
+
+	return nil, webhookutil.AggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, errorList)
+}
+
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
+func (*cloudStackClusterTemplateWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	r, ok := newObj.(*CloudStackClusterTemplate)
+	if !ok {
+		return nil, errors.NewBadRequest(fmt.Sprintf("expected a CloudStackClusterTemplate but got a %T", newObj))
+	}
+	cloudstackclustertemplatelog.V(1).Info("entered validate update webhook", "api resource name", r.Name)
+
+	oldTemplate, ok := oldObj.(*CloudStackClusterTemplate)
+	if !ok {
+		return nil, errors.NewBadRequest(fmt.Sprintf("expected a CloudStackClusterTemplate but got a %T", oldObj))
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil, errors.NewBadRequest(fmt.Sprintf("expected an admission.Request inside context: %v", err))
+	}
+	if topology.ShouldSkipImmutabilityChecks(req, r) {
+		return nil, nil
+	}
+
+	errorList := field.ErrorList(nil)
+	if !reflect.DeepEqual(r.Spec.Template.Spec, oldTemplate.Spec.Template.Spec) { // Equivalent to other Ensure funcs.
+		errorList = append(errorList, field.Forbidden(field.NewPath("spec", "template", "spec"),
+			"CloudStackClusterTemplate spec.template.spec is immutable. Please create a new resource instead."))
+	}
+
+	return nil, webhookutil.AggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, errorList)
+}
+
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
+func (*cloudStackClusterTemplateWebhook) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	r, ok := obj.(*CloudStackClusterTemplate)
+	if !ok {
+		return nil, errors.NewBadRequest(fmt.Sprintf("expected a CloudStackClusterTemplate but got a %T", obj))
+	}
+	cloudstackclustertemplatelog.V(1).Info("entered validate delete webhook", "api resource name", r.Name)
+	// No deletion validations.  Deletion webhook not enabled.
+	return nil, nil
+}
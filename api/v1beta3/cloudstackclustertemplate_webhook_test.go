@@ -0,0 +1,76 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta3_test
+
+import (
+	"context"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+	dummies "sigs.k8s.io/cluster-api-provider-cloudstack/test/dummies/v1beta3"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = ginkgo.Describe("CloudStackClusterTemplate webhooks", func() {
+	var ctx context.Context
+	forbiddenRegex := "admission webhook.*denied the request.*Forbidden\\: %s"
+	requiredRegex := "admission webhook.*denied the request.*Required value\\: %s"
+
+	ginkgo.BeforeEach(func() { // Reset test vars to initial state.
+		ctx = context.Background()
+		dummies.SetDummyVars()
+		_ = k8sClient.Delete(ctx, dummies.CSClusterTemplate) // Delete any remnants.
+		dummies.SetDummyVars()
+	})
+
+	ginkgo.Context("When creating a CloudStackClusterTemplate", func() {
+		ginkgo.It("Should accept a CloudStackClusterTemplate with all attributes present", func() {
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSClusterTemplate)).Should(gomega.Succeed())
+		})
+
+		ginkgo.It("Should accept a CloudStackClusterTemplate without FailureDomains", func() {
+			dummies.CSClusterTemplate.Spec.Template.Spec.FailureDomains = nil
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSClusterTemplate)).Should(gomega.Succeed())
+		})
+
+		ginkgo.It("Should reject a CloudStackClusterTemplate with missing Zone.Network attribute", func() {
+			dummies.CSClusterTemplate.Spec.Template.Spec.FailureDomains[0].Zone = infrav1.CloudStackZoneSpec{}
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSClusterTemplate)).Should(
+				gomega.MatchError(gomega.MatchRegexp(requiredRegex, "each Zone requires a Network specification")))
+		})
+	})
+
+	ginkgo.Context("When updating a CloudStackClusterTemplate", func() {
+		ginkgo.BeforeEach(func() {
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSClusterTemplate)).Should(gomega.Succeed())
+		})
+
+		ginkgo.It("Should reject updates to the template spec", func() {
+			dummies.CSClusterTemplate.Spec.Template.Spec.FailureDomains[0].Zone.Name = "SomeRandomUpdate"
+			gomega.Expect(k8sClient.Update(ctx, dummies.CSClusterTemplate)).
+				Should(gomega.MatchError(gomega.MatchRegexp(forbiddenRegex, "immutable")))
+		})
+
+		ginkgo.It("Should allow dry-run updates of the topology controller", func() {
+			dummies.CSClusterTemplate.Annotations = map[string]string{clusterv1.TopologyDryRunAnnotation: ""}
+			dummies.CSClusterTemplate.Spec.Template.Spec.FailureDomains[0].Zone.Name = "SomeRandomUpdate"
+			gomega.Expect(k8sClient.Update(ctx, dummies.CSClusterTemplate, client.DryRunAll)).Should(gomega.Succeed())
+		})
+	})
+})
@@ -22,6 +22,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 // FailureDomainHashedMetaName returns an MD5 name generated from the FailureDomain and Cluster name.
@@ -62,6 +63,15 @@ type Network struct {
 	// +optional
 	Netmask string `json:"netmask,omitempty"`
 
+	// Cloudstack Network IPv6 Gateway the cluster is built in.
+	// +optional
+	IP6Gateway string `json:"ip6Gateway,omitempty"`
+
+	// Cloudstack Network IPv6 CIDR the cluster is built in.
+	// Set for dual-stack networks, whose offering supports IPv6.
+	// +optional
+	IP6CIDR string `json:"ip6CIDR,omitempty"`
+
 	// Cloudstack Network Offering the cluster is built in.
 	// Default is "DefaultIsolatedNetworkOfferingWithSourceNatService" for
 	// isolated networks and "DefaultIsolatedNetworkOfferingForVpcNetworks"
@@ -73,6 +83,11 @@ type Network struct {
 	// +optional
 	VPC *VPC `json:"vpc,omitempty"`
 
+	// Network ACL list of the network. Only applies to VPC tiers.
+	// Without it, the tier uses the default ACL list of its offering.
+	// +optional
+	ACL *NetworkACL `json:"acl,omitempty"`
+
 	// Cloudstack Network's routing mode.
 	// Routing mode can be Dynamic, or Static.
 	// Empty value means the network mode is NATTED, not ROUTED.
@@ -99,6 +114,59 @@ type VPC struct {
 	Offering string `json:"offering,omitempty"`
 }
 
+// NetworkACL selects the network ACL list of a VPC tier. Either an existing list is referenced by ID or name, or
+// rules are declared for a list CAPC creates for the tier.
+type NetworkACL struct {
+	// ID of an existing network ACL list.
+	// +optional
+	ID string `json:"id,omitempty"`
+
+	// Name of an existing network ACL list.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Rules of the network ACL list CAPC creates for the tier, in order of precedence.
+	// The list is deleted along with the tier.
+	// +optional
+	Rules []NetworkACLRule `json:"rules,omitempty"`
+}
+
+// NetworkACLRule is an item of a network ACL list.
+type NetworkACLRule struct {
+	// Action taken on matching traffic.
+	// +kubebuilder:validation:Enum=Allow;Deny
+	Action string `json:"action"`
+
+	// Direction of the traffic the rule applies to.
+	// Default is "Ingress".
+	// +kubebuilder:validation:Enum=Ingress;Egress
+	// +optional
+	TrafficType string `json:"trafficType,omitempty"`
+
+	// Protocol of the traffic the rule applies to.
+	// +kubebuilder:validation:Enum=tcp;udp;icmp;all
+	Protocol string `json:"protocol"`
+
+	// First port of the range the rule applies to. Only applies to tcp and udp.
+	// Without it, the rule applies to all ports.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	StartPort int32 `json:"startPort,omitempty"`
+
+	// Last port of the range the rule applies to.
+	// Default is the start port.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	// +optional
+	EndPort int32 `json:"endPort,omitempty"`
+
+	// Source CIDRs of ingress and destination CIDRs of egress traffic the rule applies to.
+	// Default is all addresses.
+	// +optional
+	CIDRList []string `json:"cidrList,omitempty"`
+}
+
 // CloudStackZoneSpec specifies a Zone's details.
 type CloudStackZoneSpec struct {
 	// Name.
@@ -134,13 +202,32 @@ type CloudStackFailureDomainSpec struct {
 	Project string `json:"project,omitempty"`
 
 	// Apache CloudStack Endpoint secret reference.
-	ACSEndpoint corev1.SecretReference `json:"acsEndpoint"`
+	// Either this or identityRef is required. The secret must be in the namespace of the failure domain when the
+	// manager restricts acsEndpoint namespaces.
+	// +optional
+	ACSEndpoint corev1.SecretReference `json:"acsEndpoint,omitempty"`
+
+	// IdentityRef references the CloudStackClusterIdentity whose credentials are used instead of acsEndpoint.
+	// The identity must allow the cluster's namespace.
+	// +optional
+	IdentityRef *CloudStackIdentityReference `json:"identityRef,omitempty"`
+
+	// Weight of the failure domain when placing machines with the Weighted strategy.
+	// Failure domains with a weight of 0 only get machines when all failure domains have a weight of 0.
+	// Default is 1.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Weight *int32 `json:"weight,omitempty"`
 }
 
 // CloudStackFailureDomainStatus defines the observed state of CloudStackFailureDomain
 type CloudStackFailureDomainStatus struct {
 	// Reflects the readiness of the CloudStack Failure Domain.
 	Ready bool `json:"ready"`
+
+	// Conditions defines current service state of the CloudStackFailureDomain.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 }
 
 //+kubebuilder:object:root=true
@@ -165,6 +252,16 @@ type CloudStackFailureDomainList struct {
 	Items           []CloudStackFailureDomain `json:"items"`
 }
 
+// GetConditions returns the observations of the operational state of the CloudStackFailureDomain resource.
+func (r *CloudStackFailureDomain) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the CloudStackFailureDomain to the predescribed clusterv1.Conditions.
+func (r *CloudStackFailureDomain) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
 func init() {
 	objectTypes = append(objectTypes, &CloudStackFailureDomain{}, &CloudStackFailureDomainList{})
 }
@@ -48,6 +48,15 @@ type CloudStackIsolatedNetworkSpec struct {
 	// +optional
 	Netmask string `json:"netmask,omitempty"`
 
+	// IPv6 gateway for the network.
+	// +optional
+	IP6Gateway string `json:"ip6Gateway,omitempty"`
+
+	// IPv6 CIDR for the network.
+	// Only set for dual-stack networks.
+	// +optional
+	IP6CIDR string `json:"ip6CIDR,omitempty"`
+
 	// Offering for the network.
 	// Default is "DefaultIsolatedNetworkOfferingWithSourceNatService" for
 	// isolated networks and "DefaultIsolatedNetworkOfferingForVpcNetworks"
@@ -58,6 +67,10 @@ type CloudStackIsolatedNetworkSpec struct {
 	// VPC the network belongs to.
 	// +optional
 	VPC *VPC `json:"vpc,omitempty"`
+
+	// Network ACL list of the network, if in a VPC.
+	// +optional
+	ACL *NetworkACL `json:"acl,omitempty"`
 }
 
 // CloudStackIsolatedNetworkStatus defines the observed state of CloudStackIsolatedNetwork
@@ -68,6 +81,22 @@ type CloudStackIsolatedNetworkStatus struct {
 	// The ID of the lb rule used to assign VMs to the lb.
 	LBRuleID string `json:"loadBalancerRuleID,omitempty"`
 
+	// The IDs of the lb rules of the API server load balancer's additional ports, by port name.
+	// +optional
+	AdditionalLBRuleIDs map[string]string `json:"additionalLoadBalancerRuleIDs,omitempty"`
+
+	// The ID of the health check policy of the API server lb rule.
+	// +optional
+	LBHealthCheckPolicyID string `json:"loadBalancerHealthCheckPolicyID,omitempty"`
+
+	// The ID of the stickiness policy of the API server lb rule.
+	// +optional
+	LBStickinessPolicyID string `json:"loadBalancerStickinessPolicyID,omitempty"`
+
+	// The ID of the network ACL list CAPC created for the network.
+	// +optional
+	NetworkACLListID string `json:"networkACLListID,omitempty"`
+
 	// Routing mode of the network.
 	// Routing mode can be Dynamic, or Static.
 	// Empty value means the network mode is NATTED, not ROUTED.
@@ -78,6 +107,10 @@ type CloudStackIsolatedNetworkStatus struct {
 
 	// Ready indicates the readiness of this provider resource.
 	Ready bool `json:"ready"`
+
+	// Conditions defines current service state of the CloudStackIsolatedNetwork.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 }
 
 func (n *CloudStackIsolatedNetwork) Network() *Network {
@@ -87,7 +120,10 @@ func (n *CloudStackIsolatedNetwork) Network() *Network {
 		ID:          n.Spec.ID,
 		Gateway:     n.Spec.Gateway,
 		Netmask:     n.Spec.Netmask,
+		IP6Gateway:  n.Spec.IP6Gateway,
+		IP6CIDR:     n.Spec.IP6CIDR,
 		VPC:         n.Spec.VPC,
+		ACL:         n.Spec.ACL,
 		Offering:    n.Spec.Offering,
 		RoutingMode: n.Status.RoutingMode,
 	}
@@ -115,6 +151,16 @@ type CloudStackIsolatedNetworkList struct {
 	Items           []CloudStackIsolatedNetwork `json:"items"`
 }
 
+// GetConditions returns the observations of the operational state of the CloudStackIsolatedNetwork resource.
+func (r *CloudStackIsolatedNetwork) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the CloudStackIsolatedNetwork to the predescribed clusterv1.Conditions.
+func (r *CloudStackIsolatedNetwork) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
 func init() {
 	objectTypes = append(objectTypes, &CloudStackIsolatedNetwork{}, &CloudStackIsolatedNetworkList{})
 }
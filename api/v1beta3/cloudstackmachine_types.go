@@ -21,6 +21,7 @@ import (
 
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
 // The presence of a finalizer prevents CAPI from deleting the corresponding CAPI data.
@@ -30,8 +31,34 @@ const (
 	ProAffinity  = "pro"
 	AntiAffinity = "anti"
 	NoAffinity   = "no"
+	// SoftProAffinity and SoftAntiAffinity prefer placing machines on the same or different hosts, but don't fail
+	// deployments when that isn't possible.
+	SoftProAffinity  = "soft-pro"
+	SoftAntiAffinity = "soft-anti"
 )
 
+// In-place resize policies of CloudStackMachines.
+const (
+	// LiveResize scales running instances, which requires a dynamically scalable template and offering.
+	LiveResize = "Live"
+	// StopStartResize stops instances before scaling them and starts them again afterwards.
+	StopStartResize = "StopStart"
+)
+
+// Labels set on the CAPI Machine of a CloudStackMachine with the placement of its instance. CAPI syncs labels of the
+// node.cluster.x-k8s.io domain to the machine's Node.
+const (
+	PodLabel        = "node.cluster.x-k8s.io/cloudstack-pod"
+	ClusterLabel    = "node.cluster.x-k8s.io/cloudstack-cluster"
+	HostLabel       = "node.cluster.x-k8s.io/cloudstack-host"
+	HypervisorLabel = "node.cluster.x-k8s.io/cloudstack-hypervisor"
+)
+
+// InPlaceResizeOfferingAnnotation is set on a CloudStackMachineTemplate to resize the machines cloned from it to the
+// named compute offering, if they allow in-place resizing. The template itself is left unchanged, so this does not
+// trigger a rollout.
+const InPlaceResizeOfferingAnnotation = "cloudstackmachinetemplate.infrastructure.cluster.x-k8s.io/in-place-resize-offering"
+
 type NetworkSpec struct {
 	// CloudStack Network Name (required to resolve ID)
 	Name string `json:"name"`
@@ -39,8 +66,16 @@ type NetworkSpec struct {
 	// Optional IP in the network
 	IP string `json:"ip,omitempty"`
 
+	// Optional IPv6 address in the network. The network must be dual-stack.
+	IP6 string `json:"ip6,omitempty"`
+
 	// Optional Network ID (overrides Name if set)
 	ID string `json:"id,omitempty"`
+
+	// Optional IPAM pools, such as InClusterIPPools or GlobalInClusterIPPools, to allocate the IP addresses in the
+	// network from. An IPAddressClaim is created per pool and the allocated IPv4 or IPv6 address is set as IP or IP6.
+	// +optional
+	AddressesFromPools []corev1.TypedLocalObjectReference `json:"addressesFromPools,omitempty"`
 }
 
 // CloudStackMachineSpec defines the desired state of CloudStackMachine
@@ -56,16 +91,39 @@ type CloudStackMachineSpec struct {
 	// Instance ID. Should only be useful to modify an existing instance.
 	InstanceID *string `json:"instanceID,omitempty"`
 
+	// Adopt takes over the existing instance with the InstanceID instead of deploying one, e.g. to bring the nodes of a
+	// cluster that wasn't built by CAPI under its management. The instance has to be running, be in the zone and
+	// networks of the machine's failure domain and run with the machine's offering. It isn't given any userdata, and is
+	// destroyed along with the machine like the instances CAPC deploys.
+	//+optional
+	Adopt bool `json:"adopt,omitempty"`
+
 	// CloudStack compute offering.
 	Offering CloudStackResourceIdentifier `json:"offering"`
 
+	// InPlaceResize allows changing the offering of the machine, which then scales its instance instead of being
+	// replaced. With "Live", running instances are scaled. With "StopStart", instances are stopped while scaling.
+	// +kubebuilder:validation:Enum=Live;StopStart
+	// +optional
+	InPlaceResize string `json:"inPlaceResize,omitempty"`
+
 	// CloudStack template to use.
 	Template CloudStackResourceIdentifier `json:"template"`
 
+	// Size and disk offering of the root volume, overriding those of the template and compute offering.
+	// +optional
+	RootDisk *CloudStackRootDisk `json:"rootDisk,omitempty"`
+
 	// CloudStack disk offering to use.
 	// +optional
 	DiskOffering CloudStackResourceDiskOffering `json:"diskOffering,omitempty"`
 
+	// Additional data disks, created and attached once the instance is running.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	DataDisks []CloudStackDataDisk `json:"dataDisks,omitempty"`
+
 	// The list of networks (overrides zone.network)
 	// +optional
 	// In CloudStackMachineSpec
@@ -78,12 +136,18 @@ type CloudStackMachineSpec struct {
 	// Optional details map for deployVirtualMachine
 	Details map[string]string `json:"details,omitempty"`
 
+	// AdditionalTags are added to the instance and data disk volumes of the machine, on top of the AdditionalTags of
+	// its cluster.
+	// +optional
+	AdditionalTags map[string]string `json:"additionalTags,omitempty"`
+
 	// Optional affinitygroupids for deployVirtualMachine
 	// +optional
 	AffinityGroupIDs []string `json:"affinityGroupIDs,omitempty"`
 
 	// Mutually exclusive parameter with AffinityGroupIDs.
-	// Defaults to `no`. Can be `pro` or `anti`. Will create an affinity group per machine set.
+	// Defaults to `no`. Can be `pro`, `anti`, `soft-pro` or `soft-anti`. Will create an affinity group per machine set.
+	// The soft variants require CloudStack 4.18 or later.
 	// +optional
 	Affinity string `json:"affinity,omitempty"`
 
@@ -92,6 +156,10 @@ type CloudStackMachineSpec struct {
 	// +optional
 	AffinityGroupRef *corev1.ObjectReference `json:"cloudstackAffinityRef,omitempty"`
 
+	// Pod, cluster and/or host of the failure domain's zone to deploy the instance on. Requires admin privileges.
+	// +optional
+	Placement *CloudStackPlacement `json:"placement,omitempty"`
+
 	// The CS specific unique identifier. Of the form: fmt.Sprintf("cloudstack:///%s", CS Machine ID)
 	// +optional
 	ProviderID *string `json:"providerID,omitempty"`
@@ -121,6 +189,21 @@ type CloudStackResourceIdentifier struct {
 	Name string `json:"name,omitempty"`
 }
 
+// CloudStackPlacement identifies where in a zone an instance is deployed.
+type CloudStackPlacement struct {
+	// CloudStack pod.
+	// +optional
+	Pod CloudStackResourceIdentifier `json:"pod,omitempty"`
+
+	// CloudStack host cluster.
+	// +optional
+	Cluster CloudStackResourceIdentifier `json:"cluster,omitempty"`
+
+	// CloudStack host.
+	// +optional
+	Host CloudStackResourceIdentifier `json:"host,omitempty"`
+}
+
 type CloudStackResourceDiskOffering struct {
 	CloudStackResourceIdentifier `json:",inline"`
 	// Desired disk size. Used if disk offering is customizable as indicated by the ACS field 'Custom Disk Size'.
@@ -136,6 +219,61 @@ type CloudStackResourceDiskOffering struct {
 	Label string `json:"label"`
 }
 
+// CloudStackRootDisk overrides the root volume of a machine.
+type CloudStackRootDisk struct {
+	// Size of the root volume. Must not be smaller than the template.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	Size int64 `json:"sizeInGB,omitempty"`
+
+	// CloudStack disk offering of the root volume, overriding the one of the compute offering.
+	// +optional
+	Offering CloudStackResourceIdentifier `json:"offering,omitempty"`
+
+	// Storage tag the root volume should be placed on. Without an offering, the disk offering with this tag is used,
+	// otherwise the offering must have the tag.
+	// +optional
+	StorageTag string `json:"storageTag,omitempty"`
+}
+
+// CloudStackDataDisk is a data disk created for a machine in addition to the disk of its DiskOffering.
+type CloudStackDataDisk struct {
+	// Name of the data disk, unique within the machine. The disk's volume is named after the machine and this name.
+	// +kubebuilder:validation:Pattern=`^[a-z0-9]([-a-z0-9]*[a-z0-9])?$`
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// CloudStack disk offering of the data disk.
+	Offering CloudStackResourceIdentifier `json:"offering"`
+
+	// Desired disk size. Used if disk offering is customizable as indicated by the ACS field 'Custom Disk Size'.
+	// +optional
+	CustomSize int64 `json:"customSizeInGB,omitempty"`
+
+	// Minimum IOPS of the disk. Used if the disk offering has customized IOPS.
+	// +optional
+	MinIOPS int64 `json:"minIOPS,omitempty"`
+
+	// Maximum IOPS of the disk. Used if the disk offering has customized IOPS.
+	// +optional
+	MaxIOPS int64 `json:"maxIOPS,omitempty"`
+
+	// Path the disk is mounted at. Without it, the disk is attached but neither formatted nor mounted.
+	// +optional
+	MountPath string `json:"mountPath,omitempty"`
+
+	// Filesystem the disk is formatted with, if it has none yet.
+	// Default is "ext4".
+	// +kubebuilder:validation:Enum=ext4;xfs
+	// +optional
+	Filesystem string `json:"filesystem,omitempty"`
+
+	// Label of the filesystem.
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_-]{1,12}$`
+	// +optional
+	Label string `json:"label,omitempty"`
+}
+
 // Type pulled mostly from the CloudStack API.
 type CloudStackMachineStatus struct {
 	// Addresses contains a CloudStack VM instance's IP addresses.
@@ -149,6 +287,38 @@ type CloudStackMachineStatus struct {
 	// +optional
 	InstanceStateLastUpdated metav1.Time `json:"instanceStateLastUpdated,omitempty"`
 
+	// ServiceOfferingID is the ID of the compute offering the instance runs with.
+	// +optional
+	ServiceOfferingID string `json:"serviceOfferingID,omitempty"`
+
+	// ServiceOfferingName is the name of the compute offering the instance runs with.
+	// +optional
+	ServiceOfferingName string `json:"serviceOfferingName,omitempty"`
+
+	// Placement is the pod, cluster and host the instance runs on. Only reported to admin users.
+	// +optional
+	Placement *CloudStackPlacement `json:"placement,omitempty"`
+
+	// Hypervisor is the type of hypervisor the instance runs on.
+	// +optional
+	Hypervisor string `json:"hypervisor,omitempty"`
+
+	// TemplateID is the ID of the template the instance was deployed from.
+	// +optional
+	TemplateID string `json:"templateID,omitempty"`
+
+	// CPUNumber is the number of vCPUs of the instance.
+	// +optional
+	CPUNumber int `json:"cpuNumber,omitempty"`
+
+	// MemoryMiB is the memory of the instance.
+	// +optional
+	MemoryMiB int `json:"memoryMiB,omitempty"`
+
+	// RootVolumeID is the ID of the root volume of the instance.
+	// +optional
+	RootVolumeID string `json:"rootVolumeID,omitempty"`
+
 	// Ready indicates the readiness of the provider resource.
 	Ready bool `json:"ready"`
 
@@ -159,6 +329,34 @@ type CloudStackMachineStatus struct {
 	// Reason indicates the reason of status failure
 	// +optional
 	Reason *string `json:"reason,omitempty"`
+
+	// AsyncJob is the CloudStack asynchronous job the controller waits on before acting on the instance again,
+	// such as a pending deploy, destroy, stop or scale job.
+	// +optional
+	AsyncJob *CloudStackAsyncJob `json:"asyncJob,omitempty"`
+
+	// InsufficientCapacityFailureDomains are the failure domains deploying the instance failed in for lack of
+	// capacity. They're skipped when placing the machine in another failure domain.
+	// +optional
+	InsufficientCapacityFailureDomains []string `json:"insufficientCapacityFailureDomains,omitempty"`
+
+	// Conditions defines current service state of the CloudStackMachine.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// CloudStackAsyncJob is a CloudStack asynchronous job started by the controller. The job is polled through
+// queryAsyncJobResult on later reconciliations instead of blocking until it finishes.
+type CloudStackAsyncJob struct {
+	// ID is the CloudStack ID of the job.
+	ID string `json:"id"`
+
+	// Command is the CloudStack API command that started the job, e.g. destroyVirtualMachine.
+	Command string `json:"command"`
+
+	// StartTime is the time the job was started.
+	// +optional
+	StartTime metav1.Time `json:"startTime,omitempty"`
 }
 
 // TimeSinceLastStateChange returns the amount of time that's elapsed since the state was last updated.  If the state
@@ -198,6 +396,16 @@ type CloudStackMachineList struct {
 	Items           []CloudStackMachine `json:"items"`
 }
 
+// GetConditions returns the observations of the operational state of the CloudStackMachine resource.
+func (r *CloudStackMachine) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the CloudStackMachine to the predescribed clusterv1.Conditions.
+func (r *CloudStackMachine) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
 func init() {
 	objectTypes = append(objectTypes, &CloudStackMachine{}, &CloudStackMachineList{})
 }
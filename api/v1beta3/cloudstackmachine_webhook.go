@@ -17,8 +17,10 @@ limitations under the License.
 package v1beta3
 
 import (
+	"context"
 	"fmt"
 	"reflect"
+	"strings"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -33,28 +35,46 @@ import (
 // log is for logging in this package.
 var cloudstackmachinelog = logf.Log.WithName("cloudstackmachine-resource")
 
-func (r *CloudStackMachine) SetupWebhookWithManager(mgr ctrl.Manager) error {
+func (r *CloudStackMachine) SetupWebhookWithManager(mgr ctrl.Manager, opts WebhookOptions) error {
+	w := &cloudStackMachineWebhook{WebhookOptions: opts}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
+		WithDefaulter(w).
+		WithValidator(w).
 		Complete()
 }
 
+// cloudStackMachineWebhook implements the CloudStackMachine webhooks. It is a custom defaulter and validator, as
+// preflight validation queries CloudStack within the admission request's context.
+type cloudStackMachineWebhook struct {
+	WebhookOptions
+}
+
 //+kubebuilder:webhook:path=/mutate-infrastructure-cluster-x-k8s-io-v1beta3-cloudstackmachine,mutating=true,failurePolicy=fail,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=cloudstackmachines,verbs=create;update,versions=v1beta3,name=mcloudstackmachine.kb.io,admissionReviewVersions=v1;v1beta1
 
-var _ webhook.Defaulter = &CloudStackMachine{}
+var _ webhook.CustomDefaulter = &cloudStackMachineWebhook{}
 
-// Default implements webhook.Defaulter so a webhook will be registered for the type
-func (r *CloudStackMachine) Default() {
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type
+func (*cloudStackMachineWebhook) Default(_ context.Context, obj runtime.Object) error {
+	r, ok := obj.(*CloudStackMachine)
+	if !ok {
+		return errors.NewBadRequest(fmt.Sprintf("expected a CloudStackMachine but got a %T", obj))
+	}
 	cloudstackmachinelog.V(1).Info("entered api default setting webhook, no defaults to set", "api resource name", r.Name)
 	// No defaulted values supported yet.
+	return nil
 }
 
 //+kubebuilder:webhook:path=/validate-infrastructure-cluster-x-k8s-io-v1beta3-cloudstackmachine,mutating=false,failurePolicy=fail,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=cloudstackmachines,verbs=create;update,versions=v1beta3,name=vcloudstackmachine.kb.io,admissionReviewVersions=v1;v1beta1
 
-var _ webhook.Validator = &CloudStackMachine{}
+var _ webhook.CustomValidator = &cloudStackMachineWebhook{}
 
-// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
-func (r *CloudStackMachine) ValidateCreate() (admission.Warnings, error) {
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
+func (w *cloudStackMachineWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	r, ok := obj.(*CloudStackMachine)
+	if !ok {
+		return nil, errors.NewBadRequest(fmt.Sprintf("expected a CloudStackMachine but got a %T", obj))
+	}
 	cloudstackmachinelog.V(1).Info("entered validate create webhook", "api resource name", r.Name)
 
 	var errorList field.ErrorList
@@ -64,24 +84,173 @@ func (r *CloudStackMachine) ValidateCreate() (admission.Warnings, error) {
 	if len(r.Spec.DiskOffering.ID) > 0 || len(r.Spec.DiskOffering.Name) > 0 {
 		errorList = webhookutil.EnsureIntFieldsAreNotNegative(r.Spec.DiskOffering.CustomSize, "customSizeInGB", errorList)
 	}
+	errorList = validateAffinity(r.Spec, errorList)
+	errorList = validateAddressesFromPools(r.Spec.Networks, field.NewPath("spec", "networks"), errorList)
+	errorList = validateRootDisk(r.Spec.RootDisk, field.NewPath("spec", "rootDisk"), errorList)
+	errorList = validateDataDisks(r.Spec.DataDisks, field.NewPath("spec", "dataDisks"), errorList)
+	errorList = validatePlacement(r.Spec.Placement, field.NewPath("spec", "placement"), errorList)
+	errorList = validateAdditionalTags(r.Spec.AdditionalTags, field.NewPath("spec", "additionalTags"), errorList)
+	errorList = validateAdoption(r.Spec, errorList)
 
-	return nil, webhookutil.AggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, errorList)
+	var warnings admission.Warnings
+	if w.Preflight != nil && len(errorList) == 0 {
+		warnings, errorList = w.Preflight.ValidateMachineSpec(ctx, r, &r.Spec, field.NewPath("spec"))
+	}
+
+	return warnings, webhookutil.AggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, errorList)
+}
+
+// validateAdoption requires the ID of the instance to adopt, and forbids what's only applied when deploying an
+// instance: data disks and managed affinity.
+func validateAdoption(spec CloudStackMachineSpec, errorList field.ErrorList) field.ErrorList {
+	if !spec.Adopt {
+		return errorList
+	}
+	if spec.InstanceID == nil || *spec.InstanceID == "" {
+		errorList = append(errorList, field.Required(field.NewPath("spec", "instanceID"), "ID of the instance to adopt"))
+	}
+	if len(spec.DataDisks) > 0 {
+		errorList = append(errorList, field.Forbidden(field.NewPath("spec", "dataDisks"),
+			"data disks can't be added to adopted instances"))
+	}
+	if affinity := strings.ToLower(spec.Affinity); (affinity != "" && affinity != NoAffinity) || len(spec.AffinityGroupIDs) > 0 {
+		errorList = append(errorList, field.Forbidden(field.NewPath("spec", "affinity"),
+			"adopted instances can't be added to affinity groups"))
+	}
+	return errorList
+}
+
+// validateAffinity requires a known affinity, and no affinity group IDs along with a managed affinity.
+func validateAffinity(spec CloudStackMachineSpec, errorList field.ErrorList) field.ErrorList {
+	affinity := strings.ToLower(spec.Affinity)
+	switch affinity {
+	case "", NoAffinity, ProAffinity, AntiAffinity, SoftProAffinity, SoftAntiAffinity:
+	default:
+		errorList = append(errorList, field.Invalid(field.NewPath("spec", "Affinity"), spec.Affinity,
+			`Affinity must be "no", "pro", "anti", "soft-pro", "soft-anti", or unspecified.`))
+	}
+	if affinity != NoAffinity && affinity != "" && len(spec.AffinityGroupIDs) > 0 {
+		errorList = append(errorList, field.Forbidden(field.NewPath("spec", "AffinityGroupIDs"),
+			"AffinityGroupIDs cannot be specified when Affinity is specified as anything but `no`"))
+	}
+	return errorList
+}
+
+// validateRootDisk requires a root disk override to set a positive size, an offering, or a storage tag. Whether the
+// size fits the template is checked on deployment.
+func validateRootDisk(rootDisk *CloudStackRootDisk, path *field.Path, errorList field.ErrorList) field.ErrorList {
+	if rootDisk == nil {
+		return errorList
+	}
+	if rootDisk.Size < 0 {
+		errorList = append(errorList, field.Invalid(path.Child("sizeInGB"), rootDisk.Size, "must not be negative"))
+	} else if rootDisk.Size == 0 && rootDisk.Offering.ID == "" && rootDisk.Offering.Name == "" && rootDisk.StorageTag == "" {
+		errorList = append(errorList, field.Required(path, "sizeInGB, offering or storageTag"))
+	}
+	return errorList
+}
+
+// validatePlacement requires a placement to name a pod, cluster or host.
+func validatePlacement(placement *CloudStackPlacement, path *field.Path, errorList field.ErrorList) field.ErrorList {
+	if placement != nil && placement.Pod == (CloudStackResourceIdentifier{}) &&
+		placement.Cluster == (CloudStackResourceIdentifier{}) && placement.Host == (CloudStackResourceIdentifier{}) {
+		errorList = append(errorList, field.Required(path, "pod, cluster or host"))
+	}
+	return errorList
+}
+
+// validateDataDisks requires data disks to have an offering, non-negative sizes and IOPS, and distinct absolute
+// mount paths.
+func validateDataDisks(disks []CloudStackDataDisk, path *field.Path, errorList field.ErrorList) field.ErrorList {
+	mountPaths := map[string]bool{}
+	for i, disk := range disks {
+		diskPath := path.Index(i)
+		if disk.Offering.ID == "" && disk.Offering.Name == "" {
+			errorList = append(errorList, field.Required(diskPath.Child("offering"), "ID or Name of the disk offering"))
+		}
+		if disk.CustomSize < 0 {
+			errorList = append(errorList, field.Invalid(diskPath.Child("customSizeInGB"), disk.CustomSize, "must not be negative"))
+		}
+		if disk.MinIOPS < 0 || disk.MaxIOPS < 0 || (disk.MaxIOPS > 0 && disk.MinIOPS > disk.MaxIOPS) {
+			errorList = append(errorList, field.Invalid(diskPath.Child("maxIOPS"), disk.MaxIOPS,
+				"IOPS must not be negative, and the maximum must not be lower than the minimum"))
+		}
+		if disk.MountPath == "" {
+			continue
+		}
+		if !strings.HasPrefix(disk.MountPath, "/") || disk.MountPath == "/" {
+			errorList = append(errorList, field.Invalid(diskPath.Child("mountPath"), disk.MountPath,
+				"must be an absolute path other than /"))
+		} else if mountPaths[disk.MountPath] {
+			errorList = append(errorList, field.Duplicate(diskPath.Child("mountPath"), disk.MountPath))
+		}
+		mountPaths[disk.MountPath] = true
+	}
+	return errorList
+}
+
+// validateAdditionalTags forbids empty tag keys and the keys CAPC tags its resources with itself.
+func validateAdditionalTags(tags map[string]string, path *field.Path, errorList field.ErrorList) field.ErrorList {
+	for key := range tags {
+		if key == "" {
+			errorList = append(errorList, field.Invalid(path, key, "tag keys must not be empty"))
+		} else if strings.HasPrefix(strings.ToUpper(key), "CAPC_") || strings.EqualFold(key, "created_by_CAPC") {
+			errorList = append(errorList, field.Forbidden(path.Key(key), "tag keys starting with CAPC_ and created_by_CAPC are reserved"))
+		}
+	}
+	return errorList
+}
+
+// validateAddressesFromPools checks the IPAM pool references of networks. Addresses allocated from pools are set as
+// the network's IP or IP6 by the controller, so these can't be set along with pools.
+func validateAddressesFromPools(networks []NetworkSpec, path *field.Path, errorList field.ErrorList) field.ErrorList {
+	for i, network := range networks {
+		if len(network.AddressesFromPools) == 0 {
+			continue
+		}
+		if network.IP != "" || network.IP6 != "" {
+			errorList = append(errorList, field.Forbidden(path.Index(i),
+				"ip and ip6 cannot be specified along with addressesFromPools"))
+		}
+		for j, poolRef := range network.AddressesFromPools {
+			poolPath := path.Index(i).Child("addressesFromPools").Index(j)
+			if poolRef.APIGroup == nil || *poolRef.APIGroup == "" {
+				errorList = append(errorList, field.Required(poolPath.Child("apiGroup"), "apiGroup of the IP pool"))
+			}
+			if poolRef.Kind == "" {
+				errorList = append(errorList, field.Required(poolPath.Child("kind"), "kind of the IP pool"))
+			}
+			if poolRef.Name == "" {
+				errorList = append(errorList, field.Required(poolPath.Child("name"), "name of the IP pool"))
+			}
+		}
+	}
+	return errorList
 }
 
-// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
-func (r *CloudStackMachine) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
+func (*cloudStackMachineWebhook) ValidateUpdate(_ context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	r, ok := newObj.(*CloudStackMachine)
+	if !ok {
+		return nil, errors.NewBadRequest(fmt.Sprintf("expected a CloudStackMachine but got a %T", newObj))
+	}
 	cloudstackmachinelog.V(1).Info("entered validate update webhook", "api resource name", r.Name)
 
 	var errorList field.ErrorList
 
-	oldMachine, ok := old.(*CloudStackMachine)
+	oldMachine, ok := oldObj.(*CloudStackMachine)
 	if !ok {
-		return nil, errors.NewBadRequest(fmt.Sprintf("expected a CloudStackMachine but got a %T", old))
+		return nil, errors.NewBadRequest(fmt.Sprintf("expected a CloudStackMachine but got a %T", oldObj))
 	}
 	oldSpec := oldMachine.Spec
 
-	errorList = webhookutil.EnsureEqualStrings(r.Spec.Offering.ID, oldSpec.Offering.ID, "offering", errorList)
-	errorList = webhookutil.EnsureEqualStrings(r.Spec.Offering.Name, oldSpec.Offering.Name, "offering", errorList)
+	// Machines that can be resized in place scale their instance to a new offering instead.
+	if r.Spec.InPlaceResize == "" {
+		errorList = webhookutil.EnsureEqualStrings(r.Spec.Offering.ID, oldSpec.Offering.ID, "offering", errorList)
+		errorList = webhookutil.EnsureEqualStrings(r.Spec.Offering.Name, oldSpec.Offering.Name, "offering", errorList)
+	} else {
+		errorList = webhookutil.EnsureAtLeastOneFieldExists(r.Spec.Offering.ID, r.Spec.Offering.Name, "Offering", errorList)
+	}
 	errorList = webhookutil.EnsureEqualStrings(r.Spec.DiskOffering.ID, oldSpec.DiskOffering.ID, "diskOffering", errorList)
 	errorList = webhookutil.EnsureEqualStrings(r.Spec.DiskOffering.Name, oldSpec.DiskOffering.Name, "diskOffering", errorList)
 	errorList = webhookutil.EnsureIntFieldsAreNotNegative(r.Spec.DiskOffering.CustomSize, "customSizeInGB", errorList)
@@ -93,17 +262,34 @@ func (r *CloudStackMachine) ValidateUpdate(old runtime.Object) (admission.Warnin
 	errorList = webhookutil.EnsureEqualStrings(r.Spec.Template.ID, oldSpec.Template.ID, "template", errorList)
 	errorList = webhookutil.EnsureEqualStrings(r.Spec.Template.Name, oldSpec.Template.Name, "template", errorList)
 	errorList = webhookutil.EnsureEqualMapStringString(&r.Spec.Details, &oldSpec.Details, "details", errorList)
+	errorList = webhookutil.EnsureEqualMapStringString(&r.Spec.AdditionalTags, &oldSpec.AdditionalTags, "additionalTags", errorList)
 	errorList = webhookutil.EnsureEqualStrings(r.Spec.Affinity, oldSpec.Affinity, "affinity", errorList)
 
 	if !reflect.DeepEqual(r.Spec.AffinityGroupIDs, oldSpec.AffinityGroupIDs) { // Equivalent to other Ensure funcs.
 		errorList = append(errorList, field.Forbidden(field.NewPath("spec", "AffinityGroupIDs"), "AffinityGroupIDs"))
 	}
+	if !reflect.DeepEqual(r.Spec.RootDisk, oldSpec.RootDisk) {
+		errorList = append(errorList, field.Forbidden(field.NewPath("spec", "rootDisk"), "rootDisk"))
+	}
+	if !reflect.DeepEqual(r.Spec.Placement, oldSpec.Placement) {
+		errorList = append(errorList, field.Forbidden(field.NewPath("spec", "placement"), "placement"))
+	}
+	if !reflect.DeepEqual(r.Spec.DataDisks, oldSpec.DataDisks) {
+		errorList = append(errorList, field.Forbidden(field.NewPath("spec", "dataDisks"), "dataDisks"))
+	}
+	if r.Spec.Adopt != oldSpec.Adopt {
+		errorList = append(errorList, field.Forbidden(field.NewPath("spec", "adopt"), "adopt"))
+	}
 
 	return nil, webhookutil.AggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, errorList)
 }
 
-// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
-func (r *CloudStackMachine) ValidateDelete() (admission.Warnings, error) {
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
+func (*cloudStackMachineWebhook) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	r, ok := obj.(*CloudStackMachine)
+	if !ok {
+		return nil, errors.NewBadRequest(fmt.Sprintf("expected a CloudStackMachine but got a %T", obj))
+	}
 	cloudstackmachinelog.V(1).Info("entered validate delete webhook", "api resource name", r.Name)
 	// No deletion validations.  Deletion webhook not enabled.
 	return nil, nil
@@ -19,6 +19,7 @@ package v1beta3_test
 import (
 	"context"
 
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
 	dummies "sigs.k8s.io/cluster-api-provider-cloudstack/test/dummies/v1beta3"
 
@@ -70,6 +71,81 @@ var _ = ginkgo.Describe("CloudStackMachine webhook", func() {
 			gomega.Expect(k8sClient.Create(ctx, dummies.CSMachine1)).
 				Should(gomega.MatchError(gomega.MatchRegexp(requiredRegex, "Template")))
 		})
+
+		ginkgo.It("should accept a CloudStackMachine with a larger root disk", func() {
+			dummies.CSMachine1.Spec.RootDisk = &infrav1.CloudStackRootDisk{Size: 50, StorageTag: "nvme"}
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSMachine1)).Should(gomega.Succeed())
+		})
+
+		ginkgo.It("should reject an empty root disk override", func() {
+			dummies.CSMachine1.Spec.RootDisk = &infrav1.CloudStackRootDisk{}
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSMachine1)).
+				Should(gomega.MatchError(gomega.MatchRegexp(requiredRegex, "sizeInGB, offering or storageTag")))
+		})
+
+		ginkgo.It("should accept a CloudStackMachine with soft anti-affinity", func() {
+			dummies.CSMachine1.Spec.Affinity = infrav1.SoftAntiAffinity
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSMachine1)).Should(gomega.Succeed())
+		})
+
+		ginkgo.It("should reject a CloudStackMachine with an unknown affinity", func() {
+			dummies.CSMachine1.Spec.Affinity = "preferred"
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSMachine1)).
+				Should(gomega.MatchError(gomega.ContainSubstring(`Affinity must be "no", "pro", "anti", "soft-pro", "soft-anti"`)))
+		})
+
+		ginkgo.It("should accept a CloudStackMachine pinned to a host cluster", func() {
+			dummies.CSMachine1.Spec.Placement = &infrav1.CloudStackPlacement{Cluster: infrav1.CloudStackResourceIdentifier{Name: "nvme"}}
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSMachine1)).Should(gomega.Succeed())
+		})
+
+		ginkgo.It("should reject an empty placement", func() {
+			dummies.CSMachine1.Spec.Placement = &infrav1.CloudStackPlacement{}
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSMachine1)).
+				Should(gomega.MatchError(gomega.MatchRegexp(requiredRegex, "pod, cluster or host")))
+		})
+
+		ginkgo.It("should accept a CloudStackMachine with data disks", func() {
+			dummies.CSMachine1.Spec.DataDisks = []infrav1.CloudStackDataDisk{
+				{Name: "etcd", Offering: dummies.DiskOffering.CloudStackResourceIdentifier, MountPath: "/var/lib/etcd"},
+				{Name: "scratch", Offering: dummies.DiskOffering.CloudStackResourceIdentifier},
+			}
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSMachine1)).Should(gomega.Succeed())
+		})
+
+		ginkgo.It("should reject a data disk without an offering", func() {
+			dummies.CSMachine1.Spec.DataDisks = []infrav1.CloudStackDataDisk{{Name: "etcd"}}
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSMachine1)).
+				Should(gomega.MatchError(gomega.MatchRegexp(requiredRegex, "ID or Name of the disk offering")))
+		})
+
+		ginkgo.It("should reject data disks mounted at the same path", func() {
+			dummies.CSMachine1.Spec.DataDisks = []infrav1.CloudStackDataDisk{
+				{Name: "a", Offering: dummies.DiskOffering.CloudStackResourceIdentifier, MountPath: "/data"},
+				{Name: "b", Offering: dummies.DiskOffering.CloudStackResourceIdentifier, MountPath: "/data"},
+			}
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSMachine1)).
+				Should(gomega.MatchError(gomega.ContainSubstring("Duplicate value")))
+		})
+
+		ginkgo.It("should accept a CloudStackMachine adopting an instance", func() {
+			dummies.CSMachine1.Spec.InstanceID = ptr.To("hand-built-instance")
+			dummies.CSMachine1.Spec.Adopt = true
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSMachine1)).Should(gomega.Succeed())
+		})
+
+		ginkgo.It("should reject adopting an instance without its ID", func() {
+			dummies.CSMachine1.Spec.InstanceID = nil
+			dummies.CSMachine1.Spec.Adopt = true
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSMachine1)).
+				Should(gomega.MatchError(gomega.MatchRegexp(requiredRegex, "ID of the instance to adopt")))
+		})
+
+		ginkgo.It("should reject additional tags with keys reserved by CAPC", func() {
+			dummies.CSMachine1.Spec.AdditionalTags = map[string]string{"cost-center": "42", "CAPC_owner_machine": "other"}
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSMachine1)).
+				Should(gomega.MatchError(gomega.MatchRegexp(forbiddenRegex, "tag keys starting with CAPC_")))
+		})
 	})
 
 	ginkgo.Context("When updating a CloudStackMachine", func() {
@@ -83,6 +159,12 @@ var _ = ginkgo.Describe("CloudStackMachine webhook", func() {
 				Should(gomega.MatchError(gomega.MatchRegexp(forbiddenRegex, "offering")))
 		})
 
+		ginkgo.It("should accept VM offering updates to a CloudStackMachine that is resized in place", func() {
+			dummies.CSMachine1.Spec.InPlaceResize = infrav1.LiveResize
+			dummies.CSMachine1.Spec.Offering = infrav1.CloudStackResourceIdentifier{Name: "ArbitraryUpdateOffering"}
+			gomega.Ω(k8sClient.Update(ctx, dummies.CSMachine1)).Should(gomega.Succeed())
+		})
+
 		ginkgo.It("should reject VM template updates to the CloudStackMachine", func() {
 			dummies.CSMachine1.Spec.Template = infrav1.CloudStackResourceIdentifier{Name: "ArbitraryUpdateTemplate"}
 			gomega.Ω(k8sClient.Update(ctx, dummies.CSMachine1)).
@@ -106,5 +188,31 @@ var _ = ginkgo.Describe("CloudStackMachine webhook", func() {
 			gomega.Ω(k8sClient.Update(ctx, dummies.CSMachine1)).
 				Should(gomega.MatchError(gomega.MatchRegexp(forbiddenRegex, "AffinityGroupIDs")))
 		})
+
+		ginkgo.It("should reject updates to the root disk of the CloudStackMachine", func() {
+			dummies.CSMachine1.Spec.RootDisk = &infrav1.CloudStackRootDisk{Size: 50}
+			gomega.Ω(k8sClient.Update(ctx, dummies.CSMachine1)).
+				Should(gomega.MatchError(gomega.MatchRegexp(forbiddenRegex, "rootDisk")))
+		})
+
+		ginkgo.It("should reject updates to the placement of the CloudStackMachine", func() {
+			dummies.CSMachine1.Spec.Placement = &infrav1.CloudStackPlacement{Host: infrav1.CloudStackResourceIdentifier{Name: "host1"}}
+			gomega.Ω(k8sClient.Update(ctx, dummies.CSMachine1)).
+				Should(gomega.MatchError(gomega.MatchRegexp(forbiddenRegex, "placement")))
+		})
+
+		ginkgo.It("should reject updates to the data disks of the CloudStackMachine", func() {
+			dummies.CSMachine1.Spec.DataDisks = []infrav1.CloudStackDataDisk{
+				{Name: "etcd", Offering: dummies.DiskOffering.CloudStackResourceIdentifier},
+			}
+			gomega.Ω(k8sClient.Update(ctx, dummies.CSMachine1)).
+				Should(gomega.MatchError(gomega.MatchRegexp(forbiddenRegex, "dataDisks")))
+		})
+
+		ginkgo.It("should reject updates to the additional tags of the CloudStackMachine", func() {
+			dummies.CSMachine1.Spec.AdditionalTags = map[string]string{"cost-center": "42"}
+			gomega.Ω(k8sClient.Update(ctx, dummies.CSMachine1)).
+				Should(gomega.MatchError(gomega.MatchRegexp(forbiddenRegex, "additionalTags")))
+		})
 	})
 })
@@ -0,0 +1,160 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta3
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// MachinePoolFinalizer allows the CloudStackMachinePool controller to destroy the pool's VM instances before the
+// CloudStackMachinePool is removed.
+const MachinePoolFinalizer = "cloudstackmachinepool.infrastructure.cluster.x-k8s.io"
+
+// CloudStackMachinePoolSpec defines the desired state of CloudStackMachinePool
+type CloudStackMachinePoolSpec struct {
+	// ProviderIDList are the provider IDs of the pool's instances, of the form cloudstack:///<instance ID>.
+	// Set by the controller and read by the CAPI MachinePool controller to match instances with nodes.
+	// +optional
+	ProviderIDList []string `json:"providerIDList,omitempty"`
+
+	// Template describes the VM instances of the pool. Instances are replaced according to Strategy when
+	// the template's spec changes.
+	Template CloudStackMachineTemplateResource `json:"template"`
+
+	// Strategy controls how instances are replaced when Template changes.
+	// +optional
+	Strategy CloudStackMachinePoolStrategy `json:"strategy,omitempty"`
+}
+
+// CloudStackMachinePoolStrategy controls the rolling replacement of a pool's instances.
+type CloudStackMachinePoolStrategy struct {
+	// MaxSurge is the number of instances that may be created above the desired number of replicas while
+	// outdated instances are replaced. Defaults to 1.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxSurge *int32 `json:"maxSurge,omitempty"`
+
+	// MaxUnavailable is the number of instances below the desired number of replicas that may be unavailable
+	// while outdated instances are replaced. Defaults to 0. If both MaxSurge and MaxUnavailable are 0,
+	// MaxSurge is treated as 1.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	MaxUnavailable *int32 `json:"maxUnavailable,omitempty"`
+}
+
+// CloudStackMachinePoolInstance is a VM instance owned by a CloudStackMachinePool.
+type CloudStackMachinePoolInstance struct {
+	// Name of the VM instance in CloudStack. Also used as the instance's hostname.
+	Name string `json:"name"`
+
+	// InstanceID is the CloudStack ID of the VM instance. Empty until the instance has been deployed.
+	// +optional
+	InstanceID string `json:"instanceID,omitempty"`
+
+	// ProviderID is the provider ID of the instance, of the form cloudstack:///<instance ID>.
+	// +optional
+	ProviderID string `json:"providerID,omitempty"`
+
+	// FailureDomainName is the name of the failure domain the instance is placed in.
+	FailureDomainName string `json:"failureDomainName"`
+
+	// InstanceState is the state of the CloudStack instance.
+	// +optional
+	InstanceState string `json:"instanceState,omitempty"`
+
+	// Addresses contains the instance's IP addresses.
+	// +optional
+	Addresses []corev1.NodeAddress `json:"addresses,omitempty"`
+
+	// TemplateHash is the hash of the pool's template the instance was created from.
+	TemplateHash string `json:"templateHash"`
+
+	// AsyncJob is the CloudStack asynchronous job the controller waits on before acting on the instance again.
+	// +optional
+	AsyncJob *CloudStackAsyncJob `json:"asyncJob,omitempty"`
+}
+
+// CloudStackMachinePoolStatus defines the observed state of CloudStackMachinePool
+type CloudStackMachinePoolStatus struct {
+	// Ready indicates that the pool has reached its desired number of running instances at least once.
+	// +optional
+	Ready bool `json:"ready"`
+
+	// Replicas is the number of running instances in the pool.
+	// +optional
+	Replicas int32 `json:"replicas"`
+
+	// UpdatedReplicas is the number of running instances created from the current template.
+	// +optional
+	UpdatedReplicas int32 `json:"updatedReplicas"`
+
+	// TemplateHash is the hash of the current template. Instances with a different hash are outdated.
+	// +optional
+	TemplateHash string `json:"templateHash,omitempty"`
+
+	// Instances are the VM instances the pool owns.
+	// +optional
+	Instances []CloudStackMachinePoolInstance `json:"instances,omitempty"`
+
+	// Conditions defines current service state of the CloudStackMachinePool.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=cloudstackmachinepools,scope=Namespaced,categories=cluster-api,shortName=csmp
+// +kubebuilder:storageversion
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Cluster",type="string",JSONPath=".metadata.labels.cluster\\.x-k8s\\.io/cluster-name",description="Cluster to which this CloudStackMachinePool belongs"
+// +kubebuilder:printcolumn:name="Replicas",type="integer",JSONPath=".status.replicas",description="Running instances"
+// +kubebuilder:printcolumn:name="Updated",type="integer",JSONPath=".status.updatedReplicas",description="Running instances created from the current template"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.ready",description="Machine pool ready status"
+// +kubebuilder:printcolumn:name="MachinePool",type="string",JSONPath=".metadata.ownerReferences[?(@.kind==\"MachinePool\")].name",description="MachinePool object which owns this CloudStackMachinePool"
+
+// CloudStackMachinePool is the Schema for the cloudstackmachinepools API
+type CloudStackMachinePool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   CloudStackMachinePoolSpec   `json:"spec,omitempty"`
+	Status CloudStackMachinePoolStatus `json:"status,omitempty"`
+}
+
+//+kubebuilder:object:root=true
+
+// CloudStackMachinePoolList contains a list of CloudStackMachinePool
+type CloudStackMachinePoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CloudStackMachinePool `json:"items"`
+}
+
+// GetConditions returns the observations of the operational state of the CloudStackMachinePool resource.
+func (r *CloudStackMachinePool) GetConditions() clusterv1.Conditions {
+	return r.Status.Conditions
+}
+
+// SetConditions sets the underlying service state of the CloudStackMachinePool to the predescribed clusterv1.Conditions.
+func (r *CloudStackMachinePool) SetConditions(conditions clusterv1.Conditions) {
+	r.Status.Conditions = conditions
+}
+
+func init() {
+	objectTypes = append(objectTypes, &CloudStackMachinePool{}, &CloudStackMachinePoolList{})
+}
@@ -17,14 +17,15 @@ limitations under the License.
 package v1beta3
 
 import (
+	"context"
 	"fmt"
 	"reflect"
-	"strings"
 
 	"k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/webhookutil"
+	"sigs.k8s.io/cluster-api/util/topology"
 	ctrl "sigs.k8s.io/controller-runtime"
 	logf "sigs.k8s.io/controller-runtime/pkg/log"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -34,28 +35,46 @@ import (
 // log is for logging in this package.
 var cloudstackmachinetemplatelog = logf.Log.WithName("cloudstackmachinetemplate-resource")
 
-func (r *CloudStackMachineTemplate) SetupWebhookWithManager(mgr ctrl.Manager) error {
+func (r *CloudStackMachineTemplate) SetupWebhookWithManager(mgr ctrl.Manager, opts WebhookOptions) error {
+	w := &cloudStackMachineTemplateWebhook{WebhookOptions: opts}
 	return ctrl.NewWebhookManagedBy(mgr).
 		For(r).
+		WithDefaulter(w).
+		WithValidator(w).
 		Complete()
 }
 
+// cloudStackMachineTemplateWebhook implements the CloudStackMachineTemplate webhooks. The ClusterClass topology
+// controller dry-runs template updates before rotating templates, which the validator has to tell from real updates.
+type cloudStackMachineTemplateWebhook struct {
+	WebhookOptions
+}
+
 // +kubebuilder:webhook:path=/mutate-infrastructure-cluster-x-k8s-io-v1beta3-cloudstackmachinetemplate,mutating=true,failurePolicy=fail,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=cloudstackmachinetemplates,verbs=create;update,versions=v1beta3,name=mcloudstackmachinetemplate.kb.io,admissionReviewVersions=v1;v1beta1
 
-var _ webhook.Defaulter = &CloudStackMachineTemplate{}
+var _ webhook.CustomDefaulter = &cloudStackMachineTemplateWebhook{}
 
-// Default implements webhook.Defaulter so a webhook will be registered for the type
-func (r *CloudStackMachineTemplate) Default() {
+// Default implements webhook.CustomDefaulter so a webhook will be registered for the type
+func (*cloudStackMachineTemplateWebhook) Default(_ context.Context, obj runtime.Object) error {
+	r, ok := obj.(*CloudStackMachineTemplate)
+	if !ok {
+		return errors.NewBadRequest(fmt.Sprintf("expected a CloudStackMachineTemplate but got a %T", obj))
+	}
 	cloudstackmachinetemplatelog.V(1).Info("entered default setting webhook", "api resource name", r.Name)
 	// No defaulted values supported yet.
+	return nil
 }
 
 // +kubebuilder:webhook:path=/validate-infrastructure-cluster-x-k8s-io-v1beta3-cloudstackmachinetemplate,mutating=false,failurePolicy=fail,sideEffects=None,groups=infrastructure.cluster.x-k8s.io,resources=cloudstackmachinetemplates,verbs=create;update,versions=v1beta3,name=vcloudstackmachinetemplate.kb.io,admissionReviewVersions=v1;v1beta1
 
-var _ webhook.Validator = &CloudStackMachineTemplate{}
+var _ webhook.CustomValidator = &cloudStackMachineTemplateWebhook{}
 
-// ValidateCreate implements webhook.Validator so a webhook will be registered for the type
-func (r *CloudStackMachineTemplate) ValidateCreate() (admission.Warnings, error) {
+// ValidateCreate implements webhook.CustomValidator so a webhook will be registered for the type
+func (w *cloudStackMachineTemplateWebhook) ValidateCreate(ctx context.Context, obj runtime.Object) (admission.Warnings, error) {
+	r, ok := obj.(*CloudStackMachineTemplate)
+	if !ok {
+		return nil, errors.NewBadRequest(fmt.Sprintf("expected a CloudStackMachineTemplate but got a %T", obj))
+	}
 	cloudstackmachinetemplatelog.V(1).Info("entered validate create webhook", "api resource name", r.Name)
 
 	var errorList field.ErrorList
@@ -63,29 +82,49 @@ func (r *CloudStackMachineTemplate) ValidateCreate() (admission.Warnings, error)
 	// CloudStackMachineTemplateSpec.CloudStackMachineSpec
 	spec := r.Spec.Template.Spec
 
-	affinity := strings.ToLower(spec.Affinity)
-	if !(affinity == "" || affinity == "no" || affinity == "pro" || affinity == "anti") {
-		errorList = append(errorList, field.Invalid(field.NewPath("spec", "Affinity"), spec.Affinity,
-			`Affinity must be "no", "pro", "anti", or unspecified.`))
-	}
-	if affinity != "no" && affinity != "" && len(spec.AffinityGroupIDs) > 0 {
-		errorList = append(errorList, field.Forbidden(field.NewPath("spec", "AffinityGroupIDs"),
-			"AffinityGroupIDs cannot be specified when Affinity is specified as anything but `no`"))
-	}
+	errorList = validateAffinity(spec, errorList)
 
 	errorList = webhookutil.EnsureAtLeastOneFieldExists(spec.Offering.ID, spec.Offering.Name, "Offering", errorList)
 	errorList = webhookutil.EnsureAtLeastOneFieldExists(spec.Template.ID, spec.Template.Name, "Template", errorList)
+	errorList = validateAddressesFromPools(spec.Networks, field.NewPath("spec", "template", "spec", "networks"), errorList)
+	errorList = validateRootDisk(spec.RootDisk, field.NewPath("spec", "template", "spec", "rootDisk"), errorList)
+	errorList = validateDataDisks(spec.DataDisks, field.NewPath("spec", "template", "spec", "dataDisks"), errorList)
+	errorList = validatePlacement(spec.Placement, field.NewPath("spec", "template", "spec", "placement"), errorList)
+	errorList = validateAdditionalTags(spec.AdditionalTags, field.NewPath("spec", "template", "spec", "additionalTags"), errorList)
+	if spec.Adopt {
+		errorList = append(errorList, field.Forbidden(field.NewPath("spec", "template", "spec", "adopt"),
+			"instances can only be adopted by CloudStackMachines"))
+	}
 
-	return nil, webhookutil.AggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, errorList)
+	var warnings admission.Warnings
+	if w.Preflight != nil && len(errorList) == 0 {
+		warnings, errorList = w.Preflight.ValidateMachineSpec(ctx, r, &spec, field.NewPath("spec", "template", "spec"))
+	}
+
+	return warnings, webhookutil.AggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, errorList)
 }
 
-// ValidateUpdate implements webhook.Validator so a webhook will be registered for the type
-func (r *CloudStackMachineTemplate) ValidateUpdate(old runtime.Object) (admission.Warnings, error) {
+// ValidateUpdate implements webhook.CustomValidator so a webhook will be registered for the type
+func (*cloudStackMachineTemplateWebhook) ValidateUpdate(ctx context.Context, oldObj, newObj runtime.Object) (admission.Warnings, error) {
+	r, ok := newObj.(*CloudStackMachineTemplate)
+	if !ok {
+		return nil, errors.NewBadRequest(fmt.Sprintf("expected a CloudStackMachineTemplate but got a %T", newObj))
+	}
 	cloudstackmachinetemplatelog.V(1).Info("entered validate update webhook", "api resource name", r.Name)
 
-	oldMachineTemplate, ok := old.(*CloudStackMachineTemplate)
+	oldMachineTemplate, ok := oldObj.(*CloudStackMachineTemplate)
 	if !ok {
-		return nil, errors.NewBadRequest(fmt.Sprintf("expected a CloudStackMachineTemplate but got a %T", old))
+		return nil, errors.NewBadRequest(fmt.Sprintf("expected a CloudStackMachineTemplate but got a %T", oldObj))
+	}
+
+	req, err := admission.RequestFromContext(ctx)
+	if err != nil {
+		return nil, errors.NewBadRequest(fmt.Sprintf("expected an admission.Request inside context: %v", err))
+	}
+	// Templates are immutable, but the topology controller dry-runs its patches to detect whether a ClusterClass
+	// managed template has to be rotated.
+	if topology.ShouldSkipImmutabilityChecks(req, r) {
+		return nil, nil
 	}
 
 	// CloudStackMachineTemplateSpec.CloudStackMachineTemplateResource.CloudStackMachineSpec
@@ -106,12 +145,25 @@ func (r *CloudStackMachineTemplate) ValidateUpdate(old runtime.Object) (admissio
 	if !reflect.DeepEqual(spec.AffinityGroupIDs, oldSpec.AffinityGroupIDs) { // Equivalent to other Ensure funcs.
 		errorList = append(errorList, field.Forbidden(field.NewPath("spec", "AffinityGroupIDs"), "AffinityGroupIDs"))
 	}
+	if !reflect.DeepEqual(spec.RootDisk, oldSpec.RootDisk) {
+		errorList = append(errorList, field.Forbidden(field.NewPath("spec", "rootDisk"), "rootDisk"))
+	}
+	if !reflect.DeepEqual(spec.Placement, oldSpec.Placement) {
+		errorList = append(errorList, field.Forbidden(field.NewPath("spec", "placement"), "placement"))
+	}
+	if !reflect.DeepEqual(spec.DataDisks, oldSpec.DataDisks) {
+		errorList = append(errorList, field.Forbidden(field.NewPath("spec", "dataDisks"), "dataDisks"))
+	}
 
 	return nil, webhookutil.AggregateObjErrors(r.GroupVersionKind().GroupKind(), r.Name, errorList)
 }
 
-// ValidateDelete implements webhook.Validator so a webhook will be registered for the type
-func (r *CloudStackMachineTemplate) ValidateDelete() (admission.Warnings, error) {
+// ValidateDelete implements webhook.CustomValidator so a webhook will be registered for the type
+func (*cloudStackMachineTemplateWebhook) ValidateDelete(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	r, ok := obj.(*CloudStackMachineTemplate)
+	if !ok {
+		return nil, errors.NewBadRequest(fmt.Sprintf("expected a CloudStackMachineTemplate but got a %T", obj))
+	}
 	cloudstackmachinetemplatelog.V(1).Info("entered validate delete webhook", "api resource name", r.Name)
 	// No deletion validations.  Deletion webhook not enabled.
 	return nil, nil
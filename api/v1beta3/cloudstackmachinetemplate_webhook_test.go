@@ -21,8 +21,12 @@ import (
 
 	"github.com/onsi/ginkgo/v2"
 	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
 	dummies "sigs.k8s.io/cluster-api-provider-cloudstack/test/dummies/v1beta3"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 var _ = ginkgo.Describe("CloudStackMachineTemplate webhook", func() {
@@ -59,6 +63,55 @@ var _ = ginkgo.Describe("CloudStackMachineTemplate webhook", func() {
 			gomega.Expect(k8sClient.Create(ctx, dummies.CSMachineTemplate1)).
 				Should(gomega.MatchError(gomega.MatchRegexp(requiredRegex, "Template")))
 		})
+
+		ginkgo.It("Should accept a CloudStackMachineTemplate with soft affinity", func() {
+			dummies.CSMachineTemplate1.Spec.Template.Spec.Affinity = infrav1.SoftProAffinity
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSMachineTemplate1)).Should(gomega.Succeed())
+		})
+
+		ginkgo.It("Should reject a CloudStackMachineTemplate with soft affinity and affinity group IDs", func() {
+			dummies.CSMachineTemplate1.Spec.Template.Spec.Affinity = infrav1.SoftAntiAffinity
+			dummies.CSMachineTemplate1.Spec.Template.Spec.AffinityGroupIDs = []string{"28b907b8-75a7-4214-bd3d-6c61961fc2af"}
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSMachineTemplate1)).
+				Should(gomega.MatchError(gomega.MatchRegexp(forbiddenRegex, "AffinityGroupIDs")))
+		})
+
+		ginkgo.It("Should reject a CloudStackMachineTemplate adopting instances", func() {
+			dummies.CSMachineTemplate1.Spec.Template.Spec.Adopt = true
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSMachineTemplate1)).
+				Should(gomega.MatchError(gomega.MatchRegexp(forbiddenRegex, "instances can only be adopted by CloudStackMachines")))
+		})
+
+		ginkgo.It("Should accept a CloudStackMachineTemplate with networks using IP pools", func() {
+			dummies.CSMachineTemplate1.Spec.Template.Spec.Networks = []infrav1.NetworkSpec{{
+				Name: "net1",
+				AddressesFromPools: []corev1.TypedLocalObjectReference{
+					{APIGroup: ptr.To("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "pool1"},
+				},
+			}}
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSMachineTemplate1)).Should(gomega.Succeed())
+		})
+
+		ginkgo.It("Should reject a CloudStackMachineTemplate with a network setting both an IP and IP pools", func() {
+			dummies.CSMachineTemplate1.Spec.Template.Spec.Networks = []infrav1.NetworkSpec{{
+				Name: "net1",
+				IP:   "10.0.0.10",
+				AddressesFromPools: []corev1.TypedLocalObjectReference{
+					{APIGroup: ptr.To("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "pool1"},
+				},
+			}}
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSMachineTemplate1)).
+				Should(gomega.MatchError(gomega.ContainSubstring("ip and ip6 cannot be specified along with addressesFromPools")))
+		})
+
+		ginkgo.It("Should reject a CloudStackMachineTemplate with an IP pool reference missing its API group", func() {
+			dummies.CSMachineTemplate1.Spec.Template.Spec.Networks = []infrav1.NetworkSpec{{
+				Name:               "net1",
+				AddressesFromPools: []corev1.TypedLocalObjectReference{{Kind: "InClusterIPPool", Name: "pool1"}},
+			}}
+			gomega.Expect(k8sClient.Create(ctx, dummies.CSMachineTemplate1)).
+				Should(gomega.MatchError(gomega.MatchRegexp(requiredRegex, "apiGroup of the IP pool")))
+		})
 	})
 
 	ginkgo.Context("When updating a CloudStackMachineTemplate", func() {
@@ -95,5 +148,18 @@ var _ = ginkgo.Describe("CloudStackMachineTemplate webhook", func() {
 			dummies.CSMachineTemplate1.Spec.Template.Spec.AffinityGroupIDs = []string{"28b907b8-75a7-4214-bd3d-6c61961fc2ag"}
 			gomega.Expect(k8sClient.Update(ctx, dummies.CSMachineTemplate1)).ShouldNot(gomega.Succeed())
 		})
+
+		ginkgo.It("should allow dry-run updates of the topology controller to the CloudStackMachineTemplate", func() {
+			dummies.CSMachineTemplate1.Annotations = map[string]string{clusterv1.TopologyDryRunAnnotation: ""}
+			dummies.CSMachineTemplate1.Spec.Template.Spec.Offering = infrav1.CloudStackResourceIdentifier{Name: "Offering2"}
+			dummies.CSMachineTemplate1.Spec.Template.Spec.Template = infrav1.CloudStackResourceIdentifier{Name: "Template2"}
+			gomega.Expect(k8sClient.Update(ctx, dummies.CSMachineTemplate1, client.DryRunAll)).Should(gomega.Succeed())
+		})
+
+		ginkgo.It("should reject dry-run updates to the CloudStackMachineTemplate without the topology annotation", func() {
+			dummies.CSMachineTemplate1.Spec.Template.Spec.Offering = infrav1.CloudStackResourceIdentifier{Name: "Offering2"}
+			gomega.Expect(k8sClient.Update(ctx, dummies.CSMachineTemplate1, client.DryRunAll)).
+				Should(gomega.MatchError(gomega.MatchRegexp(forbiddenRegex, "offering")))
+		})
 	})
 })
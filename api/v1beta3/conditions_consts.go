@@ -0,0 +1,172 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta3
+
+import clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+// Conditions and condition reasons for the CloudStackCluster object.
+
+const (
+	// FailureDomainsReadyCondition reports on whether all of the CloudStackCluster's failure domains are ready.
+	FailureDomainsReadyCondition clusterv1.ConditionType = "FailureDomainsReady"
+
+	// WaitingForFailureDomainsReason (Severity=Info) documents a CloudStackCluster waiting for its
+	// CloudStackFailureDomains to be created and become ready.
+	WaitingForFailureDomainsReason = "WaitingForFailureDomains"
+
+	// FailureDomainsAvailableCondition reports on whether all of the CloudStackCluster's failure domains pass their
+	// health checks. Failure domains that don't are withheld from control plane placement.
+	FailureDomainsAvailableCondition clusterv1.ConditionType = "FailureDomainsAvailable"
+
+	// FailureDomainsUnavailableReason (Severity=Warning) documents a CloudStackCluster with failure domains whose
+	// endpoint, credentials, zone or network failed their health checks.
+	FailureDomainsUnavailableReason = "FailureDomainsUnavailable"
+)
+
+// Conditions and condition reasons for the CloudStackFailureDomain object.
+
+const (
+	// ZoneResolvedCondition reports on whether the failure domain's zone has been resolved in CloudStack.
+	ZoneResolvedCondition clusterv1.ConditionType = "ZoneResolved"
+
+	// ZoneResolutionFailedReason (Severity=Error) documents a failure to look up the zone in CloudStack.
+	ZoneResolutionFailedReason = "ZoneResolutionFailed"
+
+	// CredentialsValidCondition reports on whether the failure domain's CloudStack endpoint is reachable and accepts
+	// its credentials.
+	CredentialsValidCondition clusterv1.ConditionType = "CredentialsValid"
+
+	// EndpointUnreachableReason (Severity=Error) documents a CloudStack endpoint that didn't answer.
+	EndpointUnreachableReason = "EndpointUnreachable"
+	// CredentialsRejectedReason (Severity=Error) documents credentials that couldn't be loaded or that the CloudStack
+	// endpoint rejected.
+	CredentialsRejectedReason = "CredentialsRejected"
+
+	// ZoneEnabledCondition reports on whether the failure domain's zone is enabled for the allocation of new instances.
+	ZoneEnabledCondition clusterv1.ConditionType = "ZoneEnabled"
+
+	// ZoneDisabledReason (Severity=Warning) documents a zone an administrator disabled.
+	ZoneDisabledReason = "ZoneDisabled"
+	// ZoneCheckFailedReason (Severity=Error) documents a failure to look up the allocation state of the zone.
+	ZoneCheckFailedReason = "ZoneCheckFailed"
+
+	// NetworkReadyCondition reports on whether the network backing a failure domain or isolated network
+	// exists and has been resolved in CloudStack.
+	NetworkReadyCondition clusterv1.ConditionType = "NetworkReady"
+
+	// NetworkResolutionFailedReason (Severity=Error) documents a failure to look up the zone's network in CloudStack.
+	NetworkResolutionFailedReason = "NetworkResolutionFailed"
+	// NetworkNotFoundReason (Severity=Error) documents a network that no longer exists in CloudStack.
+	NetworkNotFoundReason = "NetworkNotFound"
+	// WaitingForIsolatedNetworkReason (Severity=Info) documents a failure domain waiting for its
+	// CloudStackIsolatedNetwork to become ready.
+	WaitingForIsolatedNetworkReason = "WaitingForIsolatedNetwork"
+	// IsolatedNetworkReconciliationFailedReason (Severity=Error) documents a failure domain that could not
+	// generate its CloudStackIsolatedNetwork.
+	IsolatedNetworkReconciliationFailedReason = "IsolatedNetworkReconciliationFailed"
+)
+
+// Conditions and condition reasons for the CloudStackIsolatedNetwork object.
+// The NetworkReadyCondition above is also set on CloudStackIsolatedNetworks.
+
+const (
+	// NetworkCreationFailedReason (Severity=Error) documents a failure to resolve or create the isolated network.
+	NetworkCreationFailedReason = "NetworkCreationFailed"
+
+	// PublicIPAssociatedCondition reports on whether a public IP address has been associated for the
+	// control plane endpoint. Only set on networks that are not in routed mode.
+	PublicIPAssociatedCondition clusterv1.ConditionType = "PublicIPAssociated"
+
+	// PublicIPAssociationFailedReason (Severity=Error) documents a failure to associate a public IP address.
+	PublicIPAssociationFailedReason = "PublicIPAssociationFailed"
+
+	// LoadBalancerRuleReadyCondition reports on whether the load balancer rule for the control plane endpoint exists.
+	// Only set on networks that are not in routed mode.
+	LoadBalancerRuleReadyCondition clusterv1.ConditionType = "LoadBalancerRuleReady"
+
+	// LoadBalancerRuleFailedReason (Severity=Error) documents a failure to get or create the load balancer rule.
+	LoadBalancerRuleFailedReason = "LoadBalancerRuleFailed"
+
+	// FirewallRulesOpenedCondition reports on whether the firewall and routing rules of the network have been applied.
+	FirewallRulesOpenedCondition clusterv1.ConditionType = "FirewallRulesOpened"
+
+	// FirewallRulesFailedReason (Severity=Error) documents a failure to open the network's firewall.
+	FirewallRulesFailedReason = "FirewallRulesFailed"
+)
+
+// Conditions and condition reasons for the CloudStackMachine object.
+
+const (
+	// BootstrapDataReadyCondition reports on whether the bootstrap data secret for the machine is available.
+	BootstrapDataReadyCondition clusterv1.ConditionType = "BootstrapDataReady"
+
+	// WaitingForBootstrapDataReason (Severity=Info) documents a CloudStackMachine waiting for the bootstrap
+	// provider to set the Machine's DataSecretName.
+	WaitingForBootstrapDataReason = "WaitingForBootstrapData"
+
+	// InstanceProvisionedCondition reports on whether the CloudStack VM instance has been deployed and is running.
+	InstanceProvisionedCondition clusterv1.ConditionType = "InstanceProvisioned"
+
+	// InstanceProvisionFailedReason (Severity=Error) documents a failure to deploy the VM instance.
+	InstanceProvisionFailedReason = "InstanceProvisionFailed"
+	// InstanceNotRunningReason (Severity=Info) documents a VM instance that has been deployed but is not yet running.
+	InstanceNotRunningReason = "InstanceNotRunning"
+	// InstanceErrorReason (Severity=Error) documents a VM instance that CloudStack reports to be in error state.
+	InstanceErrorReason = "InstanceError"
+	// WaitingForAffinityGroupReason (Severity=Info) documents a CloudStackMachine waiting for its
+	// CloudStackAffinityGroup to become ready.
+	WaitingForAffinityGroupReason = "WaitingForAffinityGroup"
+	// WaitingForIPAddressesReason (Severity=Info) documents a CloudStackMachine waiting for the IPAM provider to
+	// allocate the addresses it claimed from IP pools.
+	WaitingForIPAddressesReason = "WaitingForIPAddresses"
+	// InsufficientCapacityReason (Severity=Warning) documents a VM instance that couldn't be deployed for lack of
+	// capacity in its failure domain, and is placed in another one.
+	InsufficientCapacityReason = "InsufficientCapacity"
+	// InstanceAdoptionFailedReason (Severity=Error) documents a failure to adopt an existing VM instance, e.g. one
+	// that doesn't match the machine's spec.
+	InstanceAdoptionFailedReason = "InstanceAdoptionFailed"
+)
+
+// Conditions and condition reasons for the CloudStackAffinityGroup object.
+
+const (
+	// AffinityGroupReadyCondition reports on whether the affinity group exists in CloudStack.
+	AffinityGroupReadyCondition clusterv1.ConditionType = "AffinityGroupReady"
+
+	// AffinityGroupCreationFailedReason (Severity=Error) documents a failure to get or create the affinity group.
+	AffinityGroupCreationFailedReason = "AffinityGroupCreationFailed"
+)
+
+// Conditions and condition reasons for the CloudStackMachinePool object.
+// The BootstrapDataReadyCondition above is also set on CloudStackMachinePools.
+
+const (
+	// ReplicasReadyCondition reports on whether the pool has its desired number of running instances, all
+	// created from the current template.
+	ReplicasReadyCondition clusterv1.ConditionType = "ReplicasReady"
+
+	// ScalingUpReason (Severity=Info) documents a CloudStackMachinePool creating instances.
+	ScalingUpReason = "ScalingUp"
+	// ScalingDownReason (Severity=Info) documents a CloudStackMachinePool destroying surplus instances.
+	ScalingDownReason = "ScalingDown"
+	// RollingUpdateInProgressReason (Severity=Info) documents a CloudStackMachinePool replacing instances that
+	// were created from an outdated template.
+	RollingUpdateInProgressReason = "RollingUpdateInProgress"
+	// InstancesProvisionFailedReason (Severity=Error) documents a failure to deploy or destroy one of the
+	// pool's instances.
+	InstancesProvisionFailedReason = "InstancesProvisionFailed"
+)
@@ -0,0 +1,25 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta3
+
+import "sigs.k8s.io/controller-runtime/pkg/webhook"
+
+// NewCloudStackClusterValidator returns the CloudStackCluster validating webhook configured with opts, for tests of
+// options the webhooks of the suite's manager aren't registered with.
+func NewCloudStackClusterValidator(opts WebhookOptions) webhook.CustomValidator {
+	return &cloudStackClusterWebhook{WebhookOptions: opts}
+}
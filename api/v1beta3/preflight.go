@@ -0,0 +1,50 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta3
+
+import (
+	"context"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+)
+
+// PreflightValidator resolves the CloudStack resources objects reference against the CloudStack endpoints of their
+// failure domains when they're admitted. Resources that don't resolve are returned as warnings, or as errors denying
+// the request.
+type PreflightValidator interface {
+	// ValidateFailureDomains resolves the zones and networks of the failure domains of a cluster in the namespace.
+	ValidateFailureDomains(
+		ctx context.Context, namespace string, fdSpecs []CloudStackFailureDomainSpec, path *field.Path,
+	) (admission.Warnings, field.ErrorList)
+	// ValidateMachineSpec resolves the offerings, templates and networks of a machine spec in the failure domains of
+	// the object's cluster, or only in the failure domain the spec names.
+	ValidateMachineSpec(
+		ctx context.Context, obj metav1.Object, spec *CloudStackMachineSpec, path *field.Path,
+	) (admission.Warnings, field.ErrorList)
+}
+
+// WebhookOptions are the settings of the manager the webhooks validate objects with.
+type WebhookOptions struct {
+	// Preflight is set when preflight validation is enabled, and left nil otherwise.
+	Preflight PreflightValidator
+	// RestrictACSEndpointNamespace refuses failure domains whose acsEndpoint secret is in another namespace than their
+	// own, so that clusters can't use the credentials of any secret CAPC can read. It's off by default since existing
+	// clusters commonly share a secret of another namespace.
+	RestrictACSEndpointNamespace bool
+}
@@ -109,9 +109,10 @@ var _ = ginkgo.BeforeSuite(func() {
 	})
 	gomega.Expect(err).NotTo(gomega.HaveOccurred())
 
-	gomega.Ω((&infrav1.CloudStackCluster{}).SetupWebhookWithManager(mgr)).Should(gomega.Succeed())
-	gomega.Ω((&infrav1.CloudStackMachine{}).SetupWebhookWithManager(mgr)).Should(gomega.Succeed())
-	gomega.Ω((&infrav1.CloudStackMachineTemplate{}).SetupWebhookWithManager(mgr)).Should(gomega.Succeed())
+	gomega.Ω((&infrav1.CloudStackCluster{}).SetupWebhookWithManager(mgr, infrav1.WebhookOptions{})).Should(gomega.Succeed())
+	gomega.Ω((&infrav1.CloudStackMachine{}).SetupWebhookWithManager(mgr, infrav1.WebhookOptions{})).Should(gomega.Succeed())
+	gomega.Ω((&infrav1.CloudStackMachineTemplate{}).SetupWebhookWithManager(mgr, infrav1.WebhookOptions{})).Should(gomega.Succeed())
+	gomega.Ω((&infrav1.CloudStackClusterTemplate{}).SetupWebhookWithManager(mgr, infrav1.WebhookOptions{})).Should(gomega.Succeed())
 
 	//+kubebuilder:scaffold:webhook
 
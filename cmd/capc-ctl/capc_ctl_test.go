@@ -0,0 +1,222 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/ptr"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/test/fakecloudstack"
+)
+
+var _ = ginkgo.Describe("capc-ctl", func() {
+	const (
+		namespace  = "default"
+		clusterUID = "cluster1-uid"
+		otherUID   = "cluster2-uid"
+	)
+
+	var (
+		server      *fakecloudstack.Server
+		k8sClient   client.Client
+		cloudConfig string
+
+		vmID, untrackedVMID, volumeID, sharedGroupID, otherVMID string
+	)
+
+	// addTagged adds a resource tagged as created by CAPC for the clusters with the given UIDs, and for a machine if
+	// one is named.
+	addTagged := func(kind string, rType cloud.ResourceType, machine string, clusterUIDs ...string) string {
+		id := server.Add(kind, fakecloudstack.Resource{"name": "resource", "state": "Running"})["id"].(string)
+		tags := map[string]string{cloud.CreatedByCAPCTagName: "1"}
+		for _, uid := range clusterUIDs {
+			tags[cloud.ClusterTagNamePrefix+uid] = "1"
+		}
+		if machine != "" {
+			tags[cloud.OwnerMachineTagName] = machine
+		}
+		for key, value := range tags {
+			server.Add(fakecloudstack.KindTag, fakecloudstack.Resource{
+				"key": key, "value": value, "resourceid": id, "resourcetype": string(rType),
+			})
+		}
+		return id
+	}
+
+	run := func(args ...string) (string, error) {
+		out := &bytes.Buffer{}
+		cmd := newRootCommand(&ctl{k8sClient: k8sClient})
+		cmd.SetOut(out)
+		cmd.SetErr(io.Discard)
+		cmd.SetArgs(append(args, "--namespace", namespace))
+		err := cmd.ExecuteContext(context.Background())
+		return out.String(), err
+	}
+
+	exists := func(kind, id string) bool {
+		_, found := server.Get(kind, id)
+		return found
+	}
+
+	ginkgo.BeforeEach(func() {
+		server = fakecloudstack.NewServer()
+		vmID = addTagged(fakecloudstack.KindVirtualMachine, cloud.ResourceTypeVM, "machine1", clusterUID)
+		untrackedVMID = addTagged(fakecloudstack.KindVirtualMachine, cloud.ResourceTypeVM, "", clusterUID)
+		volumeID = addTagged(fakecloudstack.KindVolume, cloud.ResourceTypeVolume, "machine1", clusterUID)
+		sharedGroupID = addTagged(fakecloudstack.KindAffinityGroup, cloud.ResourceTypeAffinityGroup, "", clusterUID, otherUID)
+		otherVMID = addTagged(fakecloudstack.KindVirtualMachine, cloud.ResourceTypeVM, "", otherUID)
+
+		content, err := server.CloudConfig("cloud-config")
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		cloudConfig = filepath.Join(ginkgo.GinkgoT().TempDir(), "cloud-config.yaml")
+		gomega.Ω(os.WriteFile(cloudConfig, content, 0o600)).Should(gomega.Succeed())
+
+		inCluster := map[string]string{clusterv1.ClusterNameLabel: "cluster1"}
+		k8sClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			server.EndpointSecret("acs-credentials", namespace),
+			&clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "cluster1", Namespace: namespace},
+				Spec: clusterv1.ClusterSpec{InfrastructureRef: &corev1.ObjectReference{
+					Kind: "CloudStackCluster", Name: "cluster1-cs",
+				}},
+			},
+			&infrav1.CloudStackCluster{ObjectMeta: metav1.ObjectMeta{
+				Name: "cluster1-cs", Namespace: namespace, UID: types.UID(clusterUID),
+			}},
+			&infrav1.CloudStackFailureDomain{
+				ObjectMeta: metav1.ObjectMeta{Name: "fd1", Namespace: namespace, Labels: inCluster},
+				Spec: infrav1.CloudStackFailureDomainSpec{
+					Name:        "fd1",
+					ACSEndpoint: corev1.SecretReference{Name: "acs-credentials", Namespace: namespace},
+					Zone: infrav1.CloudStackZoneSpec{
+						Name:    fakecloudstack.ZoneName,
+						Network: infrav1.Network{Name: fakecloudstack.SharedNetworkName},
+					},
+				},
+			},
+			&infrav1.CloudStackMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "machine1", Namespace: namespace, Labels: inCluster},
+				Spec:       infrav1.CloudStackMachineSpec{InstanceID: ptr.To(vmID)},
+			},
+			&infrav1.CloudStackMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "machine2", Namespace: namespace, Labels: inCluster},
+				Spec:       infrav1.CloudStackMachineSpec{InstanceID: ptr.To("destroyed-vm-id")},
+			},
+		).Build()
+	})
+
+	ginkgo.AfterEach(func() {
+		server.Close()
+	})
+
+	ginkgo.It("lists the resources of a cluster", func() {
+		out, err := run("resources", "cluster1", "-o", "json")
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		var items []resourceItem
+		gomega.Ω(json.Unmarshal([]byte(out), &items)).Should(gomega.Succeed())
+
+		ids := map[string]cloud.ResourceType{}
+		for _, item := range items {
+			ids[item.ID] = item.Type
+		}
+		gomega.Ω(ids).Should(gomega.Equal(map[string]cloud.ResourceType{
+			vmID:          cloud.ResourceTypeVM,
+			untrackedVMID: cloud.ResourceTypeVM,
+			volumeID:      cloud.ResourceTypeVolume,
+			sharedGroupID: cloud.ResourceTypeAffinityGroup,
+		}))
+	})
+
+	ginkgo.It("lists the resources of a deleted cluster by its UID in a table", func() {
+		out, err := run("resources", "--cluster-uid", otherUID, "--cloud-config", cloudConfig)
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Ω(out).Should(gomega.HavePrefix("TYPE"))
+		gomega.Ω(out).Should(gomega.ContainSubstring(otherVMID))
+		gomega.Ω(out).Should(gomega.ContainSubstring(sharedGroupID))
+		gomega.Ω(out).ShouldNot(gomega.ContainSubstring(vmID))
+	})
+
+	ginkgo.It("diffs the resources of a cluster against its objects", func() {
+		out, err := run("diff", "cluster1", "-o", "json")
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		var items []diffItem
+		gomega.Ω(json.Unmarshal([]byte(out), &items)).Should(gomega.Succeed())
+		gomega.Ω(items).Should(gomega.ConsistOf(
+			diffItem{Status: diffUntracked, Type: cloud.ResourceTypeVM, ID: untrackedVMID},
+			diffItem{Status: diffUntracked, Type: cloud.ResourceTypeAffinityGroup, ID: sharedGroupID},
+			diffItem{Status: diffMissing, Type: cloud.ResourceTypeVM, ID: "destroyed-vm-id", Object: "CloudStackMachine/machine2"},
+		))
+	})
+
+	ginkgo.It("validates a failure domain and the resources of machines", func() {
+		out, err := run("validate", "--failure-domain", "fd1", "-o", "json",
+			"--offering", fakecloudstack.ServiceOfferingName, "--template", fakecloudstack.TemplateName)
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		var items []checkItem
+		gomega.Ω(json.Unmarshal([]byte(out), &items)).Should(gomega.Succeed())
+		results := map[string]string{}
+		for _, item := range items {
+			results[item.Check] = item.Result
+		}
+		gomega.Ω(results).Should(gomega.Equal(map[string]string{
+			"credentials": checkOK, "zone": checkOK, "network": checkOK, "offering": checkOK, "template": checkOK, "limits": checkOK,
+		}))
+	})
+
+	ginkgo.It("fails validating a zone that doesn't exist", func() {
+		out, err := run("validate", "--cloud-config", cloudConfig, "--zone", "missing-zone")
+		gomega.Ω(err).Should(gomega.MatchError("validation failed"))
+		gomega.Ω(out).Should(gomega.MatchRegexp(`zone\s+failed`))
+		gomega.Ω(out).Should(gomega.MatchRegexp(`network\s+skipped`))
+	})
+
+	ginkgo.It("deletes the resources of a deleted cluster, and untags the ones it shared", func() {
+		out, err := run("cleanup", "--cluster-uid", otherUID, "--cloud-config", cloudConfig, "--dry-run")
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Ω(out).Should(gomega.MatchRegexp(otherVMID + `\s+would be deleted`))
+		gomega.Ω(exists(fakecloudstack.KindVirtualMachine, otherVMID)).Should(gomega.BeTrue())
+
+		_, err = run("cleanup", "--cluster-uid", otherUID, "--cloud-config", cloudConfig)
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Ω(exists(fakecloudstack.KindVirtualMachine, otherVMID)).Should(gomega.BeFalse())
+		gomega.Ω(exists(fakecloudstack.KindAffinityGroup, sharedGroupID)).Should(gomega.BeTrue())
+		tags := server.List(fakecloudstack.KindTag)
+		gomega.Ω(tags).ShouldNot(gomega.ContainElement(gomega.HaveKeyWithValue("key", cloud.ClusterTagNamePrefix+otherUID)))
+	})
+
+	ginkgo.It("refuses to clean up a cluster that still exists", func() {
+		_, err := run("cleanup", "--cluster-uid", clusterUID, "--cloud-config", cloudConfig)
+		gomega.Ω(err).Should(gomega.MatchError(gomega.ContainSubstring("still exists")))
+		gomega.Ω(exists(fakecloudstack.KindVirtualMachine, vmID)).Should(gomega.BeTrue())
+	})
+})
@@ -0,0 +1,135 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
+)
+
+// cleanupItem is a resource of a cluster and what was done with it.
+type cleanupItem struct {
+	Type cloud.ResourceType `json:"type"`
+	ID   string             `json:"id"`
+	// Action is deleted or untagged, prefixed with "would be" in a dry run, or failed.
+	Action string `json:"action"`
+	Error  string `json:"error,omitempty"`
+}
+
+// Actions of cleanupItems.
+const (
+	cleanupDeleted  = "deleted"
+	cleanupUntagged = "untagged"
+	cleanupFailed   = "failed"
+	cleanupDryRun   = "would be "
+)
+
+// newCleanupCommand returns the command deleting the leftover CloudStack resources of a deleted cluster.
+func newCleanupCommand(c *ctl) *cobra.Command {
+	var clusterUID string
+	var dryRun, force bool
+	cmd := &cobra.Command{
+		Use:   "cleanup --cluster-uid UID --cloud-config FILE",
+		Short: "Delete the CloudStack resources left over by a deleted cluster",
+		Long: "Delete the CloudStack resources tagged as created by CAPC for the CloudStackCluster with the UID, " +
+			"found with the credentials of --cloud-config. Resources also used by other clusters are only untagged. " +
+			"Unless --force is given, the management cluster is checked for the CloudStackCluster first.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			if c.cloudConfig == "" {
+				return errors.New("--cloud-config is required")
+			}
+			if !force {
+				if err := c.checkClusterDeleted(cmd.Context(), clusterUID); err != nil {
+					return err
+				}
+			}
+			csClient, err := c.cloudConfigClient()
+			if err != nil {
+				return err
+			}
+			resources, err := listClusterResources([]cloud.Client{csClient}, clusterUID)
+			if err != nil {
+				return err
+			}
+
+			items := make([]cleanupItem, 0, len(resources))
+			failed := 0
+			for _, resource := range resources {
+				item := cleanupItem{Type: resource.Type, ID: resource.ID, Action: cleanupDeleted}
+				if len(resource.ClusterUIDs) > 1 {
+					item.Action = cleanupUntagged
+				}
+				if dryRun {
+					item.Action = cleanupDryRun + item.Action
+				} else if err := cleanupResource(csClient, resource, clusterUID); err != nil {
+					item.Action, item.Error = cleanupFailed, err.Error()
+					failed++
+				}
+				items = append(items, item)
+			}
+
+			if err := printItems(c, cmd.OutOrStdout(), []string{"TYPE", "ID", "ACTION", "ERROR"}, items,
+				func(item cleanupItem) []string {
+					return []string{string(item.Type), item.ID, item.Action, item.Error}
+				}); err != nil {
+				return err
+			}
+			if failed > 0 {
+				return errors.Errorf("%d of %d resources couldn't be cleaned up", failed, len(items))
+			}
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&clusterUID, "cluster-uid", "", "UID of the deleted CloudStackCluster")
+	flags.BoolVar(&dryRun, "dry-run", false, "Only list what would be deleted or untagged")
+	flags.BoolVar(&force, "force", false, "Don't check the management cluster for the CloudStackCluster")
+	_ = cmd.MarkFlagRequired("cluster-uid")
+	return cmd
+}
+
+// checkClusterDeleted returns an error if a CloudStackCluster with the UID exists in the management cluster.
+func (c *ctl) checkClusterDeleted(ctx context.Context, clusterUID string) error {
+	k8sClient, err := c.kubeClient()
+	if err != nil {
+		return errors.Wrap(err, "checking the cluster was deleted, skip with --force")
+	}
+	csClusters := &infrav1.CloudStackClusterList{}
+	if err := k8sClient.List(ctx, csClusters); err != nil {
+		return errors.Wrap(err, "checking the cluster was deleted, skip with --force")
+	}
+	for _, csCluster := range csClusters.Items {
+		if string(csCluster.UID) == clusterUID {
+			return errors.Errorf("CloudStackCluster %s/%s with UID %s still exists", csCluster.Namespace, csCluster.Name, clusterUID)
+		}
+	}
+	return nil
+}
+
+// cleanupResource deletes a resource of the cluster, or removes the cluster's tag from it when other clusters use it.
+func cleanupResource(csClient cloud.Client, resource cloud.CAPCResource, clusterUID string) error {
+	if len(resource.ClusterUIDs) > 1 {
+		return csClient.DeleteTags(resource.Type, resource.ID, map[string]string{cloud.ClusterTagNamePrefix + clusterUID: "1"})
+	}
+	return csClient.DeleteCAPCResource(resource)
+}
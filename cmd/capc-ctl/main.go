@@ -0,0 +1,271 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// capc-ctl inventories, diagnoses and cleans up the CloudStack resources of CAPC clusters.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/clientcmd"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/controllers/utils"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
+)
+
+// Output formats.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+var scheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(clientgoscheme.AddToScheme(scheme))
+	utilruntime.Must(clusterv1.AddToScheme(scheme))
+	utilruntime.Must(infrav1.AddToScheme(scheme))
+}
+
+// ctl holds the global flags and the clients shared by the commands.
+type ctl struct {
+	kubeconfig  string
+	namespace   string
+	output      string
+	cloudConfig string
+	secretName  string
+	// restrictACSEndpointNamespace refuses the acsEndpoint secrets of failure domains in other namespaces.
+	restrictACSEndpointNamespace bool
+
+	// k8sClient is the client of the management cluster, created on first use.
+	k8sClient client.Client
+}
+
+func main() {
+	if err := newRootCommand(&ctl{}).Execute(); err != nil {
+		os.Exit(1)
+	}
+}
+
+// newRootCommand returns the capc-ctl command with its subcommands.
+func newRootCommand(c *ctl) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:          "capc-ctl",
+		Short:        "Inventory, diagnose and clean up the CloudStack resources of CAPC clusters",
+		SilenceUsage: true,
+		PersistentPreRunE: func(*cobra.Command, []string) error {
+			if !slices.Contains([]string{outputTable, outputJSON}, c.output) {
+				return errors.Errorf("unknown output format %q, expected %s or %s", c.output, outputTable, outputJSON)
+			}
+			return nil
+		},
+	}
+	flags := cmd.PersistentFlags()
+	flags.StringVar(&c.kubeconfig, "kubeconfig", "", "Path to the kubeconfig of the management cluster")
+	flags.StringVarP(&c.namespace, "namespace", "n", "", "Namespace of the cluster. Defaults to the namespace of the kubeconfig context")
+	flags.StringVarP(&c.output, "output", "o", outputTable, "Output format, table or json")
+	flags.StringVar(&c.cloudConfig, "cloud-config", "",
+		"Path to a file of CloudStack credential secrets, used instead of the credentials of the cluster's failure domains")
+	flags.StringVar(&c.secretName, "secret-name", "",
+		"Name of the secret to use in the --cloud-config file. May be left out when the file holds a single secret")
+	flags.BoolVar(&c.restrictACSEndpointNamespace, "restrict-acs-endpoint-namespace", false,
+		"Refuse acsEndpoint secrets in other namespaces than the failure domain's, like the manager flag of the same name")
+
+	cmd.AddCommand(
+		newResourcesCommand(c),
+		newDiffCommand(c),
+		newValidateCommand(c),
+		newCleanupCommand(c),
+	)
+	return cmd
+}
+
+// kubeClient returns the client of the management cluster.
+func (c *ctl) kubeClient() (client.Client, error) {
+	if c.k8sClient != nil {
+		return c.k8sClient, nil
+	}
+	restConfig, err := c.clientConfig().ClientConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "loading kubeconfig")
+	}
+	k8sClient, err := client.New(restConfig, client.Options{Scheme: scheme})
+	if err != nil {
+		return nil, errors.Wrap(err, "creating management cluster client")
+	}
+	c.k8sClient = k8sClient
+	return k8sClient, nil
+}
+
+// clientConfig returns the kubeconfig given by --kubeconfig, or found the way kubectl finds it.
+func (c *ctl) clientConfig() clientcmd.ClientConfig {
+	rules := clientcmd.NewDefaultClientConfigLoadingRules()
+	rules.ExplicitPath = c.kubeconfig
+	return clientcmd.NewNonInteractiveDeferredLoadingClientConfig(rules, &clientcmd.ConfigOverrides{})
+}
+
+// ns returns the namespace given by --namespace, or the one of the kubeconfig context.
+func (c *ctl) ns() string {
+	if c.namespace != "" {
+		return c.namespace
+	}
+	if ns, _, err := c.clientConfig().Namespace(); err == nil && ns != "" {
+		return ns
+	}
+	return metav1.NamespaceDefault
+}
+
+// cloudConfigClient returns a client with the credentials of the secret selected in the --cloud-config file.
+func (c *ctl) cloudConfigClient() (cloud.Client, error) {
+	secretName := c.secretName
+	if secretName == "" {
+		content, err := os.ReadFile(c.cloudConfig)
+		if err != nil {
+			return nil, err
+		}
+		configs := []cloud.SecretConfig{}
+		if err := cloud.UnmarshalAllSecretConfigs(content, &configs); err != nil {
+			return nil, errors.Wrapf(err, "parsing %s", c.cloudConfig)
+		}
+		if len(configs) != 1 {
+			return nil, errors.Errorf("%s holds %d secrets, select one with --secret-name", c.cloudConfig, len(configs))
+		}
+		secretName = configs[0].Metadata["name"]
+	}
+	csClient, err := cloud.NewClientFromYamlPath(c.cloudConfig, secretName)
+	return csClient, errors.Wrapf(err, "creating client from %s", c.cloudConfig)
+}
+
+// clusterTarget is a cluster of the management cluster, along with its infrastructure objects.
+type clusterTarget struct {
+	cluster   *clusterv1.Cluster
+	csCluster *infrav1.CloudStackCluster
+	fds       []infrav1.CloudStackFailureDomain
+	// clients see the cluster's CloudStack resources, one for each distinct set of credentials of its failure domains
+	// or the one of --cloud-config.
+	clients []cloud.Client
+}
+
+// getCluster returns the Cluster with the name in the namespace, along with its CloudStackCluster, failure domains
+// and clients to find its CloudStack resources with.
+func (c *ctl) getCluster(ctx context.Context, name string) (*clusterTarget, error) {
+	k8sClient, err := c.kubeClient()
+	if err != nil {
+		return nil, err
+	}
+	target := &clusterTarget{cluster: &clusterv1.Cluster{}, csCluster: &infrav1.CloudStackCluster{}}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: c.ns(), Name: name}, target.cluster); err != nil {
+		return nil, errors.Wrapf(err, "getting Cluster %s/%s", c.ns(), name)
+	}
+	ref := target.cluster.Spec.InfrastructureRef
+	if ref == nil || ref.Kind != "CloudStackCluster" {
+		return nil, errors.Errorf("Cluster %s/%s has no CloudStackCluster", c.ns(), name)
+	}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: c.ns(), Name: ref.Name}, target.csCluster); err != nil {
+		return nil, errors.Wrapf(err, "getting CloudStackCluster %s/%s", c.ns(), ref.Name)
+	}
+
+	fds := &infrav1.CloudStackFailureDomainList{}
+	if err := k8sClient.List(ctx, fds, client.InNamespace(c.ns()),
+		client.MatchingLabels{clusterv1.ClusterNameLabel: name}); err != nil {
+		return nil, errors.Wrap(err, "listing failure domains")
+	}
+	target.fds = fds.Items
+
+	if c.cloudConfig != "" {
+		csClient, err := c.cloudConfigClient()
+		if err != nil {
+			return nil, err
+		}
+		target.clients = []cloud.Client{csClient}
+		return target, nil
+	}
+	seen := map[string]bool{}
+	for i := range target.fds {
+		fdSpec := &target.fds[i].Spec
+		credentials := utils.FailureDomainCredentials(fdSpec)
+		if seen[credentials] {
+			continue
+		}
+		seen[credentials] = true
+		var rotationErr *cloud.CredentialsRotationError
+		_, csUser, err := utils.FailureDomainClients(ctx, k8sClient, fdSpec, c.ns(), c.restrictACSEndpointNamespace)
+		if err != nil && !errors.As(err, &rotationErr) {
+			return nil, errors.Wrapf(err, "creating client of failure domain %s", fdSpec.Name)
+		}
+		target.clients = append(target.clients, csUser)
+	}
+	return target, nil
+}
+
+// listClusterResources lists the resources tagged as created by CAPC for the cluster with the UID, in the order they
+// have to be deleted in. Resources seen by several clients are only listed once.
+func listClusterResources(clients []cloud.Client, clusterUID string) ([]cloud.CAPCResource, error) {
+	var resources []cloud.CAPCResource
+	seen := map[string]bool{}
+	for _, csClient := range clients {
+		listed, err := csClient.ListCAPCResources()
+		if err != nil {
+			return nil, err
+		}
+		for _, resource := range listed {
+			key := string(resource.Type) + "/" + resource.ID
+			if seen[key] || !slices.Contains(resource.ClusterUIDs, clusterUID) {
+				continue
+			}
+			seen[key] = true
+			resources = append(resources, resource)
+		}
+	}
+	slices.SortStableFunc(resources, func(a, b cloud.CAPCResource) int {
+		return slices.Index(cloud.CollectableResourceTypes, a.Type) - slices.Index(cloud.CollectableResourceTypes, b.Type)
+	})
+	return resources, nil
+}
+
+// printItems prints items as JSON, or as a table with a row of columns for each item.
+func printItems[T any](c *ctl, out io.Writer, headers []string, items []T, columns func(T) []string) error {
+	if c.output == outputJSON {
+		if items == nil {
+			items = []T{}
+		}
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(items)
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 3, ' ', 0)
+	fmt.Fprintln(w, strings.Join(headers, "\t"))
+	for _, item := range items {
+		fmt.Fprintln(w, strings.Join(columns(item), "\t"))
+	}
+	return w.Flush()
+}
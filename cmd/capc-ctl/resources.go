@@ -0,0 +1,241 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
+)
+
+// resourceItem is a CloudStack resource of a cluster.
+type resourceItem struct {
+	Type        cloud.ResourceType `json:"type"`
+	ID          string             `json:"id"`
+	Machine     string             `json:"machine,omitempty"`
+	ClusterUIDs []string           `json:"clusterUIDs"`
+	Tags        map[string]string  `json:"tags,omitempty"`
+}
+
+// diffItem is a CloudStack resource of a cluster that isn't referenced by its objects, or a resource its objects
+// reference that doesn't exist.
+type diffItem struct {
+	// Status is untracked or missing.
+	Status string             `json:"status"`
+	Type   cloud.ResourceType `json:"type"`
+	ID     string             `json:"id"`
+	// Object is the kind and name of the object referencing a missing resource.
+	Object string `json:"object,omitempty"`
+}
+
+// Statuses of diffItems.
+const (
+	diffUntracked = "untracked"
+	diffMissing   = "missing"
+)
+
+// newResourcesCommand returns the command listing the CloudStack resources of a cluster.
+func newResourcesCommand(c *ctl) *cobra.Command {
+	var clusterUID string
+	cmd := &cobra.Command{
+		Use:   "resources [CLUSTER]",
+		Short: "List the CloudStack resources CAPC created for a cluster",
+		Long: "List the CloudStack resources tagged as created by CAPC for a cluster, found with the credentials of " +
+			"the cluster's failure domains. Resources of a cluster that no longer exists are found by its UID with the " +
+			"credentials of --cloud-config.",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			var clients []cloud.Client
+			switch {
+			case len(args) == 1:
+				target, err := c.getCluster(cmd.Context(), args[0])
+				if err != nil {
+					return err
+				}
+				clusterUID, clients = string(target.csCluster.UID), target.clients
+			case clusterUID != "" && c.cloudConfig != "":
+				csClient, err := c.cloudConfigClient()
+				if err != nil {
+					return err
+				}
+				clients = []cloud.Client{csClient}
+			default:
+				return errors.New("a cluster name, or --cluster-uid and --cloud-config, are required")
+			}
+
+			resources, err := listClusterResources(clients, clusterUID)
+			if err != nil {
+				return err
+			}
+			items := make([]resourceItem, 0, len(resources))
+			for _, resource := range resources {
+				items = append(items, resourceItem{
+					Type:        resource.Type,
+					ID:          resource.ID,
+					Machine:     resource.Tags[cloud.OwnerMachineTagName],
+					ClusterUIDs: resource.ClusterUIDs,
+					Tags:        resource.Tags,
+				})
+			}
+			return printItems(c, cmd.OutOrStdout(), []string{"TYPE", "ID", "MACHINE", "CLUSTER UIDS"}, items,
+				func(item resourceItem) []string {
+					return []string{string(item.Type), item.ID, item.Machine, strings.Join(item.ClusterUIDs, ",")}
+				})
+		},
+	}
+	cmd.Flags().StringVar(&clusterUID, "cluster-uid", "", "UID of the CloudStackCluster, instead of a cluster name")
+	return cmd
+}
+
+// newDiffCommand returns the command comparing the CloudStack resources of a cluster with its objects.
+func newDiffCommand(c *ctl) *cobra.Command {
+	return &cobra.Command{
+		Use:   "diff CLUSTER",
+		Short: "Compare the CloudStack resources of a cluster with its objects",
+		Long: "Compare the CloudStack resources CAPC created for a cluster with the IDs its CloudStackMachines, " +
+			"CloudStackMachinePools, CloudStackIsolatedNetworks, CloudStackAffinityGroups and CloudStackFailureDomains " +
+			"reference. Resources no object references are untracked, and instances of machines that don't exist " +
+			"in CloudStack are missing.",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			target, err := c.getCluster(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			resources, err := listClusterResources(target.clients, string(target.csCluster.UID))
+			if err != nil {
+				return err
+			}
+			refs, machines, err := c.clusterReferences(cmd.Context(), target)
+			if err != nil {
+				return err
+			}
+
+			var items []diffItem
+			found := map[string]bool{}
+			for _, resource := range resources {
+				key := string(resource.Type) + "/" + resource.ID
+				found[key] = true
+				// Data disk volumes aren't referenced by ID, but are tagged with their machine.
+				tracked := refs[key] != "" ||
+					(resource.Type == cloud.ResourceTypeVolume && machines[resource.Tags[cloud.OwnerMachineTagName]])
+				if !tracked {
+					items = append(items, diffItem{Status: diffUntracked, Type: resource.Type, ID: resource.ID})
+				}
+			}
+			// Only instances are always created by CAPC, other resources may be referenced without being tagged.
+			var missing []diffItem
+			for key, object := range refs {
+				rType, id, _ := strings.Cut(key, "/")
+				if cloud.ResourceType(rType) == cloud.ResourceTypeVM && !found[key] {
+					missing = append(missing, diffItem{Status: diffMissing, Type: cloud.ResourceTypeVM, ID: id, Object: object})
+				}
+			}
+			sort.Slice(missing, func(i, j int) bool { return missing[i].Object < missing[j].Object })
+			items = append(items, missing...)
+
+			return printItems(c, cmd.OutOrStdout(), []string{"STATUS", "TYPE", "ID", "OBJECT"}, items,
+				func(item diffItem) []string {
+					return []string{item.Status, string(item.Type), item.ID, item.Object}
+				})
+		},
+	}
+}
+
+// clusterReferences returns the objects of the cluster referencing each CloudStack resource by its type and ID, and
+// the names of the cluster's CloudStackMachines.
+func (c *ctl) clusterReferences(
+	ctx context.Context, target *clusterTarget,
+) (refs map[string]string, machines map[string]bool, retErr error) {
+	k8sClient, err := c.kubeClient()
+	if err != nil {
+		return nil, nil, err
+	}
+	refs, machines = map[string]string{}, map[string]bool{}
+	addRef := func(rType cloud.ResourceType, id, object string) {
+		if id != "" {
+			refs[string(rType)+"/"+id] = object
+		}
+	}
+	inCluster := []client.ListOption{
+		client.InNamespace(target.cluster.Namespace),
+		client.MatchingLabels{clusterv1.ClusterNameLabel: target.cluster.Name},
+	}
+
+	csMachines := &infrav1.CloudStackMachineList{}
+	if err := k8sClient.List(ctx, csMachines, inCluster...); err != nil {
+		return nil, nil, errors.Wrap(err, "listing CloudStackMachines")
+	}
+	for _, csMachine := range csMachines.Items {
+		machines[csMachine.Name] = true
+		if csMachine.Spec.InstanceID != nil {
+			addRef(cloud.ResourceTypeVM, *csMachine.Spec.InstanceID, "CloudStackMachine/"+csMachine.Name)
+		}
+	}
+
+	pools := &infrav1.CloudStackMachinePoolList{}
+	if err := k8sClient.List(ctx, pools, inCluster...); err != nil {
+		return nil, nil, errors.Wrap(err, "listing CloudStackMachinePools")
+	}
+	for _, pool := range pools.Items {
+		for _, instance := range pool.Status.Instances {
+			addRef(cloud.ResourceTypeVM, instance.InstanceID, "CloudStackMachinePool/"+pool.Name)
+		}
+	}
+
+	isoNets := &infrav1.CloudStackIsolatedNetworkList{}
+	if err := k8sClient.List(ctx, isoNets, inCluster...); err != nil {
+		return nil, nil, errors.Wrap(err, "listing CloudStackIsolatedNetworks")
+	}
+	for _, isoNet := range isoNets.Items {
+		object := "CloudStackIsolatedNetwork/" + isoNet.Name
+		addRef(cloud.ResourceTypeNetwork, isoNet.Spec.ID, object)
+		addRef(cloud.ResourceTypeIPAddress, isoNet.Status.PublicIPID, object)
+		addRef(cloud.ResourceTypeLoadBalancer, isoNet.Status.LBRuleID, object)
+		for _, id := range isoNet.Status.AdditionalLBRuleIDs {
+			addRef(cloud.ResourceTypeLoadBalancer, id, object)
+		}
+		if isoNet.Spec.VPC != nil {
+			addRef(cloud.ResourceTypeVPC, isoNet.Spec.VPC.ID, object)
+		}
+	}
+
+	affinityGroups := &infrav1.CloudStackAffinityGroupList{}
+	if err := k8sClient.List(ctx, affinityGroups, inCluster...); err != nil {
+		return nil, nil, errors.Wrap(err, "listing CloudStackAffinityGroups")
+	}
+	for _, affinityGroup := range affinityGroups.Items {
+		addRef(cloud.ResourceTypeAffinityGroup, affinityGroup.Spec.ID, "CloudStackAffinityGroup/"+affinityGroup.Name)
+	}
+
+	for _, fd := range target.fds {
+		object := "CloudStackFailureDomain/" + fd.Name
+		addRef(cloud.ResourceTypeNetwork, fd.Spec.Zone.Network.ID, object)
+		if fd.Spec.Zone.Network.VPC != nil {
+			addRef(cloud.ResourceTypeVPC, fd.Spec.Zone.Network.VPC.ID, object)
+		}
+	}
+	return refs, machines, nil
+}
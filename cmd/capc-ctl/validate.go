@@ -0,0 +1,222 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/controllers/utils"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
+)
+
+// checkItem is the result of a validation check.
+type checkItem struct {
+	Check string `json:"check"`
+	// Result is ok, failed or skipped.
+	Result string `json:"result"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// Results of checkItems.
+const (
+	checkOK      = "ok"
+	checkFailed  = "failed"
+	checkSkipped = "skipped"
+)
+
+// validateOptions are the flags of the validate command.
+type validateOptions struct {
+	failureDomain string
+	fdSpec        infrav1.CloudStackFailureDomainSpec
+	machine       infrav1.CloudStackMachine
+}
+
+// newValidateCommand returns the command validating credentials and a failure domain against CloudStack.
+func newValidateCommand(c *ctl) *cobra.Command {
+	opts := &validateOptions{}
+	cmd := &cobra.Command{
+		Use:   "validate",
+		Short: "Validate CloudStack credentials, a failure domain, and the offerings and template of machines",
+		Long: "Validate the credentials, zone, network and account of a CloudStackFailureDomain, or of the failure " +
+			"domain given by flags with the credentials of --cloud-config. The offering, template and disk offering " +
+			"of machines, and the room the resource limits and zone capacity leave for them, are validated when given.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, _ []string) error {
+			items, err := c.validate(cmd.Context(), opts)
+			if err != nil {
+				return err
+			}
+			if err := printItems(c, cmd.OutOrStdout(), []string{"CHECK", "RESULT", "DETAIL"}, items,
+				func(item checkItem) []string {
+					return []string{item.Check, item.Result, item.Detail}
+				}); err != nil {
+				return err
+			}
+			for _, item := range items {
+				if item.Result == checkFailed {
+					return errors.New("validation failed")
+				}
+			}
+			return nil
+		},
+	}
+	flags := cmd.Flags()
+	flags.StringVar(&opts.failureDomain, "failure-domain", "", "Name of the CloudStackFailureDomain to validate")
+	flags.StringVar(&opts.fdSpec.Zone.Name, "zone", "", "Name of the zone, when not validating a CloudStackFailureDomain")
+	flags.StringVar(&opts.fdSpec.Zone.Network.Name, "network", "", "Name of the network, when not validating a CloudStackFailureDomain")
+	flags.StringVar(&opts.fdSpec.Domain, "domain", "", "Domain of the account, when not validating a CloudStackFailureDomain")
+	flags.StringVar(&opts.fdSpec.Account, "account", "", "Account to act as, when not validating a CloudStackFailureDomain")
+	flags.StringVar(&opts.fdSpec.Project, "project", "", "Project, when not validating a CloudStackFailureDomain")
+	flags.StringVar(&opts.machine.Spec.Offering.Name, "offering", "", "Name of the service offering of machines")
+	flags.StringVar(&opts.machine.Spec.Template.Name, "template", "", "Name of the template of machines")
+	flags.StringVar(&opts.machine.Spec.DiskOffering.Name, "disk-offering", "", "Name of the disk offering of machines")
+	for _, flag := range []string{"zone", "network", "domain", "account", "project"} {
+		cmd.MarkFlagsMutuallyExclusive("failure-domain", flag)
+	}
+	return cmd
+}
+
+// validate runs the checks of the validate command. Checks depending on a failed one are skipped.
+func (c *ctl) validate(ctx context.Context, opts *validateOptions) ([]checkItem, error) {
+	fdSpec := opts.fdSpec
+	if opts.failureDomain != "" {
+		k8sClient, err := c.kubeClient()
+		if err != nil {
+			return nil, err
+		}
+		fd := &infrav1.CloudStackFailureDomain{}
+		if err := k8sClient.Get(ctx, client.ObjectKey{Namespace: c.ns(), Name: opts.failureDomain}, fd); err != nil {
+			return nil, errors.Wrapf(err, "getting CloudStackFailureDomain %s/%s", c.ns(), opts.failureDomain)
+		}
+		fdSpec = fd.Spec
+	} else if c.cloudConfig == "" || fdSpec.Zone.Name == "" {
+		return nil, errors.New("--failure-domain, or --cloud-config and --zone, are required")
+	}
+
+	var items []checkItem
+	check := func(name string, err error, detail string) bool {
+		if err != nil {
+			items = append(items, checkItem{Check: name, Result: checkFailed, Detail: err.Error()})
+			return false
+		}
+		items = append(items, checkItem{Check: name, Result: checkOK, Detail: detail})
+		return true
+	}
+	skip := func(reason string, names ...string) {
+		for _, name := range names {
+			items = append(items, checkItem{Check: name, Result: checkSkipped, Detail: reason})
+		}
+	}
+
+	csClient, err := c.endpointClient(ctx, &fdSpec)
+	if err == nil {
+		err = csClient.VerifyCredentials()
+	}
+	if !check("credentials", err, "the endpoint accepts the credentials") {
+		skip("the credentials were rejected", "account", "zone", "network", "offering", "template", "disk offering", "limits")
+		return items, nil
+	}
+
+	csUser := csClient
+	if fdSpec.Account != "" {
+		csUser, err = csClient.NewClientInDomainAndAccount(fdSpec.Domain, fdSpec.Account, fdSpec.Project)
+		if !check("account", err, fmt.Sprintf("acting as account %s in domain %s", fdSpec.Account, fdSpec.Domain)) {
+			skip("the account didn't resolve", "zone", "network", "offering", "template", "disk offering", "limits")
+			return items, nil
+		}
+	}
+
+	zone := fdSpec.Zone
+	err = csUser.ResolveZone(&zone)
+	if err == nil {
+		var state string
+		if state, err = csUser.GetZoneAllocationState(zone.ID); err == nil && strings.EqualFold(state, cloud.ZoneAllocationStateDisabled) {
+			err = errors.Errorf("zone %s is %s", zone.Name, state)
+		}
+	}
+	if !check("zone", err, "ID "+zone.ID) {
+		skip("the zone didn't resolve", "network", "offering", "template", "disk offering", "limits")
+		return items, nil
+	}
+
+	network := infrav1.Network{ID: zone.Network.ID, Name: zone.Network.Name}
+	if network.ID == "" && network.Name == "" {
+		skip("no network given", "network")
+	} else if err = csUser.ResolveNetwork(&network); err != nil && network.ID == "" && utils.ContainsNoMatchSubstring(err) {
+		check("network", nil, "doesn't exist, CAPC creates it as an isolated network")
+	} else {
+		check("network", err, fmt.Sprintf("ID %s, type %s", network.ID, network.Type))
+	}
+
+	machine := &opts.machine
+	offeringResolved := false
+	if machine.Spec.Offering.Name != "" {
+		offering, err := csUser.ResolveServiceOffering(machine, zone.ID)
+		offeringResolved = check("offering", err,
+			fmt.Sprintf("ID %s, %d vCPUs, %d MiB", offering.Id, offering.Cpunumber, offering.Memory))
+	}
+	if machine.Spec.Template.Name != "" {
+		templateID, err := csUser.ResolveTemplate(machine, zone.ID)
+		check("template", err, "ID "+templateID)
+	}
+	if machine.Spec.DiskOffering.Name != "" {
+		diskOfferingID, err := csUser.ResolveDiskOffering(machine, zone.ID)
+		check("disk offering", err, "ID "+diskOfferingID)
+	}
+
+	if !offeringResolved {
+		skip("no offering to fit", "limits")
+		return items, nil
+	}
+	room, err := csUser.GetZoneCapacityForMachine(machine, zone.ID)
+	switch {
+	case err != nil:
+		check("limits", err, "")
+	case room == math.MaxInt64:
+		check("limits", nil, "no limit on instances of the offering")
+	case room == 0:
+		check("limits", errors.New("no room for another instance of the offering"), "")
+	default:
+		check("limits", nil, fmt.Sprintf("room for %d more instances of the offering", room))
+	}
+	return items, nil
+}
+
+// endpointClient returns a client with the credentials of --cloud-config, or of the failure domain's endpoint secret
+// or CloudStackClusterIdentity.
+func (c *ctl) endpointClient(ctx context.Context, fdSpec *infrav1.CloudStackFailureDomainSpec) (cloud.Client, error) {
+	if c.cloudConfig != "" {
+		return c.cloudConfigClient()
+	}
+	k8sClient, err := c.kubeClient()
+	if err != nil {
+		return nil, err
+	}
+	// The account is validated separately.
+	endpointSpec := *fdSpec
+	endpointSpec.Account, endpointSpec.Domain = "", ""
+	csClient, _, err := utils.FailureDomainClients(ctx, k8sClient, &endpointSpec, c.ns(), c.restrictACSEndpointNamespace)
+	return csClient, err
+}
@@ -45,6 +45,9 @@ var _ = ginkgo.Describe("CksCloudStackClusterReconciler", func() {
 				arg1.(*infrav1.CloudStackCluster).Status.CloudStackClusterID = "cluster-id-123"
 			}).MinTimes(1).Return(nil)
 			mockCloudClient.EXPECT().ResolveZone(gomock.Any()).AnyTimes()
+			mockCloudClient.EXPECT().VerifyCredentials().AnyTimes()
+			mockCloudClient.EXPECT().GetZoneAllocationState(gomock.Any()).Return(cloud.ZoneAllocationStateEnabled, nil).AnyTimes()
+			mockCloudClient.EXPECT().VerifyNetwork(gomock.Any()).AnyTimes()
 			mockCloudClient.EXPECT().ResolveNetworkForZone(gomock.Any()).AnyTimes().Do(
 				func(arg1 interface{}) {
 					arg1.(*infrav1.CloudStackZoneSpec).Network.ID = "SomeID"
@@ -19,6 +19,8 @@ package controllers
 import (
 	"context"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
@@ -67,11 +69,27 @@ func (reconciler *CloudStackAffinityGroupReconciler) Reconcile(ctx context.Conte
 
 func (r *CloudStackAGReconciliationRunner) Reconcile() (ctrl.Result, error) {
 	controllerutil.AddFinalizer(r.ReconciliationSubject, infrav1.AffinityGroupFinalizer)
-	affinityGroup := &cloud.AffinityGroup{Name: r.ReconciliationSubject.Spec.Name, Type: r.ReconciliationSubject.Spec.Type}
+	affinityGroup := &cloud.AffinityGroup{
+		Name: r.ReconciliationSubject.Spec.Name,
+		Type: r.ReconciliationSubject.Spec.Type,
+		Tags: cloud.ResourceTags(r.CSCluster, nil),
+	}
 	if err := r.CSUser.GetOrCreateAffinityGroup(affinityGroup); err != nil {
+		conditions.MarkFalse(r.ReconciliationSubject, infrav1.AffinityGroupReadyCondition,
+			infrav1.AffinityGroupCreationFailedReason, clusterv1.ConditionSeverityError, err.Error())
 		return ctrl.Result{}, err
 	}
+	// Non-strict types fall back to strict ones on CloudStack versions before 4.18.
+	if affinityGroup.Type != r.ReconciliationSubject.Spec.Type &&
+		(r.ReconciliationSubject.Spec.Type == cloud.NonStrictAffinityGroupType ||
+			r.ReconciliationSubject.Spec.Type == cloud.NonStrictAntiAffinityGroupType) {
+		r.Recorder.Eventf(r.ReconciliationSubject, "Warning", "AffinityGroupTypeFallback",
+			"Affinity group type %q is not supported by CloudStack, using %q instead",
+			r.ReconciliationSubject.Spec.Type, affinityGroup.Type)
+		r.ReconciliationSubject.Spec.Type = affinityGroup.Type
+	}
 	r.ReconciliationSubject.Spec.ID = affinityGroup.ID
+	conditions.MarkTrue(r.ReconciliationSubject, infrav1.AffinityGroupReadyCondition)
 	r.ReconciliationSubject.Status.Ready = true
 	return ctrl.Result{}, nil
 }
@@ -58,6 +58,27 @@ var _ = ginkgo.Describe("CloudStackAffinityGroupReconciler", func() {
 		}, timeout).WithPolling(pollInterval).Should(gomega.BeTrue())
 	})
 
+	ginkgo.It("Should fall back to the strict type if CloudStack doesn't support the non-strict one.", func() {
+		dummies.CSAffinityGroup.Spec.FailureDomainName = dummies.CSFailureDomain1.Spec.Name
+		dummies.CSAffinityGroup.Spec.Type = cloud.NonStrictAntiAffinityGroupType
+
+		mockCloudClient.EXPECT().GetOrCreateAffinityGroup(gomock.Any()).Do(func(arg1 interface{}) {
+			arg1.(*cloud.AffinityGroup).Type = cloud.AntiAffinityGroupType
+		}).AnyTimes()
+
+		gomega.Ω(k8sClient.Create(ctx, dummies.CSFailureDomain1))
+		gomega.Ω(k8sClient.Create(ctx, dummies.CSAffinityGroup)).Should(gomega.Succeed())
+
+		gomega.Eventually(func() string {
+			affinityGroup := &infrav1.CloudStackAffinityGroup{}
+			key := client.ObjectKey{Namespace: dummies.ClusterNameSpace, Name: dummies.CSAffinityGroup.Name}
+			if err := k8sClient.Get(ctx, key, affinityGroup); err != nil || !affinityGroup.Status.Ready {
+				return ""
+			}
+			return affinityGroup.Spec.Type
+		}, timeout).WithPolling(pollInterval).Should(gomega.Equal(cloud.AntiAffinityGroupType))
+	})
+
 	ginkgo.It("Should remove affinity group finalizer if corresponding affinity group is not present on Cloudstack.", func() {
 		// Modify failure domain name the same way the cluster controller would.
 		dummies.CSAffinityGroup.Spec.FailureDomainName = dummies.CSFailureDomain1.Spec.Name
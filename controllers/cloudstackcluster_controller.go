@@ -20,6 +20,7 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strings"
 
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -34,6 +35,7 @@ import (
 	csCtrlrUtils "sigs.k8s.io/cluster-api-provider-cloudstack/controllers/utils"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/predicates"
 )
 
@@ -41,6 +43,8 @@ import (
 // "" empty string as the api group indicates core kubernetes objects. "*" indicates all objects.
 // +kubebuilder:rbac:groups="",resources=secrets;,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=configmaps;,verbs=get;list;watch
+// +kubebuilder:rbac:groups="",resources=namespaces,verbs=get;list;watch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=cloudstackclusteridentities,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=get;list;watch;create;update;patch
 
 // RBAC permissions for CloudStackCluster.
@@ -89,9 +93,9 @@ func (reconciler *CloudStackClusterReconciler) Reconcile(ctx context.Context, re
 // Reconcile actually reconciles the CloudStackCluster.
 func (r *CloudStackClusterReconciliationRunner) Reconcile() (res ctrl.Result, reterr error) {
 	return r.RunReconciliationStages(
-		r.SetFailureDomainsStatusMap,
 		r.CreateFailureDomains(r.ReconciliationSubject.Spec.FailureDomains),
 		r.GetFailureDomains(r.FailureDomains),
+		r.SetFailureDomainsStatusMap,
 		r.RemoveExtraneousFailureDomains(r.FailureDomains),
 		r.VerifyFailureDomainCRDs,
 		r.SetReady)
@@ -100,6 +104,7 @@ func (r *CloudStackClusterReconciliationRunner) Reconcile() (res ctrl.Result, re
 // SetReady adds a finalizer and sets the cluster status to ready.
 func (r *CloudStackClusterReconciliationRunner) SetReady() (ctrl.Result, error) {
 	controllerutil.AddFinalizer(r.ReconciliationSubject, infrav1.ClusterFinalizer)
+	conditions.MarkTrue(r.ReconciliationSubject, infrav1.FailureDomainsReadyCondition)
 	r.ReconciliationSubject.Status.Ready = true
 	return ctrl.Result{}, nil
 }
@@ -113,12 +118,16 @@ func (r *CloudStackClusterReconciliationRunner) VerifyFailureDomainCRDs() (ctrl.
 			if requiredFdSpec.Name == fd.Spec.Name {
 				found = true
 				if !fd.Status.Ready {
+					conditions.MarkFalse(r.ReconciliationSubject, infrav1.FailureDomainsReadyCondition,
+						infrav1.WaitingForFailureDomainsReason, clusterv1.ConditionSeverityInfo, "FailureDomain %s not ready", fd.Spec.Name)
 					return r.RequeueWithMessage(fmt.Sprintf("Required FailureDomain %s not ready, requeueing.", fd.Spec.Name))
 				}
 				break
 			}
 		}
 		if !found {
+			conditions.MarkFalse(r.ReconciliationSubject, infrav1.FailureDomainsReadyCondition,
+				infrav1.WaitingForFailureDomainsReason, clusterv1.ConditionSeverityInfo, "FailureDomain %s not found", requiredFdSpec.Name)
 			return r.RequeueWithMessage(fmt.Sprintf("Required FailureDomain %s not found, requeueing.", requiredFdSpec.Name))
 		}
 	}
@@ -126,17 +135,50 @@ func (r *CloudStackClusterReconciliationRunner) VerifyFailureDomainCRDs() (ctrl.
 }
 
 // SetFailureDomainsStatusMap sets failure domains in CloudStackCluster status to be used for CAPI machine placement.
+// Failure domains that failed their health checks are withheld from control plane placement.
 func (r *CloudStackClusterReconciliationRunner) SetFailureDomainsStatusMap() (ctrl.Result, error) {
+	unavailable := map[string]bool{}
+	for idx := range r.FailureDomains.Items {
+		if !failureDomainAvailable(&r.FailureDomains.Items[idx]) {
+			unavailable[r.FailureDomains.Items[idx].Spec.Name] = true
+		}
+	}
+
 	r.ReconciliationSubject.Status.FailureDomains = clusterv1.FailureDomains{}
+	var unavailableNames []string
 	for _, fdSpec := range r.ReconciliationSubject.Spec.FailureDomains {
 		metaHashName := infrav1.FailureDomainHashedMetaName(fdSpec.Name, r.CAPICluster.Name)
 		r.ReconciliationSubject.Status.FailureDomains[fdSpec.Name] = clusterv1.FailureDomainSpec{
-			ControlPlane: true, Attributes: map[string]string{"MetaHashName": metaHashName},
+			ControlPlane: !unavailable[fdSpec.Name], Attributes: map[string]string{"MetaHashName": metaHashName},
+		}
+		if unavailable[fdSpec.Name] {
+			unavailableNames = append(unavailableNames, fdSpec.Name)
 		}
 	}
+
+	if len(unavailableNames) > 0 {
+		conditions.MarkFalse(r.ReconciliationSubject, infrav1.FailureDomainsAvailableCondition,
+			infrav1.FailureDomainsUnavailableReason, clusterv1.ConditionSeverityWarning,
+			"FailureDomains %s failed their health checks", strings.Join(unavailableNames, ", "))
+	} else {
+		conditions.MarkTrue(r.ReconciliationSubject, infrav1.FailureDomainsAvailableCondition)
+	}
 	return ctrl.Result{}, nil
 }
 
+// failureDomainAvailable reports whether none of the health checks of a failure domain's endpoint, credentials, zone
+// and network failed. Failure domains that weren't checked yet are considered available.
+func failureDomainAvailable(fd *infrav1.CloudStackFailureDomain) bool {
+	for _, condition := range []clusterv1.ConditionType{
+		infrav1.CredentialsValidCondition, infrav1.ZoneEnabledCondition, infrav1.NetworkReadyCondition,
+	} {
+		if conditions.IsFalse(fd, condition) {
+			return false
+		}
+	}
+	return true
+}
+
 // ReconcileDelete cleans up resources used by the cluster and finally removes the CloudStackCluster's finalizers.
 func (r *CloudStackClusterReconciliationRunner) ReconcileDelete() (ctrl.Result, error) {
 	r.Log.Info("Deleting CloudStackCluster.")
@@ -185,6 +227,9 @@ func (reconciler *CloudStackClusterReconciler) SetupWithManager(ctx context.Cont
 		),
 		)
 
+	// Reflect the health of the cluster's failure domains in its status.
+	b = b.Owns(&infrav1.CloudStackFailureDomain{})
+
 	// Add a watch on CAPI Cluster objects for unpause and ready events.
 	b = b.Watches(
 		&clusterv1.Cluster{},
@@ -23,6 +23,7 @@ import (
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
 	"sigs.k8s.io/cluster-api-provider-cloudstack/controllers"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
 	dummies "sigs.k8s.io/cluster-api-provider-cloudstack/test/dummies/v1beta3"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -39,6 +40,9 @@ var _ = ginkgo.Describe("CloudStackClusterReconciler", func() {
 		ginkgo.It("Should create a CloudStackFailureDomain.", func() {
 			tempfd := &infrav1.CloudStackFailureDomain{}
 			mockCloudClient.EXPECT().ResolveZone(gomock.Any()).AnyTimes()
+			mockCloudClient.EXPECT().VerifyCredentials().AnyTimes()
+			mockCloudClient.EXPECT().GetZoneAllocationState(gomock.Any()).Return(cloud.ZoneAllocationStateEnabled, nil).AnyTimes()
+			mockCloudClient.EXPECT().VerifyNetwork(gomock.Any()).AnyTimes()
 			gomega.Eventually(func() bool {
 				key := client.ObjectKeyFromObject(dummies.CSFailureDomain1)
 				key.Name = key.Name + "-" + dummies.CSCluster.Name
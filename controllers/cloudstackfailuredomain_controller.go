@@ -18,30 +18,44 @@ package controllers
 
 import (
 	"context"
+	"fmt"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
 	csCtrlrUtils "sigs.k8s.io/cluster-api-provider-cloudstack/controllers/utils"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
 )
 
 const (
 	conditionTypeReady   = "Ready"
 	conditionStatusFalse = "False"
+
+	CSEndpointUnreachableMessage = "The CloudStack endpoint didn't answer: %s"
+	CSCredentialsRejectedMessage = "The CloudStack endpoint rejected the API and secret keys of the endpoint secret: %s"
 )
 
 // CloudStackFailureDomainReconciler is the k8s controller manager's interface to reconcile a CloudStackFailureDomain.
 // This is primarily to adapt to k8s.
 type CloudStackFailureDomainReconciler struct {
 	csCtrlrUtils.ReconcilerBase
+	// HealthCheckInterval is how often the endpoint, credentials, zone and network of ready failure domains are
+	// checked again. With zero, they're only checked when the failure domains are otherwise reconciled.
+	HealthCheckInterval time.Duration
 }
 
 //+kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=cloudstackfailuredomains,verbs=get;list;watch;create;update;patch;delete
@@ -61,6 +75,7 @@ type CloudStackFailureDomainReconciliationRunner struct {
 	ReconciliationSubject *infrav1.CloudStackFailureDomain
 	IsoNet                *infrav1.CloudStackIsolatedNetwork
 	Machines              []infrav1.CloudStackMachine
+	HealthCheckInterval   time.Duration
 }
 
 // Initialize a new CloudStackFailureDomain reconciliation runner with concrete types and initialized member fields.
@@ -76,7 +91,9 @@ func NewCSFailureDomainReconciliationRunner() *CloudStackFailureDomainReconcilia
 
 // Reconcile is the method k8s will call upon a reconciliation request.
 func (reconciler *CloudStackFailureDomainReconciler) Reconcile(ctx context.Context, req ctrl.Request) (retRes ctrl.Result, retErr error) {
-	return NewCSFailureDomainReconciliationRunner().
+	r := NewCSFailureDomainReconciliationRunner()
+	r.HealthCheckInterval = reconciler.HealthCheckInterval
+	return r.
 		UsingBaseReconciler(reconciler.ReconcilerBase).
 		ForRequest(req).
 		WithRequestCtx(ctx).
@@ -86,18 +103,43 @@ func (reconciler *CloudStackFailureDomainReconciler) Reconcile(ctx context.Conte
 // Reconcile on the ReconciliationRunner actually attempts to modify or create the reconciliation subject.
 func (r *CloudStackFailureDomainReconciliationRunner) Reconcile() (retRes ctrl.Result, retErr error) {
 	res, err := r.AsFailureDomainUser(&r.ReconciliationSubject.Spec)()
+	if err != nil {
+		r.markCredentialsInvalid(err)
+	}
 	if r.ShouldReturn(res, err) {
 		return res, err
 	}
 	// Prevent premature deletion.
 	controllerutil.AddFinalizer(r.ReconciliationSubject, infrav1.FailureDomainFinalizer)
 
+	// Cached clients outlive their credentials, so check that the endpoint still accepts them.
+	if err := r.CSUser.VerifyCredentials(); err != nil {
+		r.markCredentialsInvalid(err)
+		return ctrl.Result{}, errors.Wrap(err, "verifying CloudStack credentials")
+	}
+	conditions.MarkTrue(r.ReconciliationSubject, infrav1.CredentialsValidCondition)
+
 	// Start by purely data fetching information about the zone and specified network.
 	if err := r.CSUser.ResolveZone(&r.ReconciliationSubject.Spec.Zone); err != nil {
+		conditions.MarkFalse(r.ReconciliationSubject, infrav1.ZoneResolvedCondition,
+			infrav1.ZoneResolutionFailedReason, clusterv1.ConditionSeverityError, err.Error())
 		return ctrl.Result{}, errors.Wrap(err, "resolving CloudStack zone information")
 	}
+	conditions.MarkTrue(r.ReconciliationSubject, infrav1.ZoneResolvedCondition)
+	if state, err := r.CSUser.GetZoneAllocationState(r.ReconciliationSubject.Spec.Zone.ID); err != nil {
+		conditions.MarkFalse(r.ReconciliationSubject, infrav1.ZoneEnabledCondition,
+			infrav1.ZoneCheckFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		return ctrl.Result{}, errors.Wrap(err, "checking CloudStack zone allocation state")
+	} else if strings.EqualFold(state, cloud.ZoneAllocationStateDisabled) {
+		conditions.MarkFalse(r.ReconciliationSubject, infrav1.ZoneEnabledCondition, infrav1.ZoneDisabledReason,
+			clusterv1.ConditionSeverityWarning, "Zone %s is %s", r.ReconciliationSubject.Spec.Zone.Name, state)
+	} else { // Zones whose allocation state isn't reported are considered enabled.
+		conditions.MarkTrue(r.ReconciliationSubject, infrav1.ZoneEnabledCondition)
+	}
 	if err := r.CSUser.ResolveNetworkForZone(&r.ReconciliationSubject.Spec.Zone); err != nil &&
 		!csCtrlrUtils.ContainsNoMatchSubstring(err) {
+		conditions.MarkFalse(r.ReconciliationSubject, infrav1.NetworkReadyCondition,
+			infrav1.NetworkResolutionFailedReason, clusterv1.ConditionSeverityError, err.Error())
 		return ctrl.Result{}, errors.Wrap(err, "resolving Cloudstack network information")
 	}
 
@@ -110,6 +152,10 @@ func (r *CloudStackFailureDomainReconciliationRunner) Reconcile() (retRes ctrl.R
 			netName,
 			func() string { return r.ReconciliationSubject.Spec.Name },
 			r.ReconciliationSubject.Spec.Zone.Network)(); r.ShouldReturn(res, err) {
+			if err != nil {
+				conditions.MarkFalse(r.ReconciliationSubject, infrav1.NetworkReadyCondition,
+					infrav1.IsolatedNetworkReconciliationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+			}
 			return res, err
 		} else if res, err := r.GetObjectByName(r.IsoNetMetaName(netName), r.IsoNet)(); r.ShouldReturn(res, err) {
 			return res, err
@@ -118,11 +164,42 @@ func (r *CloudStackFailureDomainReconciliationRunner) Reconcile() (retRes ctrl.R
 			return r.RequeueWithMessage("Couldn't find isolated network.")
 		}
 		if !r.IsoNet.Status.Ready {
+			conditions.MarkFalse(r.ReconciliationSubject, infrav1.NetworkReadyCondition,
+				infrav1.WaitingForIsolatedNetworkReason, clusterv1.ConditionSeverityInfo, "")
 			return r.RequeueWithMessage("Isolated network dependency not ready.")
 		}
 	}
+
+	// The network may have been deleted since it was resolved.
+	networkID := r.ReconciliationSubject.Spec.Zone.Network.ID
+	if networkID == "" {
+		networkID = r.IsoNet.Spec.ID
+	}
+	if networkID != "" {
+		if err := r.CSUser.VerifyNetwork(networkID); err != nil {
+			conditions.MarkFalse(r.ReconciliationSubject, infrav1.NetworkReadyCondition,
+				infrav1.NetworkNotFoundReason, clusterv1.ConditionSeverityError, err.Error())
+			return ctrl.Result{}, errors.Wrap(err, "verifying CloudStack network")
+		}
+	}
+	conditions.MarkTrue(r.ReconciliationSubject, infrav1.NetworkReadyCondition)
 	r.ReconciliationSubject.Status.Ready = true
-	return ctrl.Result{}, nil
+	return ctrl.Result{RequeueAfter: r.HealthCheckInterval}, nil
+}
+
+// markCredentialsInvalid records why the failure domain's CloudStack endpoint couldn't be used.
+func (r *CloudStackFailureDomainReconciliationRunner) markCredentialsInvalid(err error) {
+	reason, message := infrav1.CredentialsRejectedReason, err.Error()
+	if cloud.IsEndpointUnreachableError(err) {
+		reason, message = infrav1.EndpointUnreachableReason, fmt.Sprintf(CSEndpointUnreachableMessage, err)
+	} else if cloud.IsCredentialsRejectedError(err) {
+		message = fmt.Sprintf(CSCredentialsRejectedMessage, err)
+	}
+	if !conditions.IsFalse(r.ReconciliationSubject, infrav1.CredentialsValidCondition) {
+		r.Recorder.Event(r.ReconciliationSubject, "Warning", reason, message)
+	}
+	conditions.MarkFalse(r.ReconciliationSubject, infrav1.CredentialsValidCondition,
+		reason, clusterv1.ConditionSeverityError, "%s", message)
 }
 
 // ReconcileDelete on the ReconciliationRunner attempts to delete the reconciliation subject.
@@ -271,5 +348,42 @@ func (reconciler *CloudStackFailureDomainReconciler) SetupWithManager(mgr ctrl.M
 	return ctrl.NewControllerManagedBy(mgr).
 		WithOptions(opts).
 		For(&infrav1.CloudStackFailureDomain{}).
+		// Only the metadata of secrets is watched, their content is read when failure domains are reconciled.
+		WatchesMetadata(&corev1.Secret{}, handler.EnqueueRequestsFromMapFunc(reconciler.secretToFailureDomains)).
 		Complete(reconciler)
 }
+
+// secretToFailureDomains maps an endpoint secret to the failure domains using it, directly or through a
+// CloudStackClusterIdentity, so that rotated credentials are picked up without waiting for the next resync.
+func (reconciler *CloudStackFailureDomainReconciler) secretToFailureDomains(ctx context.Context, secret client.Object) []reconcile.Request {
+	log := ctrl.LoggerFrom(ctx)
+	isSecret := func(ref corev1.SecretReference) bool {
+		return ref.Name == secret.GetName() && ref.Namespace == secret.GetNamespace()
+	}
+
+	identities := &infrav1.CloudStackClusterIdentityList{}
+	if err := reconciler.K8sClient.List(ctx, identities); err != nil {
+		log.Error(err, "Listing CloudStackClusterIdentities to map a secret to failure domains failed.")
+		return nil
+	}
+	identitiesUsingSecret := map[string]bool{}
+	for _, identity := range identities.Items {
+		if isSecret(identity.Spec.SecretRef) {
+			identitiesUsingSecret[identity.Name] = true
+		}
+	}
+
+	fds := &infrav1.CloudStackFailureDomainList{}
+	if err := reconciler.K8sClient.List(ctx, fds); err != nil {
+		log.Error(err, "Listing CloudStackFailureDomains to map a secret to failure domains failed.")
+		return nil
+	}
+	var requests []reconcile.Request
+	for _, fd := range fds.Items {
+		if (fd.Spec.IdentityRef != nil && identitiesUsingSecret[fd.Spec.IdentityRef.Name]) ||
+			(fd.Spec.IdentityRef == nil && isSecret(fd.Spec.ACSEndpoint)) {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&fd)})
+		}
+	}
+	return requests
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package controllers_test
 
 import (
+	"sync/atomic"
+
 	ginkgo "github.com/onsi/ginkgo/v2"
 	gomega "github.com/onsi/gomega"
 	gomock "go.uber.org/mock/gomock"
@@ -26,6 +28,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
 	dummies "sigs.k8s.io/cluster-api-provider-cloudstack/test/dummies/v1beta3"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -33,10 +36,20 @@ import (
 
 var _ = ginkgo.Describe("CloudStackFailureDomainReconciler", func() {
 	ginkgo.Context("With k8s like test environment.", func() {
+		var zoneAllocationState atomic.Value
+
 		ginkgo.BeforeEach(func() {
 			dummies.SetDummyVars()
 			SetupTestEnvironment()                                                                                                                        // Must happen before setting up managers/reconcilers.
 			gomega.Ω(FailureDomainReconciler.SetupWithManager(k8sManager, controller.Options{SkipNameValidation: ptr.To(true)})).Should(gomega.Succeed()) // Register CloudStack FailureDomainReconciler.
+
+			zoneAllocationState.Store(cloud.ZoneAllocationStateEnabled)
+			mockCloudClient.EXPECT().VerifyCredentials().AnyTimes()
+			mockCloudClient.EXPECT().GetZoneAllocationState(gomock.Any()).AnyTimes().DoAndReturn(
+				func(string) (string, error) {
+					return zoneAllocationState.Load().(string), nil
+				})
+			mockCloudClient.EXPECT().VerifyNetwork(gomock.Any()).AnyTimes()
 			// Modify failure domain name the same way the cluster controller would.
 			dummies.CSFailureDomain1.Name = dummies.CSFailureDomain1.Name + "-" + dummies.CSCluster.Name
 
@@ -69,6 +82,43 @@ var _ = ginkgo.Describe("CloudStackFailureDomainReconciler", func() {
 			}, timeout).WithPolling(pollInterval).Should(gomega.BeTrue())
 		})
 
+		ginkgo.It("Should report a zone that was disabled after the failure domain became ready.", func() {
+			gomega.Eventually(func() bool {
+				return getFailuredomainStatus(dummies.CSFailureDomain1)
+			}, timeout).WithPolling(pollInterval).Should(gomega.BeTrue())
+
+			zoneAllocationState.Store(cloud.ZoneAllocationStateDisabled)
+			key := client.ObjectKeyFromObject(dummies.CSFailureDomain1)
+			gomega.Eventually(func() error { // Trigger another reconciliation.
+				tempfd := &infrav1.CloudStackFailureDomain{}
+				if err := k8sClient.Get(ctx, key, tempfd); err != nil {
+					return err
+				}
+				tempfd.Annotations = map[string]string{"health-check": "again"}
+				return k8sClient.Update(ctx, tempfd)
+			}, timeout).WithPolling(pollInterval).Should(gomega.Succeed())
+
+			gomega.Eventually(func() bool {
+				tempfd := &infrav1.CloudStackFailureDomain{}
+				if err := k8sClient.Get(ctx, key, tempfd); err != nil {
+					return false
+				}
+				return conditions.IsFalse(tempfd, infrav1.ZoneEnabledCondition) && tempfd.Status.Ready
+			}, timeout).WithPolling(pollInterval).Should(gomega.BeTrue())
+		})
+
+		ginkgo.It("Should consider a zone whose allocation state isn't reported enabled.", func() {
+			zoneAllocationState.Store("")
+			key := client.ObjectKeyFromObject(dummies.CSFailureDomain1)
+			gomega.Eventually(func() bool {
+				tempfd := &infrav1.CloudStackFailureDomain{}
+				if err := k8sClient.Get(ctx, key, tempfd); err != nil {
+					return false
+				}
+				return conditions.IsTrue(tempfd, infrav1.ZoneEnabledCondition) && tempfd.Status.Ready
+			}, timeout).WithPolling(pollInterval).Should(gomega.BeTrue())
+		})
+
 		ginkgo.DescribeTable("Should function in different replicas conditions",
 			func(shouldDeleteVM bool, specReplicas, statusReplicas, statusReadyReplicas *int32, statusReady *bool, controlPlaneReady bool) {
 				gomega.Eventually(func() bool {
@@ -20,10 +20,16 @@ import (
 	"context"
 	"strings"
 
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
 
 	"github.com/pkg/errors"
 	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
@@ -80,8 +86,10 @@ func (r *CloudStackIsoNetReconciliationRunner) Reconcile() (retRes ctrl.Result,
 		return r.RequeueWithMessage("Zone ID not resolved yet.")
 	}
 	if err := r.CSUser.GetOrCreateIsolatedNetwork(r.FailureDomain, r.ReconciliationSubject, r.CSCluster); err != nil {
+		r.SetIsoNetConditions(err)
 		return ctrl.Result{}, err
 	}
+	r.SetIsoNetConditions(nil)
 	// Tag the created network.
 	if err := r.CSUser.AddClusterTag(cloud.ResourceTypeNetwork, r.ReconciliationSubject.Spec.ID, r.CSCluster); err != nil {
 		return ctrl.Result{}, errors.Wrapf(err, "tagging network with id %s", r.ReconciliationSubject.Spec.ID)
@@ -94,6 +102,35 @@ func (r *CloudStackIsoNetReconciliationRunner) Reconcile() (retRes ctrl.Result,
 	return ctrl.Result{}, nil
 }
 
+// SetIsoNetConditions marks the isolated network's conditions from the steps GetOrCreateIsolatedNetwork completed.
+// The steps run in order, so on error the first incomplete step is the one that failed.
+func (r *CloudStackIsoNetReconciliationRunner) SetIsoNetConditions(err error) {
+	isoNet := r.ReconciliationSubject
+	natted := isoNet.Status.RoutingMode == ""
+	steps := []struct {
+		condition clusterv1.ConditionType
+		reason    string
+		done      bool
+		applies   bool
+	}{
+		{infrav1.NetworkReadyCondition, infrav1.NetworkCreationFailedReason, isoNet.Spec.ID != "", true},
+		{infrav1.PublicIPAssociatedCondition, infrav1.PublicIPAssociationFailedReason, isoNet.Status.PublicIPID != "", natted},
+		{infrav1.LoadBalancerRuleReadyCondition, infrav1.LoadBalancerRuleFailedReason, isoNet.Status.LBRuleID != "", natted},
+		{infrav1.FirewallRulesOpenedCondition, infrav1.FirewallRulesFailedReason, err == nil, true},
+	}
+
+	for _, step := range steps {
+		if !step.applies {
+			conditions.Delete(isoNet, step.condition)
+		} else if err == nil || step.done {
+			conditions.MarkTrue(isoNet, step.condition)
+		} else { // Leave conditions of later steps as they were.
+			conditions.MarkFalse(isoNet, step.condition, step.reason, clusterv1.ConditionSeverityError, err.Error())
+			return
+		}
+	}
+}
+
 func (r *CloudStackIsoNetReconciliationRunner) ReconcileDelete() (retRes ctrl.Result, retErr error) {
 	r.Log.Info("Deleting IsolatedNetwork.")
 	if err := r.CSUser.DisposeIsoNetResources(r.ReconciliationSubject, r.CSCluster); err != nil {
@@ -110,5 +147,31 @@ func (reconciler *CloudStackIsoNetReconciler) SetupWithManager(mgr ctrl.Manager,
 	return ctrl.NewControllerManagedBy(mgr).
 		WithOptions(opts).
 		For(&infrav1.CloudStackIsolatedNetwork{}).
+		// Reconcile the cluster's isolated networks on CloudStackCluster spec changes to apply load balancer changes.
+		Watches(
+			&infrav1.CloudStackCluster{},
+			handler.EnqueueRequestsFromMapFunc(reconciler.csClusterToIsoNets),
+			builder.WithPredicates(predicate.GenerationChangedPredicate{}),
+		).
 		Complete(reconciler)
 }
+
+// csClusterToIsoNets maps a CloudStackCluster to reconcile requests for the isolated networks of its cluster.
+func (reconciler *CloudStackIsoNetReconciler) csClusterToIsoNets(ctx context.Context, o client.Object) []ctrl.Request {
+	clusterName := o.GetLabels()[clusterv1.ClusterNameLabel]
+	if clusterName == "" {
+		return nil
+	}
+	isoNets := &infrav1.CloudStackIsolatedNetworkList{}
+	if err := reconciler.K8sClient.List(ctx, isoNets,
+		client.InNamespace(o.GetNamespace()),
+		client.MatchingLabels{clusterv1.ClusterNameLabel: clusterName},
+	); err != nil {
+		return nil
+	}
+	requests := make([]ctrl.Request, 0, len(isoNets.Items))
+	for _, isoNet := range isoNets.Items {
+		requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&isoNet)})
+	}
+	return requests
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package controllers_test
 
 import (
+	"github.com/pkg/errors"
+
 	ginkgo "github.com/onsi/ginkgo/v2"
 	gomega "github.com/onsi/gomega"
 	g "go.uber.org/mock/gomock"
@@ -24,6 +26,8 @@ import (
 	"k8s.io/utils/ptr"
 	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
 	dummies "sigs.k8s.io/cluster-api-provider-cloudstack/test/dummies/v1beta3"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -75,5 +79,26 @@ var _ = ginkgo.Describe("CloudStackIsolatedNetworkReconciler", func() {
 			gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
 			gomega.Ω(res.RequeueAfter).ShouldNot(gomega.BeZero())
 		})
+
+		ginkgo.It("Should mark the first incomplete step's condition false when isolated network setup fails.", func() {
+			mockCloudClient.EXPECT().GetOrCreateIsolatedNetwork(g.Any(), g.Any(), g.Any()).Return(errors.New("no capacity"))
+
+			dummies.CSISONet1.Spec.FailureDomainName = dummies.CSFailureDomain2.Spec.Name
+			gomega.Ω(fakeCtrlClient.Create(ctx, dummies.CSFailureDomain2)).Should(gomega.Succeed())
+			gomega.Ω(fakeCtrlClient.Create(ctx, dummies.CSISONet1)).Should(gomega.Succeed())
+
+			requestNamespacedName := types.NamespacedName{Namespace: dummies.ClusterNameSpace, Name: dummies.CSISONet1.Name}
+			_, err := IsoNetReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: requestNamespacedName})
+			gomega.Ω(err).Should(gomega.HaveOccurred())
+
+			tempIsoNet := &infrav1.CloudStackIsolatedNetwork{}
+			gomega.Ω(fakeCtrlClient.Get(ctx, requestNamespacedName, tempIsoNet)).Should(gomega.Succeed())
+			// The dummy network already has an ID, so the public IP association is the step that failed.
+			gomega.Ω(conditions.IsTrue(tempIsoNet, infrav1.NetworkReadyCondition)).Should(gomega.BeTrue())
+			gomega.Ω(conditions.IsFalse(tempIsoNet, infrav1.PublicIPAssociatedCondition)).Should(gomega.BeTrue())
+			gomega.Ω(conditions.GetReason(tempIsoNet, infrav1.PublicIPAssociatedCondition)).Should(gomega.Equal(infrav1.PublicIPAssociationFailedReason))
+			gomega.Ω(conditions.Has(tempIsoNet, infrav1.LoadBalancerRuleReadyCondition)).Should(gomega.BeFalse())
+			gomega.Ω(conditions.IsFalse(tempIsoNet, clusterv1.ReadyCondition)).Should(gomega.BeTrue())
+		})
 	})
 })
@@ -19,17 +19,19 @@ package controllers
 import (
 	"context"
 	"fmt"
-	"math/rand"
+	"net"
 	"reflect"
 	"regexp"
-	"time"
+	"slices"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/builder"
@@ -44,6 +46,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-cloudstack/controllers/utils"
 	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
 )
 
 var (
@@ -62,15 +65,26 @@ const (
 	CSMachineStateCheckerCreationSuccess       = "CloudStackMachineStateChecker created"
 	CSMachineDeletionMessage                   = "Deleting CloudStack Machine %s"
 	CSMachineDeletionInstanceIDNotFoundMessage = "Deleting CloudStack Machine %s instanceID not found"
+	CSMachineResizingMessage                   = "Resizing CloudStack instance to offering %s"
+	CSMachineResizedMessage                    = "CloudStack instance resized from offering %s to %s"
+	CSMachineResizeFailed                      = "Resizing CloudStack instance failed: %s"
+	CSMachineResizeWaitingMessage              = "Waiting for machine %s cloned from the same template to be resized"
+	CSMachineInsufficientCapacityMessage       = "Insufficient capacity in failure domain %s, placing machine in another one"
+	CSMachineAdoptionSuccess                   = "CloudStack instance %s adopted"
+	CSMachineAdoptionFailed                    = "Adopting CloudStack instance failed: %s"
 )
 
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=cloudstackmachines,verbs=get;list;watch;create;update;patch;delete
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=cloudstackmachines/status,verbs=get;update;patch
 // +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=cloudstackmachines/finalizers,verbs=update
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=cloudstackmachinetemplates,verbs=get;list;watch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines;machines/status,verbs=get;list;watch
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machines,verbs=patch
 // +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinesets,verbs=get;list;watch
 // +kubebuilder:rbac:groups=controlplane.cluster.x-k8s.io,resources=kubeadmcontrolplanes,verbs=get;list;watch
 // +kubebuilder:rbac:groups="",resources=events,verbs=create;patch
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddressclaims,verbs=get;list;watch;create;delete
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddresses,verbs=get;list;watch
 
 // CloudStackMachineReconciliationRunner is a ReconciliationRunner with extensions specific to CloudStack machine reconciliation.
 type CloudStackMachineReconciliationRunner struct {
@@ -123,8 +137,13 @@ func (r *CloudStackMachineReconciliationRunner) Reconcile() (retRes ctrl.Result,
 			func() string { return r.IsoNetMetaName(r.FailureDomain.Spec.Zone.Network.Name) }),
 		r.RunIf(func() bool { return r.FailureDomain.Spec.Zone.Network.Type == cloud.NetworkTypeIsolated },
 			r.CheckPresent(map[string]client.Object{"CloudStackIsolatedNetwork": r.IsoNet})),
+		r.LeaveFailureDomainIfOutOfCapacity,
 		r.ConsiderAffinity,
-		r.GetOrCreateVMInstance,
+		r.ClaimIPAddresses,
+		r.RunIf(func() bool { return r.ReconciliationSubject.Spec.Adopt }, r.AdoptVMInstance),
+		r.RunIf(func() bool { return !r.ReconciliationSubject.Spec.Adopt }, r.GetOrCreateVMInstance),
+		r.ResizeVMInstance,
+		r.SetPlacementLabels,
 		r.RequeueIfInstanceNotRunning,
 		r.AddToLBIfNeeded,
 		r.GetOrCreateMachineStateChecker,
@@ -165,12 +184,68 @@ func (r *CloudStackMachineReconciliationRunner) ConsiderAffinity() (ctrl.Result,
 		Namespace: r.AffinityGroup.Namespace,
 	}
 	if !r.AffinityGroup.Status.Ready {
+		conditions.MarkFalse(r.ReconciliationSubject, infrav1.InstanceProvisionedCondition,
+			infrav1.WaitingForAffinityGroupReason, clusterv1.ConditionSeverityInfo, "")
 		return r.RequeueWithMessage("Required affinity group not ready.")
 	}
 
 	return ctrl.Result{}, nil
 }
 
+// ClaimIPAddresses claims an address from each IPAM pool referenced by the machine's networks and sets the allocated
+// addresses on the networks. It requeues until all addresses have been allocated.
+func (r *CloudStackMachineReconciliationRunner) ClaimIPAddresses() (ctrl.Result, error) {
+	if r.ReconciliationSubject.Spec.InstanceID != nil { // Addresses are only needed to deploy the VM.
+		return ctrl.Result{}, nil
+	}
+
+	allocated := true
+	for i := range r.ReconciliationSubject.Spec.Networks {
+		network := &r.ReconciliationSubject.Spec.Networks[i]
+		for j, poolRef := range network.AddressesFromPools {
+			claim := &ipamv1.IPAddressClaim{}
+			name := utils.IPAddressClaimName(r.ReconciliationSubject.Name, i, j)
+			if err := r.GetOrCreateIPAddressClaim(name, poolRef, claim); err != nil {
+				return ctrl.Result{}, err
+			}
+			address, err := r.GetClaimedIPAddress(claim)
+			if err != nil {
+				return ctrl.Result{}, err
+			} else if address == "" {
+				allocated = false
+				continue
+			}
+
+			if ip := net.ParseIP(address); ip == nil {
+				return ctrl.Result{}, errors.Errorf("invalid address %q allocated to IPAddressClaim %s", address, name)
+			} else if ip.To4() != nil {
+				network.IP = address
+			} else {
+				network.IP6 = address
+			}
+		}
+	}
+
+	if !allocated {
+		conditions.MarkFalse(r.ReconciliationSubject, infrav1.InstanceProvisionedCondition,
+			infrav1.WaitingForIPAddressesReason, clusterv1.ConditionSeverityInfo, "")
+		return r.RequeueWithMessage("IP addresses not yet allocated.")
+	}
+	return ctrl.Result{}, nil
+}
+
+// DeleteIPAddressClaims deletes the machine's IPAddressClaims, releasing the addresses back to their pools.
+func (r *CloudStackMachineReconciliationRunner) DeleteIPAddressClaims() error {
+	for i, network := range r.ReconciliationSubject.Spec.Networks {
+		for j := range network.AddressesFromPools {
+			if err := r.DeleteIPAddressClaim(utils.IPAddressClaimName(r.ReconciliationSubject.Name, i, j)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
 // SetFailureDomainOnCSMachine sets the failure domain the machine should launch in.
 func (r *CloudStackMachineReconciliationRunner) SetFailureDomainOnCSMachine() (retRes ctrl.Result, reterr error) {
 	if r.ReconciliationSubject.Spec.FailureDomainName == "" {
@@ -181,11 +256,11 @@ func (r *CloudStackMachineReconciliationRunner) SetFailureDomainOnCSMachine() (r
 				*r.CAPIMachine.Spec.FailureDomain != "") { // Or potentially another machine controller specified.
 			name = *r.CAPIMachine.Spec.FailureDomain
 			r.ReconciliationSubject.Spec.FailureDomainName = *r.CAPIMachine.Spec.FailureDomain
-		} else { // Not a control plane machine. Place randomly.
-			// Set a random seed for randomly placing CloudStackMachines in Zones.
-			randSeed := rand.New(rand.NewSource(time.Now().UnixNano())) // #nosec G404 -- weak crypt rand doesn't matter here.
-			randNum := (randSeed.Int() % len(r.CSCluster.Spec.FailureDomains))
-			name = r.CSCluster.Spec.FailureDomains[randNum].Name
+		} else { // Not a control plane machine. Place with the cluster's placement strategy.
+			var err error
+			if name, err = r.PlaceMachineInFailureDomain(r.ReconciliationSubject); err != nil {
+				return ctrl.Result{}, errors.Wrap(err, "placing machine in a failure domain")
+			}
 		}
 		r.ReconciliationSubject.Spec.FailureDomainName = name
 		r.ReconciliationSubject.Labels[infrav1.FailureDomainLabelName] = infrav1.FailureDomainHashedMetaName(name, r.CAPICluster.Name)
@@ -193,6 +268,45 @@ func (r *CloudStackMachineReconciliationRunner) SetFailureDomainOnCSMachine() (r
 	return ctrl.Result{}, nil
 }
 
+// LeaveFailureDomainIfOutOfCapacity removes a machine from a failure domain its instance couldn't be deployed in for
+// lack of capacity, so it's placed in another one. An instance the failed deployment left behind is destroyed first.
+func (r *CloudStackMachineReconciliationRunner) LeaveFailureDomainIfOutOfCapacity() (retRes ctrl.Result, reterr error) {
+	csMachine := r.ReconciliationSubject
+	if !slices.Contains(csMachine.Status.InsufficientCapacityFailureDomains, csMachine.Spec.FailureDomainName) ||
+		!r.failureDomainReplaceable() {
+		return ctrl.Result{}, nil
+	}
+
+	if csMachine.Spec.InstanceID != nil {
+		if err := r.CSUser.DestroyVMInstance(csMachine); err != nil {
+			if err.Error() == "VM deletion in progress" {
+				return r.RequeueWithMessage("Destroying instance that failed to deploy.")
+			}
+			return ctrl.Result{}, err
+		}
+		csMachine.Spec.InstanceID = nil
+	}
+	// Affinity groups CAPC manages are specific to a failure domain.
+	if ref := csMachine.Spec.AffinityGroupRef; ref != nil {
+		if agName, err := utils.GenerateAffinityGroupName(*csMachine, r.CAPIMachine, r.CAPICluster); err == nil && ref.Name == agName {
+			csMachine.Spec.AffinityGroupRef = nil
+		}
+	}
+	r.Log.Info("Leaving failure domain for lack of capacity.", "failureDomain", csMachine.Spec.FailureDomainName)
+	csMachine.Spec.FailureDomainName = ""
+	delete(csMachine.Labels, infrav1.FailureDomainLabelName)
+	return r.RequeueWithMessage("Placing machine in another failure domain.")
+}
+
+// failureDomainReplaceable reports whether the machine may be placed in another failure domain. That's not the case
+// when CAPI assigned the failure domain, or the machine is pinned to hosts of the failure domain's zone.
+func (r *CloudStackMachineReconciliationRunner) failureDomainReplaceable() bool {
+	if r.CAPIMachine != nil && r.CAPIMachine.Spec.FailureDomain != nil && *r.CAPIMachine.Spec.FailureDomain != "" {
+		return false
+	}
+	return r.ReconciliationSubject.Spec.Placement == nil && len(r.CSCluster.Spec.FailureDomains) > 1
+}
+
 // DeleteMachineIfFailuredomainNotExist delete CAPI machine if machine is deployed in a failuredomain that does not exist anymore.
 func (r *CloudStackMachineReconciliationRunner) DeleteMachineIfFailuredomainNotExist() (retRes ctrl.Result, reterr error) {
 	if r.CAPIMachine.Spec.FailureDomain == nil {
@@ -221,8 +335,11 @@ func (r *CloudStackMachineReconciliationRunner) DeleteMachineIfFailuredomainNotE
 func (r *CloudStackMachineReconciliationRunner) GetOrCreateVMInstance() (retRes ctrl.Result, reterr error) {
 	if r.CAPIMachine.Spec.Bootstrap.DataSecretName == nil {
 		r.Recorder.Event(r.ReconciliationSubject, "Normal", "Creating", BootstrapDataNotReady)
+		conditions.MarkFalse(r.ReconciliationSubject, infrav1.BootstrapDataReadyCondition,
+			infrav1.WaitingForBootstrapDataReason, clusterv1.ConditionSeverityInfo, "")
 		return r.RequeueWithMessage(BootstrapDataNotReady + ".")
 	}
+	conditions.MarkTrue(r.ReconciliationSubject, infrav1.BootstrapDataReadyCondition)
 	r.Log.Info("Got Bootstrap DataSecretName.")
 
 	// Get the kubeadm bootstrap secret for this machine.
@@ -246,10 +363,22 @@ func (r *CloudStackMachineReconciliationRunner) GetOrCreateVMInstance() (retRes
 		}
 	}
 
-	userData := processCustomMetadata(data, r)
+	userData := processCustomMetadata(data, r.CAPIMachine.Name, r.FailureDomain.Spec.Name)
 	err := r.CSUser.GetOrCreateVMInstance(r.ReconciliationSubject, r.CAPIMachine, r.CSCluster, r.FailureDomain, r.AffinityGroup, userData)
-	if err != nil {
+	if err != nil && err.Error() == "VM data disk attachment in progress" {
+		return r.RequeueWithMessage("VM data disk attachment in progress.")
+	} else if cloud.IsInsufficientCapacityError(err) && r.failureDomainReplaceable() {
+		fdName := r.ReconciliationSubject.Spec.FailureDomainName
+		r.ReconciliationSubject.Status.InsufficientCapacityFailureDomains = append(
+			r.ReconciliationSubject.Status.InsufficientCapacityFailureDomains, fdName)
+		r.Recorder.Eventf(r.ReconciliationSubject, "Warning", "Creating", CSMachineInsufficientCapacityMessage, fdName)
+		conditions.MarkFalse(r.ReconciliationSubject, infrav1.InstanceProvisionedCondition,
+			infrav1.InsufficientCapacityReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return r.LeaveFailureDomainIfOutOfCapacity()
+	} else if err != nil {
 		r.Recorder.Eventf(r.ReconciliationSubject, "Warning", "Creating", CSMachineCreationFailed, err.Error())
+		conditions.MarkFalse(r.ReconciliationSubject, infrav1.InstanceProvisionedCondition,
+			infrav1.InstanceProvisionFailedReason, clusterv1.ConditionSeverityError, err.Error())
 	}
 	if err == nil && !controllerutil.ContainsFinalizer(r.ReconciliationSubject, infrav1.MachineFinalizer) { // Fetched or Created?
 		// Adding a finalizer will make reconcile-delete try to destroy the associated VM through instanceID.
@@ -263,11 +392,153 @@ func (r *CloudStackMachineReconciliationRunner) GetOrCreateVMInstance() (retRes
 	return ctrl.Result{}, err
 }
 
-func processCustomMetadata(data []byte, r *CloudStackMachineReconciliationRunner) string {
+// AdoptVMInstance takes over the existing instance of a machine with Adopt set, instead of deploying one. The instance
+// is already bootstrapped, so the bootstrap data isn't waited for.
+func (r *CloudStackMachineReconciliationRunner) AdoptVMInstance() (retRes ctrl.Result, reterr error) {
+	csMachine := r.ReconciliationSubject
+	if err := r.CSUser.AdoptVMInstance(csMachine, r.CSCluster, r.FailureDomain); err != nil {
+		r.Recorder.Eventf(csMachine, "Warning", "Adopting", CSMachineAdoptionFailed, err.Error())
+		conditions.MarkFalse(csMachine, infrav1.InstanceProvisionedCondition,
+			infrav1.InstanceAdoptionFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		return ctrl.Result{}, err
+	}
+	if !controllerutil.ContainsFinalizer(csMachine, infrav1.MachineFinalizer) {
+		controllerutil.AddFinalizer(csMachine, infrav1.MachineFinalizer)
+		r.Recorder.Eventf(csMachine, "Normal", "Adopted", CSMachineAdoptionSuccess, *csMachine.Spec.InstanceID)
+		r.Log.Info("Adopted instance", "instanceID", *csMachine.Spec.InstanceID)
+	}
+	return ctrl.Result{}, nil
+}
+
+// ResizeVMInstance scales the instance of a machine that allows in-place resizing when its offering changed, either
+// in its spec or through the InPlaceResizeOfferingAnnotation of the template it was cloned from.
+func (r *CloudStackMachineReconciliationRunner) ResizeVMInstance() (retRes ctrl.Result, reterr error) {
+	csMachine := r.ReconciliationSubject
+	if csMachine.Spec.InPlaceResize == "" {
+		return ctrl.Result{}, nil
+	}
+	if waitingFor, err := r.applyTemplateResizeOffering(); err != nil {
+		return ctrl.Result{}, err
+	} else if waitingFor != "" {
+		r.Recorder.Eventf(csMachine, "Normal", "Resizing", CSMachineResizeWaitingMessage, waitingFor)
+		return r.RequeueWithMessage("Waiting for another machine to be resized.", "machine", waitingFor)
+	}
+
+	previousOffering := csMachine.Status.ServiceOfferingName
+	if err := r.CSUser.ResizeVMInstance(csMachine, r.FailureDomain); err != nil {
+		if err.Error() == "VM resize in progress" {
+			r.Recorder.Eventf(csMachine, "Normal", "Resizing", CSMachineResizingMessage, offeringName(csMachine.Spec.Offering))
+			return r.RequeueWithMessage("VM resize in progress.")
+		}
+		r.Recorder.Eventf(csMachine, "Warning", "Resizing", CSMachineResizeFailed, err.Error())
+		return ctrl.Result{}, err
+	}
+	if previousOffering != "" && csMachine.Status.ServiceOfferingName != previousOffering {
+		r.Recorder.Eventf(csMachine, "Normal", "Resized", CSMachineResizedMessage,
+			previousOffering, csMachine.Status.ServiceOfferingName)
+		r.Log.Info("Resized instance", "from", previousOffering, "to", csMachine.Status.ServiceOfferingName)
+	}
+	return ctrl.Result{}, nil
+}
+
+// SetPlacementLabels labels the CAPI Machine with the pod, cluster, host and hypervisor its instance runs on. CAPI syncs
+// the labels to the machine's Node, where they can be used for scheduling. Names that aren't valid label values are
+// left out.
+func (r *CloudStackMachineReconciliationRunner) SetPlacementLabels() (retRes ctrl.Result, reterr error) {
+	if r.CAPIMachine == nil || r.CAPIMachine.Name == "" {
+		return ctrl.Result{}, nil
+	}
+	status := r.ReconciliationSubject.Status
+	labels := map[string]string{infrav1.HypervisorLabel: status.Hypervisor}
+	if status.Placement != nil {
+		labels[infrav1.PodLabel] = status.Placement.Pod.Name
+		labels[infrav1.ClusterLabel] = status.Placement.Cluster.Name
+		labels[infrav1.HostLabel] = status.Placement.Host.Name
+	}
+
+	patch := client.MergeFrom(r.CAPIMachine.DeepCopy())
+	changed := false
+	for key, value := range labels {
+		if value == "" || len(validation.IsValidLabelValue(value)) > 0 || r.CAPIMachine.Labels[key] == value {
+			continue
+		}
+		if r.CAPIMachine.Labels == nil {
+			r.CAPIMachine.Labels = map[string]string{}
+		}
+		r.CAPIMachine.Labels[key] = value
+		changed = true
+	}
+	if !changed {
+		return ctrl.Result{}, nil
+	}
+	if err := r.K8sClient.Patch(r.RequestCtx, r.CAPIMachine, patch); err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "labeling CAPI Machine with the placement of its instance")
+	}
+	return ctrl.Result{}, nil
+}
+
+// applyTemplateResizeOffering sets the machine's offering to the one named by the InPlaceResizeOfferingAnnotation of
+// the CloudStackMachineTemplate it was cloned from, if any. The machines cloned from a template are resized one at a
+// time, in the order of their names, so that stopping them doesn't take down a control plane or machine deployment.
+// The name of the machine to wait for is returned while another one is due to be resized first.
+func (r *CloudStackMachineReconciliationRunner) applyTemplateResizeOffering() (string, error) {
+	csMachine := r.ReconciliationSubject
+	templateName := csMachine.Annotations[clusterv1.TemplateClonedFromNameAnnotation]
+	if templateName == "" || csMachine.Annotations[clusterv1.TemplateClonedFromGroupKindAnnotation] !=
+		infrav1.GroupVersion.WithKind("CloudStackMachineTemplate").GroupKind().String() {
+		return "", nil
+	}
+
+	template := &infrav1.CloudStackMachineTemplate{}
+	key := client.ObjectKey{Namespace: csMachine.Namespace, Name: templateName}
+	if err := r.K8sClient.Get(r.RequestCtx, key, template); err != nil {
+		return "", client.IgnoreNotFound(err)
+	}
+	offering := template.Annotations[infrav1.InPlaceResizeOfferingAnnotation]
+	if offering == "" || offering == csMachine.Spec.Offering.Name {
+		return "", nil
+	}
+
+	csMachines := &infrav1.CloudStackMachineList{}
+	if err := r.K8sClient.List(r.RequestCtx, csMachines, client.InNamespace(csMachine.Namespace)); err != nil {
+		return "", errors.Wrap(err, "listing the machines cloned from the same template")
+	}
+	for _, other := range csMachines.Items {
+		if other.Name == csMachine.Name || other.Annotations[clusterv1.TemplateClonedFromNameAnnotation] != templateName ||
+			!resizePending(&other, offering) {
+			continue
+		}
+		// Machines whose resize started go first, then those whose names come first.
+		if other.Spec.Offering.Name == offering || other.Name < csMachine.Name {
+			return other.Name, nil
+		}
+	}
+	csMachine.Spec.Offering = infrav1.CloudStackResourceIdentifier{Name: offering}
+	return "", nil
+}
+
+// resizePending reports whether a machine allowing in-place resizing has an instance still to be resized to the
+// offering, or being resized to it.
+func resizePending(csMachine *infrav1.CloudStackMachine, offering string) bool {
+	if csMachine.Spec.InPlaceResize == "" || csMachine.Spec.InstanceID == nil || !csMachine.DeletionTimestamp.IsZero() {
+		return false
+	}
+	return csMachine.Status.ServiceOfferingName != offering || csMachine.Status.AsyncJob != nil
+}
+
+// offeringName returns the name of an offering, or its ID if it has no name.
+func offeringName(offering infrav1.CloudStackResourceIdentifier) string {
+	if offering.Name != "" {
+		return offering.Name
+	}
+	return offering.ID
+}
+
+func processCustomMetadata(data []byte, hostname, failureDomainName string) string {
 	// since cloudstack metadata does not allow custom data added into meta_data, following line is a workaround to specify a hostname name
 	// {{ ds.meta_data.hostname }} is expected to be used as a node name when kubelet register a node
-	userData := hostnameMatcher.ReplaceAllString(string(data), r.CAPIMachine.Name)
-	userData = failuredomainMatcher.ReplaceAllString(userData, r.FailureDomain.Spec.Name)
+	userData := hostnameMatcher.ReplaceAllString(string(data), hostname)
+	userData = failuredomainMatcher.ReplaceAllString(userData, failureDomainName)
 	return userData
 }
 
@@ -276,10 +547,13 @@ func (r *CloudStackMachineReconciliationRunner) RequeueIfInstanceNotRunning() (r
 	if r.ReconciliationSubject.Status.InstanceState == "Running" {
 		r.Recorder.Event(r.ReconciliationSubject, "Normal", "Running", MachineInstanceRunning)
 		r.Log.Info(MachineInstanceRunning)
+		conditions.MarkTrue(r.ReconciliationSubject, infrav1.InstanceProvisionedCondition)
 		r.ReconciliationSubject.Status.Ready = true
 	} else if r.ReconciliationSubject.Status.InstanceState == "Error" {
 		r.Recorder.Event(r.ReconciliationSubject, "Warning", "Error", MachineInErrorMessage)
 		r.Log.Info(MachineInErrorMessage, "csMachine", r.ReconciliationSubject.GetName())
+		conditions.MarkFalse(r.ReconciliationSubject, infrav1.InstanceProvisionedCondition,
+			infrav1.InstanceErrorReason, clusterv1.ConditionSeverityError, MachineInErrorMessage)
 		if err := r.K8sClient.Delete(r.RequestCtx, r.CAPIMachine); err != nil {
 			return ctrl.Result{}, err
 		}
@@ -287,6 +561,8 @@ func (r *CloudStackMachineReconciliationRunner) RequeueIfInstanceNotRunning() (r
 	} else {
 		r.Recorder.Eventf(r.ReconciliationSubject, "Warning", r.ReconciliationSubject.Status.InstanceState, MachineNotReadyMessage, r.ReconciliationSubject.Status.InstanceState)
 		r.Log.Info(fmt.Sprintf(MachineNotReadyMessage, r.ReconciliationSubject.Status.InstanceState))
+		conditions.MarkFalse(r.ReconciliationSubject, infrav1.InstanceProvisionedCondition,
+			infrav1.InstanceNotRunningReason, clusterv1.ConditionSeverityInfo, MachineNotReadyMessage, r.ReconciliationSubject.Status.InstanceState)
 		return ctrl.Result{RequeueAfter: utils.RequeueTimeout}, nil
 	}
 	return ctrl.Result{}, nil
@@ -345,6 +621,8 @@ func (r *CloudStackMachineReconciliationRunner) ReconcileDelete() (retRes ctrl.R
 		}
 	}
 	r.Recorder.Eventf(r.ReconciliationSubject, "Normal", "Deleting", CSMachineDeletionMessage, r.ReconciliationSubject.Name)
+	conditions.MarkFalse(r.ReconciliationSubject, infrav1.InstanceProvisionedCondition,
+		clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
 	r.Log.Info("Deleting instance", "instance-id", r.ReconciliationSubject.Spec.InstanceID)
 	// Use CSClient instead of CSUser here to expunge as admin.
 	// The CloudStack-Go API does not return an error, but the VM won't delete with Expunge set if requested by
@@ -356,6 +634,9 @@ func (r *CloudStackMachineReconciliationRunner) ReconcileDelete() (retRes ctrl.R
 		}
 		return ctrl.Result{}, err
 	}
+	if err := r.DeleteIPAddressClaims(); err != nil {
+		return ctrl.Result{}, err
+	}
 
 	controllerutil.RemoveFinalizer(r.ReconciliationSubject, infrav1.MachineFinalizer)
 	r.Log.Info("VM Deleted", "instanceID", r.ReconciliationSubject.Spec.InstanceID)
@@ -402,6 +683,9 @@ func (reconciler *CloudStackMachineReconciler) SetupWithManager(ctx context.Cont
 			),
 		)
 
+	// Watch owned IPAddressClaims so machines waiting for addresses are reconciled once they're allocated.
+	b = b.Owns(&ipamv1.IPAddressClaim{})
+
 	// Watch CAPI machines for changes.
 	// Queues a reconcile request for owned CloudStackMachine on change.
 	// Used to update when bootstrap data becomes available.
@@ -420,6 +704,14 @@ func (reconciler *CloudStackMachineReconciler) SetupWithManager(ctx context.Cont
 			}),
 	)
 
+	// Watch CloudStackMachineTemplates so the machines cloned from a template are resized when its
+	// InPlaceResizeOfferingAnnotation changes.
+	b = b.Watches(
+		&infrav1.CloudStackMachineTemplate{},
+		handler.EnqueueRequestsFromMapFunc(reconciler.csMachineTemplateToCSMachines),
+		builder.WithPredicates(predicate.AnnotationChangedPredicate{}),
+	)
+
 	// Used below, this maps CAPI clusters to CAPC machines
 	csMachineMapper, err := util.ClusterToTypedObjectsMapper(reconciler.K8sClient, &infrav1.CloudStackMachineList{}, mgr.GetScheme())
 	if err != nil {
@@ -442,3 +734,20 @@ func (reconciler *CloudStackMachineReconciler) SetupWithManager(ctx context.Cont
 
 	return nil
 }
+
+// csMachineTemplateToCSMachines maps a CloudStackMachineTemplate to reconcile requests for the machines that allow
+// in-place resizing and were cloned from it.
+func (reconciler *CloudStackMachineReconciler) csMachineTemplateToCSMachines(ctx context.Context, o client.Object) []ctrl.Request {
+	csMachines := &infrav1.CloudStackMachineList{}
+	if err := reconciler.K8sClient.List(ctx, csMachines, client.InNamespace(o.GetNamespace())); err != nil {
+		return nil
+	}
+	var requests []ctrl.Request
+	for _, csMachine := range csMachines.Items {
+		if csMachine.Spec.InPlaceResize != "" &&
+			csMachine.Annotations[clusterv1.TemplateClonedFromNameAnnotation] == o.GetName() {
+			requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&csMachine)})
+		}
+	}
+	return requests
+}
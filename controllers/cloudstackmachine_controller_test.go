@@ -23,6 +23,7 @@ import (
 	ginkgo "github.com/onsi/ginkgo/v2"
 	gomega "github.com/onsi/gomega"
 	gomock "go.uber.org/mock/gomock"
+	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
@@ -30,6 +31,7 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
 	dummies "sigs.k8s.io/cluster-api-provider-cloudstack/test/dummies/v1beta3"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/patch"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -264,5 +266,260 @@ var _ = ginkgo.Describe("CloudStackMachineReconciler", func() {
 				return false
 			}, timeout).Should(gomega.BeTrue())
 		})
+
+		ginkgo.It("Should claim IP addresses from pools before creating the VM instance", func() {
+			key := client.ObjectKeyFromObject(dummies.CSCluster)
+			dummies.CAPIMachine.Name = "someMachine"
+			dummies.CAPIMachine.Spec.Bootstrap.DataSecretName = &dummies.BootstrapSecret.Name
+			dummies.CSMachine1.OwnerReferences = append(dummies.CSMachine1.OwnerReferences, metav1.OwnerReference{
+				Kind:       "Machine",
+				APIVersion: clusterv1.GroupVersion.String(),
+				Name:       dummies.CAPIMachine.Name,
+				UID:        "uniqueness",
+			})
+			dummies.CSMachine1.Spec.InstanceID = nil // Not yet deployed.
+			poolRef := corev1.TypedLocalObjectReference{APIGroup: ptr.To("ipam.cluster.x-k8s.io"), Kind: "InClusterIPPool", Name: "pool1"}
+			dummies.CSMachine1.Spec.Networks = []infrav1.NetworkSpec{{
+				Name:               dummies.CSFailureDomain1.Spec.Zone.Network.Name,
+				AddressesFromPools: []corev1.TypedLocalObjectReference{poolRef},
+			}}
+			gomega.Expect(fakeCtrlClient.Get(ctx, key, dummies.CSCluster)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.CAPIMachine)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.CSMachine1)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.CSFailureDomain1)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.ACSEndpointSecret1)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.BootstrapSecret)).To(gomega.Succeed())
+			setClusterReady(fakeCtrlClient)
+
+			// The claim is created and the VM isn't deployed until it has an address.
+			requestNamespacedName := types.NamespacedName{Namespace: dummies.ClusterNameSpace, Name: dummies.CSMachine1.Name}
+			res, err := MachineReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: requestNamespacedName})
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+			gomega.Expect(res.RequeueAfter).ShouldNot(gomega.BeZero())
+
+			claim := &ipamv1.IPAddressClaim{}
+			claimKey := client.ObjectKey{Namespace: dummies.ClusterNameSpace, Name: dummies.CSMachine1.Name + "-0-0"}
+			gomega.Expect(fakeCtrlClient.Get(ctx, claimKey, claim)).To(gomega.Succeed())
+			gomega.Expect(claim.Spec.PoolRef).To(gomega.Equal(poolRef))
+			gomega.Expect(claim.Spec.ClusterName).To(gomega.Equal(dummies.CAPICluster.Name))
+
+			// Allocate an address to the claim.
+			address := &ipamv1.IPAddress{
+				ObjectMeta: metav1.ObjectMeta{Name: claimKey.Name, Namespace: claimKey.Namespace},
+				Spec: ipamv1.IPAddressSpec{
+					ClaimRef: corev1.LocalObjectReference{Name: claim.Name},
+					PoolRef:  poolRef,
+					Address:  "10.0.0.10",
+					Prefix:   24,
+				},
+			}
+			gomega.Expect(fakeCtrlClient.Create(ctx, address)).To(gomega.Succeed())
+			claim.Status.AddressRef = corev1.LocalObjectReference{Name: address.Name}
+			gomega.Expect(fakeCtrlClient.Update(ctx, claim)).To(gomega.Succeed())
+
+			mockCloudClient.EXPECT().GetOrCreateVMInstance(
+				gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any(), gomock.Any(), gomock.Any()).Do(
+				func(arg1, _, _, _, _, _ interface{}) {
+					csMachine := arg1.(*infrav1.CloudStackMachine)
+					gomega.Expect(csMachine.Spec.Networks[0].IP).To(gomega.Equal("10.0.0.10"))
+					csMachine.Spec.InstanceID = ptr.To("instance-1")
+					csMachine.Status.InstanceState = "Running"
+				})
+			_, err = MachineReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: requestNamespacedName})
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+
+			csMachine := &infrav1.CloudStackMachine{}
+			gomega.Expect(fakeCtrlClient.Get(ctx, requestNamespacedName, csMachine)).To(gomega.Succeed())
+			gomega.Expect(csMachine.Spec.Networks[0].IP).To(gomega.Equal("10.0.0.10"))
+		})
+
+		ginkgo.It("Should adopt the instance of a machine without waiting for bootstrap data", func() {
+			key := client.ObjectKeyFromObject(dummies.CSCluster)
+			dummies.CAPIMachine.Name = "someMachine"
+			dummies.CAPIMachine.Spec.Bootstrap.DataSecretName = nil
+			dummies.CSMachine1.OwnerReferences = append(dummies.CSMachine1.OwnerReferences, metav1.OwnerReference{
+				Kind:       "Machine",
+				APIVersion: clusterv1.GroupVersion.String(),
+				Name:       dummies.CAPIMachine.Name,
+				UID:        "uniqueness",
+			})
+			dummies.CSMachine1.Spec.InstanceID = ptr.To("hand-built-instance")
+			dummies.CSMachine1.Spec.Adopt = true
+			mockCloudClient.EXPECT().AdoptVMInstance(gomock.Any(), gomock.Any(), gomock.Any()).Do(
+				func(arg1, _, _ interface{}) {
+					arg1.(*infrav1.CloudStackMachine).Status.InstanceState = "Running"
+				})
+			gomega.Expect(fakeCtrlClient.Get(ctx, key, dummies.CSCluster)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.CAPIMachine)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.CSMachine1)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.CSFailureDomain1)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.ACSEndpointSecret1)).To(gomega.Succeed())
+			setClusterReady(fakeCtrlClient)
+
+			requestNamespacedName := types.NamespacedName{Namespace: dummies.ClusterNameSpace, Name: dummies.CSMachine1.Name}
+			_, err := MachineReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: requestNamespacedName})
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+
+			csMachine := &infrav1.CloudStackMachine{}
+			gomega.Expect(fakeCtrlClient.Get(ctx, requestNamespacedName, csMachine)).To(gomega.Succeed())
+			gomega.Expect(csMachine.Status.Ready).To(gomega.BeTrue())
+			gomega.Expect(csMachine.Finalizers).To(gomega.ContainElement(infrav1.MachineFinalizer))
+		})
+
+		ginkgo.It("Should label the CAPI Machine with the placement of its instance", func() {
+			key := client.ObjectKeyFromObject(dummies.CSCluster)
+			dummies.CAPIMachine.Name = "someMachine"
+			dummies.CAPIMachine.Spec.Bootstrap.DataSecretName = &dummies.BootstrapSecret.Name
+			dummies.CSMachine1.OwnerReferences = append(dummies.CSMachine1.OwnerReferences, metav1.OwnerReference{
+				Kind:       "Machine",
+				APIVersion: clusterv1.GroupVersion.String(),
+				Name:       dummies.CAPIMachine.Name,
+				UID:        "uniqueness",
+			})
+			mockCloudClient.EXPECT().GetOrCreateVMInstance(
+				gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any(), gomock.Any(), gomock.Any()).Do(
+				func(arg1, _, _, _, _, _ interface{}) {
+					status := &arg1.(*infrav1.CloudStackMachine).Status
+					status.InstanceState = "Running"
+					status.Hypervisor = "KVM"
+					status.Placement = &infrav1.CloudStackPlacement{
+						Pod:     infrav1.CloudStackResourceIdentifier{Name: "pod1"},
+						Cluster: infrav1.CloudStackResourceIdentifier{Name: "nvme"},
+						Host:    infrav1.CloudStackResourceIdentifier{Name: "kvm host 1"},
+					}
+				})
+			gomega.Expect(fakeCtrlClient.Get(ctx, key, dummies.CSCluster)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.CAPIMachine)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.CSMachine1)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.CSFailureDomain1)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.ACSEndpointSecret1)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.BootstrapSecret)).To(gomega.Succeed())
+			setClusterReady(fakeCtrlClient)
+
+			requestNamespacedName := types.NamespacedName{Namespace: dummies.ClusterNameSpace, Name: dummies.CSMachine1.Name}
+			_, err := MachineReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: requestNamespacedName})
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+
+			capiMachine := &clusterv1.Machine{}
+			gomega.Expect(fakeCtrlClient.Get(ctx, client.ObjectKeyFromObject(dummies.CAPIMachine), capiMachine)).To(gomega.Succeed())
+			gomega.Expect(capiMachine.Labels).To(gomega.HaveKeyWithValue(infrav1.PodLabel, "pod1"))
+			gomega.Expect(capiMachine.Labels).To(gomega.HaveKeyWithValue(infrav1.ClusterLabel, "nvme"))
+			gomega.Expect(capiMachine.Labels).To(gomega.HaveKeyWithValue(infrav1.HypervisorLabel, "KVM"))
+			// Host names with spaces aren't valid label values.
+			gomega.Expect(capiMachine.Labels).NotTo(gomega.HaveKey(infrav1.HostLabel))
+		})
+
+		ginkgo.It("Should resize the instance to the offering annotated on its template", func() {
+			key := client.ObjectKeyFromObject(dummies.CSCluster)
+			dummies.CAPIMachine.Name = "someMachine"
+			dummies.CAPIMachine.Spec.Bootstrap.DataSecretName = &dummies.BootstrapSecret.Name
+			dummies.CSMachine1.OwnerReferences = append(dummies.CSMachine1.OwnerReferences, metav1.OwnerReference{
+				Kind:       "Machine",
+				APIVersion: clusterv1.GroupVersion.String(),
+				Name:       dummies.CAPIMachine.Name,
+				UID:        "uniqueness",
+			})
+			dummies.CSMachine1.Spec.InPlaceResize = infrav1.LiveResize
+			dummies.CSMachine1.Annotations = map[string]string{
+				clusterv1.TemplateClonedFromNameAnnotation:      dummies.CSMachineTemplate1.Name,
+				clusterv1.TemplateClonedFromGroupKindAnnotation: "CloudStackMachineTemplate.infrastructure.cluster.x-k8s.io",
+			}
+			dummies.CSMachineTemplate1.Annotations = map[string]string{infrav1.InPlaceResizeOfferingAnnotation: "Large"}
+			mockCloudClient.EXPECT().GetOrCreateVMInstance(
+				gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any(), gomock.Any(), gomock.Any()).Do(
+				func(arg1, _, _, _, _, _ interface{}) {
+					arg1.(*infrav1.CloudStackMachine).Status.InstanceState = "Running"
+					arg1.(*infrav1.CloudStackMachine).Status.ServiceOfferingName = "Small"
+				})
+			mockCloudClient.EXPECT().ResizeVMInstance(gomock.Any(), gomock.Any()).Do(
+				func(arg1, _ interface{}) {
+					csMachine := arg1.(*infrav1.CloudStackMachine)
+					gomega.Expect(csMachine.Spec.Offering).To(gomega.Equal(infrav1.CloudStackResourceIdentifier{Name: "Large"}))
+					csMachine.Status.ServiceOfferingName = "Large"
+				})
+			gomega.Expect(fakeCtrlClient.Get(ctx, key, dummies.CSCluster)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.CAPIMachine)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.CSMachine1)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.CSMachineTemplate1)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.CSFailureDomain1)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.ACSEndpointSecret1)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.BootstrapSecret)).To(gomega.Succeed())
+			setClusterReady(fakeCtrlClient)
+
+			requestNamespacedName := types.NamespacedName{Namespace: dummies.ClusterNameSpace, Name: dummies.CSMachine1.Name}
+			_, err := MachineReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: requestNamespacedName})
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+
+			csMachine := &infrav1.CloudStackMachine{}
+			gomega.Expect(fakeCtrlClient.Get(ctx, requestNamespacedName, csMachine)).To(gomega.Succeed())
+			gomega.Expect(csMachine.Spec.Offering.Name).To(gomega.Equal("Large"))
+			gomega.Eventually(func() bool {
+				for event := range fakeRecorder.Events {
+					if strings.Contains(event, "Normal Resized CloudStack instance resized from offering Small to Large") {
+						return true
+					}
+				}
+				return false
+			}, timeout).Should(gomega.BeTrue())
+		})
+
+		ginkgo.It("Should wait for another machine cloned from the same template to be resized first", func() {
+			dummies.CAPIMachine.Name = "someMachine"
+			dummies.CAPIMachine.Spec.Bootstrap.DataSecretName = &dummies.BootstrapSecret.Name
+			dummies.CSMachine1.OwnerReferences = append(dummies.CSMachine1.OwnerReferences, metav1.OwnerReference{
+				Kind:       "Machine",
+				APIVersion: clusterv1.GroupVersion.String(),
+				Name:       dummies.CAPIMachine.Name,
+				UID:        "uniqueness",
+			})
+			dummies.CSMachine1.Spec.InPlaceResize = infrav1.StopStartResize
+			dummies.CSMachine1.Annotations = map[string]string{
+				clusterv1.TemplateClonedFromNameAnnotation:      dummies.CSMachineTemplate1.Name,
+				clusterv1.TemplateClonedFromGroupKindAnnotation: "CloudStackMachineTemplate.infrastructure.cluster.x-k8s.io",
+			}
+			dummies.CSMachineTemplate1.Annotations = map[string]string{infrav1.InPlaceResizeOfferingAnnotation: "Large"}
+			// The other machine's resize started, but its instance isn't running with the new offering yet.
+			resizing := dummies.CSMachine1.DeepCopy()
+			resizing.Name = "resizing-" + dummies.CSMachine1.Name
+			resizing.OwnerReferences = nil
+			resizing.Spec.InstanceID = ptr.To("resizing-instance-id")
+			resizing.Spec.Offering = infrav1.CloudStackResourceIdentifier{Name: "Large"}
+			mockCloudClient.EXPECT().GetOrCreateVMInstance(
+				gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any(), gomock.Any(), gomock.Any()).Do(
+				func(arg1, _, _, _, _, _ interface{}) {
+					arg1.(*infrav1.CloudStackMachine).Status.InstanceState = "Running"
+					arg1.(*infrav1.CloudStackMachine).Status.ServiceOfferingName = "Small"
+				})
+			mockCloudClient.EXPECT().ResizeVMInstance(gomock.Any(), gomock.Any()).Times(0)
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.CAPIMachine)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.CSMachine1)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, resizing)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.CSMachineTemplate1)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.CSFailureDomain1)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.ACSEndpointSecret1)).To(gomega.Succeed())
+			gomega.Expect(fakeCtrlClient.Create(ctx, dummies.BootstrapSecret)).To(gomega.Succeed())
+			setClusterReady(fakeCtrlClient)
+
+			requestNamespacedName := types.NamespacedName{Namespace: dummies.ClusterNameSpace, Name: dummies.CSMachine1.Name}
+			result, err := MachineReconciler.Reconcile(ctx, ctrl.Request{NamespacedName: requestNamespacedName})
+			gomega.Expect(err).ShouldNot(gomega.HaveOccurred())
+			gomega.Expect(result.RequeueAfter).ShouldNot(gomega.BeZero())
+
+			csMachine := &infrav1.CloudStackMachine{}
+			gomega.Expect(fakeCtrlClient.Get(ctx, requestNamespacedName, csMachine)).To(gomega.Succeed())
+			gomega.Expect(csMachine.Spec.Offering.Name).NotTo(gomega.Equal("Large"))
+			gomega.Eventually(func() bool {
+				for event := range fakeRecorder.Events {
+					if strings.Contains(event, "Waiting for machine "+resizing.Name+" cloned from the same template") {
+						return true
+					}
+				}
+				return false
+			}, timeout).Should(gomega.BeTrue())
+		})
 	})
 })
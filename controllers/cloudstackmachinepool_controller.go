@@ -0,0 +1,659 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/utils/ptr"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	exputil "sigs.k8s.io/cluster-api/exp/util"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/predicates"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/controllers/utils"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	CSMachinePoolInstanceCreated        = "Created CloudStack instance %s in failure domain %s"
+	CSMachinePoolInstanceCreationFailed = "Creating CloudStack instance %s failed: %s"
+	CSMachinePoolInstanceDeleted        = "Destroyed CloudStack instance %s"
+	CSMachinePoolInstanceDeletionFailed = "Destroying CloudStack instance %s failed: %s"
+	CSMachinePoolInstanceLost           = "CloudStack instance %s no longer exists"
+
+	// machinePoolInstanceNameMaxLength keeps generated instance names valid hostnames.
+	machinePoolInstanceNameMaxLength = 63
+)
+
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=cloudstackmachinepools,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=cloudstackmachinepools/status,verbs=get;update;patch
+// +kubebuilder:rbac:groups=infrastructure.cluster.x-k8s.io,resources=cloudstackmachinepools/finalizers,verbs=update
+// +kubebuilder:rbac:groups=cluster.x-k8s.io,resources=machinepools;machinepools/status,verbs=get;list;watch
+
+// CloudStackMachinePoolReconciliationRunner is a ReconciliationRunner with extensions specific to CloudStack machine
+// pool reconciliation.
+type CloudStackMachinePoolReconciliationRunner struct {
+	*utils.ReconciliationRunner
+	ReconciliationSubject *infrav1.CloudStackMachinePool
+	MachinePool           *expv1.MachinePool
+	FailureDomains        map[string]*infrav1.CloudStackFailureDomain // Keyed by failure domain name.
+	AllowedFailureDomains []string                                    // Failure domains new instances may be placed in.
+	AffinityGroups        map[string]*infrav1.CloudStackAffinityGroup // Keyed by failure domain name.
+	BootstrapData         []byte
+	clients               map[string][2]cloud.Client // Admin and user client, keyed by failure domain name.
+}
+
+// CloudStackMachinePoolReconciler reconciles a CloudStackMachinePool object
+type CloudStackMachinePoolReconciler struct {
+	utils.ReconcilerBase
+}
+
+// Initialize a new CloudStackMachinePool reconciliation runner with concrete types and initialized member fields.
+func NewCSMachinePoolReconciliationRunner() *CloudStackMachinePoolReconciliationRunner {
+	// Set concrete type and init pointers.
+	r := &CloudStackMachinePoolReconciliationRunner{ReconciliationSubject: &infrav1.CloudStackMachinePool{}}
+	r.MachinePool = &expv1.MachinePool{}
+	r.FailureDomains = map[string]*infrav1.CloudStackFailureDomain{}
+	r.AffinityGroups = map[string]*infrav1.CloudStackAffinityGroup{}
+	r.clients = map[string][2]cloud.Client{}
+	// Setup the base runner. Initializes pointers and links reconciliation methods.
+	r.ReconciliationRunner = utils.NewRunner(r, r.ReconciliationSubject, "CloudStackMachinePool")
+	return r
+}
+
+// Reconcile is part of the main kubernetes reconciliation loop which aims to
+// move the current state of the cluster closer to the desired state.
+func (reconciler *CloudStackMachinePoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	r := NewCSMachinePoolReconciliationRunner()
+	r.UsingBaseReconciler(reconciler.ReconcilerBase).ForRequest(req).WithRequestCtx(ctx)
+	r.WithAdditionalCommonStages(
+		r.RunIf(func() bool { return r.ReconciliationSubject.GetDeletionTimestamp().IsZero() }, r.GetParent(r.ReconciliationSubject, r.MachinePool)),
+		r.RunIf(func() bool { return r.ReconciliationSubject.GetDeletionTimestamp().IsZero() }, r.RequeueIfCloudStackClusterNotReady),
+		r.GetPoolFailureDomains)
+	return r.RunBaseReconciliationStages()
+}
+
+func (r *CloudStackMachinePoolReconciliationRunner) Reconcile() (ctrl.Result, error) {
+	defer r.SetPoolStatus()
+	return r.RunReconciliationStages(
+		r.GetBootstrapData,
+		r.SetTemplateHash,
+		r.RefreshInstances,
+		r.ScaleInstances,
+		r.DeployPendingInstances,
+	)
+}
+
+// GetPoolFailureDomains fetches the failure domains the pool may place instances in, as well as the failure domains
+// of existing instances.
+func (r *CloudStackMachinePoolReconciliationRunner) GetPoolFailureDomains() (ctrl.Result, error) {
+	r.AllowedFailureDomains = nil
+	for _, fdSpec := range r.CSCluster.Spec.FailureDomains {
+		if len(r.MachinePool.Spec.FailureDomains) == 0 || slices.Contains(r.MachinePool.Spec.FailureDomains, fdSpec.Name) {
+			r.AllowedFailureDomains = append(r.AllowedFailureDomains, fdSpec.Name)
+		}
+	}
+
+	names := append([]string{}, r.AllowedFailureDomains...)
+	for _, instance := range r.ReconciliationSubject.Status.Instances {
+		names = append(names, instance.FailureDomainName)
+	}
+	clusterName := r.ReconciliationSubject.Labels[clusterv1.ClusterNameLabel]
+	for _, name := range names {
+		if _, found := r.FailureDomains[name]; found {
+			continue
+		}
+		fd := &infrav1.CloudStackFailureDomain{}
+		key := client.ObjectKey{Namespace: r.Request.Namespace, Name: infrav1.FailureDomainHashedMetaName(name, clusterName)}
+		if err := r.K8sClient.Get(r.RequestCtx, key, fd); err != nil {
+			if client.IgnoreNotFound(err) != nil {
+				return ctrl.Result{}, errors.Wrapf(err, "failed to get failure domain with name %s", name)
+			}
+			continue
+		}
+		r.FailureDomains[name] = fd
+	}
+	return ctrl.Result{}, nil
+}
+
+// GetBootstrapData fetches the MachinePool's bootstrap data, requeueing until it is available.
+func (r *CloudStackMachinePoolReconciliationRunner) GetBootstrapData() (ctrl.Result, error) {
+	dataSecretName := r.MachinePool.Spec.Template.Spec.Bootstrap.DataSecretName
+	if dataSecretName == nil {
+		conditions.MarkFalse(r.ReconciliationSubject, infrav1.BootstrapDataReadyCondition,
+			infrav1.WaitingForBootstrapDataReason, clusterv1.ConditionSeverityInfo, "")
+		return r.RequeueWithMessage(BootstrapDataNotReady + ".")
+	}
+
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: r.MachinePool.Namespace, Name: *dataSecretName}
+	if err := r.K8sClient.Get(r.RequestCtx, key, secret); err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "getting bootstrap secret %s", *dataSecretName)
+	}
+	data, present := secret.Data["value"]
+	if !present {
+		return ctrl.Result{}, errors.New("bootstrap secret data not yet set")
+	}
+	r.BootstrapData = data
+	conditions.MarkTrue(r.ReconciliationSubject, infrav1.BootstrapDataReadyCondition)
+	return ctrl.Result{}, nil
+}
+
+// SetTemplateHash records the hash of the pool's current template, which identifies outdated instances.
+func (r *CloudStackMachinePoolReconciliationRunner) SetTemplateHash() (ctrl.Result, error) {
+	hash, err := machinePoolTemplateHash(r.ReconciliationSubject.Spec.Template.Spec)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	r.ReconciliationSubject.Status.TemplateHash = hash
+	// Instances are only created once the finalizer is in place, so they are always cleaned up.
+	controllerutil.AddFinalizer(r.ReconciliationSubject, infrav1.MachinePoolFinalizer)
+	return ctrl.Result{}, nil
+}
+
+// RefreshInstances updates the status of each of the pool's instances from CloudStack and forgets instances that
+// have been removed outside of the controller.
+func (r *CloudStackMachinePoolReconciliationRunner) RefreshInstances() (ctrl.Result, error) {
+	var kept []infrav1.CloudStackMachinePoolInstance
+	for _, instance := range r.ReconciliationSubject.Status.Instances {
+		_, user, err := r.clientsFor(instance.FailureDomainName)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if user == nil { // The failure domain is gone, so the instance can't be looked up or destroyed.
+			r.Recorder.Eventf(r.ReconciliationSubject, "Warning", "Lost", CSMachinePoolInstanceLost, instance.Name)
+			continue
+		}
+
+		if job := instance.AsyncJob; job != nil && job.Command == cloud.DeployVMCommand {
+			done, err := user.QueryAsyncJob(job.ID)
+			if done {
+				// A failed VM ends up in error state and is replaced by ScaleInstances.
+				instance.AsyncJob = nil
+				if err != nil {
+					r.Recorder.Eventf(r.ReconciliationSubject, "Warning", "Creating", CSMachinePoolInstanceCreationFailed, instance.Name, err.Error())
+				}
+			} else if err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+
+		csMachine := r.machineForInstance(instance)
+		if err := user.ResolveVMInstanceDetails(csMachine); err != nil {
+			if !utils.ContainsNoMatchSubstring(err) {
+				return ctrl.Result{}, err
+			}
+			if instance.InstanceID != "" {
+				r.Recorder.Eventf(r.ReconciliationSubject, "Warning", "Lost", CSMachinePoolInstanceLost, instance.Name)
+				continue
+			}
+		}
+		kept = append(kept, updateInstanceFromMachine(instance, csMachine))
+	}
+	r.ReconciliationSubject.Status.Instances = kept
+	return ctrl.Result{}, nil
+}
+
+// ScaleInstances adds instances created from the current template until the desired number of replicas is reached,
+// and destroys surplus or outdated instances once enough up-to-date instances are running.
+//
+// New instances are recorded in the status first and only deployed on the next reconciliation, so every deployed VM
+// can be found by its name.
+func (r *CloudStackMachinePoolReconciliationRunner) ScaleInstances() (ctrl.Result, error) {
+	desired := int(ptr.Deref(r.MachinePool.Spec.Replicas, 1))
+	maxSurge := int(ptr.Deref(r.ReconciliationSubject.Spec.Strategy.MaxSurge, 1))
+	maxUnavailable := int(ptr.Deref(r.ReconciliationSubject.Spec.Strategy.MaxUnavailable, 0))
+	if maxSurge == 0 && maxUnavailable == 0 {
+		maxSurge = 1
+	}
+
+	instances := r.ReconciliationSubject.Status.Instances
+	upToDate, running, pending := 0, 0, 0
+	for _, instance := range instances {
+		if !r.isOutdated(instance) {
+			upToDate++
+		}
+		if instance.InstanceState == "Running" {
+			running++
+		}
+		if instance.InstanceID == "" {
+			pending++
+		}
+	}
+
+	added := false
+	for upToDate < desired && len(instances) < desired+maxSurge {
+		fdName := r.leastPopulatedFailureDomain(instances)
+		if fdName == "" {
+			return r.RequeueWithMessage("No failure domain available to place machine pool instances in.")
+		}
+		instances = append(instances, infrav1.CloudStackMachinePoolInstance{
+			Name:              r.newInstanceName(),
+			FailureDomainName: fdName,
+			TemplateHash:      r.ReconciliationSubject.Status.TemplateHash,
+		})
+		upToDate++
+		added = true
+	}
+	r.ReconciliationSubject.Status.Instances = instances
+	if added {
+		conditions.MarkFalse(r.ReconciliationSubject, infrav1.ReplicasReadyCondition,
+			infrav1.ScalingUpReason, clusterv1.ConditionSeverityInfo, "")
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// Destroy outdated instances first, then those that aren't running, then those in the most populated
+	// failure domain.
+	candidates := append([]infrav1.CloudStackMachinePoolInstance{}, instances...)
+	population := failureDomainPopulation(instances)
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if r.isOutdated(a) != r.isOutdated(b) {
+			return r.isOutdated(a)
+		}
+		if (a.InstanceState == "Running") != (b.InstanceState == "Running") {
+			return b.InstanceState == "Running"
+		}
+		return population[a.FailureDomainName] > population[b.FailureDomainName]
+	})
+
+	total := len(instances)
+	var destroyErr error
+	inProgress, blocked := false, false
+	for _, candidate := range candidates {
+		if total <= desired && !r.isOutdated(candidate) {
+			break
+		}
+		isRunning := candidate.InstanceState == "Running"
+		if isRunning && running-1 < desired-maxUnavailable {
+			blocked = true
+			break
+		}
+		if r.isOutdated(candidate) {
+			conditions.MarkFalse(r.ReconciliationSubject, infrav1.ReplicasReadyCondition,
+				infrav1.RollingUpdateInProgressReason, clusterv1.ConditionSeverityInfo, "")
+		} else {
+			conditions.MarkFalse(r.ReconciliationSubject, infrav1.ReplicasReadyCondition,
+				infrav1.ScalingDownReason, clusterv1.ConditionSeverityInfo, "")
+		}
+
+		destroyed, err := r.destroyInstance(candidate)
+		if err != nil {
+			destroyErr = err
+			break
+		}
+		total--
+		if isRunning {
+			running--
+		}
+		if !destroyed {
+			inProgress = true
+			continue
+		}
+		r.removeInstance(candidate.Name)
+	}
+	if destroyErr != nil {
+		return ctrl.Result{}, destroyErr
+	}
+	if inProgress {
+		return ctrl.Result{RequeueAfter: utils.DestoryVMRequeueInterval}, nil
+	}
+	if blocked && pending == 0 {
+		// Nothing is left to deploy, so the instances the rollout waits on aren't running yet.
+		return r.RequeueWithMessage("Waiting for machine pool instances to be running.")
+	}
+	return ctrl.Result{}, nil
+}
+
+// DeployPendingInstances deploys the instances that were added to the pool but don't have a VM yet, and requeues so
+// the instances they replace can be destroyed.
+func (r *CloudStackMachinePoolReconciliationRunner) DeployPendingInstances() (ctrl.Result, error) {
+	deployed := false
+	for i, instance := range r.ReconciliationSubject.Status.Instances {
+		if instance.InstanceID != "" {
+			continue
+		}
+		fd := r.FailureDomains[instance.FailureDomainName]
+		if fd == nil {
+			return r.RequeueWithMessage(fmt.Sprintf("Failure domain %s not found.", instance.FailureDomainName))
+		}
+		_, user, err := r.clientsFor(instance.FailureDomainName)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		affinityGroup, res, err := r.affinityGroupFor(fd)
+		if r.ShouldReturn(res, err) {
+			return res, err
+		}
+
+		csMachine := r.machineForInstance(instance)
+		capiMachine := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: instance.Name, Namespace: r.ReconciliationSubject.Namespace}}
+		userData := processCustomMetadata(r.BootstrapData, instance.Name, instance.FailureDomainName)
+		err = user.GetOrCreateVMInstance(csMachine, capiMachine, r.CSCluster, fd, affinityGroup, userData)
+		// The instance ID is recorded even on failure, so partially deployed VMs get cleaned up.
+		r.ReconciliationSubject.Status.Instances[i] = updateInstanceFromMachine(instance, csMachine)
+		if err != nil {
+			r.Recorder.Eventf(r.ReconciliationSubject, "Warning", "Creating", CSMachinePoolInstanceCreationFailed, instance.Name, err.Error())
+			conditions.MarkFalse(r.ReconciliationSubject, infrav1.ReplicasReadyCondition,
+				infrav1.InstancesProvisionFailedReason, clusterv1.ConditionSeverityError, err.Error())
+			return ctrl.Result{}, err
+		}
+		r.Recorder.Eventf(r.ReconciliationSubject, "Normal", "Created", CSMachinePoolInstanceCreated, instance.Name, instance.FailureDomainName)
+		deployed = true
+	}
+	return ctrl.Result{Requeue: deployed}, nil
+}
+
+// SetPoolStatus derives the pool's replica counts, provider ID list and readiness from its instances.
+func (r *CloudStackMachinePoolReconciliationRunner) SetPoolStatus() {
+	desired := ptr.Deref(r.MachinePool.Spec.Replicas, 1)
+	status := &r.ReconciliationSubject.Status
+	status.Replicas, status.UpdatedReplicas = 0, 0
+	providerIDs := []string{}
+	for _, instance := range status.Instances {
+		if instance.ProviderID != "" {
+			providerIDs = append(providerIDs, instance.ProviderID)
+		}
+		if instance.InstanceState == "Running" {
+			status.Replicas++
+			if !r.isOutdated(instance) {
+				status.UpdatedReplicas++
+			}
+		}
+	}
+	r.ReconciliationSubject.Spec.ProviderIDList = providerIDs
+
+	if !r.ReconciliationSubject.DeletionTimestamp.IsZero() {
+		return
+	}
+	if status.UpdatedReplicas >= desired {
+		status.Ready = true
+	}
+	if status.UpdatedReplicas == desired && int32(len(status.Instances)) == desired {
+		conditions.MarkTrue(r.ReconciliationSubject, infrav1.ReplicasReadyCondition)
+	} else if !conditions.Has(r.ReconciliationSubject, infrav1.ReplicasReadyCondition) ||
+		conditions.IsTrue(r.ReconciliationSubject, infrav1.ReplicasReadyCondition) {
+		// No stage reported a more specific reason, e.g. an instance was stopped outside of the controller.
+		conditions.MarkFalse(r.ReconciliationSubject, infrav1.ReplicasReadyCondition,
+			infrav1.InstanceNotRunningReason, clusterv1.ConditionSeverityInfo, "%d of %d replicas running", status.UpdatedReplicas, desired)
+	}
+}
+
+func (r *CloudStackMachinePoolReconciliationRunner) ReconcileDelete() (ctrl.Result, error) {
+	defer r.SetPoolStatus()
+	conditions.MarkFalse(r.ReconciliationSubject, infrav1.ReplicasReadyCondition,
+		clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
+
+	inProgress := false
+	for _, instance := range append([]infrav1.CloudStackMachinePoolInstance{}, r.ReconciliationSubject.Status.Instances...) {
+		destroyed, err := r.destroyInstance(instance)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if !destroyed {
+			inProgress = true
+			continue
+		}
+		r.removeInstance(instance.Name)
+	}
+	if inProgress {
+		return ctrl.Result{RequeueAfter: utils.DestoryVMRequeueInterval}, nil
+	}
+
+	controllerutil.RemoveFinalizer(r.ReconciliationSubject, infrav1.MachinePoolFinalizer)
+	return ctrl.Result{}, nil
+}
+
+// destroyInstance destroys the VM of an instance and reports whether it is gone.
+func (r *CloudStackMachinePoolReconciliationRunner) destroyInstance(instance infrav1.CloudStackMachinePoolInstance) (bool, error) {
+	admin, _, err := r.clientsFor(instance.FailureDomainName)
+	if err != nil {
+		return false, err
+	}
+	if admin == nil {
+		r.Recorder.Eventf(r.ReconciliationSubject, "Warning", "Lost", CSMachinePoolInstanceLost, instance.Name)
+		return true, nil
+	}
+
+	csMachine := r.machineForInstance(instance)
+	if csMachine.Spec.InstanceID == nil {
+		// The instance may have been deployed without its ID being recorded. Look it up by name.
+		if err := admin.ResolveVMInstanceDetails(csMachine); err != nil {
+			if utils.ContainsNoMatchSubstring(err) {
+				return true, nil
+			}
+			return false, err
+		}
+	}
+
+	// Use the admin client to expunge the VM, as in CloudStackMachine deletion.
+	err = admin.DestroyVMInstance(csMachine)
+	// Keep the destroy job, it's polled on the next attempt.
+	r.setInstance(updateInstanceFromMachine(instance, csMachine))
+	if err != nil {
+		if err.Error() == "VM deletion in progress" {
+			r.Log.Info(err.Error(), "instance", instance.Name)
+			return false, nil
+		}
+		r.Recorder.Eventf(r.ReconciliationSubject, "Warning", "Deleting", CSMachinePoolInstanceDeletionFailed, instance.Name, err.Error())
+		conditions.MarkFalse(r.ReconciliationSubject, infrav1.ReplicasReadyCondition,
+			infrav1.InstancesProvisionFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		return false, err
+	}
+	r.Recorder.Eventf(r.ReconciliationSubject, "Normal", "Deleted", CSMachinePoolInstanceDeleted, instance.Name)
+	return true, nil
+}
+
+// clientsFor returns the admin and user CloudStack clients of the named failure domain. Both are nil if the failure
+// domain no longer exists.
+func (r *CloudStackMachinePoolReconciliationRunner) clientsFor(fdName string) (cloud.Client, cloud.Client, error) {
+	if clients, found := r.clients[fdName]; found {
+		return clients[0], clients[1], nil
+	}
+	fd, found := r.FailureDomains[fdName]
+	if !found {
+		return nil, nil, nil
+	}
+	if _, err := r.AsFailureDomainUser(&fd.Spec)(); err != nil {
+		return nil, nil, err
+	}
+	r.clients[fdName] = [2]cloud.Client{r.CSClient, r.CSUser}
+	return r.CSClient, r.CSUser, nil
+}
+
+// affinityGroupFor gets or creates the pool's affinity group in a failure domain if the template asks for managed
+// affinity.
+func (r *CloudStackMachinePoolReconciliationRunner) affinityGroupFor(fd *infrav1.CloudStackFailureDomain) (*infrav1.CloudStackAffinityGroup, ctrl.Result, error) {
+	affinity := r.ReconciliationSubject.Spec.Template.Spec.Affinity
+	if affinity == infrav1.NoAffinity || affinity == "" {
+		return &infrav1.CloudStackAffinityGroup{}, ctrl.Result{}, nil
+	}
+	if ag, found := r.AffinityGroups[fd.Spec.Name]; found {
+		return ag, ctrl.Result{}, nil
+	}
+
+	ag := &infrav1.CloudStackAffinityGroup{}
+	ag.Spec.FailureDomainName = fd.Spec.Name
+	name := utils.GenerateMachinePoolAffinityGroupName(*r.ReconciliationSubject, fd.Spec.Name, r.CAPICluster)
+	if res, err := r.GetOrCreateAffinityGroup(name, affinity, ag, fd)(); r.ShouldReturn(res, err) {
+		return nil, res, err
+	}
+	if !ag.Status.Ready {
+		conditions.MarkFalse(r.ReconciliationSubject, infrav1.ReplicasReadyCondition,
+			infrav1.WaitingForAffinityGroupReason, clusterv1.ConditionSeverityInfo, "")
+		res, err := r.RequeueWithMessage("Required affinity group not ready.")
+		return nil, res, err
+	}
+	r.AffinityGroups[fd.Spec.Name] = ag
+	return ag, ctrl.Result{}, nil
+}
+
+// machineForInstance builds the CloudStackMachine the cloud client deploys, resolves and destroys the instance through.
+func (r *CloudStackMachinePoolReconciliationRunner) machineForInstance(instance infrav1.CloudStackMachinePoolInstance) *infrav1.CloudStackMachine {
+	csMachine := &infrav1.CloudStackMachine{
+		ObjectMeta: metav1.ObjectMeta{Name: instance.Name, Namespace: r.ReconciliationSubject.Namespace},
+		Spec:       *r.ReconciliationSubject.Spec.Template.Spec.DeepCopy(),
+		Status: infrav1.CloudStackMachineStatus{
+			InstanceState: instance.InstanceState,
+			Addresses:     instance.Addresses,
+			AsyncJob:      instance.AsyncJob,
+		},
+	}
+	csMachine.Spec.FailureDomainName = instance.FailureDomainName
+	csMachine.Spec.InstanceID = nil
+	csMachine.Spec.ProviderID = nil
+	if instance.InstanceID != "" {
+		csMachine.Spec.InstanceID = ptr.To(instance.InstanceID)
+	}
+	return csMachine
+}
+
+// updateInstanceFromMachine copies what the cloud client learned about an instance back into the pool's status.
+func updateInstanceFromMachine(instance infrav1.CloudStackMachinePoolInstance, csMachine *infrav1.CloudStackMachine) infrav1.CloudStackMachinePoolInstance {
+	if csMachine.Spec.InstanceID != nil {
+		instance.InstanceID = *csMachine.Spec.InstanceID
+	}
+	if csMachine.Spec.ProviderID != nil {
+		instance.ProviderID = *csMachine.Spec.ProviderID
+	}
+	instance.InstanceState = csMachine.Status.InstanceState
+	instance.Addresses = csMachine.Status.Addresses
+	instance.AsyncJob = csMachine.Status.AsyncJob
+	return instance
+}
+
+// isOutdated reports whether an instance has to be replaced: it was created from an older template, is placed in a
+// failure domain the pool may no longer use, or is in error state.
+func (r *CloudStackMachinePoolReconciliationRunner) isOutdated(instance infrav1.CloudStackMachinePoolInstance) bool {
+	return instance.TemplateHash != r.ReconciliationSubject.Status.TemplateHash ||
+		!slices.Contains(r.AllowedFailureDomains, instance.FailureDomainName) ||
+		instance.InstanceState == "Error"
+}
+
+// leastPopulatedFailureDomain picks the allowed failure domain with the fewest up-to-date instances.
+func (r *CloudStackMachinePoolReconciliationRunner) leastPopulatedFailureDomain(instances []infrav1.CloudStackMachinePoolInstance) string {
+	population := map[string]int{}
+	for _, instance := range instances {
+		if !r.isOutdated(instance) {
+			population[instance.FailureDomainName]++
+		}
+	}
+	best := ""
+	for _, name := range r.AllowedFailureDomains {
+		if _, found := r.FailureDomains[name]; !found {
+			continue
+		}
+		if best == "" || population[name] < population[best] {
+			best = name
+		}
+	}
+	return best
+}
+
+func (r *CloudStackMachinePoolReconciliationRunner) newInstanceName() string {
+	prefix := r.ReconciliationSubject.Name
+	if len(prefix) > machinePoolInstanceNameMaxLength-6 {
+		prefix = strings.TrimRight(prefix[:machinePoolInstanceNameMaxLength-6], "-.")
+	}
+	return fmt.Sprintf("%s-%s", prefix, utilrand.String(5))
+}
+
+// setInstance replaces the instance of the same name in the pool's status.
+func (r *CloudStackMachinePoolReconciliationRunner) setInstance(instance infrav1.CloudStackMachinePoolInstance) {
+	for i := range r.ReconciliationSubject.Status.Instances {
+		if r.ReconciliationSubject.Status.Instances[i].Name == instance.Name {
+			r.ReconciliationSubject.Status.Instances[i] = instance
+		}
+	}
+}
+
+func (r *CloudStackMachinePoolReconciliationRunner) removeInstance(name string) {
+	var kept []infrav1.CloudStackMachinePoolInstance
+	for _, instance := range r.ReconciliationSubject.Status.Instances {
+		if instance.Name != name {
+			kept = append(kept, instance)
+		}
+	}
+	r.ReconciliationSubject.Status.Instances = kept
+}
+
+func failureDomainPopulation(instances []infrav1.CloudStackMachinePoolInstance) map[string]int {
+	population := map[string]int{}
+	for _, instance := range instances {
+		population[instance.FailureDomainName]++
+	}
+	return population
+}
+
+// machinePoolTemplateHash hashes the instance spec of a pool's template.
+func machinePoolTemplateHash(spec infrav1.CloudStackMachineSpec) (string, error) {
+	data, err := json.Marshal(spec)
+	if err != nil {
+		return "", errors.Wrap(err, "hashing machine pool template")
+	}
+	hasher := fnv.New32a()
+	_, _ = hasher.Write(data)
+	return fmt.Sprintf("%08x", hasher.Sum32()), nil
+}
+
+// SetupWithManager registers the machine pool reconciler to the CAPI controller manager.
+func (reconciler *CloudStackMachinePoolReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opts controller.Options) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	csMachinePoolMapper, err := util.ClusterToTypedObjectsMapper(reconciler.K8sClient, &infrav1.CloudStackMachinePoolList{}, mgr.GetScheme())
+	if err != nil {
+		return err
+	}
+
+	reconciler.Recorder = mgr.GetEventRecorderFor("capc-machinepool-controller")
+	err = ctrl.NewControllerManagedBy(mgr).
+		WithOptions(opts).
+		For(&infrav1.CloudStackMachinePool{}).
+		// Replica, failure domain and bootstrap data changes are made on the owning MachinePool.
+		Watches(
+			&expv1.MachinePool{},
+			handler.EnqueueRequestsFromMapFunc(
+				exputil.MachinePoolToInfrastructureMapFunc(ctx, infrav1.GroupVersion.WithKind("CloudStackMachinePool"))),
+		).
+		Watches(
+			&clusterv1.Cluster{},
+			handler.EnqueueRequestsFromMapFunc(csMachinePoolMapper),
+			builder.WithPredicates(predicates.ClusterPausedTransitionsOrInfrastructureReady(mgr.GetScheme(), log)),
+		).
+		Complete(reconciler)
+	return errors.Wrap(err, "building CloudStackMachinePool controller")
+}
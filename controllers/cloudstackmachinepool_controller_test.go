@@ -0,0 +1,121 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"errors"
+
+	ginkgo "github.com/onsi/ginkgo/v2"
+	gomega "github.com/onsi/gomega"
+	gomock "go.uber.org/mock/gomock"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+	dummies "sigs.k8s.io/cluster-api-provider-cloudstack/test/dummies/v1beta3"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+var _ = ginkgo.Describe("CloudStackMachinePoolReconciler", func() {
+	ginkgo.Context("With a fake ctrlRuntimeClient and no test Env at all.", func() {
+		var deployed int
+
+		ginkgo.BeforeEach(func() {
+			setupFakeTestClient()
+			deployed = 0
+
+			// Instances without an ID haven't been deployed yet.
+			mockCloudClient.EXPECT().ResolveVMInstanceDetails(gomock.Any()).DoAndReturn(
+				func(csMachine *infrav1.CloudStackMachine) error {
+					if csMachine.Spec.InstanceID == nil {
+						return errors.New("no match found")
+					}
+					csMachine.Spec.ProviderID = ptr.To("cloudstack:///" + *csMachine.Spec.InstanceID)
+					csMachine.Status.InstanceState = "Running"
+					return nil
+				}).AnyTimes()
+			mockCloudClient.EXPECT().GetOrCreateVMInstance(
+				gomock.Any(), gomock.Any(), gomock.Any(),
+				gomock.Any(), gomock.Any(), gomock.Any()).Do(
+				func(arg1, _, _, _, _, _ interface{}) {
+					deployed++
+					csMachine := arg1.(*infrav1.CloudStackMachine)
+					csMachine.Spec.InstanceID = ptr.To(csMachine.Name + "-id")
+					csMachine.Spec.ProviderID = ptr.To("cloudstack:///" + *csMachine.Spec.InstanceID)
+					csMachine.Status.InstanceState = "Running"
+				}).AnyTimes()
+			mockCloudClient.EXPECT().DestroyVMInstance(gomock.Any()).Return(nil).AnyTimes()
+
+			gomega.Ω(fakeCtrlClient.Create(ctx, dummies.CSFailureDomain1)).Should(gomega.Succeed())
+			gomega.Ω(fakeCtrlClient.Create(ctx, dummies.CSFailureDomain2)).Should(gomega.Succeed())
+			gomega.Ω(fakeCtrlClient.Create(ctx, dummies.ACSEndpointSecret1)).Should(gomega.Succeed())
+			gomega.Ω(fakeCtrlClient.Create(ctx, dummies.ACSEndpointSecret2)).Should(gomega.Succeed())
+			gomega.Ω(fakeCtrlClient.Create(ctx, dummies.BootstrapSecret)).Should(gomega.Succeed())
+			gomega.Ω(fakeCtrlClient.Create(ctx, dummies.CAPIMachinePool)).Should(gomega.Succeed())
+			gomega.Ω(fakeCtrlClient.Create(ctx, dummies.CSMachinePool)).Should(gomega.Succeed())
+			setClusterReady(fakeCtrlClient)
+		})
+
+		// reconcileUntilSettled reconciles the pool until the reconciler stops asking for a requeue, draining the
+		// recorded events on the way.
+		reconcileUntilSettled := func() *infrav1.CloudStackMachinePool {
+			request := ctrl.Request{NamespacedName: client.ObjectKeyFromObject(dummies.CSMachinePool)}
+			for i := 0; i < 10; i++ {
+				res, err := MachinePoolReconciler.Reconcile(ctx, request)
+				gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+				for len(fakeRecorder.Events) > 0 {
+					<-fakeRecorder.Events
+				}
+				if !res.Requeue && res.RequeueAfter == 0 {
+					break
+				}
+			}
+			pool := &infrav1.CloudStackMachinePool{}
+			gomega.Ω(fakeCtrlClient.Get(ctx, request.NamespacedName, pool)).Should(gomega.Succeed())
+			return pool
+		}
+
+		ginkgo.It("Should spread the desired number of instances across the failure domains.", func() {
+			pool := reconcileUntilSettled()
+
+			gomega.Ω(pool.Finalizers).Should(gomega.ContainElement(infrav1.MachinePoolFinalizer))
+			gomega.Ω(pool.Status.Instances).Should(gomega.HaveLen(2))
+			gomega.Ω([]string{pool.Status.Instances[0].FailureDomainName, pool.Status.Instances[1].FailureDomainName}).
+				Should(gomega.ConsistOf(dummies.CSFailureDomain1.Spec.Name, dummies.CSFailureDomain2.Spec.Name))
+			gomega.Ω(pool.Spec.ProviderIDList).Should(gomega.HaveLen(2))
+			gomega.Ω(pool.Status.Replicas).Should(gomega.Equal(int32(2)))
+			gomega.Ω(pool.Status.Ready).Should(gomega.BeTrue())
+			gomega.Ω(deployed).Should(gomega.Equal(2))
+		})
+
+		ginkgo.It("Should replace outdated instances when the template changes.", func() {
+			pool := reconcileUntilSettled()
+			oldHash := pool.Status.TemplateHash
+
+			pool.Spec.Template.Spec.Offering.Name = "bigger-offering"
+			gomega.Ω(fakeCtrlClient.Update(ctx, pool)).Should(gomega.Succeed())
+			pool = reconcileUntilSettled()
+
+			gomega.Ω(pool.Status.TemplateHash).ShouldNot(gomega.Equal(oldHash))
+			gomega.Ω(pool.Status.Instances).Should(gomega.HaveLen(2))
+			for _, instance := range pool.Status.Instances {
+				gomega.Ω(instance.TemplateHash).Should(gomega.Equal(pool.Status.TemplateHash))
+			}
+			gomega.Ω(pool.Status.UpdatedReplicas).Should(gomega.Equal(int32(2)))
+			gomega.Ω(deployed).Should(gomega.Equal(4))
+		})
+	})
+})
@@ -26,6 +26,7 @@ import (
 
 	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
 	csCtrlrUtils "sigs.k8s.io/cluster-api-provider-cloudstack/controllers/utils"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
@@ -83,6 +84,12 @@ func (r *CloudStackMachineStateCheckerReconciliationRunner) Reconcile() (ctrl.Re
 				}
 			}
 
+			// Instances are stopped on purpose while they're resized.
+			if job := r.CSMachine.Status.AsyncJob; job != nil && (job.Command == cloud.StopVMCommand ||
+				job.Command == cloud.ScaleVMCommand || job.Command == cloud.StartVMCommand) {
+				return ctrl.Result{RequeueAfter: 5 * time.Second}, nil
+			}
+
 			// capiTimeout indicates that a new VM is running, but it isn't reachable.
 			// The cluster may not recover if the machine isn't replaced.
 			csRunning := r.CSMachine.Status.InstanceState == "Running"
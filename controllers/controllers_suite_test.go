@@ -56,6 +56,8 @@ import (
 
 	dummies "sigs.k8s.io/cluster-api-provider-cloudstack/test/dummies/v1beta3"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/patch"
 	//+kubebuilder:scaffold:imports
 )
@@ -129,6 +131,7 @@ var (
 	FailureDomainReconciler *csReconcilers.CloudStackFailureDomainReconciler
 	IsoNetReconciler        *csReconcilers.CloudStackIsoNetReconciler
 	AffinityGReconciler     *csReconcilers.CloudStackAffinityGroupReconciler
+	MachinePoolReconciler   *csReconcilers.CloudStackMachinePoolReconciler
 
 	// CKS Reconcilers
 	CksClusterReconciler *csReconcilers.CksClusterReconciler
@@ -150,6 +153,8 @@ var _ = ginkgo.BeforeSuite(func() {
 
 	gomega.Ω(infrav1.AddToScheme(scheme.Scheme)).Should(gomega.Succeed())
 	gomega.Ω(clusterv1.AddToScheme(scheme.Scheme)).Should(gomega.Succeed())
+	gomega.Ω(expv1.AddToScheme(scheme.Scheme)).Should(gomega.Succeed())
+	gomega.Ω(ipamv1.AddToScheme(scheme.Scheme)).Should(gomega.Succeed())
 	gomega.Ω(fakes.AddToScheme(scheme.Scheme)).Should(gomega.Succeed())
 
 	// Increase log verbosity.
@@ -268,7 +273,7 @@ func setupFakeTestClient() {
 	// Make a fake k8s client with CloudStack and CAPI cluster.
 	fakeCtrlClient = fake.NewClientBuilder().WithScheme(scheme.Scheme).
 		WithObjects(dummies.CSCluster, dummies.CAPICluster).
-		WithStatusSubresource(dummies.CSCluster, dummies.CSMachine1).Build()
+		WithStatusSubresource(dummies.CSCluster, dummies.CSMachine1, dummies.CSISONet1, dummies.CSMachinePool).Build()
 	fakeRecorder = record.NewFakeRecorder(fakeEventBufferSize)
 	// Setup mock clients.
 	mockCSAPIClient = cloudstack.NewMockClient(mockCtrl)
@@ -292,6 +297,7 @@ func setupFakeTestClient() {
 	FailureDomainReconciler = &csReconcilers.CloudStackFailureDomainReconciler{ReconcilerBase: base}
 	IsoNetReconciler = &csReconcilers.CloudStackIsoNetReconciler{ReconcilerBase: base}
 	AffinityGReconciler = &csReconcilers.CloudStackAffinityGroupReconciler{ReconcilerBase: base}
+	MachinePoolReconciler = &csReconcilers.CloudStackMachinePoolReconciler{ReconcilerBase: base}
 
 	// Set on reconcilers. The mock client wasn't available at suite startup, so set it now.
 	ClusterReconciler.CSClient = mockCloudClient
@@ -299,6 +305,7 @@ func setupFakeTestClient() {
 	MachineReconciler.CSClient = mockCloudClient
 	FailureDomainReconciler.CSClient = mockCloudClient
 	AffinityGReconciler.CSClient = mockCloudClient
+	MachinePoolReconciler.CSClient = mockCloudClient
 
 	ginkgo.DeferCleanup(func() {
 		cancel()
@@ -0,0 +1,110 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers_test
+
+import (
+	"time"
+
+	ginkgo "github.com/onsi/ginkgo/v2"
+	gomega "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+	csReconcilers "sigs.k8s.io/cluster-api-provider-cloudstack/controllers"
+	csCtrlrUtils "sigs.k8s.io/cluster-api-provider-cloudstack/controllers/utils"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
+	dummies "sigs.k8s.io/cluster-api-provider-cloudstack/test/dummies/v1beta3"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/test/fakecloudstack"
+)
+
+var _ = ginkgo.Describe("Reconciling against a fake CloudStack", func() {
+	// Reconciliations waiting on other objects are requeued after csCtrlrUtils.RequeueTimeout.
+	const readyTimeout = time.Minute
+
+	var server *fakecloudstack.Server
+
+	ginkgo.BeforeEach(func() {
+		server = fakecloudstack.NewServer()
+		ginkgo.DeferCleanup(server.Close)
+
+		// The cluster has a single failure domain, in the fake's zone and shared network.
+		endpointSecret := server.EndpointSecret("acs-credentials", dummies.ClusterNameSpace)
+		dummies.CSFailureDomain1.Spec.Zone = infrav1.CloudStackZoneSpec{
+			Name:    fakecloudstack.ZoneName,
+			Network: infrav1.Network{Name: fakecloudstack.SharedNetworkName, Type: cloud.NetworkTypeShared},
+		}
+		dummies.CSFailureDomain1.Spec.ACSEndpoint = corev1.SecretReference{
+			Namespace: endpointSecret.Namespace, Name: endpointSecret.Name,
+		}
+		dummies.CSCluster.Spec.FailureDomains = []infrav1.CloudStackFailureDomainSpec{dummies.CSFailureDomain1.Spec}
+		dummies.CSMachine1.Spec.InstanceID = nil
+		dummies.CSMachine1.Spec.FailureDomainName = dummies.CSFailureDomain1.Spec.Name
+		dummies.CSMachine1.Spec.Offering = infrav1.CloudStackResourceIdentifier{Name: fakecloudstack.ServiceOfferingName}
+		dummies.CSMachine1.Spec.Template = infrav1.CloudStackResourceIdentifier{Name: fakecloudstack.TemplateName}
+		dummies.CSMachine1.Spec.DiskOffering = infrav1.CloudStackResourceDiskOffering{}
+		dummies.CAPIMachine.Spec.Bootstrap.DataSecretName = &dummies.BootstrapSecret.Name
+
+		SetupTestEnvironment() // Must happen before setting up managers/reconcilers.
+
+		// Unlike the suite's reconcilers, these ones get the clients of failure domains from their endpoint secret.
+		csClient, err := cloud.NewClientFromConf(server.Config(), nil, "")
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		base := csCtrlrUtils.ReconcilerBase{
+			K8sClient:  k8sManager.GetClient(),
+			Scheme:     k8sManager.GetScheme(),
+			CSClient:   csClient,
+			BaseLogger: logger,
+			Recorder:   &record.FakeRecorder{}, // Drops events, the reconcilers emit more than a buffer would hold.
+		}
+		opts := controller.Options{SkipNameValidation: ptr.To(true)}
+		gomega.Ω((&csReconcilers.CloudStackClusterReconciler{ReconcilerBase: base}).SetupWithManager(
+			ctx, k8sManager, opts)).Should(gomega.Succeed())
+		gomega.Ω((&csReconcilers.CloudStackFailureDomainReconciler{ReconcilerBase: base}).SetupWithManager(
+			k8sManager, opts)).Should(gomega.Succeed())
+		gomega.Ω((&csReconcilers.CloudStackMachineReconciler{ReconcilerBase: base}).SetupWithManager(
+			ctx, k8sManager, opts)).Should(gomega.Succeed())
+
+		gomega.Ω(k8sClient.Create(ctx, endpointSecret)).Should(gomega.Succeed())
+		gomega.Ω(k8sClient.Create(ctx, dummies.BootstrapSecret)).Should(gomega.Succeed())
+	})
+
+	ginkgo.It("readies the cluster's failure domain, then deploys the machine's instance in it", func() {
+		setupMachineCRDs()
+
+		csMachine := &infrav1.CloudStackMachine{}
+		gomega.Eventually(func() bool {
+			err := k8sClient.Get(ctx, client.ObjectKeyFromObject(dummies.CSMachine1), csMachine)
+			return err == nil && csMachine.Status.Ready
+		}, readyTimeout).WithPolling(pollInterval).Should(gomega.BeTrue())
+
+		csCluster := &infrav1.CloudStackCluster{}
+		gomega.Ω(k8sClient.Get(ctx, client.ObjectKeyFromObject(dummies.CSCluster), csCluster)).Should(gomega.Succeed())
+		gomega.Ω(csCluster.Status.Ready).Should(gomega.BeTrue())
+
+		gomega.Ω(csMachine.Spec.InstanceID).ShouldNot(gomega.BeNil())
+		gomega.Ω(*csMachine.Spec.ProviderID).Should(gomega.Equal("cloudstack:///" + *csMachine.Spec.InstanceID))
+		vm, found := server.Get(fakecloudstack.KindVirtualMachine, *csMachine.Spec.InstanceID)
+		gomega.Ω(found).Should(gomega.BeTrue())
+		gomega.Ω(vm["name"]).Should(gomega.Equal(dummies.CSMachine1.Name))
+		gomega.Ω(vm["state"]).Should(gomega.Equal("Running"))
+		gomega.Ω(server.Calls("deployVirtualMachine")).Should(gomega.Equal(1))
+	})
+})
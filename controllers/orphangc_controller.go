@@ -0,0 +1,88 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+	csCtrlrUtils "sigs.k8s.io/cluster-api-provider-cloudstack/controllers/utils"
+)
+
+// OrphanGCReconciliationRunner is a ReconciliationRunner collecting the orphaned CloudStack resources visible with
+// the credentials of a CloudStackFailureDomain.
+type OrphanGCReconciliationRunner struct {
+	*csCtrlrUtils.ReconciliationRunner
+	ReconciliationSubject *infrav1.CloudStackFailureDomain
+	Collector             *csCtrlrUtils.OrphanCollector
+}
+
+// OrphanGCReconciler periodically collects the CloudStack resources CAPC created for CloudStackClusters that no
+// longer exist, with the credentials of each CloudStackFailureDomain.
+type OrphanGCReconciler struct {
+	csCtrlrUtils.ReconcilerBase
+	Collector *csCtrlrUtils.OrphanCollector
+}
+
+// NewOrphanGCReconciliationRunner initializes a new orphan GC reconciliation runner with concrete types and
+// initialized member fields.
+func NewOrphanGCReconciliationRunner(collector *csCtrlrUtils.OrphanCollector) *OrphanGCReconciliationRunner {
+	// Set concrete type and init pointers.
+	runner := &OrphanGCReconciliationRunner{ReconciliationSubject: &infrav1.CloudStackFailureDomain{}, Collector: collector}
+	// Setup the base runner. Initializes pointers and links reconciliation methods.
+	runner.ReconciliationRunner = csCtrlrUtils.NewRunner(runner, runner.ReconciliationSubject, "OrphanGC")
+	return runner
+}
+
+// Reconcile is the method k8s will call upon a reconciliation request.
+func (reconciler *OrphanGCReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	return NewOrphanGCReconciliationRunner(reconciler.Collector).
+		UsingBaseReconciler(reconciler.ReconcilerBase).
+		ForRequest(req).
+		WithRequestCtx(ctx).
+		RunBaseReconciliationStages()
+}
+
+// Reconcile collects the orphaned resources visible with the failure domain's credentials, unless another failure
+// domain using them already did within the collection interval.
+func (r *OrphanGCReconciliationRunner) Reconcile() (ctrl.Result, error) {
+	credentials := csCtrlrUtils.FailureDomainCredentials(&r.ReconciliationSubject.Spec)
+	if !r.Collector.Due(credentials) {
+		return ctrl.Result{RequeueAfter: r.Collector.Interval}, nil
+	}
+	return r.RunReconciliationStages(
+		r.AsFailureDomainUser(&r.ReconciliationSubject.Spec),
+		r.CollectOrphans(r.Collector, credentials),
+		func() (ctrl.Result, error) { return ctrl.Result{RequeueAfter: r.Collector.Interval}, nil })
+}
+
+// ReconcileDelete does nothing, failure domains are cleaned up by their own controller.
+func (r *OrphanGCReconciliationRunner) ReconcileDelete() (ctrl.Result, error) {
+	return ctrl.Result{}, nil
+}
+
+// SetupWithManager sets up the controller with the Manager.
+func (reconciler *OrphanGCReconciler) SetupWithManager(mgr ctrl.Manager, opts controller.Options) error {
+	return ctrl.NewControllerManagedBy(mgr).
+		WithOptions(opts).
+		Named("orphan-gc-controller").
+		For(&infrav1.CloudStackFailureDomain{}).
+		Complete(reconciler)
+}
@@ -51,11 +51,16 @@ func (r *ReconciliationRunner) GetOrCreateAffinityGroup(
 		} // Didn't find a group, so create instead.
 
 		// Set affinity group type.
-		if affinityType == infrav1.ProAffinity {
+		switch affinityType {
+		case infrav1.ProAffinity:
 			ag.Spec.Type = "host affinity"
-		} else if affinityType == infrav1.AntiAffinity {
+		case infrav1.AntiAffinity:
 			ag.Spec.Type = "host anti-affinity"
-		} else {
+		case infrav1.SoftProAffinity:
+			ag.Spec.Type = "non-strict host affinity"
+		case infrav1.SoftAntiAffinity:
+			ag.Spec.Type = "non-strict host anti-affinity"
+		default:
 			return ctrl.Result{}, errors.Errorf("unrecognized affinity type %s", affinityType)
 		}
 
@@ -68,7 +73,8 @@ func (r *ReconciliationRunner) GetOrCreateAffinityGroup(
 		for _, ref := range r.ReconciliationSubject.GetOwnerReferences() {
 			if strings.EqualFold(ref.Kind, "EtcdadmCluster") ||
 				strings.EqualFold(ref.Kind, "KubeadmControlPlane") ||
-				strings.EqualFold(ref.Kind, "MachineSet") {
+				strings.EqualFold(ref.Kind, "MachineSet") ||
+				strings.EqualFold(ref.Kind, "MachinePool") {
 				ag.OwnerReferences = []metav1.OwnerReference{ref}
 				break
 			}
@@ -105,3 +111,11 @@ func GenerateAffinityGroupName(csm infrav1.CloudStackMachine, capiMachine *clust
 	return fmt.Sprintf("%s-%s-%sAffinity-%s-%s-%s",
 		capiCluster.Name, capiCluster.UID, titleCaser.String(csm.Spec.Affinity), managerOwnerRef.Name, managerOwnerRef.UID, csm.Spec.FailureDomainName), nil
 }
+
+// GenerateMachinePoolAffinityGroupName computes the name of the affinity group for the instances a
+// CloudStackMachinePool places in the named failure domain.
+func GenerateMachinePoolAffinityGroupName(pool infrav1.CloudStackMachinePool, fdName string, capiCluster *clusterv1.Cluster) string {
+	titleCaser := cases.Title(language.English)
+	return fmt.Sprintf("%s-%s-%sAffinity-MachinePool-%s-%s-%s",
+		capiCluster.Name, capiCluster.UID, titleCaser.String(pool.Spec.Template.Spec.Affinity), pool.Name, pool.UID, fdName)
+}
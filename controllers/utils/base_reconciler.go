@@ -35,6 +35,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -49,6 +50,8 @@ type ReconcilerBase struct {
 	K8sClient  client.Client
 	CSClient   cloud.Client
 	Recorder   record.EventRecorder
+	// RestrictACSEndpointNamespace refuses the acsEndpoint secrets of failure domains in other namespaces.
+	RestrictACSEndpointNamespace bool
 	CloudClientExtension
 }
 
@@ -387,6 +390,10 @@ func (r *ReconciliationRunner) RunReconciliationStages(fns ...CloudStackReconcil
 func (r *ReconciliationRunner) RunBaseReconciliationStages() (res ctrl.Result, retErr error) {
 	defer func() {
 		if r.Patcher != nil {
+			// Summarize the subject's conditions into its Ready condition before patching.
+			if setter, ok := r.ReconciliationSubject.(conditions.Setter); ok {
+				conditions.SetSummary(setter)
+			}
 			if err := r.Patcher.Patch(r.RequestCtx, r.ReconciliationSubject); err != nil {
 				if !strings.Contains(err.Error(), "is invalid: status.ready") {
 					err = errors.Wrapf(err, "error patching reconciliation subject")
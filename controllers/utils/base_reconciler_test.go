@@ -30,6 +30,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-cloudstack/controllers/utils"
 	dummies "sigs.k8s.io/cluster-api-provider-cloudstack/test/dummies/v1beta3"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
@@ -46,6 +47,21 @@ func (m *mockConcreteRunner) Reconcile() (ctrl.Result, error) {
 	return ctrl.Result{}, nil
 }
 
+// conditionsConcreteRunner marks a condition on its subject during Reconcile.
+type conditionsConcreteRunner struct {
+	subject *infrav1.CloudStackMachine
+}
+
+func (m *conditionsConcreteRunner) ReconcileDelete() (ctrl.Result, error) {
+	return ctrl.Result{}, nil
+}
+
+func (m *conditionsConcreteRunner) Reconcile() (ctrl.Result, error) {
+	conditions.MarkFalse(m.subject, infrav1.InstanceProvisionedCondition,
+		infrav1.InstanceNotRunningReason, clusterv1.ConditionSeverityInfo, "")
+	return ctrl.Result{}, nil
+}
+
 var _ = ginkgo.Describe("ReconciliationRunner", func() {
 	var (
 		mockCtrl   *gomock.Controller
@@ -62,7 +78,7 @@ var _ = ginkgo.Describe("ReconciliationRunner", func() {
 		gomega.Expect(infrav1.AddToScheme(scheme)).To(gomega.Succeed())
 		gomega.Expect(clusterv1.AddToScheme(scheme)).To(gomega.Succeed())
 
-		k8sClient = fake.NewClientBuilder().WithScheme(scheme).Build()
+		k8sClient = fake.NewClientBuilder().WithScheme(scheme).WithStatusSubresource(&infrav1.CloudStackMachine{}).Build()
 		ctx = context.Background()
 		mockRunner = &mockConcreteRunner{}
 
@@ -206,4 +222,38 @@ var _ = ginkgo.Describe("ReconciliationRunner", func() {
 			})
 		})
 	})
+
+	ginkgo.Describe("RunBaseReconciliationStages", func() {
+		ginkgo.It("should summarize the subject's conditions into its Ready condition", func() {
+			labels := map[string]string{clusterv1.ClusterNameLabel: "test-cluster"}
+			gomega.Expect(k8sClient.Create(ctx, &clusterv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+			})).To(gomega.Succeed())
+			gomega.Expect(k8sClient.Create(ctx, &infrav1.CloudStackCluster{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default"},
+			})).To(gomega.Succeed())
+			gomega.Expect(k8sClient.Create(ctx, &infrav1.CloudStackMachine{
+				ObjectMeta: metav1.ObjectMeta{Name: "test-machine", Namespace: "default", Labels: labels},
+			})).To(gomega.Succeed())
+
+			subject := &infrav1.CloudStackMachine{}
+			runner := utils.NewRunner(&conditionsConcreteRunner{subject: subject}, subject, "TestController")
+			runner.UsingBaseReconciler(utils.ReconcilerBase{
+				K8sClient:  k8sClient,
+				Scheme:     scheme,
+				BaseLogger: logr.Discard(),
+				Recorder:   record.NewFakeRecorder(10),
+			})
+			runner.WithRequestCtx(ctx)
+			runner.ForRequest(ctrl.Request{NamespacedName: client.ObjectKey{Namespace: "default", Name: "test-machine"}})
+
+			_, err := runner.RunBaseReconciliationStages()
+			gomega.Expect(err).NotTo(gomega.HaveOccurred())
+
+			patched := &infrav1.CloudStackMachine{}
+			gomega.Expect(k8sClient.Get(ctx, client.ObjectKey{Namespace: "default", Name: "test-machine"}, patched)).To(gomega.Succeed())
+			gomega.Expect(conditions.IsFalse(patched, clusterv1.ReadyCondition)).To(gomega.BeTrue())
+			gomega.Expect(conditions.GetReason(patched, clusterv1.ReadyCondition)).To(gomega.Equal(infrav1.InstanceNotRunningReason))
+		})
+	})
 })
@@ -0,0 +1,186 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"math/rand"
+	"slices"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// FailureDomainPlacer places machines CAPI doesn't assign a failure domain to.
+type FailureDomainPlacer interface {
+	// PlaceMachine returns the name of the failure domain among the candidates to place the machine in.
+	PlaceMachine(r *ReconciliationRunner, csMachine *infrav1.CloudStackMachine, candidates []infrav1.CloudStackFailureDomainSpec) (string, error)
+}
+
+// FailureDomainPlacers holds the placer of each strategy a CloudStackCluster's FailureDomainPlacement may name.
+var FailureDomainPlacers = map[string]FailureDomainPlacer{
+	infrav1.SpreadPlacement:        SpreadPlacer{},
+	infrav1.WeightedPlacement:      WeightedPlacer{},
+	infrav1.CapacityAwarePlacement: CapacityAwarePlacer{},
+	infrav1.RandomPlacement:        RandomPlacer{},
+}
+
+// PlaceMachineInFailureDomain picks the failure domain of a machine with the placement strategy of its cluster.
+// Failure domains the machine's instance couldn't be deployed in for lack of capacity are skipped. Once that's all of
+// them, they're cleared from the machine's status and tried again.
+func (r *ReconciliationRunner) PlaceMachineInFailureDomain(csMachine *infrav1.CloudStackMachine) (string, error) {
+	if len(r.CSCluster.Spec.FailureDomains) == 0 {
+		return "", errors.New("no failure domains to place the machine in")
+	}
+	var candidates []infrav1.CloudStackFailureDomainSpec
+	for _, fdSpec := range r.CSCluster.Spec.FailureDomains {
+		if !slices.Contains(csMachine.Status.InsufficientCapacityFailureDomains, fdSpec.Name) {
+			candidates = append(candidates, fdSpec)
+		}
+	}
+	if len(candidates) == 0 {
+		candidates = r.CSCluster.Spec.FailureDomains
+		csMachine.Status.InsufficientCapacityFailureDomains = nil
+	}
+
+	strategy := r.CSCluster.Spec.FailureDomainPlacement
+	if strategy == "" {
+		strategy = infrav1.SpreadPlacement
+	}
+	placer, found := FailureDomainPlacers[strategy]
+	if !found {
+		return "", errors.Errorf("unknown failure domain placement strategy %s", strategy)
+	}
+	return placer.PlaceMachine(r, csMachine, candidates)
+}
+
+// SpreadPlacer places machines in the failure domain with the fewest machines of their MachineDeployment, or of their
+// cluster if they don't belong to one. Ties are broken randomly.
+type SpreadPlacer struct{}
+
+func (SpreadPlacer) PlaceMachine(
+	r *ReconciliationRunner, csMachine *infrav1.CloudStackMachine, candidates []infrav1.CloudStackFailureDomainSpec,
+) (string, error) {
+	population, err := r.machinesPerFailureDomain(csMachine)
+	if err != nil {
+		return "", err
+	}
+	var leastPopulated []string
+	for _, fdSpec := range candidates {
+		switch {
+		case len(leastPopulated) == 0 || population[fdSpec.Name] < population[leastPopulated[0]]:
+			leastPopulated = []string{fdSpec.Name}
+		case population[fdSpec.Name] == population[leastPopulated[0]]:
+			leastPopulated = append(leastPopulated, fdSpec.Name)
+		}
+	}
+	return leastPopulated[rand.Intn(len(leastPopulated))], nil // #nosec G404 -- weak crypt rand doesn't matter here.
+}
+
+// machinesPerFailureDomain counts the other machines of the machine's MachineDeployment in each failure domain. Machines
+// that don't belong to a MachineDeployment are counted with all machines of their cluster.
+func (r *ReconciliationRunner) machinesPerFailureDomain(csMachine *infrav1.CloudStackMachine) (map[string]int, error) {
+	selector := client.MatchingLabels{clusterv1.ClusterNameLabel: csMachine.Labels[clusterv1.ClusterNameLabel]}
+	if deployment := csMachine.Labels[clusterv1.MachineDeploymentNameLabel]; deployment != "" {
+		selector[clusterv1.MachineDeploymentNameLabel] = deployment
+	}
+	machines := &infrav1.CloudStackMachineList{}
+	if err := r.K8sClient.List(r.RequestCtx, machines, client.InNamespace(csMachine.Namespace), selector); err != nil {
+		return nil, errors.Wrap(err, "listing the machines of the machine's deployment")
+	}
+
+	population := map[string]int{}
+	for _, machine := range machines.Items {
+		if machine.Name != csMachine.Name && machine.DeletionTimestamp.IsZero() {
+			population[machine.Spec.FailureDomainName]++
+		}
+	}
+	return population, nil
+}
+
+// WeightedPlacer places machines randomly, in proportion to the weights of the failure domains.
+type WeightedPlacer struct{}
+
+func (WeightedPlacer) PlaceMachine(
+	_ *ReconciliationRunner, _ *infrav1.CloudStackMachine, candidates []infrav1.CloudStackFailureDomainSpec,
+) (string, error) {
+	weight := func(fdSpec infrav1.CloudStackFailureDomainSpec) int64 {
+		if fdSpec.Weight == nil {
+			return 1
+		}
+		return int64(max(*fdSpec.Weight, 0))
+	}
+	var total int64
+	for _, fdSpec := range candidates {
+		total += weight(fdSpec)
+	}
+	if total == 0 { // All failure domains weigh the same.
+		return RandomPlacer{}.PlaceMachine(nil, nil, candidates)
+	}
+
+	pick := rand.Int63n(total) // #nosec G404 -- weak crypt rand doesn't matter here.
+	for _, fdSpec := range candidates {
+		if pick -= weight(fdSpec); pick < 0 {
+			return fdSpec.Name, nil
+		}
+	}
+	return candidates[len(candidates)-1].Name, nil
+}
+
+// CapacityAwarePlacer spreads machines over the failure domains whose zone has room for another machine, by the zone's
+// capacity and the resource limits of the failure domain's account. When none has room, machines are spread over all
+// failure domains.
+type CapacityAwarePlacer struct{}
+
+func (CapacityAwarePlacer) PlaceMachine(
+	r *ReconciliationRunner, csMachine *infrav1.CloudStackMachine, candidates []infrav1.CloudStackFailureDomainSpec,
+) (string, error) {
+	var withRoom []infrav1.CloudStackFailureDomainSpec
+	for _, fdSpec := range candidates {
+		fd := &infrav1.CloudStackFailureDomain{}
+		if _, err := r.GetFailureDomainByName(func() string { return fdSpec.Name }, fd)(); err != nil {
+			return "", err
+		} else if fd.Spec.Zone.ID == "" { // The zone hasn't been resolved yet.
+			continue
+		}
+		if _, err := r.AsFailureDomainUser(&fd.Spec)(); err != nil {
+			return "", err
+		}
+		room, err := r.CSUser.GetZoneCapacityForMachine(csMachine, fd.Spec.Zone.ID)
+		if err != nil {
+			return "", errors.Wrapf(err, "getting the capacity of failure domain %s", fdSpec.Name)
+		}
+		if room > 0 {
+			withRoom = append(withRoom, fdSpec)
+		}
+	}
+	if len(withRoom) == 0 {
+		r.Log.Info("No failure domain has room for the machine, spreading over all of them.")
+		withRoom = candidates
+	}
+	return SpreadPlacer{}.PlaceMachine(r, csMachine, withRoom)
+}
+
+// RandomPlacer places machines in a random failure domain.
+type RandomPlacer struct{}
+
+func (RandomPlacer) PlaceMachine(
+	_ *ReconciliationRunner, _ *infrav1.CloudStackMachine, candidates []infrav1.CloudStackFailureDomainSpec,
+) (string, error) {
+	return candidates[rand.Intn(len(candidates))].Name, nil // #nosec G404 -- weak crypt rand doesn't matter here.
+}
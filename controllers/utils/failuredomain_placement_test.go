@@ -0,0 +1,179 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils_test
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/controllers/utils"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/test/fakecloudstack"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = ginkgo.Describe("PlaceMachineInFailureDomain", func() {
+	const (
+		namespace   = "default"
+		clusterName = "test-cluster"
+		deployment  = "workers"
+	)
+
+	var (
+		server    *fakecloudstack.Server
+		runner    *utils.ReconciliationRunner
+		k8sClient client.Client
+		csMachine *infrav1.CloudStackMachine
+	)
+
+	machineIn := func(name, fdName, deploymentName string) *infrav1.CloudStackMachine {
+		labels := map[string]string{clusterv1.ClusterNameLabel: clusterName}
+		if deploymentName != "" {
+			labels[clusterv1.MachineDeploymentNameLabel] = deploymentName
+		}
+		return &infrav1.CloudStackMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace, Labels: labels},
+			Spec: infrav1.CloudStackMachineSpec{
+				FailureDomainName: fdName,
+				Offering:          infrav1.CloudStackResourceIdentifier{Name: fakecloudstack.ServiceOfferingName},
+			},
+		}
+	}
+
+	ginkgo.BeforeEach(func() {
+		server = fakecloudstack.NewServer()
+		scheme := runtime.NewScheme()
+		gomega.Ω(clientgoscheme.AddToScheme(scheme)).Should(gomega.Succeed())
+		gomega.Ω(infrav1.AddToScheme(scheme)).Should(gomega.Succeed())
+
+		k8sClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			server.EndpointSecret("acs-credentials", namespace),
+		).Build()
+		csMachine = machineIn("machine", "", deployment)
+		runner = utils.NewRunner(&mockConcreteRunner{}, csMachine, "TestController")
+		runner.UsingBaseReconciler(utils.ReconcilerBase{
+			K8sClient:  k8sClient,
+			Scheme:     scheme,
+			BaseLogger: logr.Discard(),
+			Recorder:   record.NewFakeRecorder(10),
+		})
+		runner.WithRequestCtx(context.Background())
+		runner.ForRequest(ctrl.Request{NamespacedName: client.ObjectKey{Namespace: namespace, Name: csMachine.Name}})
+		runner.Log = logr.Discard()
+		runner.CAPICluster = &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: namespace}}
+		runner.CSCluster = &infrav1.CloudStackCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: clusterName, Namespace: namespace},
+			Spec: infrav1.CloudStackClusterSpec{FailureDomains: []infrav1.CloudStackFailureDomainSpec{
+				{Name: "fd1"}, {Name: "fd2"}, {Name: "fd3"},
+			}},
+		}
+	})
+
+	ginkgo.AfterEach(func() {
+		server.Close()
+	})
+
+	createMachines := func(machines ...*infrav1.CloudStackMachine) {
+		for _, machine := range machines {
+			gomega.Ω(k8sClient.Create(context.Background(), machine)).Should(gomega.Succeed())
+		}
+	}
+
+	ginkgo.It("spreads machines over the failure domains with the fewest machines of their deployment", func() {
+		createMachines(
+			machineIn("worker-1", "fd1", deployment),
+			machineIn("worker-2", "fd2", deployment),
+			machineIn("other-1", "fd3", "others"),
+			machineIn("other-2", "fd3", "others"),
+		)
+
+		gomega.Ω(runner.PlaceMachineInFailureDomain(csMachine)).Should(gomega.Equal("fd3"))
+	})
+
+	ginkgo.It("spreads machines over the failure domains the machine has room in", func() {
+		createMachines(machineIn("worker-1", "fd3", deployment))
+		csMachine.Status.InsufficientCapacityFailureDomains = []string{"fd1"}
+
+		gomega.Ω(runner.PlaceMachineInFailureDomain(csMachine)).Should(gomega.Equal("fd2"))
+	})
+
+	ginkgo.It("tries all failure domains again once none has room for the machine", func() {
+		csMachine.Status.InsufficientCapacityFailureDomains = []string{"fd1", "fd2", "fd3"}
+
+		gomega.Ω(runner.PlaceMachineInFailureDomain(csMachine)).Should(gomega.BeElementOf("fd1", "fd2", "fd3"))
+		gomega.Ω(csMachine.Status.InsufficientCapacityFailureDomains).Should(gomega.BeEmpty())
+	})
+
+	ginkgo.It("places machines by the weights of the failure domains", func() {
+		runner.CSCluster.Spec.FailureDomainPlacement = infrav1.WeightedPlacement
+		runner.CSCluster.Spec.FailureDomains[0].Weight = ptr.To[int32](0)
+		runner.CSCluster.Spec.FailureDomains[2].Weight = ptr.To[int32](0)
+
+		for range 10 {
+			gomega.Ω(runner.PlaceMachineInFailureDomain(csMachine)).Should(gomega.Equal("fd2"))
+		}
+	})
+
+	ginkgo.It("places machines in the failure domains whose zone has room for them", func() {
+		full := server.AddZone("zone2")
+		host, _ := server.Find(fakecloudstack.KindHost, "zone2-host1")
+		gomega.Ω(server.Update(fakecloudstack.KindHost, host["id"].(string), fakecloudstack.Resource{
+			"memorytotal": int64(1) << 30,
+		})).Should(gomega.Succeed())
+		zone, _ := server.Find(fakecloudstack.KindZone, fakecloudstack.ZoneName)
+
+		runner.CSCluster.Spec.FailureDomainPlacement = infrav1.CapacityAwarePlacement
+		runner.CSCluster.Spec.FailureDomains = nil
+		for name, zoneID := range map[string]string{"fd1": full["id"].(string), "fd2": zone["id"].(string)} {
+			fdSpec := infrav1.CloudStackFailureDomainSpec{
+				Name:        name,
+				Zone:        infrav1.CloudStackZoneSpec{ID: zoneID},
+				ACSEndpoint: corev1.SecretReference{Name: "acs-credentials", Namespace: namespace},
+			}
+			runner.CSCluster.Spec.FailureDomains = append(runner.CSCluster.Spec.FailureDomains, fdSpec)
+			gomega.Ω(k8sClient.Create(context.Background(), &infrav1.CloudStackFailureDomain{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      infrav1.FailureDomainHashedMetaName(name, clusterName),
+					Namespace: namespace,
+				},
+				Spec: fdSpec,
+			})).Should(gomega.Succeed())
+		}
+
+		for range 5 {
+			gomega.Ω(runner.PlaceMachineInFailureDomain(csMachine)).Should(gomega.Equal("fd2"))
+		}
+	})
+
+	ginkgo.It("fails on an unknown placement strategy", func() {
+		runner.CSCluster.Spec.FailureDomainPlacement = "RoundRobin"
+
+		_, err := runner.PlaceMachineInFailureDomain(csMachine)
+		gomega.Ω(err).Should(gomega.MatchError("unknown failure domain placement strategy RoundRobin"))
+	})
+})
@@ -17,10 +17,14 @@ limitations under the License.
 package utils
 
 import (
+	"context"
 	"fmt"
+	"slices"
 	"strings"
 
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
 	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
 
@@ -118,6 +122,10 @@ func (r *ReconciliationRunner) GetFailureDomainsAndRequeueIfMissing(fds *infrav1
 	}
 }
 
+// CredentialsRotationFailedMessage is the message of the event emitted when the new content of an endpoint secret
+// can't be used.
+const CredentialsRotationFailedMessage = "The new credentials of secret %s can't be used, still using the previous ones: %s"
+
 type CloudClientExtension interface {
 	RegisterExtension(*ReconciliationRunner) CloudClientExtension
 	AsFailureDomainUser(*infrav1.CloudStackFailureDomainSpec) CloudStackReconcilerMethod
@@ -134,33 +142,105 @@ func (c *CloudClientImplementation) RegisterExtension(r *ReconciliationRunner) C
 }
 
 // AsFailureDomainUser uses the credentials specified in the failure domain to set the ReconciliationSubject's CSUser client.
+// Credentials of a CloudStackClusterIdentity are only used if the identity allows the namespace being reconciled.
 func (c *CloudClientImplementation) AsFailureDomainUser(fdSpec *infrav1.CloudStackFailureDomainSpec) CloudStackReconcilerMethod {
 	return func() (ctrl.Result, error) {
-		endpointCredentials := &corev1.Secret{}
-		key := client.ObjectKey{Name: fdSpec.ACSEndpoint.Name, Namespace: fdSpec.ACSEndpoint.Namespace}
-		if err := c.K8sClient.Get(c.RequestCtx, key, endpointCredentials); err != nil {
-			return ctrl.Result{}, errors.Wrapf(err, "getting ACSEndpoint secret with ref: %v", fdSpec.ACSEndpoint)
+		csClient, csUser, err := FailureDomainClients(
+			c.RequestCtx, c.K8sClient, fdSpec, c.Request.Namespace, c.RestrictACSEndpointNamespace)
+		var rotationErr *cloud.CredentialsRotationError
+		if errors.As(err, &rotationErr) {
+			c.Log.Error(err, "Rotating CloudStack credentials failed.")
+			c.Recorder.Eventf(c.ReconciliationSubject, "Warning", "CredentialsRotationFailed",
+				CredentialsRotationFailedMessage, rotationErr.Secret, rotationErr.Err.Error())
+		} else if err != nil {
+			return ctrl.Result{}, err
 		}
+		c.CSClient, c.CSUser = csClient, csUser
+		return ctrl.Result{}, nil
+	}
+}
 
-		clientConfig := &corev1.ConfigMap{}
-		key = client.ObjectKey{Name: cloud.ClientConfigMapName, Namespace: cloud.ClientConfigMapNamespace}
-		_ = c.K8sClient.Get(c.RequestCtx, key, clientConfig)
-
-		var err error
-		if c.CSClient, err = cloud.NewClientFromK8sSecret(endpointCredentials, clientConfig, fdSpec.Project); err != nil {
-			return ctrl.Result{}, errors.Wrapf(err, "parsing ACSEndpoint secret with ref: %v", fdSpec.ACSEndpoint)
+// FailureDomainClients returns a client with the credentials of a failure domain's endpoint secret or
+// CloudStackClusterIdentity, and one acting as the failure domain's account, if any, for a request in the namespace.
+// Endpoint secrets in other namespaces are refused when restrictNamespace is set.
+// When the new credentials of a rotated secret can't be used, the clients of the previous ones are returned along
+// with a *cloud.CredentialsRotationError.
+func FailureDomainClients(
+	ctx context.Context, k8sClient client.Client, fdSpec *infrav1.CloudStackFailureDomainSpec, namespace string,
+	restrictNamespace bool,
+) (csClient cloud.Client, csUser cloud.Client, retErr error) {
+	secretRef := fdSpec.ACSEndpoint
+	if restrictNamespace && fdSpec.IdentityRef == nil && secretRef.Namespace != namespace {
+		return nil, nil, errors.Errorf("ACSEndpoint secret %s/%s is not in namespace %s, use a CloudStackClusterIdentity "+
+			"to share credentials across namespaces", secretRef.Namespace, secretRef.Name, namespace)
+	}
+	if fdSpec.IdentityRef != nil {
+		identity := &infrav1.CloudStackClusterIdentity{}
+		if err := k8sClient.Get(ctx, client.ObjectKey{Name: fdSpec.IdentityRef.Name}, identity); err != nil {
+			return nil, nil, errors.Wrapf(err, "getting CloudStackClusterIdentity %s", fdSpec.IdentityRef.Name)
 		}
-
-		if fdSpec.Account != "" { // Set r.CSUser CloudStack Client per Account and Domain.
-			client, err := c.CSClient.NewClientInDomainAndAccount(fdSpec.Domain, fdSpec.Account, fdSpec.Project)
-			if err != nil {
-				return ctrl.Result{}, err
-			}
-			c.CSUser = client
-		} else { // Set r.CSUser CloudStack Client to r.CSClient since Account & Domain weren't provided.
-			c.CSUser = c.CSClient
+		if allowed, err := identityAllowsNamespace(ctx, k8sClient, identity, namespace); err != nil {
+			return nil, nil, err
+		} else if !allowed {
+			return nil, nil, errors.Errorf("CloudStackClusterIdentity %s does not allow namespace %s",
+				identity.Name, namespace)
 		}
+		secretRef = identity.Spec.SecretRef
+	}
 
-		return ctrl.Result{}, nil
+	endpointCredentials := &corev1.Secret{}
+	key := client.ObjectKey{Name: secretRef.Name, Namespace: secretRef.Namespace}
+	if err := k8sClient.Get(ctx, key, endpointCredentials); err != nil {
+		return nil, nil, errors.Wrapf(err, "getting ACSEndpoint secret with ref: %v", secretRef)
+	}
+
+	clientConfig := &corev1.ConfigMap{}
+	key = client.ObjectKey{Name: cloud.ClientConfigMapName, Namespace: cloud.ClientConfigMapNamespace}
+	_ = k8sClient.Get(ctx, key, clientConfig)
+
+	var rotationErr *cloud.CredentialsRotationError
+	csClient, err := cloud.NewClientFromK8sSecret(endpointCredentials, clientConfig, fdSpec.Project)
+	if errors.As(err, &rotationErr) {
+		retErr = err
+	} else if err != nil {
+		return nil, nil, errors.Wrapf(err, "parsing ACSEndpoint secret with ref: %v", secretRef)
+	}
+
+	if fdSpec.Account == "" { // Act as the secret's user since Account & Domain weren't provided.
+		return csClient, csClient, retErr
+	}
+	csUser, err = csClient.NewClientInDomainAndAccount(fdSpec.Domain, fdSpec.Account, fdSpec.Project)
+	if err != nil {
+		return nil, nil, err
+	}
+	return csClient, csUser, retErr
+}
+
+// identityAllowsNamespace checks the namespace against the identity's allowed namespaces list and selector.
+func identityAllowsNamespace(
+	ctx context.Context, k8sClient client.Client, identity *infrav1.CloudStackClusterIdentity, namespace string,
+) (bool, error) {
+	allowed := identity.Spec.AllowedNamespaces
+	if allowed == nil {
+		return false, nil
+	}
+	if len(allowed.NamespaceList) == 0 && allowed.Selector.MatchLabels == nil && allowed.Selector.MatchExpressions == nil {
+		return true, nil // An empty allowedNamespaces allows all namespaces.
+	}
+	if slices.Contains(allowed.NamespaceList, namespace) {
+		return true, nil
+	}
+
+	selector, err := metav1.LabelSelectorAsSelector(&allowed.Selector)
+	if err != nil {
+		return false, errors.Wrapf(err, "parsing allowedNamespaces selector of CloudStackClusterIdentity %s", identity.Name)
+	}
+	if selector.Empty() {
+		return false, nil
+	}
+	ns := &corev1.Namespace{}
+	if err := k8sClient.Get(ctx, client.ObjectKey{Name: namespace}, ns); err != nil {
+		return false, errors.Wrapf(err, "getting namespace %s", namespace)
 	}
+	return selector.Matches(labels.Set(ns.Labels)), nil
 }
@@ -0,0 +1,174 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils_test
+
+import (
+	"context"
+
+	"github.com/go-logr/logr"
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/controllers/utils"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/test/fakecloudstack"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = ginkgo.Describe("AsFailureDomainUser", func() {
+	const tenantNamespace = "tenant-a"
+
+	var (
+		server   *fakecloudstack.Server
+		runner   *utils.ReconciliationRunner
+		identity *infrav1.CloudStackClusterIdentity
+		fdSpec   *infrav1.CloudStackFailureDomainSpec
+	)
+
+	ginkgo.BeforeEach(func() {
+		server = fakecloudstack.NewServer()
+		scheme := runtime.NewScheme()
+		gomega.Ω(clientgoscheme.AddToScheme(scheme)).Should(gomega.Succeed())
+		gomega.Ω(infrav1.AddToScheme(scheme)).Should(gomega.Succeed())
+
+		identity = &infrav1.CloudStackClusterIdentity{
+			ObjectMeta: metav1.ObjectMeta{Name: "team-a"},
+			Spec: infrav1.CloudStackClusterIdentitySpec{
+				SecretRef: corev1.SecretReference{Name: "team-a-credentials", Namespace: "capc-system"},
+			},
+		}
+		fdSpec = &infrav1.CloudStackFailureDomainSpec{
+			Name:        "fd1",
+			IdentityRef: &infrav1.CloudStackIdentityReference{Kind: infrav1.CloudStackClusterIdentityKind, Name: identity.Name},
+		}
+
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			server.EndpointSecret("team-a-credentials", "capc-system"),
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: tenantNamespace, Labels: map[string]string{"team": "a"}}},
+			&corev1.Namespace{ObjectMeta: metav1.ObjectMeta{Name: "tenant-b", Labels: map[string]string{"team": "b"}}},
+		).Build()
+		runner = utils.NewRunner(&mockConcreteRunner{}, &infrav1.CloudStackFailureDomain{}, "TestController")
+		runner.UsingBaseReconciler(utils.ReconcilerBase{
+			K8sClient:  k8sClient,
+			Scheme:     scheme,
+			BaseLogger: logr.Discard(),
+			Recorder:   record.NewFakeRecorder(10),
+		})
+		runner.WithRequestCtx(context.Background())
+		runner.ForRequest(ctrl.Request{NamespacedName: client.ObjectKey{Namespace: tenantNamespace, Name: "fd1"}})
+	})
+
+	ginkgo.AfterEach(func() {
+		server.Close()
+	})
+
+	createIdentity := func() {
+		gomega.Ω(runner.K8sClient.Create(context.Background(), identity)).Should(gomega.Succeed())
+	}
+
+	ginkgo.It("uses the identity's credentials when its list allows the namespace", func() {
+		identity.Spec.AllowedNamespaces = &infrav1.AllowedNamespaces{NamespaceList: []string{tenantNamespace}}
+		createIdentity()
+
+		_, err := runner.AsFailureDomainUser(fdSpec)()
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Ω(runner.CSUser).ShouldNot(gomega.BeNil())
+	})
+
+	ginkgo.It("uses the identity's credentials when its selector matches the namespace", func() {
+		identity.Spec.AllowedNamespaces = &infrav1.AllowedNamespaces{
+			Selector: metav1.LabelSelector{MatchLabels: map[string]string{"team": "a"}},
+		}
+		createIdentity()
+
+		_, err := runner.AsFailureDomainUser(fdSpec)()
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("allows all namespaces when allowedNamespaces is empty", func() {
+		identity.Spec.AllowedNamespaces = &infrav1.AllowedNamespaces{}
+		createIdentity()
+
+		_, err := runner.AsFailureDomainUser(fdSpec)()
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+	})
+
+	ginkgo.It("refuses the identity when allowedNamespaces is unset", func() {
+		createIdentity()
+
+		_, err := runner.AsFailureDomainUser(fdSpec)()
+		gomega.Ω(err).Should(gomega.MatchError("CloudStackClusterIdentity team-a does not allow namespace tenant-a"))
+		gomega.Ω(runner.CSUser).Should(gomega.BeNil())
+	})
+
+	ginkgo.It("refuses the identity when neither its list nor its selector match the namespace", func() {
+		identity.Spec.AllowedNamespaces = &infrav1.AllowedNamespaces{
+			NamespaceList: []string{"tenant-b"},
+			Selector:      metav1.LabelSelector{MatchLabels: map[string]string{"team": "b"}},
+		}
+		createIdentity()
+
+		_, err := runner.AsFailureDomainUser(fdSpec)()
+		gomega.Ω(err).Should(gomega.MatchError("CloudStackClusterIdentity team-a does not allow namespace tenant-a"))
+	})
+
+	ginkgo.It("keeps the previous credentials and emits an event when the rotated ones are rejected", func() {
+		identity.Spec.AllowedNamespaces = &infrav1.AllowedNamespaces{}
+		createIdentity()
+		_, err := runner.AsFailureDomainUser(fdSpec)()
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		previous := runner.CSClient
+
+		secret := &corev1.Secret{}
+		key := client.ObjectKey{Name: "team-a-credentials", Namespace: "capc-system"}
+		gomega.Ω(runner.K8sClient.Get(context.Background(), key, secret)).Should(gomega.Succeed())
+		secret.Data["secret-key"] = []byte("mistyped")
+		gomega.Ω(runner.K8sClient.Update(context.Background(), secret)).Should(gomega.Succeed())
+
+		_, err = runner.AsFailureDomainUser(fdSpec)()
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Ω(runner.CSClient).Should(gomega.BeIdenticalTo(previous))
+		gomega.Ω(runner.Recorder.(*record.FakeRecorder).Events).Should(
+			gomega.Receive(gomega.ContainSubstring("CredentialsRotationFailed")))
+	})
+
+	ginkgo.It("refuses an acsEndpoint secret in another namespace when the manager restricts it", func() {
+		fdSpec.IdentityRef = nil
+		fdSpec.ACSEndpoint = corev1.SecretReference{Name: "team-a-credentials", Namespace: "capc-system"}
+
+		_, err := runner.AsFailureDomainUser(fdSpec)()
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+
+		runner.CSUser = nil
+		runner.RestrictACSEndpointNamespace = true
+		_, err = runner.AsFailureDomainUser(fdSpec)()
+		gomega.Ω(err).Should(gomega.MatchError(gomega.ContainSubstring(
+			"ACSEndpoint secret capc-system/team-a-credentials is not in namespace tenant-a")))
+		gomega.Ω(runner.CSUser).Should(gomega.BeNil())
+	})
+
+	ginkgo.It("fails when the identity doesn't exist", func() {
+		_, err := runner.AsFailureDomainUser(fdSpec)()
+		gomega.Ω(err).Should(gomega.MatchError(gomega.ContainSubstring("getting CloudStackClusterIdentity team-a")))
+	})
+})
@@ -0,0 +1,79 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// IPAddressClaimName returns the name of the IPAddressClaim for a machine's address from the poolIndex-th pool of its
+// netIndex-th network.
+func IPAddressClaimName(machineName string, netIndex, poolIndex int) string {
+	return fmt.Sprintf("%s-%d-%d", machineName, netIndex, poolIndex)
+}
+
+// GetOrCreateIPAddressClaim gets or creates an IPAddressClaim for an address from the referenced pool. Created claims
+// are owned by the ReconciliationSubject.
+func (r *ReconciliationRunner) GetOrCreateIPAddressClaim(
+	name string,
+	poolRef corev1.TypedLocalObjectReference,
+	claim *ipamv1.IPAddressClaim,
+) error {
+	key := client.ObjectKey{Namespace: r.Request.Namespace, Name: name}
+	if err := r.K8sClient.Get(r.RequestCtx, key, claim); client.IgnoreNotFound(err) != nil {
+		return errors.Wrapf(err, "getting IPAddressClaim %s", name)
+	} else if err == nil {
+		return nil
+	}
+
+	claim.ObjectMeta = r.NewChildObjectMeta(name)
+	claim.Spec = ipamv1.IPAddressClaimSpec{ClusterName: r.CAPICluster.Name, PoolRef: poolRef}
+	if err := r.K8sClient.Create(r.RequestCtx, claim); err != nil {
+		return errors.Wrapf(err, "creating IPAddressClaim %s", name)
+	}
+	r.Log.Info("Created IPAddressClaim.", "claim", name, "pool", poolRef.Name)
+	return nil
+}
+
+// GetClaimedIPAddress returns the address allocated to the claim, or an empty string if none has been allocated yet.
+func (r *ReconciliationRunner) GetClaimedIPAddress(claim *ipamv1.IPAddressClaim) (string, error) {
+	if claim.Status.AddressRef.Name == "" {
+		return "", nil
+	}
+	address := &ipamv1.IPAddress{}
+	key := client.ObjectKey{Namespace: claim.Namespace, Name: claim.Status.AddressRef.Name}
+	if err := r.K8sClient.Get(r.RequestCtx, key, address); err != nil {
+		return "", errors.Wrapf(err, "getting IPAddress %s of IPAddressClaim %s", key.Name, claim.Name)
+	}
+	return address.Spec.Address, nil
+}
+
+// DeleteIPAddressClaim deletes an IPAddressClaim, releasing its address back to the pool. Missing claims are ignored.
+func (r *ReconciliationRunner) DeleteIPAddressClaim(name string) error {
+	claim := &ipamv1.IPAddressClaim{}
+	claim.Name = name
+	claim.Namespace = r.Request.Namespace
+	if err := r.K8sClient.Delete(r.RequestCtx, claim); client.IgnoreNotFound(err) != nil {
+		return errors.Wrapf(err, "deleting IPAddressClaim %s", name)
+	}
+	return nil
+}
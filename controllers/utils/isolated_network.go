@@ -45,7 +45,10 @@ func (r *ReconciliationRunner) GenerateIsolatedNetwork(name string, fdNameFunc f
 		csIsoNet.Spec.ControlPlaneEndpoint.Port = r.CSCluster.Spec.ControlPlaneEndpoint.Port
 		csIsoNet.Spec.Gateway = network.Gateway
 		csIsoNet.Spec.Netmask = network.Netmask
+		csIsoNet.Spec.IP6Gateway = network.IP6Gateway
+		csIsoNet.Spec.IP6CIDR = network.IP6CIDR
 		csIsoNet.Spec.Offering = network.Offering
+		csIsoNet.Spec.ACL = network.ACL
 
 		if network.VPC != nil {
 			csIsoNet.Spec.VPC = network.VPC
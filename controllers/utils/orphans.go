@@ -0,0 +1,164 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/metrics"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// OrphanCollector collects the CloudStack resources CAPC created for CloudStackClusters that no longer exist. Resources
+// are deleted once they've been orphaned for longer than the grace period, or only reported in dry-run mode.
+type OrphanCollector struct {
+	// Interval is the minimum time between two collections with the same credentials.
+	Interval time.Duration
+	// GracePeriod is how long resources have to be orphaned before they're deleted.
+	GracePeriod time.Duration
+	// DryRun only reports orphaned resources instead of deleting them.
+	DryRun  bool
+	Metrics metrics.OrphanGCMetrics
+
+	mu            sync.Mutex
+	lastCollected map[string]time.Time
+	orphanedSince map[string]map[string]time.Time
+}
+
+// NewOrphanCollector constructs an OrphanCollector and registers its metrics.
+func NewOrphanCollector(interval, gracePeriod time.Duration, dryRun bool) *OrphanCollector {
+	return &OrphanCollector{
+		Interval:      interval,
+		GracePeriod:   gracePeriod,
+		DryRun:        dryRun,
+		Metrics:       metrics.NewOrphanGCMetrics(),
+		lastCollected: map[string]time.Time{},
+		orphanedSince: map[string]map[string]time.Time{},
+	}
+}
+
+// FailureDomainCredentials identifies the credentials a failure domain uses, and so the resources its user can see.
+func FailureDomainCredentials(fdSpec *infrav1.CloudStackFailureDomainSpec) string {
+	credentials := "secret/" + fdSpec.ACSEndpoint.Namespace + "/" + fdSpec.ACSEndpoint.Name
+	if fdSpec.IdentityRef != nil {
+		credentials = "identity/" + fdSpec.IdentityRef.Name
+	}
+	if fdSpec.Account != "" {
+		credentials += ",domain=" + fdSpec.Domain + ",account=" + fdSpec.Account
+	}
+	if fdSpec.Project != "" {
+		credentials += ",project=" + fdSpec.Project
+	}
+	return credentials
+}
+
+// Due reports whether the resources visible with the credentials are due to be collected. Credentials are only
+// considered collected once a collection with them succeeded, so failed ones are retried by the next reconciliation,
+// and failure domains sharing collected ones don't collect the same resources again.
+func (c *OrphanCollector) Due(credentials string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	last, found := c.lastCollected[credentials]
+	return !found || time.Since(last) >= c.Interval
+}
+
+// CollectOrphans finds the resources visible to the CSUser client that CAPC created and whose cluster and owner tags
+// don't name any existing CloudStackCluster. Those orphaned for longer than the collector's grace period are deleted
+// unless the collector runs dry. Failed deletions are logged and retried by the next collection.
+//
+// The UIDs of cluster tags change when clusters are moved or restored, so resources are only deleted if their owner
+// tags name a cluster too. Those without owner tags, created by earlier versions of CAPC, are only reported.
+func (r *ReconciliationRunner) CollectOrphans(collector *OrphanCollector, credentials string) CloudStackReconcilerMethod {
+	return func() (ctrl.Result, error) {
+		clusters := &infrav1.CloudStackClusterList{}
+		if err := r.K8sClient.List(r.RequestCtx, clusters); err != nil {
+			return ctrl.Result{}, errors.Wrap(err, "listing CloudStackClusters")
+		}
+		liveClusters := map[string]bool{}
+		liveOwners := map[client.ObjectKey]bool{}
+		for _, cluster := range clusters.Items {
+			liveClusters[string(cluster.UID)] = true
+			liveOwners[client.ObjectKeyFromObject(&cluster)] = true
+		}
+
+		resources, err := r.CSUser.ListCAPCResources()
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		collector.mu.Lock()
+		previouslyOrphaned := collector.orphanedSince[credentials]
+		collector.mu.Unlock()
+
+		now := time.Now()
+		orphanedSince := map[string]time.Time{}
+		orphaned := map[cloud.ResourceType]int{}
+		for _, resource := range resources {
+			if slices.ContainsFunc(resource.ClusterUIDs, func(uid string) bool { return liveClusters[uid] }) {
+				continue
+			}
+			owner := client.ObjectKey{
+				Namespace: resource.Tags[cloud.OwnerNamespaceTagName], Name: resource.Tags[cloud.OwnerClusterTagName],
+			}
+			if liveOwners[owner] {
+				continue
+			}
+			orphaned[resource.Type]++
+			key := string(resource.Type) + "/" + resource.ID
+			since, seen := previouslyOrphaned[key]
+			if !seen {
+				since = now
+			}
+			log := r.Log.WithValues("resourceType", resource.Type, "resourceID", resource.ID,
+				"clusterUIDs", resource.ClusterUIDs, "orphanedFor", now.Sub(since).Round(time.Second).String())
+
+			if owner.Name == "" {
+				log.Info("Found orphaned CloudStack resource without owner tags, leaving it in place.")
+				orphanedSince[key] = since
+				continue
+			}
+			if collector.DryRun || now.Sub(since) < collector.GracePeriod {
+				log.Info("Found orphaned CloudStack resource.", "dryRun", collector.DryRun)
+				orphanedSince[key] = since
+				continue
+			}
+			log.Info("Deleting orphaned CloudStack resource.")
+			if err := r.CSUser.DeleteCAPCResource(resource); err != nil {
+				log.Error(err, "Deleting orphaned CloudStack resource failed.")
+				collector.Metrics.DeletionErrors.WithLabelValues(string(resource.Type)).Inc()
+				orphanedSince[key] = since
+				continue
+			}
+			collector.Metrics.Deleted.WithLabelValues(string(resource.Type)).Inc()
+		}
+
+		for _, rType := range cloud.CollectableResourceTypes {
+			collector.Metrics.Orphaned.WithLabelValues(credentials, string(rType)).Set(float64(orphaned[rType]))
+		}
+		collector.mu.Lock()
+		collector.orphanedSince[credentials] = orphanedSince
+		collector.lastCollected[credentials] = now
+		collector.mu.Unlock()
+		return ctrl.Result{}, nil
+	}
+}
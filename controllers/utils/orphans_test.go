@@ -0,0 +1,219 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/tools/record"
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/controllers/utils"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/test/fakecloudstack"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+var _ = ginkgo.Describe("CollectOrphans", func() {
+	const (
+		namespace   = "default"
+		credentials = "secret/default/acs-credentials"
+	)
+
+	var (
+		server    *fakecloudstack.Server
+		runner    *utils.ReconciliationRunner
+		collector *utils.OrphanCollector
+	)
+
+	// addOwned adds a resource tagged as created by CAPC for the cluster with the given name, and as used by the
+	// clusters with the given UIDs. Resources created by earlier versions of CAPC have no owner.
+	addOwned := func(
+		kind string, rType cloud.ResourceType, resource fakecloudstack.Resource, owner string, clusterUIDs ...string,
+	) string {
+		id := server.Add(kind, resource)["id"].(string)
+		tags := map[string]string{cloud.CreatedByCAPCTagName: "1"}
+		if owner != "" {
+			tags[cloud.OwnerClusterTagName] = owner
+			tags[cloud.OwnerNamespaceTagName] = namespace
+		}
+		for _, uid := range clusterUIDs {
+			tags[cloud.ClusterTagNamePrefix+uid] = "1"
+		}
+		for key, value := range tags {
+			server.Add(fakecloudstack.KindTag, fakecloudstack.Resource{
+				"key": key, "value": value, "resourceid": id, "resourcetype": string(rType),
+			})
+		}
+		return id
+	}
+
+	// addTagged adds a resource created by CAPC for a cluster that no longer exists.
+	addTagged := func(kind string, rType cloud.ResourceType, resource fakecloudstack.Resource, clusterUIDs ...string) string {
+		return addOwned(kind, rType, resource, "dead", clusterUIDs...)
+	}
+
+	exists := func(kind, id string) bool {
+		_, found := server.Get(kind, id)
+		return found
+	}
+
+	ginkgo.BeforeEach(func() {
+		server = fakecloudstack.NewServer()
+		scheme := runtime.NewScheme()
+		gomega.Ω(clientgoscheme.AddToScheme(scheme)).Should(gomega.Succeed())
+		gomega.Ω(infrav1.AddToScheme(scheme)).Should(gomega.Succeed())
+
+		k8sClient := fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			server.EndpointSecret("acs-credentials", namespace),
+			&infrav1.CloudStackCluster{ObjectMeta: metav1.ObjectMeta{Name: "live", Namespace: namespace, UID: types.UID("live-uid")}},
+		).Build()
+		runner = utils.NewRunner(&mockConcreteRunner{}, &infrav1.CloudStackFailureDomain{}, "TestController")
+		runner.UsingBaseReconciler(utils.ReconcilerBase{
+			K8sClient:  k8sClient,
+			Scheme:     scheme,
+			BaseLogger: logr.Discard(),
+			Recorder:   record.NewFakeRecorder(10),
+		})
+		runner.WithRequestCtx(context.Background())
+		runner.ForRequest(ctrl.Request{NamespacedName: client.ObjectKey{Namespace: namespace, Name: "fd1"}})
+		runner.Log = logr.Discard()
+		_, err := runner.AsFailureDomainUser(&infrav1.CloudStackFailureDomainSpec{
+			Name:        "fd1",
+			ACSEndpoint: corev1.SecretReference{Name: "acs-credentials", Namespace: namespace},
+		})()
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+
+		collector = utils.NewOrphanCollector(time.Hour, time.Hour, false)
+	})
+
+	ginkgo.AfterEach(func() {
+		server.Close()
+	})
+
+	ginkgo.It("deletes resources of clusters that no longer exist once the grace period is over", func() {
+		vpcID := addTagged(fakecloudstack.KindVPC, cloud.ResourceTypeVPC, fakecloudstack.Resource{"name": "orphaned-vpc"}, "dead-uid")
+		orphanedNetID := addTagged(fakecloudstack.KindNetwork, cloud.ResourceTypeNetwork, fakecloudstack.Resource{
+			"name": "orphaned-net", "vpcid": vpcID,
+		}, "dead-uid")
+		sharedNetID := addTagged(fakecloudstack.KindNetwork, cloud.ResourceTypeNetwork, fakecloudstack.Resource{
+			"name": "shared-net",
+		}, "dead-uid", "live-uid")
+
+		_, err := runner.CollectOrphans(collector, credentials)()
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Ω(exists(fakecloudstack.KindNetwork, orphanedNetID)).Should(gomega.BeTrue())
+		gomega.Ω(testutil.ToFloat64(collector.Metrics.Orphaned.WithLabelValues(credentials, "Network"))).Should(gomega.Equal(1.0))
+		gomega.Ω(testutil.ToFloat64(collector.Metrics.Orphaned.WithLabelValues(credentials, "Vpc"))).Should(gomega.Equal(1.0))
+
+		collector.GracePeriod = 0
+		_, err = runner.CollectOrphans(collector, credentials)()
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Ω(exists(fakecloudstack.KindNetwork, orphanedNetID)).Should(gomega.BeFalse())
+		gomega.Ω(exists(fakecloudstack.KindVPC, vpcID)).Should(gomega.BeFalse())
+		gomega.Ω(exists(fakecloudstack.KindNetwork, sharedNetID)).Should(gomega.BeTrue())
+	})
+
+	ginkgo.It("deletes orphaned instances before their data disks", func() {
+		vmID := addTagged(fakecloudstack.KindVirtualMachine, cloud.ResourceTypeVM, fakecloudstack.Resource{
+			"name": "orphaned-vm", "state": "Running",
+		}, "dead-uid")
+		volumeID := addTagged(fakecloudstack.KindVolume, cloud.ResourceTypeVolume, fakecloudstack.Resource{
+			"name": "orphaned-vm-data", "type": "DATADISK", "virtualmachineid": vmID,
+		}, "dead-uid")
+		collector.GracePeriod = 0
+
+		_, err := runner.CollectOrphans(collector, credentials)()
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Ω(exists(fakecloudstack.KindVirtualMachine, vmID)).Should(gomega.BeFalse())
+		gomega.Ω(exists(fakecloudstack.KindVolume, volumeID)).Should(gomega.BeFalse())
+	})
+
+	ginkgo.It("keeps the resources of a cluster that was moved, and so got a new UID", func() {
+		movedNetID := addOwned(fakecloudstack.KindNetwork, cloud.ResourceTypeNetwork, fakecloudstack.Resource{
+			"name": "moved-net",
+		}, "live", "uid-before-move")
+		legacyNetID := addOwned(fakecloudstack.KindNetwork, cloud.ResourceTypeNetwork, fakecloudstack.Resource{
+			"name": "legacy-net",
+		}, "", "uid-before-move")
+		collector.GracePeriod = 0
+
+		_, err := runner.CollectOrphans(collector, credentials)()
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Ω(exists(fakecloudstack.KindNetwork, movedNetID)).Should(gomega.BeTrue())
+		// Without owner tags, there's no telling whether the resource belongs to a moved cluster.
+		gomega.Ω(exists(fakecloudstack.KindNetwork, legacyNetID)).Should(gomega.BeTrue())
+		gomega.Ω(testutil.ToFloat64(collector.Metrics.Orphaned.WithLabelValues(credentials, "Network"))).Should(gomega.Equal(1.0))
+	})
+
+	ginkgo.It("only reports orphaned resources when running dry", func() {
+		netID := addTagged(fakecloudstack.KindNetwork, cloud.ResourceTypeNetwork, fakecloudstack.Resource{"name": "orphaned-net"})
+		collector.GracePeriod = 0
+		collector.DryRun = true
+
+		_, err := runner.CollectOrphans(collector, credentials)()
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Ω(exists(fakecloudstack.KindNetwork, netID)).Should(gomega.BeTrue())
+		gomega.Ω(testutil.ToFloat64(collector.Metrics.Orphaned.WithLabelValues(credentials, "Network"))).Should(gomega.Equal(1.0))
+	})
+
+	ginkgo.It("retries failed deletions with the next collection", func() {
+		netID := addTagged(fakecloudstack.KindNetwork, cloud.ResourceTypeNetwork, fakecloudstack.Resource{"name": "orphaned-net"}, "dead-uid")
+		collector.GracePeriod = 0
+		errorsBefore := testutil.ToFloat64(collector.Metrics.DeletionErrors.WithLabelValues("Network"))
+
+		server.FailNext("deletenetwork", "network is busy")
+		_, err := runner.CollectOrphans(collector, credentials)()
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Ω(exists(fakecloudstack.KindNetwork, netID)).Should(gomega.BeTrue())
+		gomega.Ω(testutil.ToFloat64(collector.Metrics.DeletionErrors.WithLabelValues("Network"))).Should(gomega.Equal(errorsBefore + 1))
+
+		_, err = runner.CollectOrphans(collector, credentials)()
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Ω(exists(fakecloudstack.KindNetwork, netID)).Should(gomega.BeFalse())
+	})
+
+	ginkgo.It("collects with each set of credentials once per interval", func() {
+		gomega.Ω(collector.Due(credentials)).Should(gomega.BeTrue())
+		_, err := runner.CollectOrphans(collector, credentials)()
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Ω(collector.Due(credentials)).Should(gomega.BeFalse())
+		gomega.Ω(collector.Due(utils.FailureDomainCredentials(&infrav1.CloudStackFailureDomainSpec{
+			ACSEndpoint: corev1.SecretReference{Name: "acs-credentials", Namespace: namespace},
+			Domain:      "ROOT",
+			Account:     "tenant",
+		}))).Should(gomega.BeTrue())
+	})
+
+	ginkgo.It("retries credentials whose collection failed", func() {
+		server.FailNext("listtags", "internal error")
+		_, err := runner.CollectOrphans(collector, credentials)()
+		gomega.Ω(err).Should(gomega.HaveOccurred())
+		gomega.Ω(collector.Due(credentials)).Should(gomega.BeTrue())
+	})
+})
@@ -0,0 +1,329 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
+)
+
+// Modes of preflight validation.
+const (
+	// PreflightWarn admits objects referencing CloudStack resources that don't resolve with warnings.
+	PreflightWarn = "warn"
+	// PreflightDeny denies objects referencing CloudStack resources that don't resolve.
+	PreflightDeny = "deny"
+)
+
+// PreflightValidator resolves the CloudStack resources referenced by objects being admitted against the endpoints of
+// their failure domains. Results are cached, so the machines of a machine deployment don't each query CloudStack for
+// the same offering and template.
+type PreflightValidator struct {
+	K8sClient client.Client
+	// Deny denies objects referencing resources that don't resolve instead of warning about them. Resources that can't
+	// be checked, e.g. because the endpoint is unreachable, are only warned about.
+	Deny bool
+	// CacheTTL is how long the result of resolving a resource is reused.
+	CacheTTL time.Duration
+	// Timeout bounds the time spent on CloudStack for one object, so admission doesn't time out on a slow endpoint.
+	Timeout time.Duration
+	// RestrictACSEndpointNamespace refuses the acsEndpoint secrets of failure domains in other namespaces.
+	RestrictACSEndpointNamespace bool
+
+	mu    sync.Mutex
+	cache map[string]preflightResult
+}
+
+var _ infrav1.PreflightValidator = &PreflightValidator{}
+
+// preflightCheckTimeout bounds the time checks keep running after their object's admission timed out.
+const preflightCheckTimeout = time.Minute
+
+// preflightResult is a cached resolution of a CloudStack resource.
+type preflightResult struct {
+	value   string
+	err     error
+	expires time.Time
+}
+
+// preflightProblem is a reference that didn't resolve, or couldn't be checked.
+type preflightProblem struct {
+	path       *field.Path
+	value      interface{}
+	err        error
+	unverified bool
+}
+
+// NewPreflightValidator constructs a PreflightValidator.
+func NewPreflightValidator(k8sClient client.Client, mode string, cacheTTL, timeout time.Duration) *PreflightValidator {
+	return &PreflightValidator{
+		K8sClient: k8sClient,
+		Deny:      mode == PreflightDeny,
+		CacheTTL:  cacheTTL,
+		Timeout:   timeout,
+		cache:     map[string]preflightResult{},
+	}
+}
+
+// ValidateFailureDomains resolves the zones of failure domains, and their networks unless they're missing and would
+// be created as isolated networks.
+func (v *PreflightValidator) ValidateFailureDomains(
+	ctx context.Context, namespace string, fdSpecs []infrav1.CloudStackFailureDomainSpec, path *field.Path,
+) (admission.Warnings, field.ErrorList) {
+	return v.validate(ctx, path, func(ctx context.Context) []preflightProblem {
+		var problems []preflightProblem
+		for i := range fdSpecs {
+			fdSpec := &fdSpecs[i]
+			fdPath := path.Key(fdSpec.Name)
+			csUser, credentials, problem := v.failureDomainUser(ctx, fdSpec, namespace, fdPath)
+			if problem != nil {
+				problems = append(problems, *problem)
+				continue
+			}
+			if _, err := v.resolveZone(csUser, credentials, fdSpec.Zone); err != nil {
+				problems = append(problems, v.problem(fdPath.Child("zone", "name"), fdSpec.Zone.Name, err))
+				continue
+			}
+
+			network := fdSpec.Zone.Network
+			_, err := v.resolve(credentials, "network", func() (string, error) {
+				return "", csUser.ResolveNetwork(&infrav1.Network{ID: network.ID, Name: network.Name})
+			}, network.ID, network.Name)
+			if err != nil && !(network.ID == "" && ContainsNoMatchSubstring(err)) {
+				problems = append(problems, v.problem(fdPath.Child("zone", "network"), network.Name, err))
+			}
+		}
+		return problems
+	})
+}
+
+// ValidateMachineSpec resolves the offering, template, disk offerings and additional networks of a machine spec in
+// the failure domains of the object's cluster. Objects that don't belong to a cluster, e.g. machine templates not
+// created by a ClusterClass, aren't validated.
+func (v *PreflightValidator) ValidateMachineSpec(
+	ctx context.Context, obj metav1.Object, spec *infrav1.CloudStackMachineSpec, path *field.Path,
+) (admission.Warnings, field.ErrorList) {
+	clusterName := obj.GetLabels()[clusterv1.ClusterNameLabel]
+	if clusterName == "" {
+		return nil, nil
+	}
+	return v.validate(ctx, path, func(ctx context.Context) []preflightProblem {
+		fds := &infrav1.CloudStackFailureDomainList{}
+		if err := v.K8sClient.List(ctx, fds, client.InNamespace(obj.GetNamespace()),
+			client.MatchingLabels{clusterv1.ClusterNameLabel: clusterName}); err != nil {
+			return []preflightProblem{{path: path, err: errors.Wrap(err, "listing failure domains"), unverified: true}}
+		}
+
+		var problems []preflightProblem
+		for i := range fds.Items {
+			fdSpec := &fds.Items[i].Spec
+			if spec.FailureDomainName != "" && spec.FailureDomainName != fdSpec.Name {
+				continue
+			}
+			csUser, credentials, problem := v.failureDomainUser(ctx, fdSpec, obj.GetNamespace(), path)
+			if problem != nil {
+				problems = append(problems, *problem)
+				continue
+			}
+			zoneID, err := v.resolveZone(csUser, credentials, fdSpec.Zone)
+			if err != nil {
+				problems = append(problems, preflightProblem{path: path, unverified: true,
+					err: errors.Wrapf(err, "resolving zone of failure domain %s", fdSpec.Name)})
+				continue
+			}
+			problems = append(problems, v.validateMachineSpecInZone(csUser, credentials, fdSpec, zoneID, spec, path)...)
+		}
+		return problems
+	})
+}
+
+// validateMachineSpecInZone resolves the CloudStack resources of a machine spec in the zone of a failure domain.
+func (v *PreflightValidator) validateMachineSpecInZone(
+	csUser cloud.Client,
+	credentials string,
+	fdSpec *infrav1.CloudStackFailureDomainSpec,
+	zoneID string,
+	spec *infrav1.CloudStackMachineSpec,
+	path *field.Path,
+) []preflightProblem {
+	var problems []preflightProblem
+	inFailureDomain := func(err error) error {
+		return errors.Wrapf(err, "in failure domain %s", fdSpec.Name)
+	}
+	machine := &infrav1.CloudStackMachine{Spec: *spec}
+
+	_, err := v.resolve(credentials, "offering", func() (string, error) {
+		offering, err := csUser.ResolveServiceOffering(machine, zoneID)
+		return offering.Id, err
+	}, zoneID, spec.Offering.ID, spec.Offering.Name)
+	if err != nil {
+		problems = append(problems, v.problem(path.Child("offering"), spec.Offering, inFailureDomain(err)))
+	}
+
+	_, err = v.resolve(credentials, "template", func() (string, error) {
+		return csUser.ResolveTemplate(machine, zoneID)
+	}, zoneID, spec.Template.ID, spec.Template.Name)
+	if err != nil {
+		problems = append(problems, v.problem(path.Child("template"), spec.Template, inFailureDomain(err)))
+	}
+
+	var diskPaths []*field.Path
+	var diskOfferings []infrav1.CloudStackResourceDiskOffering
+	if spec.DiskOffering.ID != "" || spec.DiskOffering.Name != "" {
+		diskPaths = append(diskPaths, path.Child("diskOffering"))
+		diskOfferings = append(diskOfferings, spec.DiskOffering)
+	}
+	for i, disk := range spec.DataDisks {
+		diskPaths = append(diskPaths, path.Child("dataDisks").Index(i).Child("offering"))
+		diskOfferings = append(diskOfferings, infrav1.CloudStackResourceDiskOffering{
+			CloudStackResourceIdentifier: disk.Offering,
+			CustomSize:                   disk.CustomSize,
+		})
+	}
+	for i, diskOffering := range diskOfferings {
+		diskPath := diskPaths[i]
+		diskMachine := &infrav1.CloudStackMachine{Spec: infrav1.CloudStackMachineSpec{DiskOffering: diskOffering}}
+		_, err := v.resolve(credentials, "diskOffering", func() (string, error) {
+			return csUser.ResolveDiskOffering(diskMachine, zoneID)
+		}, zoneID, diskOffering.ID, diskOffering.Name, strconv.FormatInt(diskOffering.CustomSize, 10))
+		if err != nil {
+			problems = append(problems, v.problem(diskPath, diskOffering.CloudStackResourceIdentifier, inFailureDomain(err)))
+		}
+	}
+
+	for i, network := range spec.Networks {
+		// The failure domain's network may be an isolated network CAPC creates.
+		if (network.ID != "" && network.ID == fdSpec.Zone.Network.ID) ||
+			(network.Name != "" && network.Name == fdSpec.Zone.Network.Name) {
+			continue
+		}
+		_, err := v.resolve(credentials, "network", func() (string, error) {
+			return "", csUser.ResolveNetwork(&infrav1.Network{ID: network.ID, Name: network.Name})
+		}, network.ID, network.Name)
+		if err != nil {
+			problems = append(problems, v.problem(path.Child("networks").Index(i), network.Name, inFailureDomain(err)))
+		}
+	}
+	return problems
+}
+
+// failureDomainUser returns a client acting as the failure domain's user and the credentials it uses, or the problem
+// preventing the failure domain's references from being checked.
+func (v *PreflightValidator) failureDomainUser(
+	ctx context.Context, fdSpec *infrav1.CloudStackFailureDomainSpec, namespace string, path *field.Path,
+) (cloud.Client, string, *preflightProblem) {
+	var rotationErr *cloud.CredentialsRotationError
+	_, csUser, err := FailureDomainClients(ctx, v.K8sClient, fdSpec, namespace, v.RestrictACSEndpointNamespace)
+	if err != nil && !errors.As(err, &rotationErr) {
+		return nil, "", &preflightProblem{path: path, unverified: true,
+			err: errors.Wrapf(err, "getting CloudStack client of failure domain %s", fdSpec.Name)}
+	}
+	return csUser, FailureDomainCredentials(fdSpec), nil
+}
+
+// resolveZone returns the ID of a failure domain's zone.
+func (v *PreflightValidator) resolveZone(csUser cloud.Client, credentials string, zone infrav1.CloudStackZoneSpec) (string, error) {
+	return v.resolve(credentials, "zone", func() (string, error) {
+		err := csUser.ResolveZone(&zone)
+		return zone.ID, err
+	}, zone.ID, zone.Name)
+}
+
+// resolve returns the cached result of resolving a resource with the credentials, or resolves it. Failures to reach
+// the endpoint aren't cached.
+func (v *PreflightValidator) resolve(credentials, kind string, resolveFunc func() (string, error), keys ...string) (string, error) {
+	key := credentials + "/" + kind + "/" + strings.Join(keys, "/")
+	now := time.Now()
+	v.mu.Lock()
+	result, found := v.cache[key]
+	v.mu.Unlock()
+	if found && now.Before(result.expires) {
+		return result.value, result.err
+	}
+
+	value, err := resolveFunc()
+	if cloud.IsEndpointUnreachableError(err) || cloud.IsCredentialsRejectedError(err) {
+		return value, err
+	}
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	for cachedKey, cached := range v.cache {
+		if !now.Before(cached.expires) {
+			delete(v.cache, cachedKey)
+		}
+	}
+	v.cache[key] = preflightResult{value: value, err: err, expires: now.Add(v.CacheTTL)}
+	return value, err
+}
+
+// problem returns the problem of a reference that didn't resolve. It's only a warning when the endpoint couldn't be
+// asked.
+func (v *PreflightValidator) problem(path *field.Path, value interface{}, err error) preflightProblem {
+	return preflightProblem{path: path, value: value, err: err,
+		unverified: cloud.IsEndpointUnreachableError(err) || cloud.IsCredentialsRejectedError(err)}
+}
+
+// validate runs the checks of an object within the timeout, and turns their problems into warnings or errors. Checks
+// that time out keep running in the background, so their results are cached for the object's next admission. They
+// run on a context detached from the admission request's, which is cancelled once the request is answered.
+func (v *PreflightValidator) validate(
+	ctx context.Context, path *field.Path, checks func(context.Context) []preflightProblem,
+) (admission.Warnings, field.ErrorList) {
+	checkCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), max(v.Timeout, preflightCheckTimeout))
+	done := make(chan []preflightProblem, 1)
+	go func() {
+		defer cancel()
+		done <- checks(checkCtx)
+	}()
+
+	var problems []preflightProblem
+	select {
+	case problems = <-done:
+	case <-time.After(v.Timeout):
+		problems = []preflightProblem{{path: path, err: errors.New("timed out"), unverified: true}}
+	case <-ctx.Done():
+		problems = []preflightProblem{{path: path, err: ctx.Err(), unverified: true}}
+	}
+
+	var warnings admission.Warnings
+	var errorList field.ErrorList
+	for _, problem := range problems {
+		switch {
+		case problem.unverified:
+			warnings = append(warnings, fmt.Sprintf("%s: couldn't be validated against CloudStack: %s", problem.path, problem.err))
+		case v.Deny:
+			errorList = append(errorList, field.Invalid(problem.path, problem.value, problem.err.Error()))
+		default:
+			warnings = append(warnings, fmt.Sprintf("%s: %s", problem.path, problem.err))
+		}
+	}
+	return warnings, errorList
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils_test
+
+import (
+	"context"
+	"time"
+
+	"github.com/onsi/ginkgo/v2"
+	"github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/interceptor"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/controllers/utils"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/test/fakecloudstack"
+)
+
+var _ = ginkgo.Describe("PreflightValidator", func() {
+	const namespace = "default"
+
+	var (
+		server    *fakecloudstack.Server
+		k8sClient client.Client
+		fdSpec    infrav1.CloudStackFailureDomainSpec
+		template  *infrav1.CloudStackMachineTemplate
+	)
+
+	ginkgo.BeforeEach(func() {
+		server = fakecloudstack.NewServer()
+		scheme := runtime.NewScheme()
+		gomega.Ω(clientgoscheme.AddToScheme(scheme)).Should(gomega.Succeed())
+		gomega.Ω(infrav1.AddToScheme(scheme)).Should(gomega.Succeed())
+
+		fdSpec = infrav1.CloudStackFailureDomainSpec{
+			Name:        "fd1",
+			ACSEndpoint: corev1.SecretReference{Name: "preflight-credentials", Namespace: namespace},
+			Zone: infrav1.CloudStackZoneSpec{
+				Name:    fakecloudstack.ZoneName,
+				Network: infrav1.Network{Name: fakecloudstack.SharedNetworkName},
+			},
+		}
+		k8sClient = fake.NewClientBuilder().WithScheme(scheme).WithObjects(
+			server.EndpointSecret("preflight-credentials", namespace),
+			&infrav1.CloudStackFailureDomain{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "fd1",
+					Namespace: namespace,
+					Labels:    map[string]string{clusterv1.ClusterNameLabel: "cluster1"},
+				},
+				Spec: fdSpec,
+			},
+		).Build()
+
+		template = &infrav1.CloudStackMachineTemplate{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "md-0",
+				Namespace: namespace,
+				Labels:    map[string]string{clusterv1.ClusterNameLabel: "cluster1"},
+			},
+			Spec: infrav1.CloudStackMachineTemplateSpec{Template: infrav1.CloudStackMachineTemplateResource{
+				Spec: infrav1.CloudStackMachineSpec{
+					Offering: infrav1.CloudStackResourceIdentifier{Name: fakecloudstack.ServiceOfferingName},
+					Template: infrav1.CloudStackResourceIdentifier{Name: fakecloudstack.TemplateName},
+				},
+			}},
+		}
+	})
+
+	ginkgo.AfterEach(func() {
+		server.Close()
+	})
+
+	validateTemplate := func(validator *utils.PreflightValidator) ([]string, field.ErrorList) {
+		return validator.ValidateMachineSpec(context.Background(), template, &template.Spec.Template.Spec,
+			field.NewPath("spec", "template", "spec"))
+	}
+
+	ginkgo.It("admits machine specs whose resources resolve, caching them", func() {
+		validator := utils.NewPreflightValidator(k8sClient, utils.PreflightDeny, time.Hour, 10*time.Second)
+		warnings, errs := validateTemplate(validator)
+		gomega.Ω(warnings).Should(gomega.BeEmpty())
+		gomega.Ω(errs).Should(gomega.BeEmpty())
+
+		calls := server.Calls("listTemplates")
+		gomega.Ω(calls).ShouldNot(gomega.BeZero())
+		_, errs = validateTemplate(validator)
+		gomega.Ω(errs).Should(gomega.BeEmpty())
+		gomega.Ω(server.Calls("listTemplates")).Should(gomega.Equal(calls))
+	})
+
+	ginkgo.It("keeps checking machine specs after the admission request is cancelled", func() {
+		// Like the API server's client, fail reads on a cancelled context.
+		k8sClient = interceptor.NewClient(k8sClient.(client.WithWatch), interceptor.Funcs{
+			List: func(ctx context.Context, c client.WithWatch, list client.ObjectList, opts ...client.ListOption) error {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				return c.List(ctx, list, opts...)
+			},
+		})
+		validator := utils.NewPreflightValidator(k8sClient, utils.PreflightDeny, time.Hour, 10*time.Second)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		_, errs := validator.ValidateMachineSpec(ctx, template, &template.Spec.Template.Spec,
+			field.NewPath("spec", "template", "spec"))
+		gomega.Ω(errs).Should(gomega.BeEmpty())
+		gomega.Eventually(func() int { return server.Calls("listTemplates") }).ShouldNot(gomega.BeZero())
+	})
+
+	ginkgo.It("warns about machine spec resources that don't resolve", func() {
+		template.Spec.Template.Spec.Template.Name = "missing-template"
+		validator := utils.NewPreflightValidator(k8sClient, utils.PreflightWarn, time.Hour, 10*time.Second)
+
+		warnings, errs := validateTemplate(validator)
+		gomega.Ω(errs).Should(gomega.BeEmpty())
+		gomega.Ω(warnings).Should(gomega.ConsistOf(gomega.HavePrefix("spec.template.spec.template: ")))
+	})
+
+	ginkgo.It("denies machine specs with resources that don't resolve", func() {
+		template.Spec.Template.Spec.DataDisks = []infrav1.CloudStackDataDisk{{
+			Offering: infrav1.CloudStackResourceIdentifier{Name: "missing-disk-offering"},
+		}}
+		validator := utils.NewPreflightValidator(k8sClient, utils.PreflightDeny, time.Hour, 10*time.Second)
+
+		warnings, errs := validateTemplate(validator)
+		gomega.Ω(warnings).Should(gomega.BeEmpty())
+		gomega.Ω(errs).Should(gomega.HaveLen(1))
+		gomega.Ω(errs[0].Field).Should(gomega.Equal("spec.template.spec.dataDisks[0].offering"))
+	})
+
+	ginkgo.It("skips objects that don't belong to a cluster", func() {
+		template.Labels = nil
+		template.Spec.Template.Spec.Template.Name = "missing-template"
+		validator := utils.NewPreflightValidator(k8sClient, utils.PreflightDeny, time.Hour, 10*time.Second)
+
+		warnings, errs := validateTemplate(validator)
+		gomega.Ω(warnings).Should(gomega.BeEmpty())
+		gomega.Ω(errs).Should(gomega.BeEmpty())
+		gomega.Ω(server.Calls("listTemplates")).Should(gomega.BeZero())
+	})
+
+	ginkgo.It("denies failure domains whose zone doesn't resolve, but not networks CAPC creates", func() {
+		missingZone := fdSpec
+		missingZone.Name = "fd2"
+		missingZone.Zone.Name = "missing-zone"
+		newNetwork := fdSpec
+		newNetwork.Name = "fd3"
+		newNetwork.Zone.Network = infrav1.Network{Name: "new-isolated-network"}
+		validator := utils.NewPreflightValidator(k8sClient, utils.PreflightDeny, time.Hour, 10*time.Second)
+
+		warnings, errs := validator.ValidateFailureDomains(context.Background(), namespace,
+			[]infrav1.CloudStackFailureDomainSpec{fdSpec, missingZone, newNetwork}, field.NewPath("spec", "failureDomains"))
+		gomega.Ω(warnings).Should(gomega.BeEmpty())
+		gomega.Ω(errs).Should(gomega.HaveLen(1))
+		gomega.Ω(errs[0].Field).Should(gomega.Equal("spec.failureDomains[fd2].zone.name"))
+	})
+
+	ginkgo.It("only warns about failure domains whose credentials can't be found", func() {
+		fdSpec.ACSEndpoint.Name = "missing-credentials"
+		validator := utils.NewPreflightValidator(k8sClient, utils.PreflightDeny, time.Hour, 10*time.Second)
+
+		warnings, errs := validator.ValidateFailureDomains(context.Background(), namespace,
+			[]infrav1.CloudStackFailureDomainSpec{fdSpec}, field.NewPath("spec", "failureDomains"))
+		gomega.Ω(errs).Should(gomega.BeEmpty())
+		gomega.Ω(warnings).Should(gomega.ConsistOf(gomega.ContainSubstring("couldn't be validated against CloudStack")))
+	})
+})
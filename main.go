@@ -18,6 +18,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -48,6 +49,8 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
 
 	infrav1b1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta1"
 	infrav1b2 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta2"
@@ -74,6 +77,8 @@ func init() {
 	utilruntime.Must(infrav1b2.AddToScheme(scheme))
 	utilruntime.Must(infrav1b3.AddToScheme(scheme))
 	utilruntime.Must(controlplanev1.AddToScheme(scheme))
+	utilruntime.Must(expv1.AddToScheme(scheme))
+	utilruntime.Must(ipamv1.AddToScheme(scheme))
 	//+kubebuilder:scaffold:scheme
 }
 
@@ -92,8 +97,19 @@ type managerOpts struct {
 	CloudStackMachineConcurrency       int
 	CloudStackAffinityGroupConcurrency int
 	CloudStackFailureDomainConcurrency int
+	CloudStackMachinePoolConcurrency   int
 	EnableCloudStackCksSync            bool
+	EnableMachinePools                 bool
 	SyncPeriod                         time.Duration
+	FailureDomainHealthCheckInterval   time.Duration
+	EnableOrphanGC                     bool
+	OrphanGCInterval                   time.Duration
+	OrphanGCGracePeriod                time.Duration
+	OrphanGCDryRun                     bool
+	PreflightValidation                string
+	PreflightValidationCacheTTL        time.Duration
+	PreflightValidationTimeout         time.Duration
+	RestrictACSEndpointNamespace       bool
 }
 
 func setFlags() *managerOpts {
@@ -174,18 +190,84 @@ func setFlags() *managerOpts {
 		5,
 		"Maximum concurrent reconciles for CloudStackFailureDomain resources",
 	)
+	flag.IntVar(
+		&opts.CloudStackMachinePoolConcurrency,
+		"cloudstackmachinepool-concurrency",
+		10,
+		"Maximum concurrent reconciles for CloudStackMachinePool resources",
+	)
 	flag.DurationVar(
 		&opts.SyncPeriod,
 		"sync-period",
 		1*time.Minute,
 		"The minimum interval at which watched resources are reconciled (e.g. 15m)",
 	)
+	flag.DurationVar(
+		&opts.FailureDomainHealthCheckInterval,
+		"failure-domain-health-check-interval",
+		5*time.Minute,
+		"The interval at which the CloudStack endpoint, credentials, zone and network of failure domains are checked (e.g. 5m). With 0, they are only checked when failure domains are otherwise reconciled",
+	)
 	flag.BoolVar(
 		&opts.EnableCloudStackCksSync,
 		"enable-cloudstack-cks-sync",
 		false,
 		"Enable syncing of CloudStack clusters and machines with CKS clusters and machines",
 	)
+	flag.BoolVar(
+		&opts.EnableMachinePools,
+		"enable-machine-pools",
+		false,
+		"Enable the CloudStackMachinePool controller. Requires the MachinePool feature of Cluster API to be enabled",
+	)
+	flag.BoolVar(
+		&opts.EnableOrphanGC,
+		"enable-orphan-gc",
+		false,
+		"Enable the garbage collection of CloudStack resources created by CAPC for clusters that no longer exist. Requires watching all namespaces",
+	)
+	flag.DurationVar(
+		&opts.OrphanGCInterval,
+		"orphan-gc-interval",
+		1*time.Hour,
+		"The interval at which the resources visible with the credentials of each failure domain are checked for orphans (e.g. 1h)",
+	)
+	flag.DurationVar(
+		&opts.OrphanGCGracePeriod,
+		"orphan-gc-grace-period",
+		24*time.Hour,
+		"How long resources have to be orphaned before they're deleted (e.g. 24h)",
+	)
+	flag.BoolVar(
+		&opts.OrphanGCDryRun,
+		"orphan-gc-dry-run",
+		false,
+		"Only report orphaned resources in the logs and metrics instead of deleting them",
+	)
+	flag.StringVar(
+		&opts.PreflightValidation,
+		"preflight-validation",
+		"off",
+		"Whether the webhooks resolve the zones, networks, offerings and templates of objects against the CloudStack endpoints of their failure domains. One of off, warn or deny",
+	)
+	flag.DurationVar(
+		&opts.PreflightValidationCacheTTL,
+		"preflight-validation-cache-ttl",
+		5*time.Minute,
+		"How long the CloudStack resources resolved by preflight validation are cached (e.g. 5m)",
+	)
+	flag.DurationVar(
+		&opts.PreflightValidationTimeout,
+		"preflight-validation-timeout",
+		5*time.Second,
+		"How long preflight validation waits for CloudStack before admitting an object with a warning (e.g. 5s)",
+	)
+	flag.BoolVar(
+		&opts.RestrictACSEndpointNamespace,
+		"restrict-acs-endpoint-namespace",
+		false,
+		"Refuse failure domains whose acsEndpoint secret is in another namespace than the cluster's, so that clusters can't use the credentials of any secret CAPC can read",
+	)
 
 	flags.AddManagerOptions(flag.CommandLine, &managerOptions)
 
@@ -254,15 +336,28 @@ func main() {
 
 	// Register reconcilers with the controller manager.
 	base := utils.ReconcilerBase{
-		K8sClient:  mgr.GetClient(),
-		BaseLogger: ctrl.Log.WithName("controllers"),
-		Recorder:   mgr.GetEventRecorderFor("capc-controller-manager"),
-		Scheme:     mgr.GetScheme(),
+		K8sClient:                    mgr.GetClient(),
+		BaseLogger:                   ctrl.Log.WithName("controllers"),
+		Recorder:                     mgr.GetEventRecorderFor("capc-controller-manager"),
+		Scheme:                       mgr.GetScheme(),
+		RestrictACSEndpointNamespace: opts.RestrictACSEndpointNamespace,
 	}
 
 	ctx := ctrl.SetupSignalHandler()
 	setupReconcilers(ctx, base, *opts, mgr)
 	infrav1b3.K8sClient = base.K8sClient
+	webhookOpts := infrav1b3.WebhookOptions{RestrictACSEndpointNamespace: opts.RestrictACSEndpointNamespace}
+	switch opts.PreflightValidation {
+	case "off":
+	case utils.PreflightWarn, utils.PreflightDeny:
+		preflight := utils.NewPreflightValidator(
+			base.K8sClient, opts.PreflightValidation, opts.PreflightValidationCacheTTL, opts.PreflightValidationTimeout)
+		preflight.RestrictACSEndpointNamespace = opts.RestrictACSEndpointNamespace
+		webhookOpts.Preflight = preflight
+	default:
+		setupLog.Error(fmt.Errorf("invalid preflight validation mode %q", opts.PreflightValidation), "unable to start manager")
+		os.Exit(1)
+	}
 
 	// +kubebuilder:scaffold:builder
 
@@ -277,18 +372,22 @@ func main() {
 	}
 
 	// Start the controller manager.
-	if err = (&infrav1b3.CloudStackCluster{}).SetupWebhookWithManager(mgr); err != nil {
+	if err = (&infrav1b3.CloudStackCluster{}).SetupWebhookWithManager(mgr, webhookOpts); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "CloudStackCluster")
 		os.Exit(1)
 	}
-	if err = (&infrav1b3.CloudStackMachine{}).SetupWebhookWithManager(mgr); err != nil {
+	if err = (&infrav1b3.CloudStackMachine{}).SetupWebhookWithManager(mgr, webhookOpts); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "CloudStackMachine")
 		os.Exit(1)
 	}
-	if err = (&infrav1b3.CloudStackMachineTemplate{}).SetupWebhookWithManager(mgr); err != nil {
+	if err = (&infrav1b3.CloudStackMachineTemplate{}).SetupWebhookWithManager(mgr, webhookOpts); err != nil {
 		setupLog.Error(err, "unable to create webhook", "webhook", "CloudStackMachineTemplate")
 		os.Exit(1)
 	}
+	if err = (&infrav1b3.CloudStackClusterTemplate{}).SetupWebhookWithManager(mgr, webhookOpts); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "CloudStackClusterTemplate")
+		os.Exit(1)
+	}
 
 	setupLog.Info("starting manager")
 	if err := mgr.Start(ctx); err != nil {
@@ -314,10 +413,33 @@ func setupReconcilers(ctx context.Context, base utils.ReconcilerBase, opts manag
 		setupLog.Error(err, "unable to create controller", "controller", "CloudStackAffinityGroup")
 		os.Exit(1)
 	}
-	if err := (&controllers.CloudStackFailureDomainReconciler{ReconcilerBase: base}).SetupWithManager(mgr, controller.Options{MaxConcurrentReconciles: opts.CloudStackFailureDomainConcurrency}); err != nil {
+	if err := (&controllers.CloudStackFailureDomainReconciler{
+		ReconcilerBase:      base,
+		HealthCheckInterval: opts.FailureDomainHealthCheckInterval,
+	}).SetupWithManager(mgr, controller.Options{MaxConcurrentReconciles: opts.CloudStackFailureDomainConcurrency}); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "CloudStackFailureDomain")
 		os.Exit(1)
 	}
+	if opts.EnableMachinePools {
+		if err := (&controllers.CloudStackMachinePoolReconciler{ReconcilerBase: base}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: opts.CloudStackMachinePoolConcurrency}); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "CloudStackMachinePool")
+			os.Exit(1)
+		}
+	}
+	if opts.EnableOrphanGC {
+		// Resources of clusters in namespaces that aren't watched would look orphaned.
+		if opts.WatchingNamespace != "" {
+			setupLog.Error(errors.New("the orphan GC requires watching all namespaces"), "unable to create controller", "controller", "OrphanGC")
+			os.Exit(1)
+		}
+		if err := (&controllers.OrphanGCReconciler{
+			ReconcilerBase: base,
+			Collector:      utils.NewOrphanCollector(opts.OrphanGCInterval, opts.OrphanGCGracePeriod, opts.OrphanGCDryRun),
+		}).SetupWithManager(mgr, controller.Options{}); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "OrphanGC")
+			os.Exit(1)
+		}
+	}
 	if opts.EnableCloudStackCksSync {
 		if err := (&controllers.CksClusterReconciler{ReconcilerBase: base}).SetupWithManager(mgr, controller.Options{}); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "CKSClusterController")
@@ -23,14 +23,25 @@ import (
 )
 
 const (
-	AntiAffinityGroupType = "host anti-affinity"
-	AffinityGroupType     = "host affinity"
+	AntiAffinityGroupType          = "host anti-affinity"
+	AffinityGroupType              = "host affinity"
+	NonStrictAntiAffinityGroupType = "non-strict host anti-affinity"
+	NonStrictAffinityGroupType     = "non-strict host affinity"
 )
 
+// strictAffinityGroupTypes maps the non-strict affinity group types to the strict types they fall back to on
+// CloudStack versions that don't support them.
+var strictAffinityGroupTypes = map[string]string{
+	NonStrictAntiAffinityGroupType: AntiAffinityGroupType,
+	NonStrictAffinityGroupType:     AffinityGroupType,
+}
+
 type AffinityGroup struct {
 	Type string
 	Name string
 	ID   string
+	// Tags are added to the group when it's created.
+	Tags map[string]string
 }
 
 type AffinityGroupIface interface {
@@ -71,8 +82,19 @@ func (c *client) FetchAffinityGroup(group *AffinityGroup) (reterr error) {
 	return errors.Errorf(`could not fetch AffinityGroup by name "%s" or id "%s"`, group.Name, group.ID)
 }
 
+// GetOrCreateAffinityGroup fetches the affinity group, or creates it with its tags if it doesn't exist. A non-strict
+// type is replaced by its strict counterpart if CloudStack doesn't support it, so callers should check the group's type
+// afterwards.
 func (c *client) GetOrCreateAffinityGroup(group *AffinityGroup) (retErr error) {
 	if err := c.FetchAffinityGroup(group); err != nil { // Group not found?
+		if strictType, found := strictAffinityGroupTypes[group.Type]; found {
+			supported, err := c.affinityGroupTypeSupported(group.Type)
+			if err != nil {
+				return err
+			} else if !supported {
+				group.Type = strictType
+			}
+		}
 		p := c.cs.AffinityGroup.NewCreateAffinityGroupParams(group.Name, group.Type)
 		p.SetName(group.Name)
 		setIfNotEmpty(c.user.Project.ID, p.SetProjectid)
@@ -82,10 +104,28 @@ func (c *client) GetOrCreateAffinityGroup(group *AffinityGroup) (retErr error) {
 			return err
 		}
 		group.ID = resp.Id
+		if len(group.Tags) > 0 {
+			return c.tagCreatedResource(ResourceTypeAffinityGroup, group.ID, group.Tags)
+		}
 	}
 	return nil
 }
 
+// affinityGroupTypeSupported checks whether CloudStack offers an affinity group type.
+func (c *client) affinityGroupTypeSupported(groupType string) (bool, error) {
+	resp, err := c.cs.AffinityGroup.ListAffinityGroupTypes(c.cs.AffinityGroup.NewListAffinityGroupTypesParams())
+	if err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return false, errors.Wrap(err, "listing affinity group types")
+	}
+	for _, t := range resp.AffinityGroupTypes {
+		if t.Type == groupType {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
 func (c *client) DeleteAffinityGroup(group *AffinityGroup) (retErr error) {
 	p := c.cs.AffinityGroup.NewDeleteAffinityGroupParams()
 	setIfNotEmpty(group.ID, p.SetId)
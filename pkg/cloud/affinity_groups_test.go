@@ -25,6 +25,7 @@ import (
 	gomock "go.uber.org/mock/gomock"
 	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
 	dummies "sigs.k8s.io/cluster-api-provider-cloudstack/test/dummies/v1beta3"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/test/fakecloudstack"
 )
 
 var _ = ginkgo.Describe("AffinityGroup Unit Tests", func() {
@@ -81,6 +82,21 @@ var _ = ginkgo.Describe("AffinityGroup Unit Tests", func() {
 			gomega.Ω(client.GetOrCreateAffinityGroup(dummies.AffinityGroup)).Should(gomega.Succeed())
 		})
 
+		ginkgo.It("tags a created affinity group", func() {
+			rs := mockClient.Resourcetags.(*cloudstack.MockResourcetagsServiceIface)
+			dummies.AffinityGroup.Tags = cloud.ResourceTags(dummies.CSCluster, nil)
+			ags.EXPECT().GetAffinityGroupByID(dummies.AffinityGroup.ID, gomock.Any()).Return(nil, -1, fakeError)
+			ags.EXPECT().NewCreateAffinityGroupParams(dummies.AffinityGroup.Name, dummies.AffinityGroup.Type).
+				Return(&cloudstack.CreateAffinityGroupParams{})
+			ags.EXPECT().CreateAffinityGroup(gomock.Any()).Return(&cloudstack.CreateAffinityGroupResponse{Id: "new-group-id"}, nil)
+			rs.EXPECT().NewCreateTagsParams([]string{"new-group-id"}, string(cloud.ResourceTypeAffinityGroup), dummies.AffinityGroup.Tags).
+				Return(&cloudstack.CreateTagsParams{})
+			rs.EXPECT().CreateTags(gomock.Any()).Return(&cloudstack.CreateTagsResponse{}, nil)
+
+			gomega.Ω(client.GetOrCreateAffinityGroup(dummies.AffinityGroup)).Should(gomega.Succeed())
+			gomega.Ω(dummies.AffinityGroup.ID).Should(gomega.Equal("new-group-id"))
+		})
+
 		ginkgo.It("creates an affinity group if Name provided returns more than one affinity group", func() {
 			dummies.AffinityGroup.ID = "" // Force name fetching.
 			agp := &cloudstack.CreateAffinityGroupParams{}
@@ -186,4 +202,38 @@ var _ = ginkgo.Describe("AffinityGroup Unit Tests", func() {
 		vms.EXPECT().StartVirtualMachine(vmp).Return(&cloudstack.StartVirtualMachineResponse{}, nil)
 		gomega.Ω(client.DisassociateAffinityGroup(dummies.CSMachine1, *dummies.AffinityGroup)).Should(gomega.Succeed())
 	})
+
+	ginkgo.Context("against a fake CloudStack", func() {
+		var fake *fakeCloud
+
+		ginkgo.BeforeEach(func() {
+			fake = newFakeCloud()
+		})
+
+		ginkgo.It("creates, finds and deletes affinity groups", func() {
+			group := &cloud.AffinityGroup{Name: "test-group", Type: "host anti-affinity"}
+			gomega.Ω(fake.client.GetOrCreateAffinityGroup(group)).Should(gomega.Succeed())
+			gomega.Ω(group.ID).ShouldNot(gomega.BeEmpty())
+
+			fetched := &cloud.AffinityGroup{Name: "test-group"}
+			gomega.Ω(fake.client.FetchAffinityGroup(fetched)).Should(gomega.Succeed())
+			gomega.Ω(fetched.ID).Should(gomega.Equal(group.ID))
+
+			gomega.Ω(fake.client.DeleteAffinityGroup(group)).Should(gomega.Succeed())
+			gomega.Ω(fake.server.List(fakecloudstack.KindAffinityGroup)).Should(gomega.BeEmpty())
+		})
+
+		ginkgo.It("falls back to strict affinity groups on CloudStack versions without non-strict ones", func() {
+			group := &cloud.AffinityGroup{Name: "soft-group", Type: cloud.NonStrictAntiAffinityGroupType}
+			gomega.Ω(fake.client.GetOrCreateAffinityGroup(group)).Should(gomega.Succeed())
+			gomega.Ω(group.Type).Should(gomega.Equal(cloud.NonStrictAntiAffinityGroupType))
+
+			fake.server.SetCapability("cloudstackversion", "4.17.2.0")
+			group = &cloud.AffinityGroup{Name: "legacy-group", Type: cloud.NonStrictAntiAffinityGroupType}
+			gomega.Ω(fake.client.GetOrCreateAffinityGroup(group)).Should(gomega.Succeed())
+			gomega.Ω(group.Type).Should(gomega.Equal(cloud.AntiAffinityGroupType))
+			created, _ := fake.server.Find(fakecloudstack.KindAffinityGroup, "legacy-group")
+			gomega.Ω(created["type"]).Should(gomega.Equal(cloud.AntiAffinityGroupType))
+		})
+	})
 })
@@ -0,0 +1,106 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"encoding/json"
+	"fmt"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+)
+
+// AsyncJobIface polls CloudStack asynchronous jobs started by the non-blocking API client.
+type AsyncJobIface interface {
+	QueryAsyncJob(string) (bool, error)
+}
+
+// CloudStack API commands whose asynchronous jobs are tracked in a CloudStackMachine's status.
+const (
+	DeployVMCommand     = "deployVirtualMachine"
+	DestroyVMCommand    = "destroyVirtualMachine"
+	StopVMCommand       = "stopVirtualMachine"
+	StartVMCommand      = "startVirtualMachine"
+	ScaleVMCommand      = "scaleVirtualMachine"
+	CreateVolumeCommand = "createVolume"
+	AttachVolumeCommand = "attachVolume"
+)
+
+// Job statuses reported by queryAsyncJobResult.
+const (
+	asyncJobPending   = 0
+	asyncJobSucceeded = 1
+)
+
+// AsyncJobError is the error of a CloudStack asynchronous job that failed.
+type AsyncJobError struct {
+	JobID string
+	Code  int
+	Text  string
+}
+
+func (e *AsyncJobError) Error() string {
+	return fmt.Sprintf("CloudStack job %s failed with error code %d: %s", e.JobID, e.Code, e.Text)
+}
+
+// QueryAsyncJob polls a CloudStack asynchronous job without waiting for it. It reports whether the job is done,
+// and returns an *AsyncJobError if the job failed.
+func (c *client) QueryAsyncJob(jobID string) (bool, error) {
+	p := c.cs.Asyncjob.NewQueryAsyncJobResultParams(jobID)
+	resp, err := c.cs.Asyncjob.QueryAsyncJobResult(p)
+	if err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return false, err
+	}
+
+	switch resp.Jobstatus {
+	case asyncJobPending:
+		return false, nil
+	case asyncJobSucceeded:
+		return true, nil
+	}
+	jobErr := &AsyncJobError{JobID: jobID, Code: resp.Jobresultcode}
+	var result struct {
+		ErrorCode int    `json:"errorcode"`
+		ErrorText string `json:"errortext"`
+	}
+	if err := json.Unmarshal(resp.Jobresult, &result); err == nil {
+		jobErr.Code, jobErr.Text = result.ErrorCode, result.ErrorText
+	}
+	return true, jobErr
+}
+
+// setMachineAsyncJob records a job started for the machine's instance, to be polled on later reconciliations.
+func setMachineAsyncJob(csMachine *infrav1.CloudStackMachine, command, jobID string) {
+	csMachine.Status.AsyncJob = &infrav1.CloudStackAsyncJob{ID: jobID, Command: command, StartTime: metav1.Now()}
+}
+
+// pollMachineAsyncJob polls the job recorded in the machine's status if it was started by the given command. It
+// reports whether such a job is still pending. Finished jobs are removed from the status, and the error of a failed
+// job is returned.
+func (c *client) pollMachineAsyncJob(csMachine *infrav1.CloudStackMachine, command string) (bool, error) {
+	job := csMachine.Status.AsyncJob
+	if job == nil || job.Command != command {
+		return false, nil
+	}
+	done, err := c.QueryAsyncJob(job.ID)
+	if !done {
+		return err == nil, err
+	}
+	csMachine.Status.AsyncJob = nil
+	return false, err
+}
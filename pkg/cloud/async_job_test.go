@@ -0,0 +1,46 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud_test
+
+import (
+	"github.com/onsi/ginkgo/v2"
+	gomega "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
+)
+
+var _ = ginkgo.Describe("Async Job", func() {
+	var fake *fakeCloud
+
+	ginkgo.BeforeEach(func() {
+		fake = newFakeCloud()
+	})
+
+	ginkgo.It("reports the error of a failed deployment job", func() {
+		gomega.Ω(fake.client.ResolveZone(&fake.fd.Spec.Zone)).Should(gomega.Succeed())
+		fake.server.SetAsyncJobPolls(1)
+		fake.server.FailNextJob("deployVirtualMachine", "Unable to start VM")
+		gomega.Ω(fake.getOrCreateVMInstance("")).Should(gomega.Succeed())
+		gomega.Ω(fake.csMachine.Status.AsyncJob).ShouldNot(gomega.BeNil())
+		gomega.Ω(fake.csMachine.Status.AsyncJob.Command).Should(gomega.Equal(cloud.DeployVMCommand))
+
+		gomega.Ω(fake.getOrCreateVMInstance("")).Should(gomega.Succeed())
+		gomega.Ω(fake.getOrCreateVMInstance("")).Should(
+			gomega.MatchError(gomega.ContainSubstring("Unable to start VM")))
+		gomega.Ω(fake.csMachine.Status.AsyncJob).Should(gomega.BeNil())
+	})
+})
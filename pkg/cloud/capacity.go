@@ -0,0 +1,120 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+)
+
+// CapacityIface checks whether zones have room for more machines.
+type CapacityIface interface {
+	GetZoneCapacityForMachine(*infrav1.CloudStackMachine, string) (int64, error)
+}
+
+// Capacity types reported by listCapacity.
+const (
+	capacityTypeMemory = 0 // In bytes.
+	capacityTypeCPU    = 1 // In MHz.
+)
+
+// insufficientCapacityErrorCode is the API error code CloudStack reports when no host has room for a VM.
+const insufficientCapacityErrorCode = 533
+
+// IsInsufficientCapacityError reports whether an error of deploying a VM, either of the API call or of its job, is
+// due to a lack of capacity in the zone.
+func IsInsufficientCapacityError(err error) bool {
+	if err == nil {
+		return false
+	}
+	var jobErr *AsyncJobError
+	if errors.As(err, &jobErr) {
+		return jobErr.Code == insufficientCapacityErrorCode
+	}
+	return strings.Contains(err.Error(), "CloudStack API error "+strconv.Itoa(insufficientCapacityErrorCode)+" ")
+}
+
+// GetZoneCapacityForMachine returns how many more instances with the machine's service offering fit in the zone, by
+// the zone's CPU and memory capacity and the resource limits of the account, domain and project. The zone's capacity
+// is only considered if the user may list it, which requires an admin account. math.MaxInt64 is returned when
+// nothing limits the number of instances.
+func (c *client) GetZoneCapacityForMachine(csMachine *infrav1.CloudStackMachine, zoneID string) (int64, error) {
+	offering, err := c.ResolveServiceOffering(csMachine, zoneID)
+	if err != nil {
+		return 0, err
+	}
+
+	room := min(
+		limitRoom(&offering, c.user.Account.CPUAvailable, c.user.Account.MemoryAvailable, c.user.Account.VMAvailable),
+		limitRoom(&offering, c.user.Account.Domain.CPUAvailable, c.user.Account.Domain.MemoryAvailable,
+			c.user.Account.Domain.VMAvailable))
+	if c.user.Project.ID != "" {
+		room = min(room, limitRoom(&offering, c.user.Project.CPUAvailable, c.user.Project.MemoryAvailable,
+			c.user.Project.VMAvailable))
+	}
+
+	p := c.cs.SystemCapacity.NewListCapacityParams()
+	p.SetZoneid(zoneID)
+	p.SetFetchlatest(true)
+	resp, err := c.cs.SystemCapacity.ListCapacity(p)
+	if err != nil {
+		if strings.Contains(err.Error(), "not available for user") {
+			return room, nil
+		}
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return 0, err
+	}
+	for _, capacity := range resp.Capacity {
+		free := capacity.Capacitytotal - max(capacity.Capacityused, capacity.Capacityallocated)
+		switch capacity.Type {
+		case capacityTypeCPU:
+			room = min(room, fitting(free, int64(offering.Cpunumber)*int64(offering.Cpuspeed)))
+		case capacityTypeMemory:
+			room = min(room, fitting(free, int64(offering.Memory)<<20))
+		}
+	}
+	return room, nil
+}
+
+// limitRoom returns how many instances with the offering fit in the available CPU, memory and VM count of resource
+// limits. Unlimited or unparsable values don't limit the number of instances.
+func limitRoom(offering *cloudstack.ServiceOffering, cpuAvailable, memoryAvailable, vmAvailable string) int64 {
+	room := int64(math.MaxInt64)
+	if cpu, err := strconv.ParseInt(cpuAvailable, 10, 64); err == nil {
+		room = min(room, fitting(cpu, int64(offering.Cpunumber)))
+	}
+	if memory, err := strconv.ParseInt(memoryAvailable, 10, 64); err == nil {
+		room = min(room, fitting(memory, int64(offering.Memory)))
+	}
+	if vms, err := strconv.ParseInt(vmAvailable, 10, 64); err == nil {
+		room = min(room, max(vms, 0))
+	}
+	return room
+}
+
+// fitting returns how many times size fits in available.
+func fitting(available, size int64) int64 {
+	if size <= 0 {
+		return math.MaxInt64
+	}
+	return max(available, 0) / size
+}
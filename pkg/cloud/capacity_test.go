@@ -0,0 +1,75 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud_test
+
+import (
+	"github.com/onsi/ginkgo/v2"
+	gomega "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/test/fakecloudstack"
+)
+
+var _ = ginkgo.Describe("Capacity", func() {
+	var fake *fakeCloud
+
+	ginkgo.BeforeEach(func() {
+		fake = newFakeCloud()
+	})
+
+	ginkgo.It("reports the room left in a zone and fails deployments that don't fit", func() {
+		fake.useSharedNetwork()
+		room, err := fake.client.GetZoneCapacityForMachine(fake.csMachine, fake.fd.Spec.Zone.ID)
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Ω(room).Should(gomega.BeEquivalentTo(64)) // CPU of two hosts for 2 vCPUs at 1000 MHz each.
+
+		// Leave room for a single machine on each host.
+		for _, host := range fake.server.List(fakecloudstack.KindHost) {
+			gomega.Ω(fake.server.Update(fakecloudstack.KindHost, host["id"].(string), fakecloudstack.Resource{
+				"memorytotal": int64(2) << 30,
+			})).Should(gomega.Succeed())
+		}
+		gomega.Ω(fake.getOrCreateVMInstance("")).Should(gomega.Succeed())
+		room, err = fake.client.GetZoneCapacityForMachine(fake.csMachine, fake.fd.Spec.Zone.ID)
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Ω(room).Should(gomega.BeEquivalentTo(1))
+
+		for _, name := range []string{"second-machine", "third-machine"} {
+			fake.csMachine = fake.csMachine.DeepCopy()
+			fake.csMachine.Name, fake.csMachine.Spec.InstanceID = name, nil
+			err = fake.getOrCreateVMInstance("")
+		}
+		gomega.Ω(cloud.IsInsufficientCapacityError(err)).Should(gomega.BeTrue())
+		gomega.Ω(fake.server.List(fakecloudstack.KindVirtualMachine)).Should(gomega.HaveLen(2))
+	})
+
+	ginkgo.It("only limits the room left in a zone by resource limits for non-admin users", func() {
+		gomega.Ω(fake.client.ResolveZone(&fake.fd.Spec.Zone)).Should(gomega.Succeed())
+		_, err := fake.server.AddAccount(fakecloudstack.RootDomainName, "tenant-account", "tenant-user")
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		account, _ := fake.server.Find(fakecloudstack.KindAccount, "tenant-account")
+		gomega.Ω(fake.server.Update(fakecloudstack.KindAccount, account["id"].(string), fakecloudstack.Resource{
+			"cpuavailable": "5",
+		})).Should(gomega.Succeed())
+
+		tenantClient, err := fake.client.NewClientInDomainAndAccount(fakecloudstack.RootDomainName, "tenant-account", "")
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		room, err := tenantClient.GetZoneCapacityForMachine(fake.csMachine, fake.fd.Spec.Zone.ID)
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Ω(room).Should(gomega.BeEquivalentTo(2))
+	})
+})
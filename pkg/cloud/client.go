@@ -46,6 +46,10 @@ type Client interface {
 	IsoNetworkIface
 	UserCredIFace
 	VPCIface
+	AsyncJobIface
+	CapacityIface
+	HealthIface
+	OrphanIface
 	NewClientInDomainAndAccount(string, string, string) (Client, error)
 }
 
@@ -76,6 +80,11 @@ type SecretConfig struct {
 var clientCache *ttlcache.Cache[string, *client]
 var cacheMutex sync.Mutex
 
+// endpointConfigs holds the config clients were last successfully created from for each endpoint secret, by the
+// secret's namespace and name.
+var endpointConfigs = map[string]Config{}
+var endpointConfigsMutex sync.Mutex
+
 var NewAsyncClient = cloudstack.NewAsyncClient
 var NewClient = cloudstack.NewClient
 
@@ -102,7 +111,25 @@ func UnmarshalAllSecretConfigs(in []byte, out *[]SecretConfig) error {
 	return nil
 }
 
-// NewClientFromK8sSecret returns a client from a k8s secret
+// CredentialsRotationError is returned along with a client of an endpoint secret's previous content when no client
+// could be created from its new content.
+type CredentialsRotationError struct {
+	Secret string
+	Err    error
+}
+
+func (e *CredentialsRotationError) Error() string {
+	return fmt.Sprintf("new credentials of secret %s can't be used, still using the previous ones: %v", e.Secret, e.Err)
+}
+
+func (e *CredentialsRotationError) Unwrap() error {
+	return e.Err
+}
+
+// NewClientFromK8sSecret returns a client from a k8s secret. When the content of the secret changed since a client was
+// last created from it, the new credentials are validated by creating a client from them before the clients of the
+// previous content are invalidated. If that fails while the previous credentials still work, a client of the previous
+// content is returned along with a *CredentialsRotationError.
 func NewClientFromK8sSecret(endpointSecret *corev1.Secret, clientConfig *corev1.ConfigMap, project string) (Client, error) {
 	endpointSecretStrings := map[string]string{}
 	for k, v := range endpointSecret.Data {
@@ -112,19 +139,69 @@ func NewClientFromK8sSecret(endpointSecret *corev1.Secret, clientConfig *corev1.
 	if err != nil {
 		return nil, err
 	}
-	return NewClientFromBytesConfig(bytes, clientConfig, project)
+	config, err := unmarshalConfig(bytes)
+	if err != nil {
+		return nil, err
+	}
+
+	// The lock only guards endpointConfigs, clients are created without holding it so that a slow or unreachable
+	// endpoint doesn't hold up the clients of every other secret.
+	secretName := endpointSecret.Namespace + "/" + endpointSecret.Name
+	endpointConfigsMutex.Lock()
+	previous, known := endpointConfigs[secretName]
+	endpointConfigsMutex.Unlock()
+
+	c, err := NewClientFromConf(config, clientConfig, project)
+	if err == nil {
+		recordEndpointConfig(secretName, previous, known, config)
+		return c, nil
+	}
+	if !known || previous == config {
+		return nil, err
+	}
+	previousClient, previousErr := NewClientFromConf(previous, clientConfig, project)
+	if previousErr != nil {
+		return nil, err
+	}
+	return previousClient, &CredentialsRotationError{Secret: secretName, Err: err}
+}
+
+// recordEndpointConfig records config as the one clients of a secret were last created from, and invalidates the
+// clients of the previous one. Nothing is recorded when the secret's entry changed since previous was read, as another
+// reconciliation then already handled a newer content of the secret.
+func recordEndpointConfig(secretName string, previous Config, known bool, config Config) {
+	endpointConfigsMutex.Lock()
+	defer endpointConfigsMutex.Unlock()
+
+	current, stillKnown := endpointConfigs[secretName]
+	if stillKnown != known || current != previous {
+		return
+	}
+	if known && previous != config {
+		InvalidateClients(previous)
+	}
+	endpointConfigs[secretName] = config
 }
 
 // NewClientFromBytesConfig returns a client from a bytes array that unmarshals to a yaml config.
 func NewClientFromBytesConfig(conf []byte, clientConfig *corev1.ConfigMap, project string) (Client, error) {
+	config, err := unmarshalConfig(conf)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewClientFromConf(config, clientConfig, project)
+}
+
+// unmarshalConfig parses a yaml config.
+func unmarshalConfig(conf []byte) (Config, error) {
 	r := bytes.NewReader(conf)
 	dec := yaml.NewDecoder(r)
 	var config Config
 	if err := dec.Decode(&config); err != nil {
-		return nil, err
+		return Config{}, err
 	}
-
-	return NewClientFromConf(config, clientConfig, project)
+	return config, nil
 }
 
 // NewClientFromYamlPath returns a client from a yaml config at path.
@@ -219,11 +296,28 @@ func (c *client) NewClientInDomainAndAccount(domain string, account string, proj
 		return nil, errors.Errorf(
 			"could not find sufficient user (with API keys) in domain/account %s/%s", domain, account)
 	}
-	c.config.APIKey = user.APIKey
-	c.config.SecretKey = user.SecretKey
-	c.user = user
+	// Copy the config rather than modify it, it identifies this client in the cache.
+	config := c.config
+	config.APIKey = user.APIKey
+	config.SecretKey = user.SecretKey
+
+	return NewClientFromConf(config, nil, project)
+}
+
+// InvalidateClients drops the clients created from a config, in all projects, from the cache. Clients created for
+// other domains and accounts with them have credentials of their own and are kept.
+func InvalidateClients(conf Config) {
+	cacheMutex.Lock()
+	defer cacheMutex.Unlock()
 
-	return NewClientFromConf(c.config, nil, project)
+	if clientCache == nil {
+		return
+	}
+	for key, item := range clientCache.Items() {
+		if item.Value().config == conf {
+			clientCache.Delete(key)
+		}
+	}
 }
 
 // NewClientFromCSAPIClient creates a client from a CloudStack-Go API client. Used only for testing.
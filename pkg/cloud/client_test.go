@@ -17,6 +17,7 @@ limitations under the License.
 package cloud_test
 
 import (
+	"errors"
 	"os"
 	"time"
 
@@ -28,6 +29,7 @@ import (
 	gomock "go.uber.org/mock/gomock"
 	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
 	dummies "sigs.k8s.io/cluster-api-provider-cloudstack/test/dummies/v1beta1"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/test/fakecloudstack"
 	"sigs.k8s.io/cluster-api-provider-cloudstack/test/helpers"
 )
 
@@ -191,4 +193,58 @@ var _ = ginkgo.Describe("Client", func() {
 			gomega.Ω(result1).Should(gomega.Equal(result2))
 		})
 	})
+
+	ginkgo.Context("against a fake CloudStack", func() {
+		var fake *fakeCloud
+
+		ginkgo.BeforeEach(func() {
+			fake = newFakeCloud()
+		})
+
+		ginkgo.It("drops the clients of an endpoint secret's previous credentials once the new ones work", func() {
+			secret := fake.server.EndpointSecret("rotated-credentials", "default")
+			previous, err := cloud.NewClientFromK8sSecret(secret, nil, "")
+			gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+
+			user, err := fake.server.AddAccount(fakecloudstack.RootDomainName, "rotation-account", "rotation-user")
+			gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+			previousData := secret.Data
+			secret.Data = map[string][]byte{}
+			for k, v := range previousData {
+				secret.Data[k] = v
+			}
+			secret.Data["api-key"] = []byte(user["apikey"].(string))
+			secret.Data["secret-key"] = []byte(user["secretkey"].(string))
+			rotated, err := cloud.NewClientFromK8sSecret(secret, nil, "")
+			gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+			gomega.Ω(rotated).ShouldNot(gomega.BeIdenticalTo(previous))
+
+			// The client of the previous credentials isn't cached anymore.
+			recreated, err := cloud.NewClientFromConf(fake.server.Config(), nil, "")
+			gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+			gomega.Ω(recreated).ShouldNot(gomega.BeIdenticalTo(previous))
+		})
+
+		ginkgo.It("keeps using an endpoint secret's previous credentials when the new ones are rejected", func() {
+			secret := fake.server.EndpointSecret("mistyped-credentials", "default")
+			previous, err := cloud.NewClientFromK8sSecret(secret, nil, "")
+			gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+
+			secret.Data["secret-key"] = []byte("mistyped")
+			current, err := cloud.NewClientFromK8sSecret(secret, nil, "")
+			var rotationErr *cloud.CredentialsRotationError
+			gomega.Ω(errors.As(err, &rotationErr)).Should(gomega.BeTrue())
+			gomega.Ω(rotationErr.Secret).Should(gomega.Equal("default/mistyped-credentials"))
+			gomega.Ω(cloud.IsCredentialsRejectedError(err)).Should(gomega.BeTrue())
+			gomega.Ω(current).Should(gomega.BeIdenticalTo(previous))
+
+			// Without previous credentials, there's nothing to fall back to.
+			secret = fake.server.EndpointSecret("new-credentials", "default")
+			secret.Data["secret-key"] = []byte("mistyped")
+			current, err = cloud.NewClientFromK8sSecret(secret, nil, "")
+			gomega.Ω(errors.As(err, &rotationErr)).Should(gomega.BeFalse())
+			gomega.Ω(cloud.IsCredentialsRejectedError(err)).Should(gomega.BeTrue())
+			gomega.Ω(current).Should(gomega.BeNil())
+		})
+	})
 })
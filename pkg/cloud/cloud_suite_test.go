@@ -24,9 +24,14 @@ import (
 	"github.com/apache/cloudstack-go/v2/cloudstack"
 	"github.com/onsi/ginkgo/v2"
 	gomega "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/uuid"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
 	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
 	dummies "sigs.k8s.io/cluster-api-provider-cloudstack/test/dummies/v1beta3"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/test/fakecloudstack"
 	"sigs.k8s.io/cluster-api-provider-cloudstack/test/helpers"
 )
 
@@ -95,3 +100,77 @@ func FetchIntegTestResources() {
 	gomega.Ω(realCloudClient.GetOrCreateIsolatedNetwork(
 		dummies.CSFailureDomain1, dummies.CSISONet1, dummies.CSCluster)).Should(gomega.Succeed())
 }
+
+// fakeCloud is a client of a fake CloudStack server, along with a cluster, its failure domain and isolated network,
+// and a machine to reconcile against it.
+type fakeCloud struct {
+	server    *fakecloudstack.Server
+	client    cloud.Client
+	csCluster *infrav1.CloudStackCluster
+	fd        *infrav1.CloudStackFailureDomain
+	isoNet    *infrav1.CloudStackIsolatedNetwork
+	csMachine *infrav1.CloudStackMachine
+	machine   *clusterv1.Machine
+}
+
+// newFakeCloud starts a fake CloudStack server, closed when the spec ends. The failure domain's zone and network
+// aren't resolved yet.
+func newFakeCloud() *fakeCloud {
+	// Other specs swap the constructors of CloudStack clients for ones returning mocks.
+	newClient, newAsyncClient := cloud.NewClient, cloud.NewAsyncClient
+	cloud.NewClient, cloud.NewAsyncClient = cloudstack.NewClient, cloudstack.NewAsyncClient
+	ginkgo.DeferCleanup(func() {
+		cloud.NewClient, cloud.NewAsyncClient = newClient, newAsyncClient
+	})
+
+	server := fakecloudstack.NewServer()
+	ginkgo.DeferCleanup(server.Close)
+	fakeClient, err := cloud.NewClientFromConf(server.Config(), nil, "")
+	gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+
+	return &fakeCloud{
+		server: server,
+		client: fakeClient,
+		csCluster: &infrav1.CloudStackCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default", UID: uuid.NewUUID()},
+		},
+		fd: &infrav1.CloudStackFailureDomain{Spec: infrav1.CloudStackFailureDomainSpec{
+			Name: "fd1",
+			Zone: infrav1.CloudStackZoneSpec{
+				Name:    fakecloudstack.ZoneName,
+				Network: infrav1.Network{Name: "test-cluster-net", Type: cloud.NetworkTypeIsolated},
+			},
+		}},
+		isoNet: &infrav1.CloudStackIsolatedNetwork{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster-net", Namespace: "default"},
+			Spec:       infrav1.CloudStackIsolatedNetworkSpec{Name: "test-cluster-net"},
+		},
+		csMachine: &infrav1.CloudStackMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-machine", Namespace: "default"},
+			Spec: infrav1.CloudStackMachineSpec{
+				Offering: infrav1.CloudStackResourceIdentifier{Name: fakecloudstack.ServiceOfferingName},
+				Template: infrav1.CloudStackResourceIdentifier{Name: fakecloudstack.TemplateName},
+			},
+		},
+		machine: &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "test-machine", Namespace: "default"}},
+	}
+}
+
+// useIsolatedNetwork resolves the failure domain's zone, and creates its isolated network.
+func (f *fakeCloud) useIsolatedNetwork() {
+	gomega.Ω(f.client.ResolveZone(&f.fd.Spec.Zone)).Should(gomega.Succeed())
+	gomega.Ω(f.client.GetOrCreateIsolatedNetwork(f.fd, f.isoNet, f.csCluster)).Should(gomega.Succeed())
+	f.fd.Spec.Zone.Network.ID = f.isoNet.Spec.ID
+}
+
+// useSharedNetwork resolves the failure domain's zone, and places its machines in the fake's shared network.
+func (f *fakeCloud) useSharedNetwork() {
+	shared, _ := f.server.Find(fakecloudstack.KindNetwork, fakecloudstack.SharedNetworkName)
+	gomega.Ω(f.client.ResolveZone(&f.fd.Spec.Zone)).Should(gomega.Succeed())
+	f.fd.Spec.Zone.Network = infrav1.Network{ID: shared["id"].(string), Name: fakecloudstack.SharedNetworkName}
+}
+
+// getOrCreateVMInstance reconciles the machine's instance in the failure domain.
+func (f *fakeCloud) getOrCreateVMInstance(userData string) error {
+	return f.client.GetOrCreateVMInstance(f.csMachine, f.machine, f.csCluster, f.fd, nil, userData)
+}
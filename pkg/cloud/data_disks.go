@@ -0,0 +1,307 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"bytes"
+	"fmt"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+)
+
+const (
+	VolumeTypeDataDisk    = "DATADISK"
+	DefaultDataDiskFSType = "ext4"
+
+	// cdromDeviceID is the device ID CloudStack reserves for the CD-ROM drive of instances.
+	cdromDeviceID = 3
+	// dataDiskScriptName is the name of the user data part mounting data disks. cloud-init runs scripts in order of
+	// their names, so it runs before the runcmd script that bootstraps the node.
+	dataDiskScriptName = "capc-data-disks.sh"
+)
+
+// dataDiskVolumeName returns the name of the volume of a machine's data disk.
+func dataDiskVolumeName(csMachine *infrav1.CloudStackMachine, disk infrav1.CloudStackDataDisk) string {
+	return csMachine.Name + "-" + disk.Name
+}
+
+// dataDiskDeviceIDs returns the device IDs the data disks of a machine are attached at. The disk of the machine's
+// DiskOffering is attached at deploy time with the first ID after the root disk's.
+func dataDiskDeviceIDs(csMachine *infrav1.CloudStackMachine) []int64 {
+	next := int64(1)
+	if csMachine.Spec.DiskOffering.ID != "" || csMachine.Spec.DiskOffering.Name != "" {
+		next++
+	}
+	ids := make([]int64, 0, len(csMachine.Spec.DataDisks))
+	for range csMachine.Spec.DataDisks {
+		if next == cdromDeviceID {
+			next++
+		}
+		ids = append(ids, next)
+		next++
+	}
+	return ids
+}
+
+// dataDiskDevice returns the device name of the disk attached at a device ID to a KVM instance with virtio disks.
+func dataDiskDevice(deviceID int64) string {
+	return "/dev/vd" + string(rune('a'+deviceID))
+}
+
+// reconcileDataDisks creates the volumes of a machine's data disks, tagged with the passed tags, and attaches them to
+// its instance. Each creation and attachment job is recorded in the machine's status and the next one is only started
+// once it's done, so this is called repeatedly until it stops returning "VM data disk attachment in progress".
+// Volumes can only be attached to running or stopped instances, so nothing is done while the instance is still being
+// deployed.
+func (c *client) reconcileDataDisks(csMachine *infrav1.CloudStackMachine, zoneID string, tags map[string]string) error {
+	job := csMachine.Status.AsyncJob
+	if len(csMachine.Spec.DataDisks) == 0 ||
+		(job != nil && job.Command != CreateVolumeCommand && job.Command != AttachVolumeCommand) ||
+		(csMachine.Status.InstanceState != "Running" && csMachine.Status.InstanceState != "Stopped") {
+		return nil
+	}
+
+	inProgress := errors.New("VM data disk attachment in progress")
+	if job != nil {
+		if pending, err := c.pollMachineAsyncJob(csMachine, job.Command); err != nil {
+			return err
+		} else if pending {
+			return inProgress
+		}
+	}
+
+	volumes, err := c.listDataDiskVolumes(csMachine)
+	if err != nil {
+		return err
+	}
+	deviceIDs := dataDiskDeviceIDs(csMachine)
+	for i, disk := range csMachine.Spec.DataDisks {
+		name := dataDiskVolumeName(csMachine, disk)
+		volume := volumes[name]
+		if volume == nil {
+			resp, err := c.createDataDiskVolume(disk, name, zoneID, tags)
+			if err != nil {
+				return err
+			} else if resp.JobID != "" {
+				setMachineAsyncJob(csMachine, CreateVolumeCommand, resp.JobID)
+				return inProgress
+			}
+			volume = &cloudstack.Volume{Id: resp.Id, Name: resp.Name, Virtualmachineid: resp.Virtualmachineid}
+		}
+		if volume.Virtualmachineid == *csMachine.Spec.InstanceID {
+			continue
+		} else if volume.Virtualmachineid != "" {
+			return errors.Errorf("volume %s of data disk %s is attached to another instance %s",
+				volume.Id, disk.Name, volume.Virtualmachineid)
+		}
+
+		p := c.cs.Volume.NewAttachVolumeParams(volume.Id, *csMachine.Spec.InstanceID)
+		p.SetDeviceid(deviceIDs[i])
+		resp, err := c.cs.Volume.AttachVolume(p)
+		if err != nil {
+			c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+			return errors.Wrapf(err, "attaching volume %s of data disk %s", volume.Id, disk.Name)
+		} else if resp.JobID != "" {
+			setMachineAsyncJob(csMachine, AttachVolumeCommand, resp.JobID)
+			return inProgress
+		}
+	}
+	return nil
+}
+
+// listDataDiskVolumes returns the existing volumes of a machine's data disks by name, attached or not.
+func (c *client) listDataDiskVolumes(csMachine *infrav1.CloudStackMachine) (map[string]*cloudstack.Volume, error) {
+	names := map[string]bool{}
+	for _, disk := range csMachine.Spec.DataDisks {
+		names[dataDiskVolumeName(csMachine, disk)] = true
+	}
+
+	p := c.cs.Volume.NewListVolumesParams()
+	p.SetKeyword(csMachine.Name)
+	p.SetType(VolumeTypeDataDisk)
+	setIfNotEmpty(c.user.Project.ID, p.SetProjectid)
+	resp, err := c.cs.Volume.ListVolumes(p)
+	if err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return nil, errors.Wrapf(err, "listing data disk volumes of machine %s", csMachine.Name)
+	}
+	volumes := map[string]*cloudstack.Volume{}
+	for _, volume := range resp.Volumes {
+		if names[volume.Name] {
+			volumes[volume.Name] = volume
+		}
+	}
+	return volumes, nil
+}
+
+// createDataDiskVolume starts creating the volume of a data disk and tags it. The volume is known to CloudStack as soon
+// as its creation starts, so it's tagged right away.
+func (c *client) createDataDiskVolume(
+	disk infrav1.CloudStackDataDisk, name, zoneID string, tags map[string]string,
+) (*cloudstack.CreateVolumeResponse, error) {
+	offeringID, err := c.resolveDiskOffering(disk.Offering, disk.CustomSize, zoneID)
+	if err != nil {
+		return nil, err
+	}
+	p := c.cs.Volume.NewCreateVolumeParams()
+	p.SetName(name)
+	p.SetDiskofferingid(offeringID)
+	p.SetZoneid(zoneID)
+	setIntIfPositive(disk.CustomSize, p.SetSize)
+	setIntIfPositive(disk.MinIOPS, p.SetMiniops)
+	setIntIfPositive(disk.MaxIOPS, p.SetMaxiops)
+	setIfNotEmpty(c.user.Project.ID, p.SetProjectid)
+	resp, err := c.cs.Volume.CreateVolume(p)
+	if err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return nil, errors.Wrapf(err, "creating volume of data disk %s", disk.Name)
+	}
+	if err := c.tagCreatedResource(ResourceTypeVolume, resp.Id, tags); err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// deleteDetachedDataDiskVolumes deletes the volumes of a machine's data disks that were created, but never attached
+// to its instance. Attached volumes are destroyed along with the instance.
+func (c *client) deleteDetachedDataDiskVolumes(csMachine *infrav1.CloudStackMachine) error {
+	if len(csMachine.Spec.DataDisks) == 0 {
+		return nil
+	}
+	volumes, err := c.listDataDiskVolumes(csMachine)
+	if err != nil {
+		return err
+	}
+	for _, volume := range volumes {
+		if volume.Virtualmachineid != "" {
+			continue
+		}
+		if _, err := c.cs.Volume.DeleteVolume(c.cs.Volume.NewDeleteVolumeParams(volume.Id)); err != nil {
+			c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+			return errors.Wrapf(err, "deleting detached volume %s", volume.Id)
+		}
+	}
+	return nil
+}
+
+// dataDiskMountScript returns a shell script formatting and mounting the data disks of a machine that have a mount
+// path, or an empty string if there are none. The disks are attached after the instance is deployed, so the script
+// waits for their devices to show up.
+func dataDiskMountScript(csMachine *infrav1.CloudStackMachine) string {
+	var mounts []string
+	deviceIDs := dataDiskDeviceIDs(csMachine)
+	for i, disk := range csMachine.Spec.DataDisks {
+		if disk.MountPath == "" {
+			continue
+		}
+		fsType := disk.Filesystem
+		if fsType == "" {
+			fsType = DefaultDataDiskFSType
+		}
+		mounts = append(mounts, fmt.Sprintf("mount_data_disk %s %s %s %s",
+			shellQuote(dataDiskDevice(deviceIDs[i])), shellQuote(fsType), shellQuote(disk.Label), shellQuote(disk.MountPath)))
+	}
+	if len(mounts) == 0 {
+		return ""
+	}
+	return `#!/bin/sh
+set -e
+
+mount_data_disk() {
+  device=$1 fstype=$2 label=$3 path=$4
+  for i in $(seq 1 120); do
+    [ -b "$device" ] && break
+    sleep 5
+  done
+  if [ ! -b "$device" ]; then
+    echo "data disk $device was not attached" >&2
+    return 1
+  fi
+  if ! blkid "$device" >/dev/null 2>&1; then
+    if [ -n "$label" ]; then
+      mkfs -t "$fstype" -L "$label" "$device"
+    else
+      mkfs -t "$fstype" "$device"
+    fi
+  fi
+  mkdir -p "$path"
+  uuid=$(blkid -s UUID -o value "$device")
+  grep -q "^UUID=$uuid " /etc/fstab || echo "UUID=$uuid $path $fstype defaults,nofail 0 2" >> /etc/fstab
+  mountpoint -q "$path" || mount "$path"
+}
+
+` + strings.Join(mounts, "\n") + "\n"
+}
+
+// shellQuote quotes a string as a single shell word.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// withDataDiskMounts adds the script mounting a machine's data disks to its cloud-init user data, combining both in a
+// MIME multipart archive. User data is returned as is if there is nothing to mount.
+func withDataDiskMounts(csMachine *infrav1.CloudStackMachine, userData string) (string, error) {
+	script := dataDiskMountScript(csMachine)
+	if script == "" {
+		return userData, nil
+	}
+
+	var contentType string
+	switch {
+	case strings.HasPrefix(userData, "## template: jinja"):
+		contentType = "text/jinja2"
+	case strings.HasPrefix(userData, "#cloud-config"):
+		contentType = "text/cloud-config"
+	case strings.HasPrefix(userData, "#!"):
+		contentType = "text/x-shellscript"
+	default:
+		return "", errors.New("mounting data disks requires cloud-config, jinja templated cloud-config, " +
+			"or shell script user data")
+	}
+
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	parts := []struct {
+		contentType, filename, content string
+	}{
+		{contentType, "user-data", userData},
+		{"text/x-shellscript", dataDiskScriptName, script},
+	}
+	for _, part := range parts {
+		header := textproto.MIMEHeader{}
+		header.Set("Content-Type", part.contentType+`; charset="utf-8"`)
+		header.Set("MIME-Version", "1.0")
+		header.Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", part.filename))
+		w, err := writer.CreatePart(header)
+		if err != nil {
+			return "", err
+		}
+		if _, err := w.Write([]byte(part.content)); err != nil {
+			return "", err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Content-Type: multipart/mixed; boundary=%q\nMIME-Version: 1.0\n\n", writer.Boundary()) +
+		body.String(), nil
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud_test
+
+import (
+	"encoding/base64"
+
+	"github.com/onsi/ginkgo/v2"
+	gomega "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/test/fakecloudstack"
+)
+
+var _ = ginkgo.Describe("Data Disks", func() {
+	var fake *fakeCloud
+
+	ginkgo.BeforeEach(func() {
+		fake = newFakeCloud()
+	})
+
+	ginkgo.It("creates, attaches, mounts and destroys the data disks of a machine", func() {
+		fake.useSharedNetwork()
+		fake.csMachine.Spec.UncompressedUserData = ptr.To(true)
+		fake.csMachine.Spec.DiskOffering.Name = fakecloudstack.DiskOfferingName
+		fake.csMachine.Spec.DataDisks = []infrav1.CloudStackDataDisk{
+			{Name: "etcd", Offering: infrav1.CloudStackResourceIdentifier{Name: fakecloudstack.CustomDiskOfferingName},
+				CustomSize: 20, MountPath: "/var/lib/etcd", Label: "etcd"},
+			{Name: "logs", Offering: infrav1.CloudStackResourceIdentifier{Name: fakecloudstack.DiskOfferingName}},
+		}
+
+		gomega.Ω(fake.getOrCreateVMInstance("#cloud-config")).Should(gomega.Succeed())
+		vm, _ := fake.server.Get(fakecloudstack.KindVirtualMachine, *fake.csMachine.Spec.InstanceID)
+		userData, err := base64.StdEncoding.DecodeString(vm["userdata"].(string))
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Ω(string(userData)).Should(gomega.HavePrefix("Content-Type: multipart/mixed"))
+		gomega.Ω(string(userData)).Should(gomega.ContainSubstring("mount_data_disk '/dev/vdc' 'ext4' 'etcd' '/var/lib/etcd'"))
+
+		// The volumes are created and attached one job per reconciliation once the instance runs, skipping the CD-ROM's
+		// device ID.
+		for _, command := range []string{
+			cloud.CreateVolumeCommand, cloud.AttachVolumeCommand, cloud.CreateVolumeCommand, cloud.AttachVolumeCommand,
+		} {
+			gomega.Ω(fake.getOrCreateVMInstance("#cloud-config")).Should(
+				gomega.MatchError("VM data disk attachment in progress"))
+			gomega.Ω(fake.csMachine.Status.AsyncJob.Command).Should(gomega.Equal(command))
+		}
+		gomega.Ω(fake.getOrCreateVMInstance("#cloud-config")).Should(gomega.Succeed())
+		gomega.Ω(fake.csMachine.Status.AsyncJob).Should(gomega.BeNil())
+		gomega.Ω(fake.server.Calls("createVolume")).Should(gomega.Equal(2))
+		gomega.Ω(fake.server.Calls("attachVolume")).Should(gomega.Equal(2))
+		etcd, _ := fake.server.Find(fakecloudstack.KindVolume, "test-machine-etcd")
+		gomega.Ω(etcd["virtualmachineid"]).Should(gomega.Equal(*fake.csMachine.Spec.InstanceID))
+		gomega.Ω(etcd["deviceid"]).Should(gomega.Equal(int64(2)))
+		gomega.Ω(etcd["size"]).Should(gomega.Equal(int64(20) << 30))
+		logs, _ := fake.server.Find(fakecloudstack.KindVolume, "test-machine-logs")
+		gomega.Ω(logs["deviceid"]).Should(gomega.Equal(int64(4)))
+
+		gomega.Ω(fake.client.DestroyVMInstance(fake.csMachine)).Should(gomega.MatchError("VM deletion in progress"))
+		gomega.Ω(fake.client.DestroyVMInstance(fake.csMachine)).Should(gomega.Succeed())
+		gomega.Ω(fake.server.List(fakecloudstack.KindVolume)).Should(gomega.BeEmpty())
+	})
+})
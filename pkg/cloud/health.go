@@ -0,0 +1,80 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"net/url"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// HealthIface probes whether the CloudStack resources a failure domain depends on are still usable.
+type HealthIface interface {
+	VerifyCredentials() error
+	GetZoneAllocationState(string) (string, error)
+	VerifyNetwork(string) error
+}
+
+// Allocation states of zones. New instances may not be deployed in disabled zones.
+const (
+	ZoneAllocationStateEnabled  = "Enabled"
+	ZoneAllocationStateDisabled = "Disabled"
+)
+
+// VerifyCredentials checks that the endpoint is reachable and accepts the client's credentials.
+func (c *client) VerifyCredentials() error {
+	if _, err := c.cs.Configuration.ListCapabilities(c.cs.Configuration.NewListCapabilitiesParams()); err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return errors.Wrap(err, "listing capabilities")
+	}
+	return nil
+}
+
+// GetZoneAllocationState returns the allocation state of a zone, e.g. Enabled or Disabled.
+func (c *client) GetZoneAllocationState(zoneID string) (string, error) {
+	zone, count, err := c.cs.Zone.GetZoneByID(zoneID)
+	if err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return "", errors.Wrapf(err, "could not get Zone by ID %s", zoneID)
+	} else if count != 1 {
+		return "", errors.Errorf("expected 1 Zone with UUID %s, but got %d", zoneID, count)
+	}
+	return zone.Allocationstate, nil
+}
+
+// VerifyNetwork checks that a network still exists.
+func (c *client) VerifyNetwork(networkID string) error {
+	if _, err := c.getNetwork(networkID); err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return err
+	}
+	return nil
+}
+
+// IsEndpointUnreachableError reports whether an error is due to the CloudStack endpoint not answering, as opposed to
+// an error returned by the API.
+func IsEndpointUnreachableError(err error) bool {
+	var urlErr *url.Error
+	return errors.As(err, &urlErr)
+}
+
+// IsCredentialsRejectedError reports whether an error is due to the CloudStack endpoint rejecting the API key or the
+// signature of a request.
+func IsCredentialsRejectedError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "CloudStack API error 401 ")
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud_test
+
+import (
+	"github.com/onsi/ginkgo/v2"
+	gomega "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/test/fakecloudstack"
+)
+
+var _ = ginkgo.Describe("Health", func() {
+	var fake *fakeCloud
+
+	ginkgo.BeforeEach(func() {
+		fake = newFakeCloud()
+	})
+
+	ginkgo.It("reports disabled zones and missing networks", func() {
+		gomega.Ω(fake.client.ResolveZone(&fake.fd.Spec.Zone)).Should(gomega.Succeed())
+		gomega.Ω(fake.client.GetZoneAllocationState(fake.fd.Spec.Zone.ID)).Should(gomega.Equal(cloud.ZoneAllocationStateEnabled))
+		gomega.Ω(fake.server.Update(fakecloudstack.KindZone, fake.fd.Spec.Zone.ID, fakecloudstack.Resource{
+			"allocationstate": "Disabled",
+		})).Should(gomega.Succeed())
+		gomega.Ω(fake.client.GetZoneAllocationState(fake.fd.Spec.Zone.ID)).Should(gomega.Equal("Disabled"))
+
+		shared, _ := fake.server.Find(fakecloudstack.KindNetwork, fakecloudstack.SharedNetworkName)
+		gomega.Ω(fake.client.VerifyNetwork(shared["id"].(string))).Should(gomega.Succeed())
+		gomega.Ω(fake.client.VerifyNetwork("deleted-network")).ShouldNot(gomega.Succeed())
+	})
+
+	ginkgo.It("tells rejected credentials from an unreachable endpoint", func() {
+		gomega.Ω(fake.client.VerifyCredentials()).Should(gomega.Succeed())
+
+		for _, user := range fake.server.List(fakecloudstack.KindUser) {
+			if user["apikey"] == fake.server.Config().APIKey {
+				gomega.Ω(fake.server.Update(fakecloudstack.KindUser, user["id"].(string), fakecloudstack.Resource{
+					"apikey": "rotated",
+				})).Should(gomega.Succeed())
+			}
+		}
+		err := fake.client.VerifyCredentials()
+		gomega.Ω(err).Should(gomega.MatchError(gomega.ContainSubstring("unable to verify user credentials")))
+		gomega.Ω(cloud.IsEndpointUnreachableError(err)).Should(gomega.BeFalse())
+
+		fake.server.Close()
+		err = fake.client.VerifyCredentials()
+		gomega.Ω(err).Should(gomega.HaveOccurred())
+		gomega.Ω(cloud.IsEndpointUnreachableError(err)).Should(gomega.BeTrue())
+	})
+})
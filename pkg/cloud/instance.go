@@ -38,6 +38,11 @@ type VMIface interface {
 	GetOrCreateVMInstance(*infrav1.CloudStackMachine, *clusterv1.Machine, *infrav1.CloudStackCluster, *infrav1.CloudStackFailureDomain, *infrav1.CloudStackAffinityGroup, string) error
 	ResolveVMInstanceDetails(*infrav1.CloudStackMachine) error
 	DestroyVMInstance(*infrav1.CloudStackMachine) error
+	ResizeVMInstance(*infrav1.CloudStackMachine, *infrav1.CloudStackFailureDomain) error
+	ResolveServiceOffering(*infrav1.CloudStackMachine, string) (cloudstack.ServiceOffering, error)
+	ResolveTemplate(*infrav1.CloudStackMachine, string) (string, error)
+	ResolveDiskOffering(*infrav1.CloudStackMachine, string) (string, error)
+	AdoptVMInstance(*infrav1.CloudStackMachine, *infrav1.CloudStackCluster, *infrav1.CloudStackFailureDomain) error
 }
 
 // Set infrastructure spec and status from the CloudStack API's virtual machine metrics type.
@@ -53,7 +58,19 @@ func setMachineDataFromVMMetrics(vmResponse *cloudstack.VirtualMachinesMetric, c
 				Address: nic.Ipaddress,
 			})
 		}
+		if nic.Ip6address != "" {
+			csMachine.Status.Addresses = append(csMachine.Status.Addresses, corev1.NodeAddress{
+				Type:    corev1.NodeInternalIP,
+				Address: nic.Ip6address,
+			})
+		}
 	}
+	csMachine.Status.ServiceOfferingID = vmResponse.Serviceofferingid
+	csMachine.Status.ServiceOfferingName = vmResponse.Serviceofferingname
+	csMachine.Status.Hypervisor = vmResponse.Hypervisor
+	csMachine.Status.TemplateID = vmResponse.Templateid
+	csMachine.Status.CPUNumber = vmResponse.Cpunumber
+	csMachine.Status.MemoryMiB = vmResponse.Memory
 	newInstanceState := vmResponse.State
 	if newInstanceState != csMachine.Status.InstanceState || (newInstanceState != "" && csMachine.Status.InstanceStateLastUpdated.IsZero()) {
 		csMachine.Status.InstanceState = newInstanceState
@@ -74,7 +91,7 @@ func (c *client) ResolveVMInstanceDetails(csMachine *infrav1.CloudStackMachine)
 			return fmt.Errorf("found more than one VM Instance with ID %s", *csMachine.Spec.InstanceID)
 		} else if err == nil {
 			setMachineDataFromVMMetrics(vmResp, csMachine)
-			return nil
+			return c.resolveMachineResources(vmResp, csMachine)
 		}
 	}
 
@@ -88,12 +105,37 @@ func (c *client) ResolveVMInstanceDetails(csMachine *infrav1.CloudStackMachine)
 			return fmt.Errorf("found more than one VM Instance with name %s", csMachine.Name)
 		} else if err == nil {
 			setMachineDataFromVMMetrics(vmResp, csMachine)
-			return nil
+			return c.resolveMachineResources(vmResp, csMachine)
 		}
 	}
 	return errors.New("no match found")
 }
 
+// resolveMachineResources sets the placement and root volume of an instance in the machine's status, which are not part
+// of the VM metrics.
+func (c *client) resolveMachineResources(vm *cloudstack.VirtualMachinesMetric, csMachine *infrav1.CloudStackMachine) error {
+	if err := c.resolveMachinePlacement(vm, csMachine); err != nil {
+		return err
+	}
+	// The root volume is created along with the instance and kept for its lifetime.
+	if csMachine.Status.RootVolumeID != "" || (vm.State != "Running" && vm.State != "Stopped") {
+		return nil
+	}
+	p := c.cs.Volume.NewListVolumesParams()
+	p.SetVirtualmachineid(vm.Id)
+	p.SetType("ROOT")
+	setIfNotEmpty(c.user.Project.ID, p.SetProjectid)
+	resp, err := c.cs.Volume.ListVolumes(p)
+	if err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return errors.Wrapf(err, "listing root volume of VM %s", vm.Id)
+	}
+	if len(resp.Volumes) > 0 {
+		csMachine.Status.RootVolumeID = resp.Volumes[0].Id
+	}
+	return nil
+}
+
 func (c *client) ResolveServiceOffering(csMachine *infrav1.CloudStackMachine, zoneID string) (offering cloudstack.ServiceOffering, retErr error) {
 	if len(csMachine.Spec.Offering.ID) > 0 {
 		csOffering, count, err := c.cs.ServiceOffering.GetServiceOfferingByID(csMachine.Spec.Offering.ID, cloudstack.WithProject(c.user.Project.ID))
@@ -159,34 +201,45 @@ func (c *client) ResolveTemplate(
 // disk offering name matches name provided in spec.
 // If disk offering ID is not provided, the disk offering name is used to retrieve disk offering ID.
 func (c *client) ResolveDiskOffering(csMachine *infrav1.CloudStackMachine, zoneID string) (diskOfferingID string, retErr error) {
-	diskOfferingID = csMachine.Spec.DiskOffering.ID
-	if len(csMachine.Spec.DiskOffering.Name) > 0 {
-		diskID, count, err := c.cs.DiskOffering.GetDiskOfferingID(csMachine.Spec.DiskOffering.Name, cloudstack.WithZone(zoneID), cloudstack.WithProject(c.user.Project.ID))
-		if err != nil {
-			c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
-			return "", errors.Wrapf(err, "could not get DiskOffering ID from %s", csMachine.Spec.DiskOffering.Name)
-		} else if count != 1 {
-			return "", errors.Errorf(
-				"expected 1 DiskOffering with name %s in zone %s, but got %d", csMachine.Spec.DiskOffering.Name, zoneID, count)
-		} else if len(csMachine.Spec.DiskOffering.ID) > 0 && diskID != csMachine.Spec.DiskOffering.ID {
-			return "", errors.Errorf(
-				"diskOffering ID %s does not match ID %s returned using name %s in zone %s",
-				csMachine.Spec.DiskOffering.ID, diskID, csMachine.Spec.DiskOffering.Name, zoneID)
-		} else if len(diskID) == 0 {
-			return "", errors.Errorf(
-				"empty diskOffering ID %s returned using name %s in zone %s",
-				diskID, csMachine.Spec.DiskOffering.Name, zoneID)
-		}
-		diskOfferingID = diskID
-	}
-	if len(diskOfferingID) == 0 {
-		return "", nil
+	return c.resolveDiskOffering(csMachine.Spec.DiskOffering.CloudStackResourceIdentifier, csMachine.Spec.DiskOffering.CustomSize, zoneID)
+}
+
+// resolveDiskOffering resolves the ID of a disk offering, checking that a custom size is given if and only if the
+// offering is customized.
+func (c *client) resolveDiskOffering(offering infrav1.CloudStackResourceIdentifier, customSize int64, zoneID string) (diskOfferingID string, retErr error) {
+	diskOfferingID, err := c.resolveDiskOfferingID(offering, zoneID)
+	if err != nil || len(diskOfferingID) == 0 {
+		return "", err
 	}
 
-	return verifyDiskoffering(csMachine, c, diskOfferingID)
+	return verifyDiskoffering(c, diskOfferingID, customSize)
+}
+
+// resolveDiskOfferingID returns the ID of a disk offering, looking it up by name if given.
+func (c *client) resolveDiskOfferingID(offering infrav1.CloudStackResourceIdentifier, zoneID string) (string, error) {
+	if len(offering.Name) == 0 {
+		return offering.ID, nil
+	}
+	diskID, count, err := c.cs.DiskOffering.GetDiskOfferingID(offering.Name, cloudstack.WithZone(zoneID), cloudstack.WithProject(c.user.Project.ID))
+	if err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return "", errors.Wrapf(err, "could not get DiskOffering ID from %s", offering.Name)
+	} else if count != 1 {
+		return "", errors.Errorf(
+			"expected 1 DiskOffering with name %s in zone %s, but got %d", offering.Name, zoneID, count)
+	} else if len(offering.ID) > 0 && diskID != offering.ID {
+		return "", errors.Errorf(
+			"diskOffering ID %s does not match ID %s returned using name %s in zone %s",
+			offering.ID, diskID, offering.Name, zoneID)
+	} else if len(diskID) == 0 {
+		return "", errors.Errorf(
+			"empty diskOffering ID %s returned using name %s in zone %s",
+			diskID, offering.Name, zoneID)
+	}
+	return diskID, nil
 }
 
-func verifyDiskoffering(csMachine *infrav1.CloudStackMachine, c *client, diskOfferingID string) (string, error) {
+func verifyDiskoffering(c *client, diskOfferingID string, customSize int64) (string, error) {
 	csDiskOffering, count, err := c.cs.DiskOffering.GetDiskOfferingByID(diskOfferingID, cloudstack.WithProject(c.user.Project.ID))
 	if err != nil {
 		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
@@ -196,13 +249,13 @@ func verifyDiskoffering(csMachine *infrav1.CloudStackMachine, c *client, diskOff
 			"expected 1 DiskOffering with UUID %s, but got %d", diskOfferingID, count)
 	}
 
-	if csDiskOffering.Iscustomized && csMachine.Spec.DiskOffering.CustomSize == 0 {
+	if csDiskOffering.Iscustomized && customSize == 0 {
 		return "", errors.Errorf(
 			"diskOffering with UUID %s is customized, disk size can not be 0 GB",
 			diskOfferingID)
 	}
 
-	if !csDiskOffering.Iscustomized && csMachine.Spec.DiskOffering.CustomSize > 0 {
+	if !csDiskOffering.Iscustomized && customSize > 0 {
 		return "", errors.Errorf(
 			"diskOffering with UUID %s is not customized, disk size can not be specified",
 			diskOfferingID)
@@ -210,6 +263,97 @@ func verifyDiskoffering(csMachine *infrav1.CloudStackMachine, c *client, diskOff
 	return diskOfferingID, nil
 }
 
+// configureRootDisk sets the size and disk offering of the root volume if the machine overrides them. The size can
+// only grow the template's disk.
+func (c *client) configureRootDisk(
+	p *cloudstack.DeployVirtualMachineParams,
+	csMachine *infrav1.CloudStackMachine,
+	templateID, zoneID string,
+) error {
+	rootDisk := csMachine.Spec.RootDisk
+	if rootDisk == nil {
+		return nil
+	}
+
+	if rootDisk.Size > 0 {
+		csTemplate, count, err := c.cs.Template.GetTemplateByID(templateID, "executable", cloudstack.WithProject(c.user.Project.ID))
+		if err != nil {
+			c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+			return errors.Wrapf(err, "could not get Template by ID %s", templateID)
+		} else if count != 1 {
+			return errors.Errorf("expected 1 Template with UUID %s, but got %d", templateID, count)
+		}
+		// Round the template size up to the next GB, which is what a root volume of that size can hold.
+		if templateSize := (csTemplate.Size + 1<<30 - 1) >> 30; rootDisk.Size < templateSize {
+			return errors.Errorf("root disk size of %d GB is smaller than the %d GB of template %s",
+				rootDisk.Size, templateSize, csTemplate.Name)
+		}
+		p.SetRootdisksize(rootDisk.Size)
+	}
+
+	offeringID, err := c.resolveRootDiskOffering(rootDisk, zoneID)
+	if err != nil {
+		return err
+	}
+	setIfNotEmpty(offeringID, p.SetOverridediskofferingid)
+	return nil
+}
+
+// resolveRootDiskOffering returns the ID of the disk offering overriding the root volume's, if any. An offering given
+// by ID or name must carry the storage tag, if one is set. Otherwise, the one offering in the zone with the storage
+// tag is used.
+func (c *client) resolveRootDiskOffering(rootDisk *infrav1.CloudStackRootDisk, zoneID string) (string, error) {
+	if rootDisk.Offering.ID == "" && rootDisk.Offering.Name == "" {
+		if rootDisk.StorageTag == "" {
+			return "", nil
+		}
+		p := c.cs.DiskOffering.NewListDiskOfferingsParams()
+		p.SetZoneid(zoneID)
+		setIfNotEmpty(c.user.Project.ID, p.SetProjectid)
+		resp, err := c.cs.DiskOffering.ListDiskOfferings(p)
+		if err != nil {
+			c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+			return "", errors.Wrapf(err, "listing disk offerings in zone %s", zoneID)
+		}
+		var offeringIDs []string
+		for _, offering := range resp.DiskOfferings {
+			if hasStorageTag(offering.Tags, rootDisk.StorageTag) {
+				offeringIDs = append(offeringIDs, offering.Id)
+			}
+		}
+		if len(offeringIDs) != 1 {
+			return "", errors.Errorf("expected 1 DiskOffering with storage tag %s in zone %s, but got %d",
+				rootDisk.StorageTag, zoneID, len(offeringIDs))
+		}
+		return offeringIDs[0], nil
+	}
+
+	offeringID, err := c.resolveDiskOfferingID(rootDisk.Offering, zoneID)
+	if err != nil || rootDisk.StorageTag == "" {
+		return offeringID, err
+	}
+	offering, count, err := c.cs.DiskOffering.GetDiskOfferingByID(offeringID, cloudstack.WithProject(c.user.Project.ID))
+	if err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return "", errors.Wrapf(err, "could not get DiskOffering by ID %s", offeringID)
+	} else if count != 1 {
+		return "", errors.Errorf("expected 1 DiskOffering with UUID %s, but got %d", offeringID, count)
+	} else if !hasStorageTag(offering.Tags, rootDisk.StorageTag) {
+		return "", errors.Errorf("diskOffering with UUID %s does not have storage tag %s", offeringID, rootDisk.StorageTag)
+	}
+	return offeringID, nil
+}
+
+// hasStorageTag checks whether a comma-separated list of storage tags contains a tag.
+func hasStorageTag(tags, tag string) bool {
+	for _, t := range strings.Split(tags, ",") {
+		if strings.TrimSpace(t) == tag {
+			return true
+		}
+	}
+	return false
+}
+
 // CheckAccountLimits Checks the account's limit of VM, CPU & Memory
 func (c *client) CheckAccountLimits(offering *cloudstack.ServiceOffering) error {
 	if c.user.Account.CPUAvailable != "Unlimited" {
@@ -336,12 +480,20 @@ func (c *client) isFreeIPAvailable(networkID, ip string) (bool, error) {
 	return false, nil
 }
 
-func (c *client) buildIPEntry(resolvedNet *cloudstack.Network, ip string) (map[string]string, error) {
+func (c *client) buildIPEntry(resolvedNet *cloudstack.Network, ip, ip6 string) (map[string]string, error) {
 	if ip != "" {
 		if err := validateIPInCIDR(ip, resolvedNet.Cidr); err != nil {
 			return nil, err
 		}
 	}
+	if ip6 != "" {
+		if resolvedNet.Ip6cidr == "" {
+			return nil, errors.Errorf("IPv6 address %q requested but network %q has no IPv6 CIDR", ip6, resolvedNet.Id)
+		}
+		if err := validateIPInCIDR(ip6, resolvedNet.Ip6cidr); err != nil {
+			return nil, err
+		}
+	}
 
 	if resolvedNet.Type == NetworkTypeShared {
 		isAvailable, err := c.isFreeIPAvailable(resolvedNet.Id, ip)
@@ -362,6 +514,9 @@ func (c *client) buildIPEntry(resolvedNet *cloudstack.Network, ip string) (map[s
 	if ip != "" {
 		entry["ip"] = ip
 	}
+	if ip6 != "" {
+		entry["ipv6"] = ip6
+	}
 	return entry, nil
 }
 
@@ -385,7 +540,7 @@ func (c *client) buildIPToNetworkList(csMachine *infrav1.CloudStackMachine) ([]m
 			return nil, err
 		}
 
-		entry, err := c.buildIPEntry(resolvedNet, net.IP)
+		entry, err := c.buildIPEntry(resolvedNet, net.IP, net.IP6)
 		if err != nil {
 			return nil, err
 		}
@@ -456,7 +611,7 @@ func (c *client) configureNetworkParams(
 	return nil
 }
 
-// DeployVM will create a VM instance,
+// DeployVM will create a VM instance tagged with the passed tags,
 // and sets the infrastructure machine spec and status accordingly.
 func (c *client) DeployVM(
 	csMachine *infrav1.CloudStackMachine,
@@ -465,6 +620,7 @@ func (c *client) DeployVM(
 	affinity *infrav1.CloudStackAffinityGroup,
 	offering *cloudstack.ServiceOffering,
 	userData string,
+	tags map[string]string,
 ) error {
 	templateID, err := c.ResolveTemplate(csMachine, fd.Spec.Zone.ID)
 	if err != nil {
@@ -486,9 +642,20 @@ func (c *client) DeployVM(
 	setIfNotEmpty(diskOfferingID, p.SetDiskofferingid)
 	setIfNotEmpty(c.user.Project.ID, p.SetProjectid)
 	setIntIfPositive(csMachine.Spec.DiskOffering.CustomSize, p.SetSize)
+	if err := c.configureRootDisk(p, csMachine, templateID, fd.Spec.Zone.ID); err != nil {
+		return err
+	}
+
+	if err := c.configurePlacement(p, csMachine, fd.Spec.Zone.ID); err != nil {
+		return err
+	}
 
 	setIfNotEmpty(csMachine.Spec.SSHKey, p.SetKeypair)
 
+	userData, err = withDataDiskMounts(csMachine, userData)
+	if err != nil {
+		return err
+	}
 	if csMachine.CompressUserdata() {
 		userData, err = compress(userData)
 		if err != nil {
@@ -534,8 +701,11 @@ func (c *client) DeployVM(
 
 	csMachine.Spec.InstanceID = ptr.To(deployVMResp.Id)
 	csMachine.Status.Status = ptr.To(metav1.StatusSuccess)
+	if deployVMResp.JobID != "" {
+		setMachineAsyncJob(csMachine, DeployVMCommand, deployVMResp.JobID)
+	}
 
-	return nil
+	return c.tagCreatedResource(ResourceTypeVM, deployVMResp.Id, tags)
 }
 
 // GetOrCreateVMInstance CreateVMInstance will fetch or create a VM instance, and
@@ -543,14 +713,27 @@ func (c *client) DeployVM(
 func (c *client) GetOrCreateVMInstance(
 	csMachine *infrav1.CloudStackMachine,
 	capiMachine *clusterv1.Machine,
-	_ *infrav1.CloudStackCluster,
+	csCluster *infrav1.CloudStackCluster,
 	fd *infrav1.CloudStackFailureDomain,
 	affinity *infrav1.CloudStackAffinityGroup,
 	userData string,
 ) error {
+	// A failed deployment is only reported by its job. The VM itself may be in Error state or already gone.
+	if _, err := c.pollMachineAsyncJob(csMachine, DeployVMCommand); err != nil {
+		return err
+	}
+
 	// Check if VM instance already exists.
-	if err := c.ResolveVMInstanceDetails(csMachine); err == nil ||
-		!strings.Contains(strings.ToLower(err.Error()), "no match") {
+	if err := c.ResolveVMInstanceDetails(csMachine); err == nil {
+		// Tagging a deployed instance may have failed, or the instance may have been found by name after an
+		// incomplete deployment. Its tags are completed until the machine is ready.
+		if !csMachine.Status.Ready {
+			if err := c.AddTags(ResourceTypeVM, *csMachine.Spec.InstanceID, ResourceTags(csCluster, csMachine)); err != nil {
+				return err
+			}
+		}
+		return c.reconcileDataDisks(csMachine, fd.Spec.Zone.ID, ResourceTags(csCluster, csMachine))
+	} else if !strings.Contains(strings.ToLower(err.Error()), "no match") {
 		return err
 	}
 
@@ -564,7 +747,7 @@ func (c *client) GetOrCreateVMInstance(
 		return err
 	}
 
-	if err := c.DeployVM(csMachine, capiMachine, fd, affinity, &offering, userData); err != nil {
+	if err := c.DeployVM(csMachine, capiMachine, fd, affinity, &offering, userData, ResourceTags(csCluster, csMachine)); err != nil {
 		return err
 	}
 
@@ -573,6 +756,194 @@ func (c *client) GetOrCreateVMInstance(
 	return c.ResolveVMInstanceDetails(csMachine)
 }
 
+// AdoptVMInstance takes over the existing instance with the machine's InstanceID, which wasn't deployed by CAPC. The
+// instance has to be running or stopped, in the zone and networks of the failure domain, and run with the machine's
+// offering. It's then tagged as managed by CAPC for the machine, and the machine's spec and status are set from it.
+func (c *client) AdoptVMInstance(
+	csMachine *infrav1.CloudStackMachine,
+	csCluster *infrav1.CloudStackCluster,
+	fd *infrav1.CloudStackFailureDomain,
+) error {
+	if csMachine.Spec.InstanceID == nil {
+		return errors.New("no instance ID to adopt")
+	}
+	instanceID := *csMachine.Spec.InstanceID
+	tags, err := c.GetTags(ResourceTypeVM, instanceID)
+	if err != nil {
+		return errors.Wrapf(err, "fetching tags of instance %s", instanceID)
+	}
+	if tags[OwnerMachineTagName] == csMachine.Name && tags[OwnerNamespaceTagName] == csMachine.Namespace {
+		// Adopted by an earlier reconciliation, the instance may have been resized since.
+		return c.ResolveVMInstanceDetails(csMachine)
+	} else if _, found := tags[CreatedByCAPCTagName]; found {
+		return fmt.Errorf("instance %s is already managed by CAPC", instanceID)
+	}
+
+	vm, count, err := c.cs.VirtualMachine.GetVirtualMachinesMetricByID(instanceID, cloudstack.WithProject(c.user.Project.ID))
+	if err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return errors.Wrapf(err, "fetching instance %s to adopt", instanceID)
+	} else if count != 1 {
+		return fmt.Errorf("expected 1 instance with ID %s to adopt, found %d", instanceID, count)
+	}
+	if err := c.verifyAdoptableVM(vm, csMachine, fd); err != nil {
+		return err
+	}
+
+	if err := c.AddTags(ResourceTypeVM, vm.Id, ResourceTags(csCluster, csMachine)); err != nil {
+		return errors.Wrapf(err, "tagging adopted instance %s", vm.Id)
+	}
+	setMachineDataFromVMMetrics(vm, csMachine)
+	return c.resolveMachineResources(vm, csMachine)
+}
+
+// verifyAdoptableVM checks that an instance to adopt matches the machine and its failure domain.
+func (c *client) verifyAdoptableVM(
+	vm *cloudstack.VirtualMachinesMetric,
+	csMachine *infrav1.CloudStackMachine,
+	fd *infrav1.CloudStackFailureDomain,
+) error {
+	// Adopted instances aren't started, and machines wait for their instance to run.
+	if vm.State != "Running" {
+		return fmt.Errorf("instance %s is %s, only running instances can be adopted", vm.Id, vm.State)
+	}
+	if vm.Zoneid != fd.Spec.Zone.ID {
+		return fmt.Errorf("instance %s is in zone %s, not in zone %s of failure domain %s",
+			vm.Id, vm.Zonename, fd.Spec.Zone.Name, fd.Spec.Name)
+	}
+
+	networkIDs := map[string]bool{}
+	for _, nic := range vm.Nic {
+		networkIDs[nic.Networkid] = true
+	}
+	// Like on deployment, the machine's networks start with the failure domain's.
+	networks := csMachine.Spec.Networks
+	if len(networks) == 0 {
+		networks = []infrav1.NetworkSpec{{ID: fd.Spec.Zone.Network.ID, Name: fd.Spec.Zone.Network.Name}}
+	}
+	for _, network := range networks {
+		resolvedNet, err := c.resolveNetwork(network)
+		if err != nil {
+			return err
+		}
+		if !networkIDs[resolvedNet.Id] {
+			return fmt.Errorf("instance %s isn't attached to network %s", vm.Id, resolvedNet.Name)
+		}
+	}
+
+	offering, err := c.ResolveServiceOffering(csMachine, fd.Spec.Zone.ID)
+	if err != nil {
+		return err
+	}
+	if vm.Serviceofferingid != offering.Id {
+		return fmt.Errorf("instance %s runs with offering %s, not with offering %s of the machine",
+			vm.Id, vm.Serviceofferingname, offering.Name)
+	}
+	return nil
+}
+
+// ResizeVMInstance scales the machine's instance to the machine's offering if it differs from the one the instance
+// runs with and the machine allows in-place resizing. With the StopStart policy, the instance is stopped first and
+// started again afterwards. Each step's job is recorded in the machine's status and the next step is only taken once
+// it's done, so this is called repeatedly until it stops returning "VM resize in progress".
+func (c *client) ResizeVMInstance(csMachine *infrav1.CloudStackMachine, fd *infrav1.CloudStackFailureDomain) error {
+	if csMachine.Spec.InPlaceResize == "" || csMachine.Spec.InstanceID == nil {
+		return nil
+	}
+	job := csMachine.Status.AsyncJob
+	if job != nil && job.Command != StopVMCommand && job.Command != ScaleVMCommand && job.Command != StartVMCommand {
+		return nil // Another operation on the instance is in progress.
+	}
+
+	offering, err := c.ResolveServiceOffering(csMachine, fd.Spec.Zone.ID)
+	if err != nil {
+		return err
+	}
+	if job == nil && offering.Id == csMachine.Status.ServiceOfferingID {
+		return nil
+	}
+
+	inProgress := errors.New("VM resize in progress")
+	step := ScaleVMCommand
+	if csMachine.Spec.InPlaceResize == infrav1.StopStartResize && csMachine.Status.InstanceState == "Running" {
+		step = StopVMCommand
+	}
+	if job != nil {
+		if pending, err := c.pollMachineAsyncJob(csMachine, job.Command); err != nil {
+			if job.Command == ScaleVMCommand && csMachine.Spec.InPlaceResize == infrav1.StopStartResize {
+				c.startAfterFailedScale(csMachine)
+			}
+			return err
+		} else if pending {
+			return inProgress
+		}
+		// The job of the last step taken is done, move on to the next one.
+		switch job.Command {
+		case StopVMCommand:
+			step = ScaleVMCommand
+		case ScaleVMCommand:
+			if csMachine.Spec.InPlaceResize != infrav1.StopStartResize {
+				return c.ResolveVMInstanceDetails(csMachine)
+			}
+			step = StartVMCommand
+		case StartVMCommand:
+			return c.ResolveVMInstanceDetails(csMachine)
+		}
+	}
+
+	if step == StopVMCommand {
+		resp, err := c.cs.VirtualMachine.StopVirtualMachine(c.cs.VirtualMachine.NewStopVirtualMachineParams(*csMachine.Spec.InstanceID))
+		if err != nil {
+			c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+			return errors.Wrapf(err, "stopping VM %s to resize it", *csMachine.Spec.InstanceID)
+		} else if resp.JobID != "" {
+			setMachineAsyncJob(csMachine, StopVMCommand, resp.JobID)
+			return inProgress
+		}
+		step = ScaleVMCommand
+	}
+
+	if step == ScaleVMCommand {
+		p := c.cs.VirtualMachine.NewScaleVirtualMachineParams(*csMachine.Spec.InstanceID, offering.Id)
+		resp, err := c.cs.VirtualMachine.ScaleVirtualMachine(p)
+		if err != nil {
+			c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+			if csMachine.Spec.InPlaceResize == infrav1.StopStartResize {
+				c.startAfterFailedScale(csMachine)
+			}
+			return errors.Wrapf(err, "scaling VM %s to offering %s", *csMachine.Spec.InstanceID, offering.Name)
+		} else if resp.JobID != "" {
+			setMachineAsyncJob(csMachine, ScaleVMCommand, resp.JobID)
+			return inProgress
+		}
+		if csMachine.Spec.InPlaceResize != infrav1.StopStartResize {
+			return c.ResolveVMInstanceDetails(csMachine)
+		}
+	}
+
+	resp, err := c.cs.VirtualMachine.StartVirtualMachine(c.cs.VirtualMachine.NewStartVirtualMachineParams(*csMachine.Spec.InstanceID))
+	if err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return errors.Wrapf(err, "starting VM %s after resizing it", *csMachine.Spec.InstanceID)
+	} else if resp.JobID != "" {
+		setMachineAsyncJob(csMachine, StartVMCommand, resp.JobID)
+		return inProgress
+	}
+	return c.ResolveVMInstanceDetails(csMachine)
+}
+
+// startAfterFailedScale starts an instance stopped to be resized after scaling it failed, so it isn't left stopped.
+// The resize is retried from the start once the instance is running again.
+func (c *client) startAfterFailedScale(csMachine *infrav1.CloudStackMachine) {
+	resp, err := c.cs.VirtualMachine.StartVirtualMachine(
+		c.cs.VirtualMachine.NewStartVirtualMachineParams(*csMachine.Spec.InstanceID))
+	if err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+	} else if resp.JobID != "" {
+		setMachineAsyncJob(csMachine, StartVMCommand, resp.JobID)
+	}
+}
+
 // findVirtualMachine retrieves a virtual machine by matching its expected name, template, failure
 // domain zone and failure domain network. If no virtual machine is found it returns nil, nil.
 func findVirtualMachine(
@@ -601,7 +972,22 @@ func findVirtualMachine(
 }
 
 // DestroyVMInstance Destroys a VM instance. Assumes machine has been fetched prior and has an instance ID.
+// The destroy job is recorded in the machine's status and polled on later calls instead of waiting for it, so
+// "VM deletion in progress" is returned until the job is done.
 func (c *client) DestroyVMInstance(csMachine *infrav1.CloudStackMachine) error {
+	if job := csMachine.Status.AsyncJob; job != nil && job.Command == DestroyVMCommand {
+		pending, err := c.pollMachineAsyncJob(csMachine, DestroyVMCommand)
+		if err != nil {
+			if strings.Contains(strings.ToLower(err.Error()), "unable to find uuid for id") {
+				return nil
+			}
+			return err
+		} else if pending {
+			return errors.New("VM deletion in progress")
+		}
+		return c.verifyVMInstanceDestroyed(csMachine)
+	}
+
 	p := c.cs.Configuration.NewListCapabilitiesParams()
 	capabilities, err := c.cs.Configuration.ListCapabilities(p)
 	expunge := true
@@ -610,22 +996,31 @@ func (c *client) DestroyVMInstance(csMachine *infrav1.CloudStackMachine) error {
 	}
 
 	// Attempt deletion regardless of machine state.
-	p2 := c.csAsync.VirtualMachine.NewDestroyVirtualMachineParams(*csMachine.Spec.InstanceID)
-	volIDs, err := c.listVMInstanceDatadiskVolumeIDs(*csMachine.Spec.InstanceID)
+	p2 := c.cs.VirtualMachine.NewDestroyVirtualMachineParams(*csMachine.Spec.InstanceID)
+	volIDs, err := c.listVMInstanceDatadiskVolumeIDs(csMachine)
 	if err != nil {
 		return err
 	}
 	p2.SetExpunge(expunge)
 	setArrayIfNotEmpty(volIDs, p2.SetVolumeids)
-	if _, err := c.csAsync.VirtualMachine.DestroyVirtualMachine(p2); err != nil &&
-		strings.Contains(strings.ToLower(err.Error()), "unable to find uuid for id") {
+	resp, err := c.cs.VirtualMachine.DestroyVirtualMachine(p2)
+	if err != nil && strings.Contains(strings.ToLower(err.Error()), "unable to find uuid for id") {
 		// VM doesn't exist. Success...
 		return nil
 	} else if err != nil {
 		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
 		return err
 	}
+	if resp.JobID == "" {
+		return c.verifyVMInstanceDestroyed(csMachine)
+	}
 
+	setMachineAsyncJob(csMachine, DestroyVMCommand, resp.JobID)
+	return errors.New("VM deletion in progress")
+}
+
+// verifyVMInstanceDestroyed checks whether a VM whose destroy job has finished is gone or getting expunged.
+func (c *client) verifyVMInstanceDestroyed(csMachine *infrav1.CloudStackMachine) error {
 	if err := c.ResolveVMInstanceDetails(csMachine); err == nil && (csMachine.Status.InstanceState == "Expunging" ||
 		csMachine.Status.InstanceState == "Expunged") {
 		// VM is stopped and getting expunged.  So the desired state is getting satisfied.  Let's move on.
@@ -641,11 +1036,17 @@ func (c *client) DestroyVMInstance(csMachine *infrav1.CloudStackMachine) error {
 	return errors.New("VM deletion in progress")
 }
 
-func (c *client) listVMInstanceDatadiskVolumeIDs(instanceID string) ([]string, error) {
+// listVMInstanceDatadiskVolumeIDs returns the IDs of the data disk volumes attached to a machine's instance, to be
+// destroyed along with it. Volumes of the machine's data disks that never got attached are deleted right away.
+func (c *client) listVMInstanceDatadiskVolumeIDs(csMachine *infrav1.CloudStackMachine) ([]string, error) {
+	if err := c.deleteDetachedDataDiskVolumes(csMachine); err != nil {
+		return nil, err
+	}
+
 	p := c.cs.Volume.NewListVolumesParams()
-	p.SetVirtualmachineid(instanceID)
+	p.SetVirtualmachineid(*csMachine.Spec.InstanceID)
 	// VM root volumes are destroyed automatically, no need to explicitly include
-	p.SetType("DATADISK")
+	p.SetType(VolumeTypeDataDisk)
 	setIfNotEmpty(c.user.Project.ID, p.SetProjectid)
 
 	listVOLResp, err := c.csAsync.Volume.ListVolumes(p)
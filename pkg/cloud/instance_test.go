@@ -27,6 +27,7 @@ import (
 	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
 	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
 	dummies "sigs.k8s.io/cluster-api-provider-cloudstack/test/dummies/v1beta3"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/test/fakecloudstack"
 
 	"github.com/onsi/ginkgo/v2"
 	gomega "github.com/onsi/gomega"
@@ -54,6 +55,7 @@ var _ = ginkgo.Describe("Instance", func() {
 		dos           *cloudstack.MockDiskOfferingServiceIface
 		ts            *cloudstack.MockTemplateServiceIface
 		vs            *cloudstack.MockVolumeServiceIface
+		rs            *cloudstack.MockResourcetagsServiceIface
 		client        cloud.Client
 	)
 
@@ -66,6 +68,7 @@ var _ = ginkgo.Describe("Instance", func() {
 		dos = mockClient.DiskOffering.(*cloudstack.MockDiskOfferingServiceIface)
 		ts = mockClient.Template.(*cloudstack.MockTemplateServiceIface)
 		vs = mockClient.Volume.(*cloudstack.MockVolumeServiceIface)
+		rs = mockClient.Resourcetags.(*cloudstack.MockResourcetagsServiceIface)
 		client = cloud.NewClientFromCSAPIClient(mockClient, nil)
 
 		dummies.SetDummyVars()
@@ -95,6 +98,31 @@ var _ = ginkgo.Describe("Instance", func() {
 			gomega.Ω(dummies.CSMachine1.Spec.InstanceID).Should(gomega.Equal(ptr.To(vmsResp.Id)))
 		})
 
+		ginkgo.It("sets the resources of a running VM instance in the status", func() {
+			vmsResp := &cloudstack.VirtualMachinesMetric{
+				Id:         *dummies.CSMachine1.Spec.InstanceID,
+				State:      "Running",
+				Hypervisor: "KVM",
+				Templateid: "template-id",
+				Cpunumber:  4,
+				Memory:     8192,
+			}
+			vms.EXPECT().GetVirtualMachinesMetricByID(*dummies.CSMachine1.Spec.InstanceID, gomock.Any()).Return(vmsResp, 1, nil)
+			vs.EXPECT().NewListVolumesParams().Return(&cloudstack.ListVolumesParams{})
+			vs.EXPECT().ListVolumes(gomock.Any()).Return(&cloudstack.ListVolumesResponse{
+				Count: 1, Volumes: []*cloudstack.Volume{{Id: "root-volume-id", Type: "ROOT"}}}, nil)
+			gomega.Ω(client.ResolveVMInstanceDetails(dummies.CSMachine1)).Should(gomega.Succeed())
+			gomega.Ω(dummies.CSMachine1.Status.Hypervisor).Should(gomega.Equal("KVM"))
+			gomega.Ω(dummies.CSMachine1.Status.TemplateID).Should(gomega.Equal("template-id"))
+			gomega.Ω(dummies.CSMachine1.Status.CPUNumber).Should(gomega.Equal(4))
+			gomega.Ω(dummies.CSMachine1.Status.MemoryMiB).Should(gomega.Equal(8192))
+			gomega.Ω(dummies.CSMachine1.Status.RootVolumeID).Should(gomega.Equal("root-volume-id"))
+
+			// The root volume isn't looked up again.
+			vms.EXPECT().GetVirtualMachinesMetricByID(*dummies.CSMachine1.Spec.InstanceID, gomock.Any()).Return(vmsResp, 1, nil)
+			gomega.Ω(client.ResolveVMInstanceDetails(dummies.CSMachine1)).Should(gomega.Succeed())
+		})
+
 		ginkgo.It("handles an unknown error when fetching by name", func() {
 			vms.EXPECT().GetVirtualMachinesMetricByID(*dummies.CSMachine1.Spec.InstanceID, gomock.Any()).Return(nil, -1, notFoundError)
 			vms.EXPECT().GetVirtualMachinesMetricByName(dummies.CSMachine1.Name, gomock.Any()).Return(nil, -1, unknownError)
@@ -130,8 +158,20 @@ var _ = ginkgo.Describe("Instance", func() {
 			vms.EXPECT().GetVirtualMachinesMetricByName(dummies.CSMachine1.Name, gomock.Any()).Return(nil, -1, notFoundError)
 		}
 
+		expectVMTagged := func(tags map[string]string) {
+			rs.EXPECT().NewCreateTagsParams([]string{*dummies.CSMachine1.Spec.InstanceID}, string(cloud.ResourceTypeVM), tags).
+				Return(&cloudstack.CreateTagsParams{})
+			rs.EXPECT().CreateTags(gomock.Any()).Return(&cloudstack.CreateTagsResponse{}, nil)
+		}
+
 		ginkgo.It("doesn't re-create if one already exists.", func() {
 			vms.EXPECT().GetVirtualMachinesMetricByID(*dummies.CSMachine1.Spec.InstanceID, gomock.Any()).Return(vmMetricResp, -1, nil)
+			tagsResp := &cloudstack.ListTagsResponse{}
+			for key, value := range cloud.ResourceTags(dummies.CSCluster, dummies.CSMachine1) {
+				tagsResp.Tags = append(tagsResp.Tags, &cloudstack.Tag{Key: key, Value: value})
+			}
+			rs.EXPECT().NewListTagsParams().Return(&cloudstack.ListTagsParams{})
+			rs.EXPECT().ListTags(gomock.Any()).Return(tagsResp, nil)
 			gomega.Ω(client.GetOrCreateVMInstance(
 				dummies.CSMachine1, dummies.CAPIMachine, dummies.CSCluster, dummies.CSFailureDomain1, dummies.CSAffinityGroup, "")).
 				Should(gomega.Succeed())
@@ -624,6 +664,7 @@ var _ = ginkgo.Describe("Instance", func() {
 
 						gomega.Ω(string(decompressedUserData)).To(gomega.Equal(expectUserData))
 					}).Return(deploymentResp, nil)
+				expectVMTagged(cloud.ResourceTags(dummies.CSCluster, dummies.CSMachine1))
 
 				gomega.Ω(client.GetOrCreateVMInstance(
 					dummies.CSMachine1, dummies.CAPIMachine, dummies.CSCluster, dummies.CSFailureDomain1, dummies.CSAffinityGroup, expectUserData)).
@@ -861,6 +902,20 @@ var _ = ginkgo.Describe("Instance", func() {
 					gomega.Ω(string(userData)).To(gomega.Equal(expectUserData))
 				}).Return(deploymentResp, nil)
 
+			// The VM is tagged with its owners and the additional tags of its cluster and machine.
+			dummies.CSCluster.Spec.AdditionalTags = map[string]string{"cost-center": "cluster", "team": "platform"}
+			dummies.CSMachine1.Spec.AdditionalTags = map[string]string{"cost-center": "machine"}
+			expectVMTagged(map[string]string{
+				cloud.CreatedByCAPCTagName:                                 "1",
+				cloud.ClusterTagNamePrefix + string(dummies.CSCluster.UID): "1",
+				cloud.OwnerClusterTagName:                                  dummies.CSCluster.Name,
+				cloud.OwnerNamespaceTagName:                                dummies.CSCluster.Namespace,
+				cloud.OwnerMachineTagName:                                  dummies.CSMachine1.Name,
+				cloud.VersionTagName:                                       cloud.Version,
+				"cost-center":                                              "machine",
+				"team":                                                     "platform",
+			})
+
 			err := client.GetOrCreateVMInstance(
 				dummies.CSMachine1,
 				dummies.CAPIMachine,
@@ -925,7 +980,7 @@ var _ = ginkgo.Describe("Instance", func() {
 			listVolumesParams.SetType("DATADISK")
 			vms.EXPECT().NewDestroyVirtualMachineParams(*dummies.CSMachine1.Spec.InstanceID).
 				Return(expungeDestroyParams)
-			vms.EXPECT().DestroyVirtualMachine(expungeDestroyParams).Return(nil, nil)
+			vms.EXPECT().DestroyVirtualMachine(expungeDestroyParams).Return(&cloudstack.DestroyVirtualMachineResponse{}, nil)
 			vs.EXPECT().NewListVolumesParams().Return(listVolumesParams)
 			vs.EXPECT().ListVolumes(listVolumesParams).Return(listVolumesResponse, nil)
 			vms.EXPECT().GetVirtualMachinesMetricByID(*dummies.CSMachine1.Spec.InstanceID, gomock.Any()).Return(nil, -1, notFoundError)
@@ -939,7 +994,7 @@ var _ = ginkgo.Describe("Instance", func() {
 			listVolumesParams.SetType("DATADISK")
 			vms.EXPECT().NewDestroyVirtualMachineParams(*dummies.CSMachine1.Spec.InstanceID).
 				Return(expungeDestroyParams)
-			vms.EXPECT().DestroyVirtualMachine(expungeDestroyParams).Return(nil, nil)
+			vms.EXPECT().DestroyVirtualMachine(expungeDestroyParams).Return(&cloudstack.DestroyVirtualMachineResponse{}, nil)
 			vs.EXPECT().NewListVolumesParams().Return(listVolumesParams)
 			vs.EXPECT().ListVolumes(listVolumesParams).Return(listVolumesResponse, nil)
 			vms.EXPECT().GetVirtualMachinesMetricByID(*dummies.CSMachine1.Spec.InstanceID, gomock.Any()).
@@ -955,7 +1010,7 @@ var _ = ginkgo.Describe("Instance", func() {
 			listVolumesParams.SetType("DATADISK")
 			vms.EXPECT().NewDestroyVirtualMachineParams(*dummies.CSMachine1.Spec.InstanceID).
 				Return(expungeDestroyParams)
-			vms.EXPECT().DestroyVirtualMachine(expungeDestroyParams).Return(nil, nil)
+			vms.EXPECT().DestroyVirtualMachine(expungeDestroyParams).Return(&cloudstack.DestroyVirtualMachineResponse{}, nil)
 			vs.EXPECT().NewListVolumesParams().Return(listVolumesParams)
 			vs.EXPECT().ListVolumes(listVolumesParams).Return(listVolumesResponse, nil)
 			vms.EXPECT().GetVirtualMachinesMetricByID(*dummies.CSMachine1.Spec.InstanceID, gomock.Any()).
@@ -971,7 +1026,7 @@ var _ = ginkgo.Describe("Instance", func() {
 			listVolumesParams.SetType("DATADISK")
 			vms.EXPECT().NewDestroyVirtualMachineParams(*dummies.CSMachine1.Spec.InstanceID).
 				Return(expungeDestroyParams)
-			vms.EXPECT().DestroyVirtualMachine(expungeDestroyParams).Return(nil, nil)
+			vms.EXPECT().DestroyVirtualMachine(expungeDestroyParams).Return(&cloudstack.DestroyVirtualMachineResponse{}, nil)
 			vs.EXPECT().NewListVolumesParams().Return(listVolumesParams)
 			vs.EXPECT().ListVolumes(listVolumesParams).Return(listVolumesResponse, nil)
 			vms.EXPECT().GetVirtualMachinesMetricByID(*dummies.CSMachine1.Spec.InstanceID, gomock.Any()).
@@ -980,5 +1035,199 @@ var _ = ginkgo.Describe("Instance", func() {
 				}, 1, nil)
 			gomega.Ω(client.DestroyVMInstance(dummies.CSMachine1)).Should(gomega.MatchError("VM deletion in progress"))
 		})
+
+		ginkgo.It("records the destroy job instead of waiting for it", func() {
+			listVolumesParams.SetVirtualmachineid(*dummies.CSMachine1.Spec.InstanceID)
+			listVolumesParams.SetType("DATADISK")
+			vms.EXPECT().NewDestroyVirtualMachineParams(*dummies.CSMachine1.Spec.InstanceID).
+				Return(expungeDestroyParams)
+			vms.EXPECT().DestroyVirtualMachine(expungeDestroyParams).
+				Return(&cloudstack.DestroyVirtualMachineResponse{JobID: "destroy-job"}, nil)
+			vs.EXPECT().NewListVolumesParams().Return(listVolumesParams)
+			vs.EXPECT().ListVolumes(listVolumesParams).Return(listVolumesResponse, nil)
+			gomega.Ω(client.DestroyVMInstance(dummies.CSMachine1)).Should(gomega.MatchError("VM deletion in progress"))
+			gomega.Ω(dummies.CSMachine1.Status.AsyncJob).ShouldNot(gomega.BeNil())
+			gomega.Ω(dummies.CSMachine1.Status.AsyncJob.ID).Should(gomega.Equal("destroy-job"))
+			gomega.Ω(dummies.CSMachine1.Status.AsyncJob.Command).Should(gomega.Equal(cloud.DestroyVMCommand))
+		})
+
+		ginkgo.It("deletes data disk volumes that were never attached", func() {
+			dummies.CSMachine1.Spec.DataDisks = []infrav1.CloudStackDataDisk{{Name: "etcd"}}
+			detachedParams := &cloudstack.ListVolumesParams{}
+			vs.EXPECT().NewListVolumesParams().Return(detachedParams)
+			vs.EXPECT().ListVolumes(detachedParams).Return(&cloudstack.ListVolumesResponse{Volumes: []*cloudstack.Volume{
+				{Id: "detached", Name: dummies.CSMachine1.Name + "-etcd"},
+				{Id: "other", Name: dummies.CSMachine1.Name + "-other"},
+			}}, nil)
+			deleteParams := &cloudstack.DeleteVolumeParams{}
+			vs.EXPECT().NewDeleteVolumeParams("detached").Return(deleteParams)
+			vs.EXPECT().DeleteVolume(deleteParams).Return(&cloudstack.DeleteVolumeResponse{Success: true}, nil)
+
+			vms.EXPECT().NewDestroyVirtualMachineParams(*dummies.CSMachine1.Spec.InstanceID).
+				Return(expungeDestroyParams)
+			vs.EXPECT().NewListVolumesParams().Return(listVolumesParams)
+			vs.EXPECT().ListVolumes(listVolumesParams).Return(listVolumesResponse, nil)
+			vms.EXPECT().DestroyVirtualMachine(expungeDestroyParams).Return(nil, fmt.Errorf("unable to find uuid for id"))
+			gomega.Ω(client.DestroyVMInstance(dummies.CSMachine1)).Should(gomega.Succeed())
+			keyword, _ := detachedParams.GetKeyword()
+			gomega.Ω(keyword).Should(gomega.Equal(dummies.CSMachine1.Name))
+		})
+	})
+
+	ginkgo.Context("when a destroy job is recorded", func() {
+		var ajs *cloudstack.MockAsyncjobServiceIface
+		queryParams := &cloudstack.QueryAsyncJobResultParams{}
+
+		ginkgo.BeforeEach(func() {
+			ajs = mockClient.Asyncjob.(*cloudstack.MockAsyncjobServiceIface)
+			dummies.CSMachine1.Status.AsyncJob = &infrav1.CloudStackAsyncJob{ID: "destroy-job", Command: cloud.DestroyVMCommand}
+			ajs.EXPECT().NewQueryAsyncJobResultParams("destroy-job").Return(queryParams)
+		})
+
+		ginkgo.It("reports deletion in progress while the job is pending", func() {
+			ajs.EXPECT().QueryAsyncJobResult(queryParams).Return(&cloudstack.QueryAsyncJobResultResponse{Jobstatus: 0}, nil)
+			gomega.Ω(client.DestroyVMInstance(dummies.CSMachine1)).Should(gomega.MatchError("VM deletion in progress"))
+			gomega.Ω(dummies.CSMachine1.Status.AsyncJob).ShouldNot(gomega.BeNil())
+		})
+
+		ginkgo.It("returns the error of a failed job", func() {
+			ajs.EXPECT().QueryAsyncJobResult(queryParams).Return(&cloudstack.QueryAsyncJobResultResponse{
+				Jobstatus: 2,
+				Jobresult: []byte(`{"errorcode":530,"errortext":"host is unreachable"}`),
+			}, nil)
+			err := client.DestroyVMInstance(dummies.CSMachine1)
+			gomega.Ω(err).Should(gomega.MatchError(&cloud.AsyncJobError{JobID: "destroy-job", Code: 530, Text: "host is unreachable"}))
+			gomega.Ω(dummies.CSMachine1.Status.AsyncJob).Should(gomega.BeNil())
+		})
+
+		ginkgo.It("checks the VM is gone once the job is done", func() {
+			ajs.EXPECT().QueryAsyncJobResult(queryParams).Return(&cloudstack.QueryAsyncJobResultResponse{Jobstatus: 1}, nil)
+			vms.EXPECT().GetVirtualMachinesMetricByID(*dummies.CSMachine1.Spec.InstanceID, gomock.Any()).Return(nil, -1, notFoundError)
+			vms.EXPECT().GetVirtualMachinesMetricByName(dummies.CSMachine1.Name, gomock.Any()).Return(nil, -1, notFoundError)
+			gomega.Ω(client.DestroyVMInstance(dummies.CSMachine1)).Should(gomega.Succeed())
+			gomega.Ω(dummies.CSMachine1.Status.AsyncJob).Should(gomega.BeNil())
+		})
+	})
+
+	ginkgo.Context("against a fake CloudStack", func() {
+		var fake *fakeCloud
+
+		ginkgo.BeforeEach(func() {
+			fake = newFakeCloud()
+		})
+
+		ginkgo.It("adopts an instance that wasn't deployed by CAPC", func() {
+			fake.useIsolatedNetwork()
+
+			// Deploy an instance and strip CAPC's tags, as if it had been built by hand.
+			handBuilt := fake.csMachine.DeepCopy()
+			handBuilt.Name = "hand-built"
+			gomega.Ω(fake.client.GetOrCreateVMInstance(handBuilt, fake.machine, fake.csCluster, fake.fd, nil, "")).Should(gomega.Succeed())
+			for _, tag := range fake.server.List(fakecloudstack.KindTag) {
+				if tag["resourceid"] == *handBuilt.Spec.InstanceID {
+					fake.server.Delete(fakecloudstack.KindTag, tag["id"].(string))
+				}
+			}
+
+			fake.csMachine.Spec.InstanceID = ptr.To(*handBuilt.Spec.InstanceID)
+			fake.csMachine.Spec.Adopt = true
+			fake.csMachine.Spec.Offering = infrav1.CloudStackResourceIdentifier{Name: fakecloudstack.MediumServiceOfferingName}
+			gomega.Ω(fake.client.AdoptVMInstance(fake.csMachine, fake.csCluster, fake.fd)).Should(gomega.MatchError(gomega.ContainSubstring(
+				"runs with offering " + fakecloudstack.ServiceOfferingName)))
+
+			fake.csMachine.Spec.Offering = infrav1.CloudStackResourceIdentifier{Name: fakecloudstack.ServiceOfferingName}
+			gomega.Ω(fake.server.Update(fakecloudstack.KindVirtualMachine, *handBuilt.Spec.InstanceID,
+				fakecloudstack.Resource{"state": "Stopped"})).Should(gomega.Succeed())
+			gomega.Ω(fake.client.AdoptVMInstance(fake.csMachine, fake.csCluster, fake.fd)).Should(gomega.MatchError(gomega.ContainSubstring(
+				"only running instances can be adopted")))
+
+			gomega.Ω(fake.server.Update(fakecloudstack.KindVirtualMachine, *handBuilt.Spec.InstanceID,
+				fakecloudstack.Resource{"state": "Running"})).Should(gomega.Succeed())
+			gomega.Ω(fake.client.AdoptVMInstance(fake.csMachine, fake.csCluster, fake.fd)).Should(gomega.Succeed())
+			gomega.Ω(*fake.csMachine.Spec.ProviderID).Should(gomega.Equal("cloudstack:///" + *handBuilt.Spec.InstanceID))
+			gomega.Ω(fake.csMachine.Status.Addresses).Should(gomega.Equal(handBuilt.Status.Addresses))
+			gomega.Ω(fake.csMachine.Status.InstanceState).Should(gomega.Equal("Running"))
+			tags, err := fake.client.GetTags(cloud.ResourceTypeVM, *fake.csMachine.Spec.InstanceID)
+			gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+			gomega.Ω(tags).Should(gomega.Equal(cloud.ResourceTags(fake.csCluster, fake.csMachine)))
+
+			// Adopting again finds the instance adopted, but other machines can't adopt it anymore.
+			gomega.Ω(fake.client.AdoptVMInstance(fake.csMachine, fake.csCluster, fake.fd)).Should(gomega.Succeed())
+			handBuilt.Spec.Adopt = true
+			gomega.Ω(fake.client.AdoptVMInstance(handBuilt, fake.csCluster, fake.fd)).Should(gomega.MatchError(gomega.ContainSubstring(
+				"already managed by CAPC")))
+			gomega.Ω(fake.server.Calls("deployVirtualMachine")).Should(gomega.Equal(1))
+		})
+
+		ginkgo.It("tags an instance whose tagging failed when it was deployed", func() {
+			fake.useSharedNetwork()
+			fake.server.FailNext("createTags", "tagging unavailable")
+			gomega.Ω(fake.getOrCreateVMInstance("")).Should(gomega.MatchError(gomega.ContainSubstring("tagging unavailable")))
+			tags, err := fake.client.GetTags(cloud.ResourceTypeVM, *fake.csMachine.Spec.InstanceID)
+			gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+			gomega.Ω(tags).Should(gomega.BeEmpty())
+
+			gomega.Ω(fake.getOrCreateVMInstance("")).Should(gomega.Succeed())
+			tags, err = fake.client.GetTags(cloud.ResourceTypeVM, *fake.csMachine.Spec.InstanceID)
+			gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+			gomega.Ω(tags).Should(gomega.Equal(cloud.ResourceTags(fake.csCluster, fake.csMachine)))
+			gomega.Ω(fake.server.Calls("deployVirtualMachine")).Should(gomega.Equal(1))
+		})
+
+		ginkgo.It("overrides the size and disk offering of the root volume", func() {
+			fake.useSharedNetwork()
+
+			fake.csMachine.Spec.RootDisk = &infrav1.CloudStackRootDisk{Size: 4, StorageTag: fakecloudstack.NVMeStorageTag}
+			gomega.Ω(fake.getOrCreateVMInstance("")).Should(
+				gomega.MatchError(gomega.ContainSubstring("smaller than the 8 GB of template")))
+
+			fake.csMachine.Spec.RootDisk.Size = 40
+			gomega.Ω(fake.getOrCreateVMInstance("")).Should(gomega.Succeed())
+			nvme, _ := fake.server.Find(fakecloudstack.KindDiskOffering, fakecloudstack.NVMeDiskOfferingName)
+			root, _ := fake.server.Find(fakecloudstack.KindVolume, "ROOT-"+*fake.csMachine.Spec.InstanceID)
+			gomega.Ω(root["size"]).Should(gomega.Equal(int64(40) << 30))
+			gomega.Ω(root["diskofferingid"]).Should(gomega.Equal(nvme["id"]))
+		})
+
+		ginkgo.It("resizes a VM in place by stopping, scaling and starting it", func() {
+			fake.useSharedNetwork()
+			fake.csMachine.Spec.InPlaceResize = infrav1.StopStartResize
+			gomega.Ω(fake.getOrCreateVMInstance("")).Should(gomega.Succeed())
+			gomega.Ω(fake.getOrCreateVMInstance("")).Should(gomega.Succeed())
+			gomega.Ω(fake.csMachine.Status.AsyncJob).Should(gomega.BeNil())
+			gomega.Ω(fake.client.ResizeVMInstance(fake.csMachine, fake.fd)).Should(gomega.Succeed())
+			gomega.Ω(fake.server.Calls("scaleVirtualMachine")).Should(gomega.Equal(0))
+
+			fake.csMachine.Spec.Offering = infrav1.CloudStackResourceIdentifier{Name: fakecloudstack.MediumServiceOfferingName}
+			for _, command := range []string{cloud.StopVMCommand, cloud.ScaleVMCommand, cloud.StartVMCommand} {
+				gomega.Ω(fake.client.ResizeVMInstance(fake.csMachine, fake.fd)).Should(gomega.MatchError("VM resize in progress"))
+				gomega.Ω(fake.csMachine.Status.AsyncJob.Command).Should(gomega.Equal(command))
+			}
+			gomega.Ω(fake.client.ResizeVMInstance(fake.csMachine, fake.fd)).Should(gomega.Succeed())
+			gomega.Ω(fake.csMachine.Status.AsyncJob).Should(gomega.BeNil())
+			gomega.Ω(fake.csMachine.Status.InstanceState).Should(gomega.BeEquivalentTo("Running"))
+			gomega.Ω(fake.csMachine.Status.ServiceOfferingName).Should(gomega.Equal(fakecloudstack.MediumServiceOfferingName))
+			vm, _ := fake.server.Get(fakecloudstack.KindVirtualMachine, *fake.csMachine.Spec.InstanceID)
+			gomega.Ω(vm["memory"]).Should(gomega.BeEquivalentTo(8192))
+		})
+
+		ginkgo.It("starts a VM stopped to be resized again when its scale job fails", func() {
+			fake.useSharedNetwork()
+			fake.csMachine.Spec.InPlaceResize = infrav1.StopStartResize
+			gomega.Ω(fake.getOrCreateVMInstance("")).Should(gomega.Succeed())
+			gomega.Ω(fake.getOrCreateVMInstance("")).Should(gomega.Succeed())
+
+			fake.server.FailNextJob("scaleVirtualMachine", "insufficient capacity")
+			fake.csMachine.Spec.Offering = infrav1.CloudStackResourceIdentifier{Name: fakecloudstack.MediumServiceOfferingName}
+			for _, command := range []string{cloud.StopVMCommand, cloud.ScaleVMCommand} {
+				gomega.Ω(fake.client.ResizeVMInstance(fake.csMachine, fake.fd)).Should(gomega.MatchError("VM resize in progress"))
+				gomega.Ω(fake.csMachine.Status.AsyncJob.Command).Should(gomega.Equal(command))
+			}
+			gomega.Ω(fake.client.ResizeVMInstance(fake.csMachine, fake.fd)).Should(
+				gomega.MatchError(gomega.ContainSubstring("insufficient capacity")))
+			gomega.Ω(fake.csMachine.Status.AsyncJob.Command).Should(gomega.Equal(cloud.StartVMCommand))
+			vm, _ := fake.server.Get(fakecloudstack.KindVirtualMachine, *fake.csMachine.Spec.InstanceID)
+			gomega.Ω(vm["state"]).Should(gomega.Equal("Running"))
+		})
 	})
 })
@@ -17,6 +17,8 @@ limitations under the License.
 package cloud
 
 import (
+	"maps"
+	"slices"
 	"strconv"
 	"strings"
 
@@ -30,7 +32,7 @@ type IsoNetworkIface interface {
 
 	AssociatePublicIPAddress(*infrav1.CloudStackFailureDomain, *infrav1.CloudStackIsolatedNetwork, *infrav1.CloudStackCluster) error
 	GetOrCreateLoadBalancerRule(*infrav1.CloudStackIsolatedNetwork, *infrav1.CloudStackCluster) error
-	OpenFirewallRules(*infrav1.CloudStackIsolatedNetwork) error
+	OpenFirewallRules(*infrav1.CloudStackIsolatedNetwork, *infrav1.CloudStackCluster) error
 	GetPublicIP(*infrav1.CloudStackFailureDomain, *infrav1.CloudStackCluster) (*cloudstack.PublicIpAddress, error)
 	ResolveLoadBalancerRuleDetails(*infrav1.CloudStackIsolatedNetwork) error
 
@@ -84,18 +86,20 @@ func (c *client) AssociatePublicIPAddress(
 		return errors.Wrapf(err,
 			"associating public IP address with ID %s to network with ID %s",
 			publicAddress.Id, isoNet.Spec.ID)
-	} else if err := c.AddClusterTag(ResourceTypeIPAddress, publicAddress.Id, csCluster); err != nil {
+	} else if err := c.AddTags(ResourceTypeIPAddress, publicAddress.Id, ResourceTags(csCluster, nil)); err != nil {
 		return errors.Wrapf(err,
-			"adding tag to public IP address with ID %s", publicAddress.Id)
-	} else if err := c.AddCreatedByCAPCTag(ResourceTypeIPAddress, isoNet.Status.PublicIPID); err != nil {
-		return errors.Wrapf(err,
-			"adding tag to public IP address with ID %s", publicAddress.Id)
+			"adding tags to public IP address with ID %s", publicAddress.Id)
 	}
 	return nil
 }
 
-// CreateIsolatedNetwork creates an isolated network in the relevant FailureDomain per passed network specification.
-func (c *client) CreateIsolatedNetwork(fd *infrav1.CloudStackFailureDomain, isoNet *infrav1.CloudStackIsolatedNetwork) (retErr error) {
+// CreateIsolatedNetwork creates an isolated network in the relevant FailureDomain per passed network specification,
+// tagged as created by CAPC for the cluster.
+func (c *client) CreateIsolatedNetwork(
+	fd *infrav1.CloudStackFailureDomain,
+	isoNet *infrav1.CloudStackIsolatedNetwork,
+	csCluster *infrav1.CloudStackCluster,
+) (retErr error) {
 	// Get network offering ID.
 	offeringName := NetOffering
 	// First, check if VPC is specified and handle it
@@ -103,7 +107,7 @@ func (c *client) CreateIsolatedNetwork(fd *infrav1.CloudStackFailureDomain, isoN
 		// Try to resolve or create the VPC
 		err := c.ResolveVPC(isoNet.Spec.VPC)
 		if err != nil { // No VPC found, create it
-			err = c.CreateVPC(fd, isoNet.Spec.VPC)
+			err = c.CreateVPC(fd, isoNet.Spec.VPC, csCluster)
 			if err != nil {
 				return errors.Wrap(err, "creating VPC with name "+isoNet.Spec.VPC.Name)
 			}
@@ -133,6 +137,10 @@ func (c *client) CreateIsolatedNetwork(fd *infrav1.CloudStackFailureDomain, isoN
 		p.SetNetmask(isoNet.Spec.Netmask)
 	}
 
+	// If unset, CloudStack allocates the IPv6 subnet from the zone's guest IPv6 prefix when the offering is dual-stack.
+	setIfNotEmpty(isoNet.Spec.IP6Gateway, p.SetIp6gateway)
+	setIfNotEmpty(isoNet.Spec.IP6CIDR, p.SetIp6cidr)
+
 	setIfNotEmpty(c.user.Project.ID, p.SetProjectid)
 
 	// If VPC is specified, set the VPC ID for the network
@@ -148,12 +156,18 @@ func (c *client) CreateIsolatedNetwork(fd *infrav1.CloudStackFailureDomain, isoN
 	isoNet.Spec.ID = resp.Id
 	isoNet.Spec.Gateway = resp.Gateway
 	isoNet.Spec.Netmask = resp.Netmask
+	isoNet.Spec.IP6Gateway = resp.Ip6gateway
+	isoNet.Spec.IP6CIDR = resp.Ip6cidr
 	isoNet.Status.RoutingMode = resp.Ip4routing
-	return c.AddCreatedByCAPCTag(ResourceTypeNetwork, isoNet.Spec.ID)
+	return c.tagCreatedResource(ResourceTypeNetwork, isoNet.Spec.ID, ResourceTags(csCluster, nil))
 }
 
-// OpenFirewallRules opens a CloudStack egress firewall for an isolated network.
-func (c *client) OpenFirewallRules(isoNet *infrav1.CloudStackIsolatedNetwork) (retErr error) {
+// OpenFirewallRules opens a CloudStack egress firewall for an isolated network. Created rules are tagged as created
+// by CAPC for the cluster.
+func (c *client) OpenFirewallRules(
+	isoNet *infrav1.CloudStackIsolatedNetwork,
+	csCluster *infrav1.CloudStackCluster,
+) (retErr error) {
 	// Early return if VPC is present
 	// Firewall rules are not opened for isolated networks within a VPC because VPCs have their own mechanisms for managing firewall rules.
 	if isoNet.Spec.VPC != nil && isoNet.Spec.VPC.ID != "" {
@@ -187,8 +201,9 @@ func (c *client) OpenFirewallRules(isoNet *infrav1.CloudStackIsolatedNetwork) (r
 
 	// Create firewall rules for each protocol
 	protocols := []string{NetworkProtocolTCP, NetworkProtocolUDP, NetworkProtocolICMP}
+	tags := ResourceTags(csCluster, nil)
 	for _, proto := range protocols {
-		if err := c.createFirewallRule(isoNet, proto); err != nil {
+		if err := c.createFirewallRule(isoNet, proto, tags); err != nil {
 			c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
 			return errors.Wrapf(err, "failed creating firewall rule for network ID %s", isoNet.Spec.ID)
 		}
@@ -240,6 +255,34 @@ func (c *client) checkFirewallRules(isoNet *infrav1.CloudStackIsolatedNetwork) (
 			return false, nil
 		}
 	}
+
+	if isoNet.Spec.IP6CIDR != "" {
+		return c.checkIpv6FirewallRules(isoNet)
+	}
+	return true, nil
+}
+
+// Helper function to check if the IPv6 egress firewall rules of a dual-stack network exist
+func (c *client) checkIpv6FirewallRules(isoNet *infrav1.CloudStackIsolatedNetwork) (bool, error) {
+	p := c.cs.Firewall.NewListIpv6FirewallRulesParams()
+	p.SetNetworkid(isoNet.Spec.ID)
+	p.SetTraffictype("Egress")
+	setIfNotEmpty(c.user.Project.ID, p.SetProjectid)
+
+	rules, err := c.cs.Firewall.ListIpv6FirewallRules(p)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to list IPv6 firewall rules for network ID %s", isoNet.Spec.ID)
+	}
+
+	foundProtocols := make(map[string]bool)
+	for _, rule := range rules.Ipv6FirewallRules {
+		foundProtocols[rule.Protocol] = true
+	}
+	for _, proto := range []string{NetworkProtocolTCP, NetworkProtocolUDP, NetworkProtocolICMP} {
+		if !foundProtocols[proto] {
+			return false, nil
+		}
+	}
 	return true, nil
 }
 
@@ -255,8 +298,8 @@ func (c *client) getNetwork(networkID string) (*cloudstack.Network, error) {
 	return network, nil
 }
 
-// Helper function to create a firewall rule for a given protocol
-func (c *client) createFirewallRule(isoNet *infrav1.CloudStackIsolatedNetwork, proto string) error {
+// Helper function to create a firewall rule for a given protocol. Created rules are tagged with the passed tags.
+func (c *client) createFirewallRule(isoNet *infrav1.CloudStackIsolatedNetwork, proto string, tags map[string]string) error {
 	if isoNet.Status.RoutingMode != "" {
 		// Handle routing firewall rules
 		p := c.cs.Firewall.NewCreateRoutingFirewallRuleParams(isoNet.Spec.ID, proto)
@@ -264,9 +307,11 @@ func (c *client) createFirewallRule(isoNet *infrav1.CloudStackIsolatedNetwork, p
 			p.SetIcmptype(-1)
 			p.SetIcmpcode(-1)
 		}
-		_, err := c.cs.Firewall.CreateRoutingFirewallRule(p)
+		resp, err := c.cs.Firewall.CreateRoutingFirewallRule(p)
 		if err != nil && !c.isIgnorableFirewallRuleError(err) {
 			return errors.Wrapf(err, "failed creating routing firewall rule for network ID %s protocol %s", isoNet.Spec.ID, proto)
+		} else if err == nil {
+			return c.tagCreatedResource(ResourceTypeFirewallRule, resp.Id, tags)
 		}
 		return nil
 	}
@@ -277,9 +322,34 @@ func (c *client) createFirewallRule(isoNet *infrav1.CloudStackIsolatedNetwork, p
 		p.SetIcmptype(-1)
 		p.SetIcmpcode(-1)
 	}
-	_, err := c.cs.Firewall.CreateEgressFirewallRule(p)
+	resp, err := c.cs.Firewall.CreateEgressFirewallRule(p)
 	if err != nil && !c.isIgnorableFirewallRuleError(err) {
 		return errors.Wrapf(err, "failed creating egress firewall rule for network ID %s protocol %s", isoNet.Spec.ID, proto)
+	} else if err == nil {
+		if err := c.tagCreatedResource(ResourceTypeFirewallRule, resp.Id, tags); err != nil {
+			return err
+		}
+	}
+
+	if isoNet.Spec.IP6CIDR != "" {
+		return c.createIpv6FirewallRule(isoNet, proto, tags)
+	}
+	return nil
+}
+
+// Helper function to create an IPv6 egress firewall rule for a given protocol
+func (c *client) createIpv6FirewallRule(isoNet *infrav1.CloudStackIsolatedNetwork, proto string, tags map[string]string) error {
+	p := c.cs.Firewall.NewCreateIpv6FirewallRuleParams(isoNet.Spec.ID, proto)
+	p.SetTraffictype("Egress")
+	if proto == "icmp" {
+		p.SetIcmptype(-1)
+		p.SetIcmpcode(-1)
+	}
+	resp, err := c.cs.Firewall.CreateIpv6FirewallRule(p)
+	if err != nil && !c.isIgnorableFirewallRuleError(err) {
+		return errors.Wrapf(err, "failed creating IPv6 firewall rule for network ID %s protocol %s", isoNet.Spec.ID, proto)
+	} else if err == nil {
+		return c.tagCreatedResource(ResourceTypeFirewallRule, resp.Id, tags)
 	}
 	return nil
 }
@@ -351,7 +421,8 @@ func (c *client) ResolveLoadBalancerRuleDetails(
 	return errors.New("no load balancer rule found")
 }
 
-// GetOrCreateLoadBalancerRule Create a load balancer rule that can be assigned to instances.
+// GetOrCreateLoadBalancerRule Create a load balancer rule that can be assigned to instances. The rule, its policies
+// and the rules of additional ports are kept in line with the CloudStackCluster's APIServerLoadBalancer.
 func (c *client) GetOrCreateLoadBalancerRule(
 	isoNet *infrav1.CloudStackIsolatedNetwork,
 	csCluster *infrav1.CloudStackCluster,
@@ -367,25 +438,47 @@ func (c *client) GetOrCreateLoadBalancerRule(
 		isoNet.Spec.ControlPlaneEndpoint.Port = 6443
 	}
 
-	// Check if rule exists.
-	if err := c.ResolveLoadBalancerRuleDetails(isoNet); err == nil ||
-		!strings.Contains(strings.ToLower(err.Error()), "no load balancer rule found") {
+	lb := csCluster.Spec.APIServerLoadBalancer
+	if lb == nil {
+		lb = &infrav1.APIServerLoadBalancer{}
+	}
+	rules, err := c.listLoadBalancerRules(isoNet)
+	if err != nil {
 		return errors.Wrap(err, "resolving load balancer rule details")
 	}
 
-	p := c.cs.LoadBalancer.NewCreateLoadBalancerRuleParams(
-		"roundrobin", "Kubernetes_API_Server", K8sDefaultAPIPort, K8sDefaultAPIPort)
-	p.SetPublicport(int(csCluster.Spec.ControlPlaneEndpoint.Port))
-	p.SetNetworkid(isoNet.Spec.ID)
+	tags := ResourceTags(csCluster, nil)
+	apiServerRule := loadBalancerRule{
+		name:        APIServerLBRuleName,
+		publicPort:  int(csCluster.Spec.ControlPlaneEndpoint.Port),
+		privatePort: K8sDefaultAPIPort,
+		tags:        tags,
+	}
+	ruleID, replaced, err := c.reconcileLoadBalancerRule(isoNet, rules, apiServerRule, lb, "", false)
+	if err != nil {
+		return err
+	}
+	if ruleID != isoNet.Status.LBRuleID { // Policies are deleted along with a replaced rule.
+		isoNet.Status.LBHealthCheckPolicyID = ""
+		isoNet.Status.LBStickinessPolicyID = ""
+	}
+	isoNet.Status.LBRuleID = ruleID
 
-	p.SetPublicipid(isoNet.Status.PublicIPID)
-	p.SetProtocol(NetworkProtocolTCP)
-	resp, err := c.cs.LoadBalancer.CreateLoadBalancerRule(p)
+	if err := c.reconcileLBHealthCheckPolicy(isoNet, lb.HealthCheck); err != nil {
+		return err
+	}
+	if err := c.reconcileLBStickinessPolicy(isoNet, lb.Stickiness); err != nil {
+		return err
+	}
+	additionalReplaced, err := c.reconcileAdditionalLoadBalancerRules(isoNet, rules, lb, tags)
 	if err != nil {
-		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
 		return err
 	}
-	isoNet.Status.LBRuleID = resp.Id
+
+	// Rules are only replaced when their CIDRs or ports change, in which case previous restrictions need removing.
+	if len(lb.AllowedCIDRs) > 0 || replaced || additionalReplaced {
+		return c.reconcileAPIServerAccess(isoNet, csCluster, lb)
+	}
 	return nil
 }
 
@@ -398,13 +491,15 @@ func (c *client) GetOrCreateIsolatedNetwork(
 	net := isoNet.Network()
 	err := c.ResolveNetwork(net)
 	if err != nil {
-		if err = c.CreateIsolatedNetwork(fd, isoNet); err != nil {
+		if err = c.CreateIsolatedNetwork(fd, isoNet, csCluster); err != nil {
 			return errors.Wrap(err, "creating a new isolated network")
 		}
 	} else { // Network existed and was resolved. Set ID on isoNet CloudStackIsolatedNetwork in case it only had name set.
 		isoNet.Spec.ID = net.ID
 		isoNet.Spec.Gateway = net.Gateway
 		isoNet.Spec.Netmask = net.Netmask
+		isoNet.Spec.IP6Gateway = net.IP6Gateway
+		isoNet.Spec.IP6CIDR = net.IP6CIDR
 		isoNet.Status.RoutingMode = net.RoutingMode
 		if net.VPC != nil && net.VPC.ID != "" {
 			isoNet.Spec.VPC = net.VPC
@@ -424,6 +519,11 @@ func (c *client) GetOrCreateIsolatedNetwork(
 		}
 	}
 
+	// Attach the network ACL list before restricting API server access, which may add items to it.
+	if err := c.reconcileNetworkACL(isoNet); err != nil {
+		return errors.Wrap(err, "reconciling the network ACL list")
+	}
+
 	// Handle control plane endpoint based on network type
 	if isoNet.Status.RoutingMode == "" {
 		// For non-routed networks, use public IP and load balancer
@@ -438,34 +538,35 @@ func (c *client) GetOrCreateIsolatedNetwork(
 	}
 
 	//  Open the Isolated Network on endopint port.
-	return errors.Wrap(c.OpenFirewallRules(isoNet), "opening the isolated network's firewall")
+	return errors.Wrap(c.OpenFirewallRules(isoNet, csCluster), "opening the isolated network's firewall")
 }
 
 // AssignVMToLoadBalancerRule assigns a VM instance to a load balancing rule (specifying lb membership).
+// The instance is also assigned to the rules of the load balancer's additional ports.
 func (c *client) AssignVMToLoadBalancerRule(isoNet *infrav1.CloudStackIsolatedNetwork, instanceID string) (retErr error) {
-
-	// Check that the instance isn't already in LB rotation.
-	lbRuleInstances, retErr := c.cs.LoadBalancer.ListLoadBalancerRuleInstances(
-		c.cs.LoadBalancer.NewListLoadBalancerRuleInstancesParams(isoNet.Status.LBRuleID))
-	if retErr != nil {
-		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(retErr)
-		return retErr
+	ruleIDs := []string{isoNet.Status.LBRuleID}
+	for _, name := range slices.Sorted(maps.Keys(isoNet.Status.AdditionalLBRuleIDs)) {
+		ruleIDs = append(ruleIDs, isoNet.Status.AdditionalLBRuleIDs[name])
 	}
-	for _, instance := range lbRuleInstances.LoadBalancerRuleInstances {
-		if instance.Id == instanceID { // Already assigned to load balancer..
-			return nil
+
+	for _, ruleID := range ruleIDs {
+		// Check that the instance isn't already in LB rotation.
+		instanceIDs, err := c.loadBalancerRuleInstanceIDs(ruleID)
+		if err != nil {
+			return err
+		}
+		if slices.Contains(instanceIDs, instanceID) { // Already assigned to load balancer..
+			continue
 		}
-	}
 
-	// Assign to Load Balancer.
-	p := c.cs.LoadBalancer.NewAssignToLoadBalancerRuleParams(isoNet.Status.LBRuleID)
-	p.SetVirtualmachineids([]string{instanceID})
-	_, retErr = c.cs.LoadBalancer.AssignToLoadBalancerRule(p)
-	c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(retErr)
-	return retErr
+		// Assign to Load Balancer.
+		if err := c.assignToLoadBalancerRule(ruleID, []string{instanceID}); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
-// DeleteNetwork deletes an isolated network.
 func (c *client) DeleteNetwork(net infrav1.Network) error {
 	_, err := c.cs.Network.DeleteNetwork(c.cs.Network.NewDeleteNetworkParams(net.ID))
 	c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
@@ -491,6 +592,11 @@ func (c *client) DisposeIsoNetResources(
 	if err := c.DeleteNetworkIfNotInUse(*isoNet.Network()); err != nil && !strings.Contains(strings.ToLower(err.Error()), "no match found") {
 		return err
 	}
+	if isoNet.Status.NetworkACLListID != "" && isoNet.Spec.VPC != nil && isoNet.Spec.VPC.ID != "" {
+		if err := c.DeleteNetworkACLListIfNotInUse(isoNet); err != nil && !strings.Contains(strings.ToLower(err.Error()), "no match found") {
+			return err
+		}
+	}
 	if isoNet.Spec.VPC != nil && isoNet.Spec.VPC.ID != "" {
 		if err := c.RemoveClusterTagFromVPC(csCluster, *isoNet.Spec.VPC); err != nil {
 			return err
@@ -24,8 +24,10 @@ import (
 	gomega "github.com/onsi/gomega"
 	"github.com/pkg/errors"
 	gomock "go.uber.org/mock/gomock"
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
 	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
 	dummies "sigs.k8s.io/cluster-api-provider-cloudstack/test/dummies/v1beta3"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/test/fakecloudstack"
 )
 
 var _ = ginkgo.Describe("Network", func() {
@@ -45,6 +47,7 @@ var _ = ginkgo.Describe("Network", func() {
 		as         *csapi.MockAddressServiceIface
 		lbs        *csapi.MockLoadBalancerServiceIface
 		rs         *csapi.MockResourcetagsServiceIface
+		acls       *csapi.MockNetworkACLServiceIface
 		client     cloud.Client
 	)
 
@@ -58,6 +61,7 @@ var _ = ginkgo.Describe("Network", func() {
 		as = mockClient.Address.(*csapi.MockAddressServiceIface)
 		lbs = mockClient.LoadBalancer.(*csapi.MockLoadBalancerServiceIface)
 		rs = mockClient.Resourcetags.(*csapi.MockResourcetagsServiceIface)
+		acls = mockClient.NetworkACL.(*csapi.MockNetworkACLServiceIface)
 		client = cloud.NewClientFromCSAPIClient(mockClient, nil)
 		dummies.SetDummyVars()
 	})
@@ -66,6 +70,15 @@ var _ = ginkgo.Describe("Network", func() {
 		mockCtrl.Finish()
 	})
 
+	// expectTagged expects resources created for the cluster to be tagged with its resource tags.
+	expectTagged := func(rType cloud.ResourceType, ids ...string) {
+		for _, id := range ids {
+			rs.EXPECT().NewCreateTagsParams([]string{id}, string(rType), cloud.ResourceTags(dummies.CSCluster, nil)).
+				Return(&csapi.CreateTagsParams{})
+		}
+		rs.EXPECT().CreateTags(gomock.Any()).Return(&csapi.CreateTagsResponse{}, nil).Times(len(ids))
+	}
+
 	ginkgo.Context("Get or Create Isolated network in CloudStack", func() {
 		ginkgo.It("calls to create an isolated network when not found", func() {
 			dummies.Zone1.Network = dummies.ISONet1
@@ -116,29 +129,29 @@ var _ = ginkgo.Describe("Network", func() {
 					Return(&csapi.CreateEgressFirewallRuleResponse{}, nil),
 			)
 
-			// Expect 6 ListTags calls: 3 for network (AddCreatedByCAPCTag, AddClusterTag x2),
-			// 3 for public IP (GetPublicIP, AddClusterTag, AddCreatedByCAPCTag)
+			// Expect 3 ListTags calls: 2 for network (AddClusterTag), 1 for public IP (AddTags)
 			emptyResponse := &csapi.ListTagsResponse{Tags: []*csapi.Tag{}}
 			clusterTagResponse := &csapi.ListTagsResponse{Tags: []*csapi.Tag{{Key: cloud.CreatedByCAPCTagName, Value: "1"}}}
 			gomock.InOrder(
-				rs.EXPECT().NewListTagsParams().Return(&csapi.ListTagsParams{}), // For AddCreatedByCAPCTag (Network)
-				rs.EXPECT().ListTags(gomock.Any()).Return(emptyResponse, nil),
 				rs.EXPECT().NewListTagsParams().Return(&csapi.ListTagsParams{}), // For AddClusterTag (Network, IsCapcManaged)
 				rs.EXPECT().ListTags(gomock.Any()).Return(clusterTagResponse, nil),
 				rs.EXPECT().NewListTagsParams().Return(&csapi.ListTagsParams{}), // For AddClusterTag (Network, AddTags)
 				rs.EXPECT().ListTags(gomock.Any()).Return(clusterTagResponse, nil),
-				rs.EXPECT().NewListTagsParams().Return(&csapi.ListTagsParams{}), // For GetPublicIP (PublicIpAddress)
-				rs.EXPECT().ListTags(gomock.Any()).Return(clusterTagResponse, nil),
-				rs.EXPECT().NewListTagsParams().Return(&csapi.ListTagsParams{}), // For AddClusterTag (PublicIpAddress)
-				rs.EXPECT().ListTags(gomock.Any()).Return(clusterTagResponse, nil),
-				rs.EXPECT().NewListTagsParams().Return(&csapi.ListTagsParams{}), // For AddCreatedByCAPCTag (PublicIpAddress)
+				rs.EXPECT().NewListTagsParams().Return(&csapi.ListTagsParams{}), // For AddTags (PublicIpAddress)
 				rs.EXPECT().ListTags(gomock.Any()).Return(emptyResponse, nil),
 			)
 
-			// Expect 4 CreateTags calls: 2 for network (cluster + created-by), 2 for public IP (cluster + created-by)
-			rs.EXPECT().NewCreateTagsParams(gomock.Any(), gomock.Any(), gomock.Any()).
-				Return(&csapi.CreateTagsParams{}).Times(4)
-			rs.EXPECT().CreateTags(gomock.Any()).Return(&csapi.CreateTagsResponse{}, nil).Times(4)
+			// Expect 6 CreateTags calls: 2 for network (creation + cluster), 1 for public IP, 3 for firewall rules
+			tags := cloud.ResourceTags(dummies.CSCluster, nil)
+			rs.EXPECT().NewCreateTagsParams([]string{dummies.ISONet1.ID}, string(cloud.ResourceTypeNetwork), tags).
+				Return(&csapi.CreateTagsParams{})
+			rs.EXPECT().NewCreateTagsParams([]string{dummies.ISONet1.ID}, string(cloud.ResourceTypeNetwork), gomock.Any()).
+				Return(&csapi.CreateTagsParams{})
+			rs.EXPECT().NewCreateTagsParams(gomock.Any(), string(cloud.ResourceTypeIPAddress), tags).
+				Return(&csapi.CreateTagsParams{})
+			rs.EXPECT().NewCreateTagsParams(gomock.Any(), string(cloud.ResourceTypeFirewallRule), tags).
+				Return(&csapi.CreateTagsParams{}).Times(3)
+			rs.EXPECT().CreateTags(gomock.Any()).Return(&csapi.CreateTagsResponse{}, nil).Times(6)
 
 			lbs.EXPECT().NewListLoadBalancerRulesParams().Return(&csapi.ListLoadBalancerRulesParams{})
 			lbs.EXPECT().ListLoadBalancerRules(gomock.Any()).Return(
@@ -186,8 +199,11 @@ var _ = ginkgo.Describe("Network", func() {
 					return p
 				}).Times(3)
 			fs.EXPECT().CreateEgressFirewallRule(gomock.Any()).Return(&csapi.CreateEgressFirewallRuleResponse{}, nil).Times(3)
+			rs.EXPECT().NewCreateTagsParams(gomock.Any(), string(cloud.ResourceTypeFirewallRule), cloud.ResourceTags(dummies.CSCluster, nil)).
+				Return(&csapi.CreateTagsParams{}).Times(3)
+			rs.EXPECT().CreateTags(gomock.Any()).Return(&csapi.CreateTagsResponse{}, nil).Times(3)
 
-			err := client.OpenFirewallRules(isoNet)
+			err := client.OpenFirewallRules(isoNet, dummies.CSCluster)
 			gomega.Expect(err).To(gomega.BeNil())
 			gomega.Expect(isoNet.Status.FirewallRulesOpened).To(gomega.BeTrue())
 		})
@@ -211,7 +227,7 @@ var _ = ginkgo.Describe("Network", func() {
 				},
 			}, nil)
 
-			err := client.OpenFirewallRules(isoNet)
+			err := client.OpenFirewallRules(isoNet, dummies.CSCluster)
 			gomega.Expect(err).To(gomega.BeNil())
 			gomega.Expect(isoNet.Status.FirewallRulesOpened).To(gomega.BeTrue())
 		})
@@ -234,14 +250,54 @@ var _ = ginkgo.Describe("Network", func() {
 			// Mock firewall rule creation: fail on UDP
 			fs.EXPECT().NewCreateEgressFirewallRuleParams(isoNet.Spec.ID, "tcp").Return(&csapi.CreateEgressFirewallRuleParams{})
 			fs.EXPECT().CreateEgressFirewallRule(gomock.Any()).Return(&csapi.CreateEgressFirewallRuleResponse{}, nil)
+			rs.EXPECT().NewCreateTagsParams(gomock.Any(), gomock.Any(), gomock.Any()).Return(&csapi.CreateTagsParams{})
+			rs.EXPECT().CreateTags(gomock.Any()).Return(&csapi.CreateTagsResponse{}, nil)
 			fs.EXPECT().NewCreateEgressFirewallRuleParams(isoNet.Spec.ID, "udp").Return(&csapi.CreateEgressFirewallRuleParams{})
 			fs.EXPECT().CreateEgressFirewallRule(gomock.Any()).Return(nil, errors.New("failed to create UDP rule"))
 
-			err := client.OpenFirewallRules(isoNet)
+			err := client.OpenFirewallRules(isoNet, dummies.CSCluster)
 			gomega.Expect(err).To(gomega.HaveOccurred())
 			gomega.Expect(err.Error()).To(gomega.ContainSubstring("failed creating egress firewall rule for network ID net-123 protocol udp"))
 			gomega.Expect(isoNet.Status.FirewallRulesOpened).To(gomega.BeFalse())
 		})
+
+		ginkgo.It("creates IPv6 firewall rules for dual-stack networks", func() {
+			isoNet := dummies.CSISONet1
+			isoNet.Status.RoutingMode = "" // Egress rules
+			isoNet.Spec.ID = "net-123"
+			isoNet.Spec.IP6CIDR = "fd00:1::/64"
+
+			ns.EXPECT().GetNetworkByID(isoNet.Spec.ID, gomock.Any()).Return(&csapi.Network{Egressdefaultpolicy: false}, 1, nil)
+
+			// Mock firewall rule check: IPv4 rules exist, IPv6 rules don't
+			fs.EXPECT().NewListEgressFirewallRulesParams().Return(&csapi.ListEgressFirewallRulesParams{})
+			fs.EXPECT().ListEgressFirewallRules(gomock.Any()).Return(&csapi.ListEgressFirewallRulesResponse{
+				Count: 3,
+				EgressFirewallRules: []*csapi.EgressFirewallRule{
+					{Protocol: "tcp"},
+					{Protocol: "udp"},
+					{Protocol: "icmp", Icmptype: -1, Icmpcode: -1},
+				},
+			}, nil)
+			fs.EXPECT().NewListIpv6FirewallRulesParams().Return(&csapi.ListIpv6FirewallRulesParams{})
+			fs.EXPECT().ListIpv6FirewallRules(gomock.Any()).Return(&csapi.ListIpv6FirewallRulesResponse{}, nil)
+
+			// Existing IPv4 rules conflict and are ignored.
+			fs.EXPECT().NewCreateEgressFirewallRuleParams(isoNet.Spec.ID, gomock.Any()).
+				Return(&csapi.CreateEgressFirewallRuleParams{}).Times(3)
+			fs.EXPECT().CreateEgressFirewallRule(gomock.Any()).
+				Return(nil, errors.New("There is already a firewall rule specified")).Times(3)
+			fs.EXPECT().NewCreateIpv6FirewallRuleParams(isoNet.Spec.ID, gomock.Any()).
+				Return(&csapi.CreateIpv6FirewallRuleParams{}).Times(3)
+			fs.EXPECT().CreateIpv6FirewallRule(gomock.Any()).Return(&csapi.CreateIpv6FirewallRuleResponse{}, nil).Times(3)
+			rs.EXPECT().NewCreateTagsParams(gomock.Any(), string(cloud.ResourceTypeFirewallRule), gomock.Any()).
+				Return(&csapi.CreateTagsParams{}).Times(3)
+			rs.EXPECT().CreateTags(gomock.Any()).Return(&csapi.CreateTagsResponse{}, nil).Times(3)
+
+			err := client.OpenFirewallRules(isoNet, dummies.CSCluster)
+			gomega.Expect(err).To(gomega.BeNil())
+			gomega.Expect(isoNet.Status.FirewallRulesOpened).To(gomega.BeTrue())
+		})
 	})
 
 	ginkgo.Context("in an isolated network with public IPs available", func() {
@@ -305,19 +361,16 @@ var _ = ginkgo.Describe("Network", func() {
 			aip := &csapi.AssociateIpAddressParams{}
 			as.EXPECT().NewAssociateIpAddressParams().Return(aip)
 			as.EXPECT().AssociateIpAddress(aip).Return(&csapi.AssociateIpAddressResponse{}, nil)
-			createdByResponse := &csapi.ListTagsResponse{Tags: []*csapi.Tag{{Key: cloud.CreatedByCAPCTagName, Value: "1"}}}
-			emptyResponse := &csapi.ListTagsResponse{Tags: []*csapi.Tag{}}
-			gomock.InOrder(
-				rs.EXPECT().NewListTagsParams().Return(&csapi.ListTagsParams{}), // AddClusterTag: IsCapcManaged
-				rs.EXPECT().ListTags(gomock.Any()).Return(createdByResponse, nil),
-				rs.EXPECT().NewListTagsParams().Return(&csapi.ListTagsParams{}), // AddClusterTag: AddTags
-				rs.EXPECT().ListTags(gomock.Any()).Return(emptyResponse, nil),
-				rs.EXPECT().NewListTagsParams().Return(&csapi.ListTagsParams{}), // AddCreatedByCAPCTag: AddTags
-				rs.EXPECT().ListTags(gomock.Any()).Return(emptyResponse, nil),
-			)
-			rs.EXPECT().NewCreateTagsParams(gomock.Any(), gomock.Any(), gomock.Any()).
-				Return(&csapi.CreateTagsParams{}).Times(2)
-			rs.EXPECT().CreateTags(gomock.Any()).Return(&csapi.CreateTagsResponse{}, nil).Times(2)
+			// The address still carries an owner tag from a previous association, which is replaced.
+			staleTagsResponse := &csapi.ListTagsResponse{Tags: []*csapi.Tag{{Key: cloud.OwnerClusterTagName, Value: "old-cluster"}}}
+			rs.EXPECT().NewListTagsParams().Return(&csapi.ListTagsParams{})
+			rs.EXPECT().ListTags(gomock.Any()).Return(staleTagsResponse, nil)
+			rs.EXPECT().NewDeleteTagsParams([]string{"PublicIPID"}, string(cloud.ResourceTypeIPAddress)).
+				Return(&csapi.DeleteTagsParams{})
+			rs.EXPECT().DeleteTags(gomock.Any()).Return(&csapi.DeleteTagsResponse{}, nil)
+			rs.EXPECT().NewCreateTagsParams([]string{"PublicIPID"}, string(cloud.ResourceTypeIPAddress), cloud.ResourceTags(dummies.CSCluster, nil)).
+				Return(&csapi.CreateTagsParams{})
+			rs.EXPECT().CreateTags(gomock.Any()).Return(&csapi.CreateTagsResponse{}, nil)
 			gomega.Ω(client.AssociatePublicIPAddress(dummies.CSFailureDomain1, dummies.CSISONet1, dummies.CSCluster)).Should(gomega.Succeed())
 		})
 
@@ -433,6 +486,7 @@ var _ = ginkgo.Describe("Network", func() {
 				Return(&csapi.CreateLoadBalancerRuleParams{})
 			lbs.EXPECT().CreateLoadBalancerRule(gomock.Any()).
 				Return(&csapi.CreateLoadBalancerRuleResponse{Id: "2ndLBRuleID"}, nil)
+			expectTagged(cloud.ResourceTypeLoadBalancer, "2ndLBRuleID")
 
 			gomega.Ω(client.GetOrCreateLoadBalancerRule(dummies.CSISONet1, dummies.CSCluster)).Should(gomega.Succeed())
 			gomega.Ω(dummies.CSISONet1.Status.LBRuleID).Should(gomega.Equal("2ndLBRuleID"))
@@ -463,6 +517,175 @@ var _ = ginkgo.Describe("Network", func() {
 		})
 	})
 
+	ginkgo.Context("API server load balancer configuration", func() {
+		apiServerRule := func(cidrList string) *csapi.LoadBalancerRule {
+			return &csapi.LoadBalancerRule{
+				Publicport: strconv.Itoa(int(dummies.EndPointPort)), Id: dummies.LBRuleID, Algorithm: "roundrobin", Cidrlist: cidrList}
+		}
+		expectListRules := func(rules ...*csapi.LoadBalancerRule) {
+			lbs.EXPECT().NewListLoadBalancerRulesParams().Return(&csapi.ListLoadBalancerRulesParams{})
+			lbs.EXPECT().ListLoadBalancerRules(gomock.Any()).
+				Return(&csapi.ListLoadBalancerRulesResponse{LoadBalancerRules: rules}, nil)
+		}
+
+		ginkgo.BeforeEach(func() {
+			dummies.CSISONet1.Status.LBRuleID = dummies.LBRuleID
+			dummies.CSCluster.Spec.APIServerLoadBalancer = &infrav1.APIServerLoadBalancer{}
+		})
+
+		ginkgo.It("updates the algorithm of the existing rule", func() {
+			dummies.CSCluster.Spec.APIServerLoadBalancer.Algorithm = "leastconn"
+			expectListRules(apiServerRule(""))
+			ulp := &csapi.UpdateLoadBalancerRuleParams{}
+			lbs.EXPECT().NewUpdateLoadBalancerRuleParams(dummies.LBRuleID).Return(ulp)
+			lbs.EXPECT().UpdateLoadBalancerRule(ulp).Return(&csapi.UpdateLoadBalancerRuleResponse{}, nil)
+
+			gomega.Ω(client.GetOrCreateLoadBalancerRule(dummies.CSISONet1, dummies.CSCluster)).Should(gomega.Succeed())
+			algorithm, _ := ulp.GetAlgorithm()
+			gomega.Ω(algorithm).Should(gomega.Equal("leastconn"))
+			gomega.Ω(dummies.CSISONet1.Status.LBRuleID).Should(gomega.Equal(dummies.LBRuleID))
+		})
+
+		ginkgo.It("replaces the rule when its allowed CIDRs change, keeping its instances", func() {
+			dummies.CSCluster.Spec.APIServerLoadBalancer.AllowedCIDRs = []string{"10.0.0.0/8"}
+			dummies.CSISONet1.Status.LBHealthCheckPolicyID = "stale-policy"
+			expectListRules(apiServerRule("0.0.0.0/0"))
+			lbs.EXPECT().NewListLoadBalancerRuleInstancesParams(dummies.LBRuleID).
+				Return(&csapi.ListLoadBalancerRuleInstancesParams{})
+			lbs.EXPECT().ListLoadBalancerRuleInstances(gomock.Any()).Return(&csapi.ListLoadBalancerRuleInstancesResponse{
+				LoadBalancerRuleInstances: []*csapi.VirtualMachine{{Id: "vm-1"}}}, nil)
+			lbs.EXPECT().NewDeleteLoadBalancerRuleParams(dummies.LBRuleID).Return(&csapi.DeleteLoadBalancerRuleParams{})
+			lbs.EXPECT().DeleteLoadBalancerRule(gomock.Any()).Return(&csapi.DeleteLoadBalancerRuleResponse{}, nil)
+			clp := &csapi.CreateLoadBalancerRuleParams{}
+			lbs.EXPECT().NewCreateLoadBalancerRuleParams("roundrobin", cloud.APIServerLBRuleName, cloud.K8sDefaultAPIPort, int(dummies.EndPointPort)).
+				Return(clp)
+			lbs.EXPECT().CreateLoadBalancerRule(clp).Return(&csapi.CreateLoadBalancerRuleResponse{Id: "newLBRuleID"}, nil)
+			expectTagged(cloud.ResourceTypeLoadBalancer, "newLBRuleID")
+			alp := &csapi.AssignToLoadBalancerRuleParams{}
+			lbs.EXPECT().NewAssignToLoadBalancerRuleParams("newLBRuleID").Return(alp)
+			lbs.EXPECT().AssignToLoadBalancerRule(alp).Return(&csapi.AssignToLoadBalancerRuleResponse{}, nil)
+			// The firewall rule opening the port to all sources is replaced by one for the allowed CIDRs.
+			fs.EXPECT().NewListFirewallRulesParams().Return(&csapi.ListFirewallRulesParams{})
+			fs.EXPECT().ListFirewallRules(gomock.Any()).Return(&csapi.ListFirewallRulesResponse{FirewallRules: []*csapi.FirewallRule{{
+				Id: "open-rule", Protocol: "tcp", Startport: int(dummies.EndPointPort), Endport: int(dummies.EndPointPort), Cidrlist: "0.0.0.0/0"}}}, nil)
+			fs.EXPECT().NewDeleteFirewallRuleParams("open-rule").Return(&csapi.DeleteFirewallRuleParams{})
+			fs.EXPECT().DeleteFirewallRule(gomock.Any()).Return(&csapi.DeleteFirewallRuleResponse{}, nil)
+			cfp := &csapi.CreateFirewallRuleParams{}
+			fs.EXPECT().NewCreateFirewallRuleParams(dummies.CSISONet1.Status.PublicIPID, "tcp").Return(cfp)
+			fs.EXPECT().CreateFirewallRule(cfp).Return(&csapi.CreateFirewallRuleResponse{Id: "restricted-rule"}, nil)
+			expectTagged(cloud.ResourceTypeFirewallRule, "restricted-rule")
+
+			gomega.Ω(client.GetOrCreateLoadBalancerRule(dummies.CSISONet1, dummies.CSCluster)).Should(gomega.Succeed())
+			openFirewall, _ := clp.GetOpenfirewall()
+			gomega.Ω(openFirewall).Should(gomega.BeFalse())
+			firewallCIDRs, _ := cfp.GetCidrlist()
+			gomega.Ω(firewallCIDRs).Should(gomega.Equal([]string{"10.0.0.0/8"}))
+			cidrList, _ := clp.GetCidrlist()
+			gomega.Ω(cidrList).Should(gomega.Equal([]string{"10.0.0.0/8"}))
+			vmIDs, _ := alp.GetVirtualmachineids()
+			gomega.Ω(vmIDs).Should(gomega.Equal([]string{"vm-1"}))
+			gomega.Ω(dummies.CSISONet1.Status.LBRuleID).Should(gomega.Equal("newLBRuleID"))
+			gomega.Ω(dummies.CSISONet1.Status.LBHealthCheckPolicyID).Should(gomega.BeEmpty())
+		})
+
+		ginkgo.It("creates a health check policy on the rule", func() {
+			dummies.CSCluster.Spec.APIServerLoadBalancer.HealthCheck = &infrav1.LoadBalancerHealthCheck{
+				PingPath: "/healthz", IntervalSeconds: 10}
+			expectListRules(apiServerRule(""))
+			hcp := &csapi.CreateLBHealthCheckPolicyParams{}
+			lbs.EXPECT().NewCreateLBHealthCheckPolicyParams(dummies.LBRuleID).Return(hcp)
+			lbs.EXPECT().CreateLBHealthCheckPolicy(hcp).Return(&csapi.CreateLBHealthCheckPolicyResponse{
+				Healthcheckpolicy: []csapi.CreateLBHealthCheckPolicyResponseHealthcheckpolicy{{Id: "policy-1"}}}, nil)
+
+			gomega.Ω(client.GetOrCreateLoadBalancerRule(dummies.CSISONet1, dummies.CSCluster)).Should(gomega.Succeed())
+			pingPath, _ := hcp.GetPingpath()
+			gomega.Ω(pingPath).Should(gomega.Equal("/healthz"))
+			interval, _ := hcp.GetIntervaltime()
+			gomega.Ω(interval).Should(gomega.Equal(10))
+			gomega.Ω(dummies.CSISONet1.Status.LBHealthCheckPolicyID).Should(gomega.Equal("policy-1"))
+		})
+
+		ginkgo.It("deletes the stickiness policy once it's removed from the spec", func() {
+			dummies.CSISONet1.Status.LBStickinessPolicyID = "policy-1"
+			expectListRules(apiServerRule(""))
+			lbs.EXPECT().NewDeleteLBStickinessPolicyParams("policy-1").Return(&csapi.DeleteLBStickinessPolicyParams{})
+			lbs.EXPECT().DeleteLBStickinessPolicy(gomock.Any()).Return(&csapi.DeleteLBStickinessPolicyResponse{}, nil)
+
+			gomega.Ω(client.GetOrCreateLoadBalancerRule(dummies.CSISONet1, dummies.CSCluster)).Should(gomega.Succeed())
+			gomega.Ω(dummies.CSISONet1.Status.LBStickinessPolicyID).Should(gomega.BeEmpty())
+		})
+
+		ginkgo.It("creates rules for additional ports and deletes the rules of removed ports", func() {
+			dummies.CSCluster.Spec.APIServerLoadBalancer.AdditionalPorts = []infrav1.LoadBalancerPort{
+				{Name: "konnectivity", Port: 8132}}
+			dummies.CSISONet1.Status.AdditionalLBRuleIDs = map[string]string{"removed": "removedLBRuleID"}
+			expectListRules(apiServerRule(""), &csapi.LoadBalancerRule{Publicport: "9000", Id: "removedLBRuleID"})
+			lbs.EXPECT().NewDeleteLoadBalancerRuleParams("removedLBRuleID").Return(&csapi.DeleteLoadBalancerRuleParams{})
+			lbs.EXPECT().DeleteLoadBalancerRule(gomock.Any()).Return(&csapi.DeleteLoadBalancerRuleResponse{}, nil)
+			fs.EXPECT().NewListFirewallRulesParams().Return(&csapi.ListFirewallRulesParams{})
+			fs.EXPECT().ListFirewallRules(gomock.Any()).Return(&csapi.ListFirewallRulesResponse{}, nil)
+			clp := &csapi.CreateLoadBalancerRuleParams{}
+			lbs.EXPECT().NewCreateLoadBalancerRuleParams("roundrobin", cloud.APIServerLBRuleName+"_konnectivity", 8132, 8132).
+				Return(clp)
+			lbs.EXPECT().CreateLoadBalancerRule(clp).Return(&csapi.CreateLoadBalancerRuleResponse{Id: "konnectivityLBRuleID"}, nil)
+			expectTagged(cloud.ResourceTypeLoadBalancer, "konnectivityLBRuleID")
+			lbs.EXPECT().NewListLoadBalancerRuleInstancesParams(dummies.LBRuleID).
+				Return(&csapi.ListLoadBalancerRuleInstancesParams{})
+			lbs.EXPECT().ListLoadBalancerRuleInstances(gomock.Any()).Return(&csapi.ListLoadBalancerRuleInstancesResponse{}, nil)
+
+			gomega.Ω(client.GetOrCreateLoadBalancerRule(dummies.CSISONet1, dummies.CSCluster)).Should(gomega.Succeed())
+			gomega.Ω(dummies.CSISONet1.Status.AdditionalLBRuleIDs).Should(
+				gomega.Equal(map[string]string{"konnectivity": "konnectivityLBRuleID"}))
+		})
+
+		ginkgo.It("restricts access to the API server of VPC tiers with network ACL items", func() {
+			dummies.CSISONet1.Spec.VPC = &infrav1.VPC{ID: "vpc-1"}
+			dummies.CSCluster.Spec.APIServerLoadBalancer.AllowedCIDRs = []string{"10.0.0.0/8"}
+			expectListRules(apiServerRule("10.0.0.0/8"))
+			ns.EXPECT().GetNetworkByID(dummies.CSISONet1.Spec.ID, gomock.Any()).
+				Return(&csapi.Network{Aclid: "acl-1", Aclname: "cluster-acl"}, 1, nil)
+			acls.EXPECT().NewListNetworkACLsParams().Return(&csapi.ListNetworkACLsParams{})
+			acls.EXPECT().ListNetworkACLs(gomock.Any()).Return(&csapi.ListNetworkACLsResponse{}, nil)
+			var actions []string
+			acls.EXPECT().NewCreateNetworkACLParams("tcp").Return(&csapi.CreateNetworkACLParams{}).Times(2)
+			acls.EXPECT().CreateNetworkACL(gomock.Any()).DoAndReturn(func(p *csapi.CreateNetworkACLParams) (*csapi.CreateNetworkACLResponse, error) {
+				action, _ := p.GetAction()
+				port, _ := p.GetStartport()
+				gomega.Ω(port).Should(gomega.Equal(cloud.K8sDefaultAPIPort))
+				actions = append(actions, action)
+				return &csapi.CreateNetworkACLResponse{}, nil
+			}).Times(2)
+
+			gomega.Ω(client.GetOrCreateLoadBalancerRule(dummies.CSISONet1, dummies.CSCluster)).Should(gomega.Succeed())
+			gomega.Ω(actions).Should(gomega.Equal([]string{"Allow", "Deny"}))
+		})
+
+		ginkgo.It("refuses to restrict access to the API server of VPC tiers using a default ACL list", func() {
+			dummies.CSISONet1.Spec.VPC = &infrav1.VPC{ID: "vpc-1"}
+			dummies.CSCluster.Spec.APIServerLoadBalancer.AllowedCIDRs = []string{"10.0.0.0/8"}
+			expectListRules(apiServerRule("10.0.0.0/8"))
+			ns.EXPECT().GetNetworkByID(dummies.CSISONet1.Spec.ID, gomock.Any()).
+				Return(&csapi.Network{Aclid: "acl-default", Aclname: "default_allow"}, 1, nil)
+			acls.EXPECT().NewListNetworkACLsParams().Return(&csapi.ListNetworkACLsParams{})
+			acls.EXPECT().ListNetworkACLs(gomock.Any()).Return(&csapi.ListNetworkACLsResponse{}, nil)
+
+			gomega.Ω(client.GetOrCreateLoadBalancerRule(dummies.CSISONet1, dummies.CSCluster)).Should(
+				gomega.MatchError(gomega.ContainSubstring("requires a custom network ACL list")))
+		})
+
+		ginkgo.It("assigns VMs to the rules of additional ports", func() {
+			dummies.CSISONet1.Status.AdditionalLBRuleIDs = map[string]string{"konnectivity": "konnectivityLBRuleID"}
+			for _, ruleID := range []string{dummies.LBRuleID, "konnectivityLBRuleID"} {
+				lbs.EXPECT().NewListLoadBalancerRuleInstancesParams(ruleID).Return(&csapi.ListLoadBalancerRuleInstancesParams{})
+				lbs.EXPECT().NewAssignToLoadBalancerRuleParams(ruleID).Return(&csapi.AssignToLoadBalancerRuleParams{})
+			}
+			lbs.EXPECT().ListLoadBalancerRuleInstances(gomock.Any()).Return(&csapi.ListLoadBalancerRuleInstancesResponse{}, nil).Times(2)
+			lbs.EXPECT().AssignToLoadBalancerRule(gomock.Any()).Return(&csapi.AssignToLoadBalancerRuleResponse{}, nil).Times(2)
+
+			gomega.Ω(client.AssignVMToLoadBalancerRule(dummies.CSISONet1, *dummies.CSMachine1.Spec.InstanceID)).Should(gomega.Succeed())
+		})
+	})
+
 	ginkgo.Context("Delete Network", func() {
 		ginkgo.It("Calls CloudStack to delete network", func() {
 			dnp := &csapi.DeleteNetworkParams{}
@@ -582,4 +805,73 @@ var _ = ginkgo.Describe("Network", func() {
 			gomega.Ω(client.GetOrCreateIsolatedNetwork(dummies.CSFailureDomain1, dummies.CSISONet1, dummies.CSCluster)).Should(gomega.Succeed())
 		})
 	})
+
+	ginkgo.Context("against a fake CloudStack", func() {
+		var fake *fakeCloud
+
+		ginkgo.BeforeEach(func() {
+			fake = newFakeCloud()
+		})
+
+		ginkgo.It("runs an isolated network and VM instance through their lifecycle", func() {
+			gomega.Ω(fake.client.ResolveZone(&fake.fd.Spec.Zone)).Should(gomega.Succeed())
+			gomega.Ω(fake.fd.Spec.Zone.ID).ShouldNot(gomega.BeEmpty())
+
+			gomega.Ω(fake.client.GetOrCreateIsolatedNetwork(fake.fd, fake.isoNet, fake.csCluster)).Should(gomega.Succeed())
+			gomega.Ω(fake.isoNet.Spec.ID).ShouldNot(gomega.BeEmpty())
+			gomega.Ω(fake.isoNet.Status.PublicIPID).ShouldNot(gomega.BeEmpty())
+			gomega.Ω(fake.isoNet.Status.LBRuleID).ShouldNot(gomega.BeEmpty())
+			gomega.Ω(fake.isoNet.Status.FirewallRulesOpened).Should(gomega.BeTrue())
+			gomega.Ω(fake.csCluster.Spec.ControlPlaneEndpoint.Host).Should(gomega.HavePrefix(fakecloudstack.PublicIPRange))
+			gomega.Ω(fake.server.List(fakecloudstack.KindEgressFirewallRule)).Should(gomega.HaveLen(3))
+
+			// Reconciling again finds everything in place.
+			gomega.Ω(fake.client.GetOrCreateIsolatedNetwork(fake.fd, fake.isoNet, fake.csCluster)).Should(gomega.Succeed())
+			gomega.Ω(fake.server.Calls("createNetwork")).Should(gomega.Equal(1))
+			gomega.Ω(fake.server.Calls("createLoadBalancerRule")).Should(gomega.Equal(1))
+
+			fake.fd.Spec.Zone.Network.ID = fake.isoNet.Spec.ID
+			gomega.Ω(fake.getOrCreateVMInstance("#cloud-config")).Should(gomega.Succeed())
+			gomega.Ω(fake.csMachine.Spec.InstanceID).ShouldNot(gomega.BeNil())
+			gomega.Ω(*fake.csMachine.Spec.ProviderID).Should(gomega.Equal("cloudstack:///" + *fake.csMachine.Spec.InstanceID))
+			gomega.Ω(fake.csMachine.Status.InstanceState).Should(gomega.Equal("Running"))
+			gomega.Ω(fake.csMachine.Status.Addresses).Should(gomega.HaveLen(1))
+
+			gomega.Ω(fake.client.AssignVMToLoadBalancerRule(fake.isoNet, *fake.csMachine.Spec.InstanceID)).Should(gomega.Succeed())
+			gomega.Ω(fake.client.AssignVMToLoadBalancerRule(fake.isoNet, *fake.csMachine.Spec.InstanceID)).Should(gomega.Succeed())
+			gomega.Ω(fake.server.Calls("assignToLoadBalancerRule")).Should(gomega.Equal(1))
+
+			// The destroy job is recorded and checked on the next call.
+			gomega.Ω(fake.client.DestroyVMInstance(fake.csMachine)).Should(gomega.MatchError("VM deletion in progress"))
+			gomega.Ω(fake.csMachine.Status.AsyncJob).ShouldNot(gomega.BeNil())
+			gomega.Ω(fake.client.DestroyVMInstance(fake.csMachine)).Should(gomega.Succeed())
+			gomega.Ω(fake.csMachine.Status.AsyncJob).Should(gomega.BeNil())
+			_, found := fake.server.Get(fakecloudstack.KindVirtualMachine, *fake.csMachine.Spec.InstanceID)
+			gomega.Ω(found).Should(gomega.BeFalse())
+
+			gomega.Ω(fake.client.DisposeIsoNetResources(fake.isoNet, fake.csCluster)).Should(gomega.Succeed())
+			_, found = fake.server.Get(fakecloudstack.KindNetwork, fake.isoNet.Spec.ID)
+			gomega.Ω(found).Should(gomega.BeFalse())
+			ip, _ := fake.server.Get(fakecloudstack.KindPublicIPAddress, fake.isoNet.Status.PublicIPID)
+			gomega.Ω(ip["state"]).Should(gomega.Equal("Free"))
+		})
+
+		ginkgo.It("runs a dual-stack isolated network and VM instance", func() {
+			gomega.Ω(fake.client.ResolveZone(&fake.fd.Spec.Zone)).Should(gomega.Succeed())
+			fake.isoNet.Spec.IP6Gateway = "fd00:1::1"
+			fake.isoNet.Spec.IP6CIDR = "fd00:1::/64"
+
+			gomega.Ω(fake.client.GetOrCreateIsolatedNetwork(fake.fd, fake.isoNet, fake.csCluster)).Should(gomega.Succeed())
+			gomega.Ω(fake.isoNet.Status.FirewallRulesOpened).Should(gomega.BeTrue())
+			gomega.Ω(fake.server.List(fakecloudstack.KindIPv6FirewallRule)).Should(gomega.HaveLen(3))
+			gomega.Ω(fake.client.OpenFirewallRules(fake.isoNet, fake.csCluster)).Should(gomega.Succeed())
+			gomega.Ω(fake.server.Calls("createIpv6FirewallRule")).Should(gomega.Equal(3))
+
+			fake.fd.Spec.Zone.Network.ID = fake.isoNet.Spec.ID
+			fake.csMachine.Spec.Networks = []infrav1.NetworkSpec{{ID: fake.isoNet.Spec.ID, IP6: "fd00:1::10"}}
+			gomega.Ω(fake.getOrCreateVMInstance("")).Should(gomega.Succeed())
+			gomega.Ω(fake.csMachine.Status.Addresses).Should(gomega.HaveLen(2))
+			gomega.Ω(fake.csMachine.Status.Addresses[1].Address).Should(gomega.Equal("fd00:1::10"))
+		})
+	})
 })
@@ -0,0 +1,484 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+)
+
+const (
+	APIServerLBRuleName = "Kubernetes_API_Server"
+	DefaultLBAlgorithm  = "roundrobin"
+	allSourcesCIDR      = "0.0.0.0/0"
+)
+
+// loadBalancerRule describes a load balancer rule on the public IP of an isolated network.
+type loadBalancerRule struct {
+	name        string
+	publicPort  int
+	privatePort int
+	// tags are added to the rule when it's created.
+	tags map[string]string
+}
+
+// listLoadBalancerRules lists the load balancer rules of the isolated network's public IP.
+func (c *client) listLoadBalancerRules(isoNet *infrav1.CloudStackIsolatedNetwork) ([]*cloudstack.LoadBalancerRule, error) {
+	p := c.cs.LoadBalancer.NewListLoadBalancerRulesParams()
+	p.SetPublicipid(isoNet.Status.PublicIPID)
+	setIfNotEmpty(c.user.Project.ID, p.SetProjectid)
+	resp, err := c.cs.LoadBalancer.ListLoadBalancerRules(p)
+	if err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return nil, errors.Wrap(err, "listing load balancer rules")
+	}
+	return resp.LoadBalancerRules, nil
+}
+
+// reconcileLoadBalancerRule gets, updates or creates the load balancer rule on the rule's public port and returns its
+// ID, and whether an existing rule was replaced. CloudStack can't change the CIDRs or private port of a rule, so a rule
+// that differs in those is replaced. Instances of the replaced rule are assigned to its replacement. Newly created
+// rules get the instances of the membersOf rule, if set.
+func (c *client) reconcileLoadBalancerRule(
+	isoNet *infrav1.CloudStackIsolatedNetwork,
+	existing []*cloudstack.LoadBalancerRule,
+	rule loadBalancerRule,
+	lb *infrav1.APIServerLoadBalancer,
+	membersOf string,
+	checkPrivatePort bool,
+) (string, bool, error) {
+	for _, r := range existing {
+		if r.Publicport != strconv.Itoa(rule.publicPort) {
+			continue
+		}
+		if cidrListsEqual(r.Cidrlist, lb.AllowedCIDRs) &&
+			(!checkPrivatePort || r.Privateport == strconv.Itoa(rule.privatePort)) {
+			if lb.Algorithm != "" && r.Algorithm != lb.Algorithm {
+				p := c.cs.LoadBalancer.NewUpdateLoadBalancerRuleParams(r.Id)
+				p.SetAlgorithm(lb.Algorithm)
+				if _, err := c.cs.LoadBalancer.UpdateLoadBalancerRule(p); err != nil {
+					c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+					return "", false, errors.Wrapf(err, "updating algorithm of load balancer rule %s", r.Id)
+				}
+			}
+			return r.Id, false, nil
+		}
+
+		instanceIDs, err := c.loadBalancerRuleInstanceIDs(r.Id)
+		if err != nil {
+			return "", false, err
+		}
+		if err := c.deleteLoadBalancerRule(r.Id); err != nil {
+			return "", false, err
+		}
+		id, err := c.createLoadBalancerRule(isoNet, rule, lb)
+		if err != nil {
+			return "", true, err
+		}
+		return id, true, c.assignToLoadBalancerRule(id, instanceIDs)
+	}
+
+	id, err := c.createLoadBalancerRule(isoNet, rule, lb)
+	if err != nil || membersOf == "" {
+		return id, false, err
+	}
+	instanceIDs, err := c.loadBalancerRuleInstanceIDs(membersOf)
+	if err != nil {
+		return "", false, err
+	}
+	return id, false, c.assignToLoadBalancerRule(id, instanceIDs)
+}
+
+// createLoadBalancerRule creates a load balancer rule on the isolated network's public IP and tags it.
+func (c *client) createLoadBalancerRule(
+	isoNet *infrav1.CloudStackIsolatedNetwork,
+	rule loadBalancerRule,
+	lb *infrav1.APIServerLoadBalancer,
+) (string, error) {
+	algorithm := lb.Algorithm
+	if algorithm == "" {
+		algorithm = DefaultLBAlgorithm
+	}
+	p := c.cs.LoadBalancer.NewCreateLoadBalancerRuleParams(algorithm, rule.name, rule.privatePort, rule.publicPort)
+	p.SetPublicport(rule.publicPort)
+	p.SetNetworkid(isoNet.Spec.ID)
+	if len(lb.AllowedCIDRs) > 0 {
+		// CloudStack would otherwise open the port to all sources; access is restricted by reconcileAPIServerAccess.
+		p.SetCidrlist(lb.AllowedCIDRs)
+		p.SetOpenfirewall(false)
+	}
+
+	p.SetPublicipid(isoNet.Status.PublicIPID)
+	p.SetProtocol(NetworkProtocolTCP)
+	resp, err := c.cs.LoadBalancer.CreateLoadBalancerRule(p)
+	if err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return "", err
+	}
+	return resp.Id, c.tagCreatedResource(ResourceTypeLoadBalancer, resp.Id, rule.tags)
+}
+
+func (c *client) deleteLoadBalancerRule(id string) error {
+	_, err := c.cs.LoadBalancer.DeleteLoadBalancerRule(c.cs.LoadBalancer.NewDeleteLoadBalancerRuleParams(id))
+	if err != nil && !strings.Contains(strings.ToLower(err.Error()), "no match found") {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return errors.Wrapf(err, "deleting load balancer rule %s", id)
+	}
+	return nil
+}
+
+// loadBalancerRuleInstanceIDs lists the IDs of the instances assigned to a load balancer rule.
+func (c *client) loadBalancerRuleInstanceIDs(id string) ([]string, error) {
+	resp, err := c.cs.LoadBalancer.ListLoadBalancerRuleInstances(c.cs.LoadBalancer.NewListLoadBalancerRuleInstancesParams(id))
+	if err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return nil, errors.Wrapf(err, "listing instances of load balancer rule %s", id)
+	}
+	ids := make([]string, 0, len(resp.LoadBalancerRuleInstances))
+	for _, instance := range resp.LoadBalancerRuleInstances {
+		ids = append(ids, instance.Id)
+	}
+	return ids, nil
+}
+
+func (c *client) assignToLoadBalancerRule(id string, instanceIDs []string) error {
+	if len(instanceIDs) == 0 {
+		return nil
+	}
+	p := c.cs.LoadBalancer.NewAssignToLoadBalancerRuleParams(id)
+	p.SetVirtualmachineids(instanceIDs)
+	if _, err := c.cs.LoadBalancer.AssignToLoadBalancerRule(p); err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return errors.Wrapf(err, "assigning instances to load balancer rule %s", id)
+	}
+	return nil
+}
+
+// reconcileAdditionalLoadBalancerRules reconciles the rules of the load balancer's additional ports and deletes the
+// rules of ports that were removed. Created rules are tagged with the passed tags. It returns whether any rule was
+// replaced.
+func (c *client) reconcileAdditionalLoadBalancerRules(
+	isoNet *infrav1.CloudStackIsolatedNetwork,
+	existing []*cloudstack.LoadBalancerRule,
+	lb *infrav1.APIServerLoadBalancer,
+	tags map[string]string,
+) (bool, error) {
+	// Delete the rules of removed ports first. Rules on ports still in use are reconciled below.
+	desiredPorts := map[string]bool{}
+	for _, port := range lb.AdditionalPorts {
+		desiredPorts[strconv.Itoa(int(port.Port))] = true
+	}
+	for _, r := range existing {
+		if r.Id == isoNet.Status.LBRuleID || desiredPorts[r.Publicport] {
+			continue
+		}
+		for _, id := range isoNet.Status.AdditionalLBRuleIDs {
+			if id != r.Id {
+				continue
+			}
+			if err := c.deleteLoadBalancerRule(id); err != nil {
+				return false, err
+			}
+			if isoNet.Spec.VPC == nil || isoNet.Spec.VPC.ID == "" {
+				port, _ := strconv.Atoi(r.Publicport)
+				if err := c.deletePortFirewallRules(isoNet, port); err != nil {
+					return false, err
+				}
+			}
+		}
+	}
+
+	ruleIDs := map[string]string{}
+	anyReplaced := false
+	for _, port := range lb.AdditionalPorts {
+		targetPort := port.TargetPort
+		if targetPort == 0 {
+			targetPort = port.Port
+		}
+		rule := loadBalancerRule{
+			name:        APIServerLBRuleName + "_" + port.Name,
+			publicPort:  int(port.Port),
+			privatePort: int(targetPort),
+			tags:        tags,
+		}
+		id, replaced, err := c.reconcileLoadBalancerRule(isoNet, existing, rule, lb, isoNet.Status.LBRuleID, true)
+		if err != nil {
+			return false, errors.Wrapf(err, "reconciling load balancer rule for port %s", port.Name)
+		}
+		ruleIDs[port.Name] = id
+		anyReplaced = anyReplaced || replaced
+	}
+
+	isoNet.Status.AdditionalLBRuleIDs = nil
+	if len(ruleIDs) > 0 {
+		isoNet.Status.AdditionalLBRuleIDs = ruleIDs
+	}
+	return anyReplaced, nil
+}
+
+// reconcileLBHealthCheckPolicy creates, replaces or deletes the health check policy of the API server rule.
+func (c *client) reconcileLBHealthCheckPolicy(
+	isoNet *infrav1.CloudStackIsolatedNetwork,
+	healthCheck *infrav1.LoadBalancerHealthCheck,
+) error {
+	if isoNet.Status.LBHealthCheckPolicyID != "" {
+		if healthCheck != nil {
+			p := c.cs.LoadBalancer.NewListLBHealthCheckPoliciesParams()
+			p.SetLbruleid(isoNet.Status.LBRuleID)
+			resp, err := c.cs.LoadBalancer.ListLBHealthCheckPolicies(p)
+			if err != nil {
+				c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+				return errors.Wrap(err, "listing load balancer health check policies")
+			}
+			for _, policies := range resp.LBHealthCheckPolicies {
+				for _, policy := range policies.Healthcheckpolicy {
+					if policy.Id == isoNet.Status.LBHealthCheckPolicyID && healthCheckMatches(policy, healthCheck) {
+						return nil
+					}
+				}
+			}
+		}
+		_, err := c.cs.LoadBalancer.DeleteLBHealthCheckPolicy(
+			c.cs.LoadBalancer.NewDeleteLBHealthCheckPolicyParams(isoNet.Status.LBHealthCheckPolicyID))
+		if err != nil && !strings.Contains(strings.ToLower(err.Error()), "no match found") {
+			c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+			return errors.Wrapf(err, "deleting load balancer health check policy %s", isoNet.Status.LBHealthCheckPolicyID)
+		}
+		isoNet.Status.LBHealthCheckPolicyID = ""
+	}
+	if healthCheck == nil {
+		return nil
+	}
+
+	p := c.cs.LoadBalancer.NewCreateLBHealthCheckPolicyParams(isoNet.Status.LBRuleID)
+	setIfNotEmpty(healthCheck.PingPath, p.SetPingpath)
+	if healthCheck.IntervalSeconds > 0 {
+		p.SetIntervaltime(healthCheck.IntervalSeconds)
+	}
+	if healthCheck.TimeoutSeconds > 0 {
+		p.SetResponsetimeout(healthCheck.TimeoutSeconds)
+	}
+	if healthCheck.HealthyThreshold > 0 {
+		p.SetHealthythreshold(healthCheck.HealthyThreshold)
+	}
+	if healthCheck.UnhealthyThreshold > 0 {
+		p.SetUnhealthythreshold(healthCheck.UnhealthyThreshold)
+	}
+	// Wait for the job, as only its result holds the policy's ID.
+	resp, err := c.csAsync.LoadBalancer.CreateLBHealthCheckPolicy(p)
+	if err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return errors.Wrap(err, "creating load balancer health check policy")
+	}
+	if len(resp.Healthcheckpolicy) > 0 {
+		isoNet.Status.LBHealthCheckPolicyID = resp.Healthcheckpolicy[0].Id
+	}
+	return nil
+}
+
+// healthCheckMatches checks the values set in the health check against the policy. Unset values are CloudStack's
+// defaults and aren't compared.
+func healthCheckMatches(policy cloudstack.LBHealthCheckPolicyHealthcheckpolicy, healthCheck *infrav1.LoadBalancerHealthCheck) bool {
+	return (healthCheck.PingPath == "" || policy.Pingpath == healthCheck.PingPath) &&
+		(healthCheck.IntervalSeconds == 0 || policy.Healthcheckinterval == healthCheck.IntervalSeconds) &&
+		(healthCheck.TimeoutSeconds == 0 || policy.Responsetime == healthCheck.TimeoutSeconds) &&
+		(healthCheck.HealthyThreshold == 0 || policy.Healthcheckthresshold == healthCheck.HealthyThreshold) &&
+		(healthCheck.UnhealthyThreshold == 0 || policy.Unhealthcheckthresshold == healthCheck.UnhealthyThreshold)
+}
+
+// reconcileLBStickinessPolicy creates, replaces or deletes the stickiness policy of the API server rule.
+func (c *client) reconcileLBStickinessPolicy(
+	isoNet *infrav1.CloudStackIsolatedNetwork,
+	stickiness *infrav1.LoadBalancerStickiness,
+) error {
+	if isoNet.Status.LBStickinessPolicyID != "" {
+		if stickiness != nil {
+			p := c.cs.LoadBalancer.NewListLBStickinessPoliciesParams()
+			p.SetLbruleid(isoNet.Status.LBRuleID)
+			resp, err := c.cs.LoadBalancer.ListLBStickinessPolicies(p)
+			if err != nil {
+				c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+				return errors.Wrap(err, "listing load balancer stickiness policies")
+			}
+			for _, policies := range resp.LBStickinessPolicies {
+				for _, policy := range policies.Stickinesspolicy {
+					if policy.Id == isoNet.Status.LBStickinessPolicyID && policy.Methodname == stickiness.Method &&
+						(len(policy.Params) == 0 && len(stickiness.Parameters) == 0 ||
+							reflect.DeepEqual(policy.Params, stickiness.Parameters)) {
+						return nil
+					}
+				}
+			}
+		}
+		_, err := c.cs.LoadBalancer.DeleteLBStickinessPolicy(
+			c.cs.LoadBalancer.NewDeleteLBStickinessPolicyParams(isoNet.Status.LBStickinessPolicyID))
+		if err != nil && !strings.Contains(strings.ToLower(err.Error()), "no match found") {
+			c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+			return errors.Wrapf(err, "deleting load balancer stickiness policy %s", isoNet.Status.LBStickinessPolicyID)
+		}
+		isoNet.Status.LBStickinessPolicyID = ""
+	}
+	if stickiness == nil {
+		return nil
+	}
+
+	p := c.cs.LoadBalancer.NewCreateLBStickinessPolicyParams(isoNet.Status.LBRuleID, stickiness.Method, APIServerLBRuleName+"_Stickiness")
+	if len(stickiness.Parameters) > 0 {
+		p.SetParam(stickiness.Parameters)
+	}
+	resp, err := c.csAsync.LoadBalancer.CreateLBStickinessPolicy(p)
+	if err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return errors.Wrap(err, "creating load balancer stickiness policy")
+	}
+	if len(resp.Stickinesspolicy) > 0 {
+		isoNet.Status.LBStickinessPolicyID = resp.Stickinesspolicy[0].Id
+	}
+	return nil
+}
+
+// cidrListsEqual compares a rule's comma separated CIDR list with the allowed CIDRs. An empty list and 0.0.0.0/0 both
+// allow all sources.
+func cidrListsEqual(cidrList string, allowed []string) bool {
+	normalize := func(cidrs []string) []string {
+		out := []string{}
+		for _, cidr := range cidrs {
+			if cidr = strings.TrimSpace(cidr); cidr != "" {
+				out = append(out, cidr)
+			}
+		}
+		if len(out) == 1 && out[0] == allSourcesCIDR {
+			return []string{}
+		}
+		sort.Strings(out)
+		return out
+	}
+	return reflect.DeepEqual(normalize(strings.Split(cidrList, ",")), normalize(allowed))
+}
+
+// apiServerPorts returns the public and private ports of the load balancer rules.
+func apiServerPorts(isoNet *infrav1.CloudStackIsolatedNetwork, lb *infrav1.APIServerLoadBalancer) (publicPorts, privatePorts []int) {
+	publicPorts = []int{int(isoNet.Spec.ControlPlaneEndpoint.Port)}
+	privatePorts = []int{K8sDefaultAPIPort}
+	for _, port := range lb.AdditionalPorts {
+		publicPorts = append(publicPorts, int(port.Port))
+		if port.TargetPort != 0 {
+			privatePorts = append(privatePorts, int(port.TargetPort))
+		} else {
+			privatePorts = append(privatePorts, int(port.Port))
+		}
+	}
+	return publicPorts, privatePorts
+}
+
+// reconcileAPIServerAccess restricts the sources that can reach the load balancer rules to the allowed CIDRs. Isolated
+// networks use ingress firewall rules on the public IP, VPC tiers network ACL items on the tier's ACL list.
+func (c *client) reconcileAPIServerAccess(
+	isoNet *infrav1.CloudStackIsolatedNetwork,
+	csCluster *infrav1.CloudStackCluster,
+	lb *infrav1.APIServerLoadBalancer,
+) error {
+	publicPorts, privatePorts := apiServerPorts(isoNet, lb)
+	if isoNet.Spec.VPC != nil && isoNet.Spec.VPC.ID != "" {
+		return c.reconcileAPIServerACLItems(isoNet, csCluster, lb, privatePorts)
+	}
+
+	rules, err := c.listPublicIPFirewallRules(isoNet)
+	if err != nil {
+		return err
+	}
+	cidrs := lb.AllowedCIDRs
+	if len(cidrs) == 0 {
+		cidrs = []string{allSourcesCIDR}
+	}
+	for _, port := range publicPorts {
+		found := false
+		for _, rule := range rules {
+			if rule.Protocol != NetworkProtocolTCP || rule.Startport != port || rule.Endport != port {
+				continue
+			}
+			if cidrListsEqual(rule.Cidrlist, lb.AllowedCIDRs) {
+				found = true
+				continue
+			}
+			if err := c.deleteFirewallRule(rule.Id); err != nil {
+				return err
+			}
+		}
+		if found {
+			continue
+		}
+
+		p := c.cs.Firewall.NewCreateFirewallRuleParams(isoNet.Status.PublicIPID, NetworkProtocolTCP)
+		p.SetStartport(port)
+		p.SetEndport(port)
+		p.SetCidrlist(cidrs)
+		resp, err := c.cs.Firewall.CreateFirewallRule(p)
+		if err != nil {
+			c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+			return errors.Wrapf(err, "creating firewall rule for port %d of public IP %s", port, isoNet.Status.PublicIPID)
+		}
+		if err := c.tagCreatedResource(ResourceTypeFirewallRule, resp.Id, ResourceTags(csCluster, nil)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// listPublicIPFirewallRules lists the ingress firewall rules of the isolated network's public IP.
+func (c *client) listPublicIPFirewallRules(isoNet *infrav1.CloudStackIsolatedNetwork) ([]*cloudstack.FirewallRule, error) {
+	p := c.cs.Firewall.NewListFirewallRulesParams()
+	p.SetIpaddressid(isoNet.Status.PublicIPID)
+	setIfNotEmpty(c.user.Project.ID, p.SetProjectid)
+	resp, err := c.cs.Firewall.ListFirewallRules(p)
+	if err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return nil, errors.Wrapf(err, "listing firewall rules of public IP %s", isoNet.Status.PublicIPID)
+	}
+	return resp.FirewallRules, nil
+}
+
+// deletePortFirewallRules deletes the TCP ingress firewall rules of a single port of the isolated network's public IP.
+func (c *client) deletePortFirewallRules(isoNet *infrav1.CloudStackIsolatedNetwork, port int) error {
+	rules, err := c.listPublicIPFirewallRules(isoNet)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		if rule.Protocol == NetworkProtocolTCP && rule.Startport == port && rule.Endport == port {
+			if err := c.deleteFirewallRule(rule.Id); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func (c *client) deleteFirewallRule(id string) error {
+	_, err := c.cs.Firewall.DeleteFirewallRule(c.cs.Firewall.NewDeleteFirewallRuleParams(id))
+	if err != nil && !strings.Contains(strings.ToLower(err.Error()), "no match found") {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return errors.Wrapf(err, "deleting firewall rule %s", id)
+	}
+	return nil
+}
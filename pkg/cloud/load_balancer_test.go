@@ -0,0 +1,101 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud_test
+
+import (
+	"github.com/onsi/ginkgo/v2"
+	gomega "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/test/fakecloudstack"
+)
+
+var _ = ginkgo.Describe("Load Balancer", func() {
+	var fake *fakeCloud
+
+	ginkgo.BeforeEach(func() {
+		fake = newFakeCloud()
+	})
+
+	ginkgo.It("applies load balancer configuration changes to the existing rules", func() {
+		fake.useIsolatedNetwork()
+		gomega.Ω(fake.getOrCreateVMInstance("")).Should(gomega.Succeed())
+		gomega.Ω(fake.client.AssignVMToLoadBalancerRule(fake.isoNet, *fake.csMachine.Spec.InstanceID)).Should(gomega.Succeed())
+
+		fake.csCluster.Spec.APIServerLoadBalancer = &infrav1.APIServerLoadBalancer{
+			Algorithm:       "leastconn",
+			HealthCheck:     &infrav1.LoadBalancerHealthCheck{PingPath: "/healthz"},
+			Stickiness:      &infrav1.LoadBalancerStickiness{Method: "SourceBased"},
+			AdditionalPorts: []infrav1.LoadBalancerPort{{Name: "konnectivity", Port: 8132}},
+			AllowedCIDRs:    []string{"10.0.0.0/8"},
+		}
+		gomega.Ω(fake.client.GetOrCreateIsolatedNetwork(fake.fd, fake.isoNet, fake.csCluster)).Should(gomega.Succeed())
+		rule, found := fake.server.Get(fakecloudstack.KindLoadBalancerRule, fake.isoNet.Status.LBRuleID)
+		gomega.Ω(found).Should(gomega.BeTrue())
+		gomega.Ω(rule["algorithm"]).Should(gomega.Equal("leastconn"))
+		gomega.Ω(rule["cidrlist"]).Should(gomega.Equal("10.0.0.0/8"))
+		gomega.Ω(fake.server.List(fakecloudstack.KindLBHealthCheckPolicy)).Should(gomega.HaveLen(1))
+		gomega.Ω(fake.server.List(fakecloudstack.KindLBStickinessPolicy)).Should(gomega.HaveLen(1))
+		gomega.Ω(fake.isoNet.Status.AdditionalLBRuleIDs).Should(gomega.HaveKey("konnectivity"))
+		gomega.Ω(fake.server.List(fakecloudstack.KindLoadBalancerRule)).Should(gomega.HaveLen(2))
+
+		// The replaced API server rule and the new rule both balance the existing VM.
+		gomega.Ω(fake.client.AssignVMToLoadBalancerRule(fake.isoNet, *fake.csMachine.Spec.InstanceID)).Should(gomega.Succeed())
+		gomega.Ω(fake.server.Calls("assignToLoadBalancerRule")).Should(gomega.Equal(3))
+
+		// Reconciling again leaves everything in place.
+		gomega.Ω(fake.client.GetOrCreateIsolatedNetwork(fake.fd, fake.isoNet, fake.csCluster)).Should(gomega.Succeed())
+		gomega.Ω(fake.server.Calls("createLoadBalancerRule")).Should(gomega.Equal(3))
+		gomega.Ω(fake.server.Calls("createLBHealthCheckPolicy")).Should(gomega.Equal(1))
+		gomega.Ω(fake.server.Calls("createLBStickinessPolicy")).Should(gomega.Equal(1))
+
+		fake.csCluster.Spec.APIServerLoadBalancer = nil
+		gomega.Ω(fake.client.GetOrCreateIsolatedNetwork(fake.fd, fake.isoNet, fake.csCluster)).Should(gomega.Succeed())
+		gomega.Ω(fake.server.List(fakecloudstack.KindLBHealthCheckPolicy)).Should(gomega.BeEmpty())
+		gomega.Ω(fake.server.List(fakecloudstack.KindLBStickinessPolicy)).Should(gomega.BeEmpty())
+		gomega.Ω(fake.server.List(fakecloudstack.KindLoadBalancerRule)).Should(gomega.HaveLen(1))
+		gomega.Ω(fake.isoNet.Status.AdditionalLBRuleIDs).Should(gomega.BeNil())
+	})
+
+	ginkgo.It("restricts the public endpoint to the allowed CIDRs", func() {
+		gomega.Ω(fake.client.ResolveZone(&fake.fd.Spec.Zone)).Should(gomega.Succeed())
+		gomega.Ω(fake.client.GetOrCreateIsolatedNetwork(fake.fd, fake.isoNet, fake.csCluster)).Should(gomega.Succeed())
+		gomega.Ω(fake.server.List(fakecloudstack.KindFirewallRule)).Should(gomega.ConsistOf(
+			gomega.HaveKeyWithValue("cidrlist", "0.0.0.0/0")))
+
+		fake.csCluster.Spec.APIServerLoadBalancer = &infrav1.APIServerLoadBalancer{
+			AdditionalPorts: []infrav1.LoadBalancerPort{{Name: "konnectivity", Port: 8132}},
+			AllowedCIDRs:    []string{"10.0.0.0/8", "192.168.0.0/16"},
+		}
+		gomega.Ω(fake.client.GetOrCreateIsolatedNetwork(fake.fd, fake.isoNet, fake.csCluster)).Should(gomega.Succeed())
+		gomega.Ω(fake.server.List(fakecloudstack.KindFirewallRule)).Should(gomega.ConsistOf(
+			gomega.And(gomega.HaveKeyWithValue("startport", int(fake.csCluster.Spec.ControlPlaneEndpoint.Port)),
+				gomega.HaveKeyWithValue("cidrlist", "10.0.0.0/8,192.168.0.0/16")),
+			gomega.And(gomega.HaveKeyWithValue("startport", 8132),
+				gomega.HaveKeyWithValue("cidrlist", "10.0.0.0/8,192.168.0.0/16")),
+		))
+
+		// Reconciling again leaves the rules in place.
+		gomega.Ω(fake.client.GetOrCreateIsolatedNetwork(fake.fd, fake.isoNet, fake.csCluster)).Should(gomega.Succeed())
+		gomega.Ω(fake.server.Calls("createFirewallRule")).Should(gomega.Equal(2))
+
+		fake.csCluster.Spec.APIServerLoadBalancer = nil
+		gomega.Ω(fake.client.GetOrCreateIsolatedNetwork(fake.fd, fake.isoNet, fake.csCluster)).Should(gomega.Succeed())
+		gomega.Ω(fake.server.List(fakecloudstack.KindFirewallRule)).Should(gomega.ConsistOf(
+			gomega.HaveKeyWithValue("cidrlist", "0.0.0.0/0")))
+	})
+})
@@ -65,6 +65,8 @@ func (c *client) ResolveNetwork(net *infrav1.Network) (retErr error) {
 		net.Type = netDetails.Type
 		net.Gateway = netDetails.Gateway
 		net.Netmask = netDetails.Netmask
+		net.IP6Gateway = netDetails.Ip6gateway
+		net.IP6CIDR = netDetails.Ip6cidr
 		net.Offering = netDetails.Networkofferingname
 		net.RoutingMode = netDetails.Ip4routing
 		if netDetails.Vpcid != "" {
@@ -90,6 +92,8 @@ func (c *client) ResolveNetwork(net *infrav1.Network) (retErr error) {
 	net.Type = netDetails.Type
 	net.Gateway = netDetails.Gateway
 	net.Netmask = netDetails.Netmask
+	net.IP6Gateway = netDetails.Ip6gateway
+	net.IP6CIDR = netDetails.Ip6cidr
 	net.Offering = netDetails.Networkofferingname
 	net.RoutingMode = netDetails.Ip4routing
 	if netDetails.Vpcid != "" {
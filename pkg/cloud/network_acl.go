@@ -0,0 +1,337 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+)
+
+const (
+	NetworkACLActionAllow      = "Allow"
+	NetworkACLActionDeny       = "Deny"
+	NetworkACLTrafficIngress   = "Ingress"
+	DefaultAllowNetworkACLList = "default_allow"
+	DefaultDenyNetworkACLList  = "default_deny"
+
+	ResourceTypeNetworkACLList ResourceType = "NetworkACLList"
+)
+
+// networkACLItem is the part of a network ACL item CAPC compares to decide whether it is up to date.
+type networkACLItem struct {
+	action string
+	port   int
+	cidrs  string
+}
+
+// networkACLListName returns the name of the network ACL list CAPC creates for a VPC tier.
+func networkACLListName(isoNet *infrav1.CloudStackIsolatedNetwork) string {
+	return isoNet.Spec.Name + "-acl"
+}
+
+// reconcileNetworkACL attaches the network ACL list selected by the network's ACL spec to a VPC tier. If rules are
+// declared, a list with these rules is created for the tier first.
+func (c *client) reconcileNetworkACL(isoNet *infrav1.CloudStackIsolatedNetwork) error {
+	acl := isoNet.Spec.ACL
+	if acl == nil || isoNet.Spec.VPC == nil || isoNet.Spec.VPC.ID == "" {
+		return nil
+	}
+
+	var listID string
+	if len(acl.Rules) > 0 {
+		if err := c.getOrCreateNetworkACLList(isoNet); err != nil {
+			return err
+		}
+		if err := c.reconcileNetworkACLRules(isoNet.Status.NetworkACLListID, acl.Rules); err != nil {
+			return err
+		}
+		listID = isoNet.Status.NetworkACLListID
+	} else {
+		list, err := c.resolveNetworkACLList(acl, isoNet.Spec.VPC.ID)
+		if err != nil {
+			return err
+		}
+		listID = list.Id
+	}
+
+	network, err := c.getNetwork(isoNet.Spec.ID)
+	if err != nil {
+		return err
+	}
+	if network.Aclid == listID {
+		return nil
+	}
+	p := c.cs.NetworkACL.NewReplaceNetworkACLListParams(listID)
+	p.SetNetworkid(isoNet.Spec.ID)
+	if _, err := c.cs.NetworkACL.ReplaceNetworkACLList(p); err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return errors.Wrapf(err, "attaching network ACL list %s to network %s", listID, isoNet.Spec.ID)
+	}
+	return nil
+}
+
+// resolveNetworkACLList finds an existing network ACL list by ID or name. Lists found by name must belong to the VPC,
+// or be one of the default lists that belong to none.
+func (c *client) resolveNetworkACLList(acl *infrav1.NetworkACL, vpcID string) (*cloudstack.NetworkACLList, error) {
+	p := c.cs.NetworkACL.NewListNetworkACLListsParams()
+	setIfNotEmpty(acl.ID, p.SetId)
+	setIfNotEmpty(acl.Name, p.SetName)
+	setIfNotEmpty(c.user.Project.ID, p.SetProjectid)
+	resp, err := c.cs.NetworkACL.ListNetworkACLLists(p)
+	if err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return nil, errors.Wrapf(err, "listing network ACL lists with ID %q and name %q", acl.ID, acl.Name)
+	}
+
+	var found []*cloudstack.NetworkACLList
+	for _, list := range resp.NetworkACLLists {
+		if acl.ID != "" || list.Vpcid == "" || list.Vpcid == vpcID {
+			found = append(found, list)
+		}
+	}
+	if len(found) != 1 {
+		return nil, errors.Errorf("expected 1 network ACL list with ID %q and name %q in VPC %s, but got %d",
+			acl.ID, acl.Name, vpcID, len(found))
+	}
+	return found[0], nil
+}
+
+// getOrCreateNetworkACLList finds or creates the network ACL list of a VPC tier, and records it in the network's status.
+func (c *client) getOrCreateNetworkACLList(isoNet *infrav1.CloudStackIsolatedNetwork) error {
+	if isoNet.Status.NetworkACLListID != "" {
+		return nil
+	}
+	name := networkACLListName(isoNet)
+	list, err := c.resolveNetworkACLList(&infrav1.NetworkACL{Name: name}, isoNet.Spec.VPC.ID)
+	if err == nil && list.Vpcid == isoNet.Spec.VPC.ID { // Created before the status could be updated.
+		isoNet.Status.NetworkACLListID = list.Id
+		return nil
+	}
+
+	p := c.cs.NetworkACL.NewCreateNetworkACLListParams(name, isoNet.Spec.VPC.ID)
+	p.SetDescription("Network ACL list of network " + isoNet.Spec.Name)
+	resp, err := c.csAsync.NetworkACL.CreateNetworkACLList(p)
+	if err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return errors.Wrapf(err, "creating network ACL list %s in VPC %s", name, isoNet.Spec.VPC.ID)
+	}
+	isoNet.Status.NetworkACLListID = resp.Id
+	return c.AddCreatedByCAPCTag(ResourceTypeNetworkACLList, resp.Id)
+}
+
+// reconcileNetworkACLRules creates the items of the declared rules missing from a network ACL list. Each rule's item
+// is numbered by its position, so items created by earlier, interrupted reconciliations are recognized.
+func (c *client) reconcileNetworkACLRules(listID string, rules []infrav1.NetworkACLRule) error {
+	p := c.cs.NetworkACL.NewListNetworkACLsParams()
+	p.SetAclid(listID)
+	setIfNotEmpty(c.user.Project.ID, p.SetProjectid)
+	resp, err := c.cs.NetworkACL.ListNetworkACLs(p)
+	if err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return errors.Wrapf(err, "listing items of network ACL list %s", listID)
+	}
+	numbers := map[int]bool{}
+	for _, item := range resp.NetworkACLs {
+		numbers[item.Number] = true
+	}
+
+	for i, rule := range rules {
+		number := i + 1
+		if numbers[number] {
+			continue
+		}
+		p := c.cs.NetworkACL.NewCreateNetworkACLParams(rule.Protocol)
+		p.SetAclid(listID)
+		p.SetNumber(number)
+		p.SetAction(rule.Action)
+		p.SetTraffictype(NetworkACLTrafficIngress)
+		setIfNotEmpty(rule.TrafficType, p.SetTraffictype)
+		if rule.StartPort != 0 {
+			p.SetStartport(int(rule.StartPort))
+			p.SetEndport(int(rule.StartPort))
+		}
+		if rule.EndPort != 0 {
+			p.SetEndport(int(rule.EndPort))
+		}
+		if rule.Protocol == NetworkProtocolICMP {
+			p.SetIcmptype(-1)
+			p.SetIcmpcode(-1)
+		}
+		p.SetCidrlist([]string{allSourcesCIDR})
+		if len(rule.CIDRList) > 0 {
+			p.SetCidrlist(rule.CIDRList)
+		}
+		if _, err := c.cs.NetworkACL.CreateNetworkACL(p); err != nil {
+			c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+			return errors.Wrapf(err, "creating item %d of network ACL list %s", number, listID)
+		}
+	}
+	return nil
+}
+
+// DeleteNetworkACLListIfNotInUse deletes the network ACL list CAPC created for a VPC tier once no tier of the VPC
+// uses it anymore.
+func (c *client) DeleteNetworkACLListIfNotInUse(isoNet *infrav1.CloudStackIsolatedNetwork) error {
+	listID := isoNet.Status.NetworkACLListID
+	if managedByCAPC, err := c.IsCapcManaged(ResourceTypeNetworkACLList, listID); err != nil {
+		return err
+	} else if !managedByCAPC {
+		return nil
+	}
+
+	p := c.cs.Network.NewListNetworksParams()
+	p.SetVpcid(isoNet.Spec.VPC.ID)
+	setIfNotEmpty(c.user.Project.ID, p.SetProjectid)
+	resp, err := c.cs.Network.ListNetworks(p)
+	if err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return errors.Wrapf(err, "listing networks of VPC %s", isoNet.Spec.VPC.ID)
+	}
+	for _, network := range resp.Networks {
+		if network.Aclid == listID {
+			return nil
+		}
+	}
+
+	if _, err := c.cs.NetworkACL.DeleteNetworkACLList(c.cs.NetworkACL.NewDeleteNetworkACLListParams(listID)); err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return errors.Wrapf(err, "deleting network ACL list %s", listID)
+	}
+	isoNet.Status.NetworkACLListID = ""
+	return nil
+}
+
+// apiServerACLReason marks the network ACL items restricting access to a cluster's API server.
+func apiServerACLReason(csCluster *infrav1.CloudStackCluster) string {
+	return fmt.Sprintf("CAPC API server access of cluster %s", csCluster.UID)
+}
+
+// reconcileAPIServerACLItems restricts access to the private ports of the API server load balancer rules in a VPC tier
+// to the allowed CIDRs. Each port gets an item allowing the allowed CIDRs, followed by one denying all other sources.
+// The items are replaced as a whole when the allowed CIDRs or ports change, to keep the allow items first.
+func (c *client) reconcileAPIServerACLItems(
+	isoNet *infrav1.CloudStackIsolatedNetwork,
+	csCluster *infrav1.CloudStackCluster,
+	lb *infrav1.APIServerLoadBalancer,
+	ports []int,
+) error {
+	network, err := c.getNetwork(isoNet.Spec.ID)
+	if err != nil {
+		return err
+	}
+
+	var desired []networkACLItem
+	if len(lb.AllowedCIDRs) > 0 {
+		allowed := normalizeCIDRs(lb.AllowedCIDRs)
+		for _, port := range ports {
+			desired = append(desired, networkACLItem{action: NetworkACLActionAllow, port: port, cidrs: allowed})
+		}
+		for _, port := range ports {
+			desired = append(desired, networkACLItem{action: NetworkACLActionDeny, port: port, cidrs: allSourcesCIDR})
+		}
+	}
+
+	var current []*cloudstack.NetworkACL
+	if network.Aclid != "" {
+		p := c.cs.NetworkACL.NewListNetworkACLsParams()
+		p.SetAclid(network.Aclid)
+		setIfNotEmpty(c.user.Project.ID, p.SetProjectid)
+		resp, err := c.cs.NetworkACL.ListNetworkACLs(p)
+		if err != nil {
+			c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+			return errors.Wrapf(err, "listing items of network ACL list %s", network.Aclid)
+		}
+		reason := apiServerACLReason(csCluster)
+		for _, item := range resp.NetworkACLs {
+			if item.Reason == reason {
+				current = append(current, item)
+			}
+		}
+	}
+	if networkACLItemsMatch(current, desired) {
+		return nil
+	}
+	if len(desired) > 0 && (network.Aclid == "" ||
+		network.Aclname == DefaultAllowNetworkACLList || network.Aclname == DefaultDenyNetworkACLList) {
+		return errors.Errorf("restricting API server access in VPC tier %s requires a custom network ACL list, "+
+			"but the tier uses %q", isoNet.Spec.ID, network.Aclname)
+	}
+
+	for _, item := range current {
+		if _, err := c.cs.NetworkACL.DeleteNetworkACL(c.cs.NetworkACL.NewDeleteNetworkACLParams(item.Id)); err != nil {
+			c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+			return errors.Wrapf(err, "deleting network ACL item %s", item.Id)
+		}
+	}
+	for _, item := range desired {
+		p := c.cs.NetworkACL.NewCreateNetworkACLParams(NetworkProtocolTCP)
+		p.SetAclid(network.Aclid)
+		p.SetAction(item.action)
+		p.SetTraffictype(NetworkACLTrafficIngress)
+		p.SetStartport(item.port)
+		p.SetEndport(item.port)
+		p.SetCidrlist(strings.Split(item.cidrs, ","))
+		p.SetReason(apiServerACLReason(csCluster))
+		if _, err := c.cs.NetworkACL.CreateNetworkACL(p); err != nil {
+			c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+			return errors.Wrapf(err, "creating network ACL item for port %d in network ACL list %s", item.port, network.Aclid)
+		}
+	}
+	return nil
+}
+
+// networkACLItemsMatch checks whether the current items are the desired ones, ignoring order.
+func networkACLItemsMatch(current []*cloudstack.NetworkACL, desired []networkACLItem) bool {
+	if len(current) != len(desired) {
+		return false
+	}
+	remaining := map[networkACLItem]int{}
+	for _, item := range desired {
+		remaining[item]++
+	}
+	for _, item := range current {
+		port, _ := strconv.Atoi(item.Startport)
+		key := networkACLItem{
+			action: item.Action,
+			port:   port,
+			cidrs:  normalizeCIDRs(strings.Split(item.Cidrlist, ",")),
+		}
+		if item.Endport != item.Startport || remaining[key] == 0 {
+			return false
+		}
+		remaining[key]--
+	}
+	return true
+}
+
+// normalizeCIDRs returns the CIDRs sorted and comma separated.
+func normalizeCIDRs(cidrs []string) string {
+	out := make([]string, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		if cidr = strings.TrimSpace(cidr); cidr != "" {
+			out = append(out, cidr)
+		}
+	}
+	sort.Strings(out)
+	return strings.Join(out, ",")
+}
@@ -0,0 +1,80 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud_test
+
+import (
+	"github.com/onsi/ginkgo/v2"
+	gomega "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/test/fakecloudstack"
+)
+
+var _ = ginkgo.Describe("Network ACL", func() {
+	var fake *fakeCloud
+
+	ginkgo.BeforeEach(func() {
+		fake = newFakeCloud()
+	})
+
+	ginkgo.It("creates, attaches and deletes the network ACL list of a VPC tier", func() {
+		gomega.Ω(fake.client.ResolveZone(&fake.fd.Spec.Zone)).Should(gomega.Succeed())
+		fake.isoNet.Spec.VPC = &infrav1.VPC{Name: "test-cluster-vpc", CIDR: "10.1.0.0/16"}
+		fake.isoNet.Spec.ACL = &infrav1.NetworkACL{Rules: []infrav1.NetworkACLRule{
+			{Action: "Allow", Protocol: "tcp", StartPort: 22, CIDRList: []string{"10.0.0.0/8"}},
+			{Action: "Allow", Protocol: "all", TrafficType: "Egress"},
+		}}
+		fake.csCluster.Spec.APIServerLoadBalancer = &infrav1.APIServerLoadBalancer{AllowedCIDRs: []string{"192.168.0.0/16"}}
+
+		gomega.Ω(fake.client.GetOrCreateIsolatedNetwork(fake.fd, fake.isoNet, fake.csCluster)).Should(gomega.Succeed())
+		gomega.Ω(fake.isoNet.Status.NetworkACLListID).ShouldNot(gomega.BeEmpty())
+		network, _ := fake.server.Get(fakecloudstack.KindNetwork, fake.isoNet.Spec.ID)
+		gomega.Ω(network["aclid"]).Should(gomega.Equal(fake.isoNet.Status.NetworkACLListID))
+		// The API server access items are added to the list after the declared rules.
+		gomega.Ω(fake.server.List(fakecloudstack.KindNetworkACL)).Should(gomega.ConsistOf(
+			gomega.And(gomega.HaveKeyWithValue("number", 1), gomega.HaveKeyWithValue("startport", "22")),
+			gomega.And(gomega.HaveKeyWithValue("number", 2), gomega.HaveKeyWithValue("traffictype", "Egress")),
+			gomega.And(gomega.HaveKeyWithValue("number", 3), gomega.HaveKeyWithValue("action", "Allow"),
+				gomega.HaveKeyWithValue("cidrlist", "192.168.0.0/16")),
+			gomega.And(gomega.HaveKeyWithValue("number", 4), gomega.HaveKeyWithValue("action", "Deny")),
+		))
+
+		// Reconciling again leaves the list in place.
+		gomega.Ω(fake.client.GetOrCreateIsolatedNetwork(fake.fd, fake.isoNet, fake.csCluster)).Should(gomega.Succeed())
+		gomega.Ω(fake.server.Calls("createNetworkACLList")).Should(gomega.Equal(1))
+		gomega.Ω(fake.server.Calls("createNetworkACL")).Should(gomega.Equal(4))
+		gomega.Ω(fake.server.Calls("replaceNetworkACLList")).Should(gomega.Equal(1))
+
+		gomega.Ω(fake.client.DisposeIsoNetResources(fake.isoNet, fake.csCluster)).Should(gomega.Succeed())
+		_, found := fake.server.Get(fakecloudstack.KindNetworkACLList, fake.isoNet.Status.NetworkACLListID)
+		gomega.Ω(found).Should(gomega.BeFalse())
+		gomega.Ω(fake.isoNet.Status.NetworkACLListID).Should(gomega.BeEmpty())
+		gomega.Ω(fake.server.List(fakecloudstack.KindNetworkACL)).Should(gomega.BeEmpty())
+	})
+
+	ginkgo.It("attaches an existing network ACL list to a VPC tier", func() {
+		gomega.Ω(fake.client.ResolveZone(&fake.fd.Spec.Zone)).Should(gomega.Succeed())
+		fake.isoNet.Spec.VPC = &infrav1.VPC{Name: "test-cluster-vpc", CIDR: "10.1.0.0/16"}
+		fake.isoNet.Spec.ACL = &infrav1.NetworkACL{Name: "default_allow"}
+
+		gomega.Ω(fake.client.GetOrCreateIsolatedNetwork(fake.fd, fake.isoNet, fake.csCluster)).Should(gomega.Succeed())
+		network, _ := fake.server.Get(fakecloudstack.KindNetwork, fake.isoNet.Spec.ID)
+		gomega.Ω(network["aclname"]).Should(gomega.Equal("default_allow"))
+		gomega.Ω(fake.isoNet.Status.NetworkACLListID).Should(gomega.BeEmpty())
+		gomega.Ω(fake.server.Calls("createNetworkACLList")).Should(gomega.Equal(0))
+	})
+})
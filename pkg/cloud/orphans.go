@@ -0,0 +1,142 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+)
+
+// OrphanIface lists and deletes the resources CAPC created, to collect the ones of clusters that no longer exist.
+type OrphanIface interface {
+	ListCAPCResources() ([]CAPCResource, error)
+	DeleteCAPCResource(CAPCResource) error
+}
+
+// CAPCResource is a resource tagged as created by CAPC.
+type CAPCResource struct {
+	Type ResourceType
+	ID   string
+	// ClusterUIDs are the UIDs of the CloudStackClusters the resource's cluster tags say are using it.
+	ClusterUIDs []string
+	// Tags are all the tags of the resource.
+	Tags map[string]string
+}
+
+// CollectableResourceTypes are the types of the resources CAPC tags with the clusters using them, in the order they
+// have to be deleted in. Firewall rules aren't collected, they're deleted along with their public IP or network.
+var CollectableResourceTypes = []ResourceType{
+	ResourceTypeVM,
+	ResourceTypeVolume,
+	ResourceTypeLoadBalancer,
+	ResourceTypeAffinityGroup,
+	ResourceTypeIPAddress,
+	ResourceTypeNetwork,
+	ResourceTypeVPC,
+}
+
+// ListCAPCResources lists the resources of collectable types the client's user can see that are tagged as created by
+// CAPC, in the order they have to be deleted in.
+func (c *client) ListCAPCResources() ([]CAPCResource, error) {
+	p := c.cs.Resourcetags.NewListTagsParams()
+	p.SetKey(CreatedByCAPCTagName)
+	p.SetListall(true)
+	setIfNotEmpty(c.user.Project.ID, p.SetProjectid)
+	resp, err := c.cs.Resourcetags.ListTags(p)
+	if err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return nil, errors.Wrap(err, "listing resources created by CAPC")
+	}
+
+	var resources []CAPCResource
+	for _, tag := range resp.Tags {
+		rType := ResourceType(tag.Resourcetype)
+		if !slices.Contains(CollectableResourceTypes, rType) {
+			continue
+		}
+		// The cluster tags are fetched per resource, a listing of all tags may be cut short by CloudStack's page size.
+		tags, err := c.GetTags(rType, tag.Resourceid)
+		if err != nil {
+			return nil, errors.Wrapf(err, "fetching tags for resource %s with ID %s", rType, tag.Resourceid)
+		}
+		resource := CAPCResource{Type: rType, ID: tag.Resourceid, Tags: tags}
+		for tagName := range tags {
+			if strings.HasPrefix(tagName, ClusterTagNamePrefix) {
+				resource.ClusterUIDs = append(resource.ClusterUIDs, strings.TrimPrefix(tagName, ClusterTagNamePrefix))
+			}
+		}
+		sort.Strings(resource.ClusterUIDs)
+		resources = append(resources, resource)
+	}
+
+	sort.SliceStable(resources, func(i, j int) bool {
+		return slices.Index(CollectableResourceTypes, resources[i].Type) <
+			slices.Index(CollectableResourceTypes, resources[j].Type)
+	})
+	return resources, nil
+}
+
+// DeleteCAPCResource deletes a resource created by CAPC.
+func (c *client) DeleteCAPCResource(resource CAPCResource) error {
+	switch resource.Type {
+	case ResourceTypeVM:
+		return c.destroyOrphanedVM(resource.ID)
+	case ResourceTypeVolume:
+		if _, err := c.cs.Volume.DeleteVolume(c.cs.Volume.NewDeleteVolumeParams(resource.ID)); err != nil {
+			c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+			return errors.Wrapf(err, "deleting volume with ID %s", resource.ID)
+		}
+		return nil
+	case ResourceTypeLoadBalancer:
+		return c.deleteLoadBalancerRule(resource.ID)
+	case ResourceTypeAffinityGroup:
+		return errors.Wrapf(c.DeleteAffinityGroup(&AffinityGroup{ID: resource.ID}),
+			"deleting affinity group with ID %s", resource.ID)
+	case ResourceTypeIPAddress:
+		_, err := c.cs.Address.DisassociateIpAddress(c.cs.Address.NewDisassociateIpAddressParams(resource.ID))
+		if err != nil {
+			c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+			return errors.Wrapf(err, "disassociating public IP address with ID %s", resource.ID)
+		}
+		return nil
+	case ResourceTypeNetwork:
+		return c.DeleteNetwork(infrav1.Network{ID: resource.ID})
+	case ResourceTypeVPC:
+		return c.DeleteVPC(infrav1.VPC{ID: resource.ID})
+	}
+	return errors.Errorf("deleting resources of type %s is not supported", resource.Type)
+}
+
+// destroyOrphanedVM destroys a VM and waits for it to be destroyed, so its detached data disk volumes can be collected
+// next. The VM is expunged if the user is allowed to.
+func (c *client) destroyOrphanedVM(id string) error {
+	expunge := true
+	if capabilities, err := c.cs.Configuration.ListCapabilities(c.cs.Configuration.NewListCapabilitiesParams()); err == nil {
+		expunge = capabilities.Capabilities.Allowuserexpungerecovervm
+	}
+	p := c.csAsync.VirtualMachine.NewDestroyVirtualMachineParams(id)
+	p.SetExpunge(expunge)
+	if _, err := c.csAsync.VirtualMachine.DestroyVirtualMachine(p); err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return errors.Wrapf(err, "destroying VM with ID %s", id)
+	}
+	return nil
+}
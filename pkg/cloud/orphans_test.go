@@ -0,0 +1,67 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud_test
+
+import (
+	"slices"
+
+	"github.com/onsi/ginkgo/v2"
+	gomega "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/test/fakecloudstack"
+)
+
+var _ = ginkgo.Describe("Orphans", func() {
+	var fake *fakeCloud
+
+	ginkgo.BeforeEach(func() {
+		fake = newFakeCloud()
+	})
+
+	ginkgo.It("lists the resources created by CAPC in the order they have to be deleted in, and deletes them", func() {
+		fake.useIsolatedNetwork()
+		gomega.Ω(fake.getOrCreateVMInstance("")).Should(gomega.Succeed())
+		// Resources that weren't created by CAPC aren't listed.
+		handBuiltID := fake.server.Add(fakecloudstack.KindVirtualMachine, fakecloudstack.Resource{
+			"name": "hand-built", "state": "Running",
+		})["id"].(string)
+
+		resources, err := fake.client.ListCAPCResources()
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		var listed []cloud.ResourceType
+		for _, resource := range resources {
+			gomega.Ω(resource.ID).ShouldNot(gomega.Equal(handBuiltID))
+			gomega.Ω(resource.ClusterUIDs).Should(gomega.Equal([]string{string(fake.csCluster.UID)}))
+			listed = append(listed, resource.Type)
+		}
+		gomega.Ω(listed).Should(gomega.ContainElements(cloud.ResourceTypeVM, cloud.ResourceTypeIPAddress))
+		gomega.Ω(slices.IsSortedFunc(listed, func(a, b cloud.ResourceType) int {
+			return slices.Index(cloud.CollectableResourceTypes, a) - slices.Index(cloud.CollectableResourceTypes, b)
+		})).Should(gomega.BeTrue())
+
+		for _, resource := range resources {
+			gomega.Ω(fake.client.DeleteCAPCResource(resource)).Should(gomega.Succeed())
+		}
+		_, found := fake.server.Get(fakecloudstack.KindVirtualMachine, *fake.csMachine.Spec.InstanceID)
+		gomega.Ω(found).Should(gomega.BeFalse())
+		_, found = fake.server.Get(fakecloudstack.KindVirtualMachine, handBuiltID)
+		gomega.Ω(found).Should(gomega.BeTrue())
+		ip, _ := fake.server.Get(fakecloudstack.KindPublicIPAddress, fake.isoNet.Status.PublicIPID)
+		gomega.Ω(ip["state"]).Should(gomega.Equal("Free"))
+	})
+})
@@ -0,0 +1,90 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/pkg/errors"
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+)
+
+// configurePlacement sets the pod, cluster and host a machine is pinned to on its deployment parameters.
+func (c *client) configurePlacement(p *cloudstack.DeployVirtualMachineParams, csMachine *infrav1.CloudStackMachine, zoneID string) error {
+	placement := csMachine.Spec.Placement
+	if placement == nil {
+		return nil
+	}
+	podID, err := c.resolvePlacementID("pod", placement.Pod, zoneID, c.cs.Pod.GetPodID)
+	if err != nil {
+		return err
+	}
+	clusterID, err := c.resolvePlacementID("cluster", placement.Cluster, zoneID, c.cs.Cluster.GetClusterID)
+	if err != nil {
+		return err
+	}
+	hostID, err := c.resolvePlacementID("host", placement.Host, zoneID, c.cs.Host.GetHostID)
+	if err != nil {
+		return err
+	}
+	setIfNotEmpty(podID, p.SetPodid)
+	setIfNotEmpty(clusterID, p.SetClusterid)
+	setIfNotEmpty(hostID, p.SetHostid)
+	return nil
+}
+
+// resolvePlacementID returns the ID of a pod, cluster or host, looking it up by name in the zone if no ID is given.
+func (c *client) resolvePlacementID(
+	kind string,
+	identifier infrav1.CloudStackResourceIdentifier,
+	zoneID string,
+	getID func(name string, opts ...cloudstack.OptionFunc) (string, int, error),
+) (string, error) {
+	if identifier.ID != "" || identifier.Name == "" {
+		return identifier.ID, nil
+	}
+	id, count, err := getID(identifier.Name, cloudstack.WithZone(zoneID))
+	if err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return "", errors.Wrapf(err, "could not get %s ID from %s in zone %s", kind, identifier.Name, zoneID)
+	} else if count != 1 {
+		return "", errors.Errorf("expected 1 %s with name %s in zone %s, but got %d", kind, identifier.Name, zoneID, count)
+	}
+	return id, nil
+}
+
+// resolveMachinePlacement sets the pod, cluster and host an instance runs on in the machine's status. The host is only
+// reported to admin users, and it is only looked up again when the instance moved to another host.
+func (c *client) resolveMachinePlacement(vm *cloudstack.VirtualMachinesMetric, csMachine *infrav1.CloudStackMachine) error {
+	if vm.Hostid == "" {
+		return nil
+	} else if csMachine.Status.Placement != nil && csMachine.Status.Placement.Host.ID == vm.Hostid {
+		return nil
+	}
+	host, count, err := c.cs.Host.GetHostByID(vm.Hostid)
+	if err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return errors.Wrapf(err, "could not get host %s of VM %s", vm.Hostid, vm.Id)
+	} else if count != 1 {
+		return errors.Errorf("expected 1 host with ID %s, but got %d", vm.Hostid, count)
+	}
+	csMachine.Status.Placement = &infrav1.CloudStackPlacement{
+		Pod:     infrav1.CloudStackResourceIdentifier{ID: host.Podid, Name: host.Podname},
+		Cluster: infrav1.CloudStackResourceIdentifier{ID: host.Clusterid, Name: host.Clustername},
+		Host:    infrav1.CloudStackResourceIdentifier{ID: host.Id, Name: host.Name},
+	}
+	return nil
+}
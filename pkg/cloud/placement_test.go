@@ -0,0 +1,56 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud_test
+
+import (
+	"github.com/onsi/ginkgo/v2"
+	gomega "github.com/onsi/gomega"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/test/fakecloudstack"
+)
+
+var _ = ginkgo.Describe("Placement", func() {
+	var fake *fakeCloud
+
+	ginkgo.BeforeEach(func() {
+		fake = newFakeCloud()
+	})
+
+	ginkgo.It("deploys a VM on the host cluster it is pinned to and reports its placement", func() {
+		fake.useSharedNetwork()
+
+		fake.csMachine.Spec.Placement = &infrav1.CloudStackPlacement{Cluster: infrav1.CloudStackResourceIdentifier{Name: "ssd"}}
+		gomega.Ω(fake.getOrCreateVMInstance("")).Should(
+			gomega.MatchError(gomega.ContainSubstring("could not get cluster ID from ssd")))
+
+		fake.csMachine.Spec.Placement.Cluster.Name = fakecloudstack.NVMeClusterName
+		gomega.Ω(fake.getOrCreateVMInstance("")).Should(gomega.Succeed())
+		pod, _ := fake.server.Find(fakecloudstack.KindPod, fakecloudstack.PodName)
+		host, _ := fake.server.Find(fakecloudstack.KindHost, fakecloudstack.NVMeClusterName+"-host1")
+		gomega.Ω(fake.csMachine.Status.Placement).ShouldNot(gomega.BeNil())
+		gomega.Ω(fake.csMachine.Status.Placement.Pod).Should(gomega.Equal(
+			infrav1.CloudStackResourceIdentifier{ID: pod["id"].(string), Name: fakecloudstack.PodName}))
+		gomega.Ω(fake.csMachine.Status.Placement.Cluster.Name).Should(gomega.Equal(fakecloudstack.NVMeClusterName))
+		gomega.Ω(fake.csMachine.Status.Placement.Host).Should(gomega.Equal(
+			infrav1.CloudStackResourceIdentifier{ID: host["id"].(string), Name: host["name"].(string)}))
+		root, _ := fake.server.Find(fakecloudstack.KindVolume, "ROOT-"+*fake.csMachine.Spec.InstanceID)
+		gomega.Ω(fake.csMachine.Status.RootVolumeID).Should(gomega.Equal(root["id"]))
+		gomega.Ω(fake.csMachine.Status.Hypervisor).Should(gomega.Equal("Simulator"))
+		gomega.Ω(fake.csMachine.Status.MemoryMiB).Should(gomega.Equal(2048))
+	})
+})
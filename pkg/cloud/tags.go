@@ -39,12 +39,55 @@ type TagIface interface {
 type ResourceType string
 
 const (
-	ClusterTagNamePrefix               = "CAPC_cluster_"
-	CreatedByCAPCTagName               = "created_by_CAPC"
-	ResourceTypeNetwork   ResourceType = "Network"
-	ResourceTypeIPAddress ResourceType = "PublicIpAddress"
+	ClusterTagNamePrefix                   = "CAPC_cluster_"
+	CreatedByCAPCTagName                   = "created_by_CAPC"
+	OwnerClusterTagName                    = "CAPC_owner_cluster"
+	OwnerNamespaceTagName                  = "CAPC_owner_namespace"
+	OwnerMachineTagName                    = "CAPC_owner_machine"
+	VersionTagName                         = "CAPC_version"
+	ResourceTypeNetwork       ResourceType = "Network"
+	ResourceTypeIPAddress     ResourceType = "PublicIpAddress"
+	ResourceTypeVM            ResourceType = "UserVm"
+	ResourceTypeVolume        ResourceType = "Volume"
+	ResourceTypeAffinityGroup ResourceType = "AffinityGroup"
+	ResourceTypeLoadBalancer  ResourceType = "LoadBalancer"
+	ResourceTypeFirewallRule  ResourceType = "FirewallRule"
 )
 
+// Version is the CAPC version resources are tagged with. It's set at build time.
+var Version = "dev"
+
+// ResourceTags returns the tags of a resource CAPC creates for a cluster, and for a machine if one is given. Besides
+// the created_by_CAPC and cluster tags, these name the owning cluster, namespace and machine and the CAPC version, and
+// include the AdditionalTags of the cluster and machine, the machine's taking precedence.
+func ResourceTags(csCluster *infrav1.CloudStackCluster, csMachine *infrav1.CloudStackMachine) map[string]string {
+	tags := map[string]string{}
+	if csCluster != nil {
+		for key, value := range csCluster.Spec.AdditionalTags {
+			tags[key] = value
+		}
+	}
+	if csMachine != nil {
+		for key, value := range csMachine.Spec.AdditionalTags {
+			tags[key] = value
+		}
+	}
+
+	tags[CreatedByCAPCTagName] = "1"
+	tags[VersionTagName] = Version
+	if csCluster != nil {
+		if csCluster.UID != "" {
+			tags[generateClusterTagName(csCluster)] = "1"
+		}
+		tags[OwnerClusterTagName] = csCluster.Name
+		tags[OwnerNamespaceTagName] = csCluster.Namespace
+	}
+	if csMachine != nil {
+		tags[OwnerMachineTagName] = csMachine.Name
+	}
+	return tags
+}
+
 func (c *client) IsCapcManaged(resourceType ResourceType, resourceID string) (bool, error) {
 	tags, err := c.GetTags(resourceType, resourceID)
 	if err != nil {
@@ -116,9 +159,20 @@ func (c *client) AddTags(resourceType ResourceType, resourceID string, tags map[
 
 	// Identify tags that need to be added or updated
 	tagsToAdd := make(map[string]string)
+	tagsToReplace := make(map[string]string)
 	for key, value := range tags {
 		if existingValue, exists := existingTags[key]; !exists || existingValue != value {
 			tagsToAdd[key] = value
+			if exists {
+				tagsToReplace[key] = existingValue
+			}
+		}
+	}
+
+	// CloudStack can't change the value of a tag, so tags being updated are deleted first.
+	if len(tagsToReplace) > 0 {
+		if err := c.DeleteTags(resourceType, resourceID, tagsToReplace); err != nil {
+			return err
 		}
 	}
 
@@ -134,6 +188,16 @@ func (c *client) AddTags(resourceType ResourceType, resourceID string, tags map[
 	return nil
 }
 
+// tagCreatedResource adds tags to a resource that was just created, and so has none yet.
+func (c *client) tagCreatedResource(resourceType ResourceType, resourceID string, tags map[string]string) error {
+	p := c.cs.Resourcetags.NewCreateTagsParams([]string{resourceID}, string(resourceType), tags)
+	if _, err := c.cs.Resourcetags.CreateTags(p); err != nil {
+		c.customMetrics.EvaluateErrorAndIncrementAcsReconciliationErrorCounter(err)
+		return errors.Wrapf(err, "tagging %s with ID %s", resourceType, resourceID)
+	}
+	return nil
+}
+
 // GetTags gets all of a resource's tags.
 func (c *client) GetTags(resourceType ResourceType, resourceID string) (map[string]string, error) {
 	p := c.cs.Resourcetags.NewListTagsParams()
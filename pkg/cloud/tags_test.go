@@ -24,6 +24,7 @@ import (
 	gomock "go.uber.org/mock/gomock"
 	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
 	dummies "sigs.k8s.io/cluster-api-provider-cloudstack/test/dummies/v1beta3"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/test/fakecloudstack"
 )
 
 var _ = ginkgo.Describe("Tag Unit Tests", func() {
@@ -198,4 +199,41 @@ var _ = ginkgo.Describe("Tag Unit Tests", func() {
 			gomega.Ω(err).ShouldNot(gomega.Succeed())
 		})
 	})
+
+	ginkgo.Context("against a fake CloudStack", func() {
+		var fake *fakeCloud
+
+		ginkgo.BeforeEach(func() {
+			fake = newFakeCloud()
+		})
+
+		ginkgo.It("tags the resources it creates with their owners and additional tags", func() {
+			tagsOf := func(id string) map[string]string {
+				tags := map[string]string{}
+				for _, tag := range fake.server.List(fakecloudstack.KindTag) {
+					if tag["resourceid"] == id {
+						tags[tag["key"].(string)] = tag["value"].(string)
+					}
+				}
+				return tags
+			}
+			fake.csCluster.Spec.AdditionalTags = map[string]string{"cost-center": "4242", "team": "platform"}
+			fake.csMachine.Spec.AdditionalTags = map[string]string{"team": "data"}
+
+			fake.useIsolatedNetwork()
+			gomega.Ω(fake.getOrCreateVMInstance("")).Should(gomega.Succeed())
+
+			gomega.Ω(tagsOf(fake.isoNet.Status.LBRuleID)).Should(gomega.Equal(cloud.ResourceTags(fake.csCluster, nil)))
+			gomega.Ω(tagsOf(fake.isoNet.Status.PublicIPID)).Should(gomega.Equal(cloud.ResourceTags(fake.csCluster, nil)))
+			vmTags := tagsOf(*fake.csMachine.Spec.InstanceID)
+			gomega.Ω(vmTags).Should(gomega.Equal(cloud.ResourceTags(fake.csCluster, fake.csMachine)))
+			gomega.Ω(vmTags).Should(gomega.HaveKeyWithValue("team", "data"))
+			gomega.Ω(vmTags).Should(gomega.HaveKeyWithValue("cost-center", "4242"))
+			gomega.Ω(vmTags).Should(gomega.HaveKeyWithValue(cloud.OwnerMachineTagName, "test-machine"))
+
+			gomega.Ω(fake.client.DestroyVMInstance(fake.csMachine)).Should(gomega.MatchError("VM deletion in progress"))
+			gomega.Ω(fake.client.DestroyVMInstance(fake.csMachine)).Should(gomega.Succeed())
+			gomega.Ω(tagsOf(*fake.csMachine.Spec.InstanceID)).Should(gomega.BeEmpty())
+		})
+	})
 })
@@ -485,4 +485,28 @@ var _ = ginkgo.Describe("User Credentials", func() {
 			gomega.Ω(newClient).ShouldNot(gomega.BeNil())
 		})
 	})
+
+	ginkgo.Context("against a fake CloudStack", func() {
+		var fake *fakeCloud
+
+		ginkgo.BeforeEach(func() {
+			fake = newFakeCloud()
+		})
+
+		ginkgo.It("resolves users in other domains and accounts", func() {
+			_, err := fake.server.AddDomain("ROOT/tenant")
+			gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+			user, err := fake.server.AddAccount("ROOT/tenant", "tenant-account", "tenant-user")
+			gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+
+			tenantClient, err := fake.client.NewClientInDomainAndAccount("tenant", "tenant-account", "")
+			gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+
+			tenantUser := &cloud.User{Account: cloud.Account{Name: "tenant-account", Domain: cloud.Domain{Path: "ROOT/tenant"}}}
+			found, err := tenantClient.GetUserWithKeys(tenantUser)
+			gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+			gomega.Ω(found).Should(gomega.BeTrue())
+			gomega.Ω(tenantUser.APIKey).Should(gomega.Equal(user["apikey"]))
+		})
+	})
 })
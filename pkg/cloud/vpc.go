@@ -34,7 +34,7 @@ const (
 // VPCIface defines the interface for VPC operations.
 type VPCIface interface {
 	ResolveVPC(*infrav1.VPC) error
-	CreateVPC(*infrav1.CloudStackFailureDomain, *infrav1.VPC) error
+	CreateVPC(*infrav1.CloudStackFailureDomain, *infrav1.VPC, *infrav1.CloudStackCluster) error
 	RemoveClusterTagFromVPC(*infrav1.CloudStackCluster, infrav1.VPC) error
 	DeleteVPCIfNotInUse(infrav1.VPC) (retError error)
 }
@@ -89,8 +89,8 @@ func (c *client) ResolveVPC(vpc *infrav1.VPC) error {
 	return nil
 }
 
-// CreateVPC creates a new VPC in CloudStack.
-func (c *client) CreateVPC(fd *infrav1.CloudStackFailureDomain, vpc *infrav1.VPC) error {
+// CreateVPC creates a new VPC in CloudStack, tagged as created by CAPC for the cluster.
+func (c *client) CreateVPC(fd *infrav1.CloudStackFailureDomain, vpc *infrav1.VPC, csCluster *infrav1.CloudStackCluster) error {
 	if vpc == nil || vpc.Name == "" {
 		return errors.New("VPC name must be specified")
 	}
@@ -114,7 +114,7 @@ func (c *client) CreateVPC(fd *infrav1.CloudStackFailureDomain, vpc *infrav1.VPC
 		return errors.Wrapf(err, "creating VPC with name %s", vpc.Name)
 	}
 	vpc.ID = resp.Id
-	return c.AddCreatedByCAPCTag(ResourceTypeVPC, vpc.ID)
+	return c.tagCreatedResource(ResourceTypeVPC, vpc.ID, ResourceTags(csCluster, nil))
 }
 
 // DeleteVPC deletes a VPC.
@@ -168,12 +168,11 @@ var _ = ginkgo.Describe("VPC", func() {
 			vs.EXPECT().GetVPCOfferingID(cloud.VPCOffering).Return(offeringID, 1, nil)
 			vs.EXPECT().NewCreateVPCParams(dummyVPC.CIDR, dummyVPC.Name, dummyVPC.Name, offeringID, dummyFD.Spec.Zone.ID).Return(createVPCParams)
 			vs.EXPECT().CreateVPC(createVPCParams).Return(createVPCResponse, nil)
-			rs.EXPECT().NewCreateTagsParams(gomock.Any(), gomock.Any(), gomock.Any()).Return(&csapi.CreateTagsParams{})
-			rs.EXPECT().NewListTagsParams().Return(&csapi.ListTagsParams{})
-			rs.EXPECT().ListTags(gomock.Any()).Return(&csapi.ListTagsResponse{}, nil)
+			rs.EXPECT().NewCreateTagsParams([]string{"vpc-123"}, cloud.ResourceTypeVPC, cloud.ResourceTags(dummies.CSCluster, nil)).
+				Return(&csapi.CreateTagsParams{})
 			rs.EXPECT().CreateTags(gomock.Any()).Return(&csapi.CreateTagsResponse{}, nil)
 
-			gomega.Ω(client.CreateVPC(&dummyFD, &dummyVPC)).Should(gomega.Succeed())
+			gomega.Ω(client.CreateVPC(&dummyFD, &dummyVPC, dummies.CSCluster)).Should(gomega.Succeed())
 			gomega.Ω(dummyVPC.ID).Should(gomega.Equal("vpc-123"))
 		})
 
@@ -181,7 +180,7 @@ var _ = ginkgo.Describe("VPC", func() {
 			expectedErr := errors.New("failed to get VPC offering")
 			vs.EXPECT().GetVPCOfferingID(cloud.VPCOffering).Return("", 0, expectedErr)
 
-			err := client.CreateVPC(&dummyFD, &dummyVPC)
+			err := client.CreateVPC(&dummyFD, &dummyVPC, dummies.CSCluster)
 			gomega.Ω(err).ShouldNot(gomega.Succeed())
 			gomega.Ω(err.Error()).Should(gomega.Equal(expectedErr.Error()))
 		})
@@ -189,7 +188,7 @@ var _ = ginkgo.Describe("VPC", func() {
 		ginkgo.It("returns error when multiple VPC offerings found", func() {
 			vs.EXPECT().GetVPCOfferingID(cloud.VPCOffering).Return("", 2, nil)
 
-			err := client.CreateVPC(&dummyFD, &dummyVPC)
+			err := client.CreateVPC(&dummyFD, &dummyVPC, dummies.CSCluster)
 			gomega.Ω(err).ShouldNot(gomega.Succeed())
 			gomega.Ω(err.Error()).Should(gomega.Equal("found more than one vpc offering"))
 		})
@@ -203,13 +202,13 @@ var _ = ginkgo.Describe("VPC", func() {
 			vs.EXPECT().NewCreateVPCParams(dummyVPC.CIDR, dummyVPC.Name, dummyVPC.Name, offeringID, dummyFD.Spec.Zone.ID).Return(createVPCParams)
 			vs.EXPECT().CreateVPC(createVPCParams).Return(nil, expectedErr)
 
-			err := client.CreateVPC(&dummyFD, &dummyVPC)
+			err := client.CreateVPC(&dummyFD, &dummyVPC, dummies.CSCluster)
 			gomega.Ω(err).ShouldNot(gomega.Succeed())
 			gomega.Ω(err.Error()).Should(gomega.ContainSubstring(fmt.Sprintf("creating VPC with name %s", dummyVPC.Name)))
 		})
 
 		ginkgo.It("returns error when VPC is nil", func() {
-			err := client.CreateVPC(&dummyFD, nil)
+			err := client.CreateVPC(&dummyFD, nil, dummies.CSCluster)
 			gomega.Ω(err).ShouldNot(gomega.Succeed())
 			gomega.Ω(err.Error()).Should(gomega.Equal("VPC name must be specified"))
 		})
@@ -218,7 +217,7 @@ var _ = ginkgo.Describe("VPC", func() {
 			emptyNameVPC := &infrav1.VPC{
 				CIDR: "10.0.0.0/16",
 			}
-			err := client.CreateVPC(&dummyFD, emptyNameVPC)
+			err := client.CreateVPC(&dummyFD, emptyNameVPC, dummies.CSCluster)
 			gomega.Ω(err).ShouldNot(gomega.Succeed())
 			gomega.Ω(err.Error()).Should(gomega.Equal("VPC name must be specified"))
 		})
@@ -0,0 +1,72 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metrics
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	crtlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// OrphanGCMetrics encapsulates the metrics of the garbage collector of orphaned CloudStack resources.
+type OrphanGCMetrics struct {
+	// Orphaned is the number of orphaned resources found by the last collection with a set of credentials.
+	Orphaned *prometheus.GaugeVec
+	// Deleted counts the orphaned resources deleted.
+	Deleted *prometheus.CounterVec
+	// DeletionErrors counts the failed attempts to delete orphaned resources.
+	DeletionErrors *prometheus.CounterVec
+}
+
+// NewOrphanGCMetrics constructs and registers the OrphanGCMetrics.
+func NewOrphanGCMetrics() OrphanGCMetrics {
+	return OrphanGCMetrics{
+		Orphaned: register(prometheus.NewGaugeVec(
+			prometheus.GaugeOpts{
+				Name: "capc_orphaned_resources",
+				Help: "Number of CloudStack resources created by CAPC for clusters that no longer exist, by credentials and resource type",
+			},
+			[]string{"credentials", "resource_type"},
+		)),
+		Deleted: register(prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "capc_orphaned_resources_deleted_total",
+				Help: "Count of orphaned CloudStack resources deleted, by resource type",
+			},
+			[]string{"resource_type"},
+		)),
+		DeletionErrors: register(prometheus.NewCounterVec(
+			prometheus.CounterOpts{
+				Name: "capc_orphaned_resource_deletion_errors_total",
+				Help: "Count of failed deletions of orphaned CloudStack resources, by resource type",
+			},
+			[]string{"resource_type"},
+		)),
+	}
+}
+
+// register registers a collector with the controller-runtime registry, or returns the collector already registered
+// under its name.
+func register[T prometheus.Collector](collector T) T {
+	if err := crtlmetrics.Registry.Register(collector); err != nil {
+		if are, ok := err.(prometheus.AlreadyRegisteredError); ok {
+			return are.ExistingCollector.(T)
+		}
+		// Something else went wrong!
+		panic(err)
+	}
+	return collector
+}
@@ -13,6 +13,7 @@ import (
 	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
 	"sigs.k8s.io/cluster-api-provider-cloudstack/test/fakes"
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 )
 
 // GetYamlVal fetches the values in test/e2e/config/cloudstack.yaml by yaml node. A common config file.
@@ -33,6 +34,7 @@ var ( // Declare exported dummy vars.
 	ClusterName             string
 	ClusterNameSpace        string
 	CSMachineTemplate1      *infrav1.CloudStackMachineTemplate
+	CSClusterTemplate       *infrav1.CloudStackClusterTemplate
 	ACSEndpointSecret1      *corev1.Secret
 	ACSEndpointSecret2      *corev1.Secret
 	Zone1                   infrav1.CloudStackZoneSpec
@@ -88,6 +90,8 @@ var ( // Declare exported dummy vars.
 	BootstrapSecretName     string
 	CSMachineOwner          *fakes.CloudStackMachineOwner
 	CSMachineOwnerReference metav1.OwnerReference
+	CAPIMachinePool         *expv1.MachinePool
+	CSMachinePool           *infrav1.CloudStackMachinePool
 )
 
 // SetDummyVars sets/resets all dummy vars.
@@ -113,6 +117,7 @@ func SetDummyVars() {
 	SetDummyCSMachineVars()
 	SetDummyTagVars()
 	SetDummyBootstrapSecretVar()
+	SetDummyMachinePoolVars()
 	SetCSMachineOwner()
 	SetDummyOwnerReferences()
 	LBRuleID = "FakeLBRuleID"
@@ -249,6 +254,56 @@ func SetDummyCSMachineVars() {
 	}
 }
 
+// SetDummyMachinePoolVars resets the CAPI MachinePool and the CloudStackMachinePool it owns.
+func SetDummyMachinePoolVars() {
+	CSMachinePool = &infrav1.CloudStackMachinePool{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: CSApiVersion,
+			Kind:       "CloudStackMachinePool",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-machinepool",
+			Namespace: ClusterNameSpace,
+			Labels:    ClusterLabel,
+		},
+		Spec: infrav1.CloudStackMachinePoolSpec{
+			Template: *CSMachineTemplate1.Spec.Template.DeepCopy(),
+		},
+	}
+	CAPIMachinePool = &expv1.MachinePool{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: expv1.GroupVersion.String(),
+			Kind:       "MachinePool",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-machinepool",
+			Namespace: ClusterNameSpace,
+			Labels:    ClusterLabel,
+		},
+		Spec: expv1.MachinePoolSpec{
+			ClusterName: ClusterName,
+			Replicas:    ptr.To(int32(2)),
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					ClusterName: ClusterName,
+					Bootstrap:   clusterv1.Bootstrap{DataSecretName: ptr.To(BootstrapSecret.Name)},
+					InfrastructureRef: corev1.ObjectReference{
+						APIVersion: CSApiVersion,
+						Kind:       "CloudStackMachinePool",
+						Name:       CSMachinePool.Name,
+					},
+				},
+			},
+		},
+	}
+	CSMachinePool.OwnerReferences = []metav1.OwnerReference{{
+		APIVersion: expv1.GroupVersion.String(),
+		Kind:       "MachinePool",
+		Name:       CAPIMachinePool.Name,
+		UID:        "uniqueness",
+	}}
+}
+
 func SetDummyZoneVars() {
 	Zone1 = infrav1.CloudStackZoneSpec{Network: Net1}
 	Zone1.Name = GetYamlVal("CLOUDSTACK_ZONE_NAME")
@@ -350,6 +405,20 @@ func SetDummyCAPCClusterVars() {
 			ControlPlaneEndpoint: CSCluster.Spec.ControlPlaneEndpoint}}
 	CSISONet1.Spec.Name = ISONet1.Name
 	CSISONet1.Spec.ID = ISONet1.ID
+
+	CSClusterTemplate = &infrav1.CloudStackClusterTemplate{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: CSApiVersion,
+			Kind:       "CloudStackClusterTemplate",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      ClusterName + "-template",
+			Namespace: "default",
+		},
+		Spec: infrav1.CloudStackClusterTemplateSpec{
+			Template: infrav1.CloudStackClusterTemplateResource{Spec: *CSCluster.Spec.DeepCopy()},
+		},
+	}
 }
 
 func SetACSEndpointSecretVars() {
@@ -0,0 +1,1820 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakecloudstack
+
+import (
+	"encoding/binary"
+	"fmt"
+	"net"
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// handler executes a single API command. Synchronous commands return the body of the command's response,
+// asynchronous commands return an *asyncResult that is turned into a job.
+type handler func(s *Server, caller Resource, params url.Values) (interface{}, error)
+
+// asyncResult is the result of an asynchronous command, as reported by queryAsyncJobResult. The object is
+// wrapped in a map under key unless key is empty.
+type asyncResult struct {
+	key string
+	obj interface{}
+}
+
+// success is the result of delete-style commands.
+var success = &asyncResult{obj: Resource{"success": true}}
+
+var handlers = map[string]handler{
+	"queryasyncjobresult": queryAsyncJobResult,
+	"listcapabilities":    listCapabilities,
+
+	"listdomains":  list(KindDomain, KindDomain),
+	"listaccounts": list(KindAccount, KindAccount),
+	"listprojects": list(KindProject, KindProject),
+	"listusers":    listUsers,
+	"getuser":      getUser,
+	"getuserkeys":  getUserKeys,
+
+	"listzones":            list(KindZone, KindZone),
+	"listpods":             list(KindPod, KindPod),
+	"listclusters":         list(KindCluster, KindCluster),
+	"listhosts":            list(KindHost, KindHost),
+	"listcapacity":         listCapacity,
+	"listserviceofferings": list(KindServiceOffering, KindServiceOffering),
+	"listdiskofferings":    list(KindDiskOffering, KindDiskOffering),
+	"listtemplates":        list(KindTemplate, KindTemplate),
+	"listnetworkofferings": list(KindNetworkOffering, KindNetworkOffering),
+	"listvpcofferings":     list(KindVPCOffering, KindVPCOffering),
+	"listvolumes":          list(KindVolume, KindVolume),
+	"createvolume":         createVolume,
+	"attachvolume":         attachVolume,
+	"deletevolume":         deleteVolume,
+
+	"createnetwork": createNetwork,
+	"deletenetwork": deleteNetwork,
+	"listnetworks":  list(KindNetwork, KindNetwork),
+	"createvpc":     createVPC,
+	"deletevpc":     deleteVPC,
+	"listvpcs":      list(KindVPC, KindVPC),
+
+	"createnetworkacllist":  createNetworkACLList,
+	"deletenetworkacllist":  deleteNetworkACLList,
+	"listnetworkacllists":   list(KindNetworkACLList, KindNetworkACLList),
+	"replacenetworkacllist": replaceNetworkACLList,
+	"createnetworkacl":      createNetworkACL,
+	"deletenetworkacl":      deleteResource(KindNetworkACL),
+	"listnetworkacls":       list(KindNetworkACL, KindNetworkACL),
+
+	"associateipaddress":    associateIPAddress,
+	"disassociateipaddress": disassociateIPAddress,
+	"listpublicipaddresses": listPublicIPAddresses,
+
+	"createloadbalancerrule":        createLoadBalancerRule,
+	"deleteloadbalancerrule":        deleteLoadBalancerRule,
+	"listloadbalancerrules":         list(KindLoadBalancerRule, KindLoadBalancerRule),
+	"assigntoloadbalancerrule":      assignToLoadBalancerRule,
+	"removefromloadbalancerrule":    removeFromLoadBalancerRule,
+	"listloadbalancerruleinstances": listLoadBalancerRuleInstances,
+	"updateloadbalancerrule":        updateLoadBalancerRule,
+	"createlbhealthcheckpolicy":     createLBHealthCheckPolicy,
+	"deletelbhealthcheckpolicy":     deleteResource(KindLBHealthCheckPolicy),
+	"listlbhealthcheckpolicies":     listLBPolicies(KindLBHealthCheckPolicy, "healthcheckpolicy"),
+	"createlbstickinesspolicy":      createLBStickinessPolicy,
+	"deletelbstickinesspolicy":      deleteResource(KindLBStickinessPolicy),
+	"listlbstickinesspolicies":      listLBPolicies(KindLBStickinessPolicy, "stickinesspolicy"),
+
+	"createfirewallrule":                         createFirewallRule(KindFirewallRule, KindFirewallRule),
+	"deletefirewallrule":                         deleteResource(KindFirewallRule),
+	"listfirewallrules":                          list(KindFirewallRule, KindFirewallRule),
+	"createegressfirewallrule":                   createFirewallRule(KindEgressFirewallRule, KindFirewallRule),
+	"deleteegressfirewallrule":                   deleteResource(KindEgressFirewallRule),
+	"listegressfirewallrules":                    list(KindEgressFirewallRule, KindFirewallRule),
+	"createroutingfirewallrule":                  createFirewallRule(KindRoutingFirewallRule, KindRoutingFirewallRule),
+	"deleteroutingfirewallrule":                  deleteResource(KindRoutingFirewallRule),
+	"listroutingfirewallrules":                   list(KindRoutingFirewallRule, KindRoutingFirewallRule),
+	"createipv6firewallrule":                     createFirewallRule(KindIPv6FirewallRule, KindIPv6FirewallRule),
+	"deleteipv6firewallrule":                     deleteResource(KindIPv6FirewallRule),
+	"listipv6firewallrules":                      list(KindIPv6FirewallRule, KindIPv6FirewallRule),
+	"createaffinitygroup":                        createAffinityGroup,
+	"listaffinitygrouptypes":                     listAffinityGroupTypes,
+	"deleteaffinitygroup":                        deleteAffinityGroup,
+	"listaffinitygroups":                         list(KindAffinityGroup, KindAffinityGroup),
+	"updatevmaffinitygroup":                      updateVMAffinityGroup,
+	"createtags":                                 createTags,
+	"deletetags":                                 deleteTags,
+	"listtags":                                   list(KindTag, KindTag),
+	"deployvirtualmachine":                       deployVirtualMachine,
+	"destroyvirtualmachine":                      destroyVirtualMachine,
+	"startvirtualmachine":                        setVirtualMachineState("Running"),
+	"stopvirtualmachine":                         setVirtualMachineState("Stopped"),
+	"scalevirtualmachine":                        scaleVirtualMachine,
+	"listvirtualmachines":                        listVirtualMachines,
+	"listvirtualmachinesmetrics":                 listVirtualMachines,
+	"createkubernetescluster":                    createKubernetesCluster,
+	"deletekubernetescluster":                    deleteKubernetesCluster,
+	"listkubernetesclusters":                     listKubernetesClusters,
+	"addvirtualmachinestokubernetescluster":      addVirtualMachinesToKubernetesCluster,
+	"removevirtualmachinesfromkubernetescluster": removeVirtualMachinesFromKubernetesCluster,
+}
+
+// list returns a handler listing the resources of kind under the given response key.
+func list(kind, key string) handler {
+	return func(s *Server, _ Resource, params url.Values) (interface{}, error) {
+		return listResponse(key, s.filter(kind, params)), nil
+	}
+}
+
+// deleteResource returns a handler for asynchronous commands deleting a resource by ID.
+func deleteResource(kind string) handler {
+	return func(s *Server, _ Resource, params url.Values) (interface{}, error) {
+		if err := required(params, "id"); err != nil {
+			return nil, err
+		}
+		if s.get(kind, params.Get("id")) == nil {
+			return nil, notFound(params.Get("id"))
+		}
+		s.remove(kind, params.Get("id"))
+		return success, nil
+	}
+}
+
+func queryAsyncJobResult(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "jobid"); err != nil {
+		return nil, err
+	}
+	job, found := s.jobs[params.Get("jobid")]
+	if !found {
+		return nil, notFound(params.Get("jobid"))
+	}
+	resp := Resource{"jobid": job.id, "cmd": job.command, "jobprocstatus": 0, "jobresultcode": 0}
+	if job.pendingPolls > 0 {
+		job.pendingPolls--
+		resp["jobstatus"] = 0
+		return resp, nil
+	}
+	resp["jobstatus"] = 1
+	if job.failed {
+		resp["jobstatus"], resp["jobresultcode"] = 2, 530
+	}
+	resp["jobresulttype"] = "object"
+	resp["jobresult"] = job.result
+	return resp, nil
+}
+
+func listCapabilities(s *Server, _ Resource, _ url.Values) (interface{}, error) {
+	return Resource{"capability": s.capabilities}, nil
+}
+
+func listUsers(s *Server, caller Resource, params url.Values) (interface{}, error) {
+	var users []Resource
+	for _, u := range s.filter(KindUser, params) {
+		if caller["accounttype"] != 1 && (u["account"] != caller["account"] || u["domainid"] != caller["domainid"]) {
+			continue // Users only see the users of their own account unless they're admins.
+		}
+		u = u.copy()
+		delete(u, "secretkey")
+		users = append(users, u)
+	}
+	return listResponse(KindUser, users), nil
+}
+
+func getUser(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "userapikey"); err != nil {
+		return nil, err
+	}
+	for _, u := range s.resources[KindUser] {
+		if u.str("apikey") == params.Get("userapikey") {
+			u = u.copy()
+			delete(u, "secretkey")
+			return Resource{"user": u}, nil
+		}
+	}
+	return nil, invalidParameter("Unable to find user with api key %s", params.Get("userapikey"))
+}
+
+func getUserKeys(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "id"); err != nil {
+		return nil, err
+	}
+	u := s.get(KindUser, params.Get("id"))
+	if u == nil {
+		return nil, notFound(params.Get("id"))
+	}
+	return Resource{"userkeys": Resource{"apikey": u["apikey"], "secretkey": u["secretkey"], "apikeyaccess": true}}, nil
+}
+
+func createNetwork(s *Server, caller Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "name", "networkofferingid", "zoneid"); err != nil {
+		return nil, err
+	}
+	offering := s.get(KindNetworkOffering, params.Get("networkofferingid"))
+	if offering == nil {
+		return nil, notFound(params.Get("networkofferingid"))
+	}
+	zone := s.get(KindZone, params.Get("zoneid"))
+	if zone == nil {
+		return nil, notFound(params.Get("zoneid"))
+	}
+	if vpcID := params.Get("vpcid"); vpcID != "" {
+		if s.get(KindVPC, vpcID) == nil {
+			return nil, notFound(vpcID)
+		}
+		if offering["forvpc"] != true {
+			return nil, invalidParameter("Network offering %s can't be used for VPC networks", offering["name"])
+		}
+	} else if offering["forvpc"] == true {
+		return nil, invalidParameter("Network offering %s can be used for VPC networks only", offering["name"])
+	}
+
+	// VPC tiers deny all traffic unless created with another ACL list.
+	var aclList Resource
+	if params.Get("vpcid") != "" {
+		aclID := params.Get("aclid")
+		if aclID == "" {
+			aclID = s.filter(KindNetworkACLList, url.Values{"name": {"default_deny"}})[0].str("id")
+		}
+		if aclList = s.get(KindNetworkACLList, aclID); aclList == nil {
+			return nil, notFound(aclID)
+		}
+	}
+
+	gateway, netmask := params.Get("gateway"), params.Get("netmask")
+	if gateway == "" {
+		gateway = fmt.Sprintf("10.1.%d.1", len(s.resources[KindNetwork])+1)
+	}
+	if netmask == "" {
+		netmask = "255.255.255.0"
+	}
+	mask := net.IPMask(net.ParseIP(netmask).To4())
+	gatewayIP := net.ParseIP(gateway).To4()
+	if mask == nil || gatewayIP == nil {
+		return nil, invalidParameter("Invalid gateway %s or netmask %s", gateway, netmask)
+	}
+	cidr := &net.IPNet{IP: gatewayIP.Mask(mask), Mask: mask}
+
+	displayText := params.Get("displaytext")
+	if displayText == "" {
+		displayText = params.Get("name")
+	}
+	network := s.add(KindNetwork, owned(caller, params, Resource{
+		"name":                params.Get("name"),
+		"displaytext":         displayText,
+		"networkofferingid":   offering["id"],
+		"networkofferingname": offering["name"],
+		"zoneid":              zone["id"],
+		"zonename":            zone["name"],
+		"type":                offering["guestiptype"],
+		"traffictype":         "Guest",
+		"cidr":                cidr.String(),
+		"gateway":             gateway,
+		"netmask":             netmask,
+		"vpcid":               params.Get("vpcid"),
+		"egressdefaultpolicy": offering["egressdefaultpolicy"],
+		"ip4routing":          params.Get("routingmode"),
+		"state":               "Allocated",
+	}))
+	// Dual-stack networks only get an IPv6 subnet when one is requested.
+	if ip6CIDR := params.Get("ip6cidr"); ip6CIDR != "" {
+		if _, _, err := net.ParseCIDR(ip6CIDR); err != nil {
+			return nil, invalidParameter("Invalid ip6cidr %s", ip6CIDR)
+		}
+		network["ip6cidr"] = ip6CIDR
+		network["ip6gateway"] = params.Get("ip6gateway")
+	}
+	if aclList != nil {
+		network["aclid"] = aclList["id"]
+		network["aclname"] = aclList["name"]
+	}
+	return Resource{"network": network}, nil
+}
+
+func deleteNetwork(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "id"); err != nil {
+		return nil, err
+	}
+	id := params.Get("id")
+	if s.get(KindNetwork, id) == nil {
+		return nil, notFound(id)
+	}
+	for _, vm := range s.resources[KindVirtualMachine] {
+		if vmInNetwork(vm, id) {
+			return nil, &apiError{code: 530, text: fmt.Sprintf(
+				"Can't delete the network, not all user vms are expunged. Vm %s is in %s state", vm["name"], vm["state"])}
+		}
+	}
+
+	for _, kind := range []string{
+		KindLoadBalancerRule, KindFirewallRule, KindEgressFirewallRule, KindRoutingFirewallRule, KindIPv6FirewallRule,
+	} {
+		for _, r := range s.filter(kind, url.Values{"networkid": {id}}) {
+			if kind == KindLoadBalancerRule {
+				s.removeLoadBalancerRule(r.str("id"))
+			} else {
+				s.remove(kind, r.str("id"))
+				s.removeTags(r.str("id"))
+			}
+		}
+	}
+	for _, ip := range s.resources[KindPublicIPAddress] {
+		if ip["associatednetworkid"] == id {
+			releaseIP(ip)
+		}
+	}
+	s.remove(KindNetwork, id)
+	s.removeTags(id)
+	return success, nil
+}
+
+func createVPC(s *Server, caller Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "cidr", "name", "vpcofferingid", "zoneid"); err != nil {
+		return nil, err
+	}
+	offering := s.get(KindVPCOffering, params.Get("vpcofferingid"))
+	if offering == nil {
+		return nil, notFound(params.Get("vpcofferingid"))
+	}
+	zone := s.get(KindZone, params.Get("zoneid"))
+	if zone == nil {
+		return nil, notFound(params.Get("zoneid"))
+	}
+	if _, _, err := net.ParseCIDR(params.Get("cidr")); err != nil {
+		return nil, invalidParameter("Invalid CIDR %s", params.Get("cidr"))
+	}
+	displayText := params.Get("displaytext")
+	if displayText == "" {
+		displayText = params.Get("name")
+	}
+	vpc := s.add(KindVPC, owned(caller, params, Resource{
+		"name":            params.Get("name"),
+		"displaytext":     displayText,
+		"cidr":            params.Get("cidr"),
+		"vpcofferingid":   offering["id"],
+		"vpcofferingname": offering["name"],
+		"zoneid":          zone["id"],
+		"zonename":        zone["name"],
+		"state":           "Enabled",
+	}))
+	return &asyncResult{key: "vpc", obj: vpc}, nil
+}
+
+func deleteVPC(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "id"); err != nil {
+		return nil, err
+	}
+	id := params.Get("id")
+	if s.get(KindVPC, id) == nil {
+		return nil, notFound(id)
+	}
+	var networks int
+	for _, network := range s.resources[KindNetwork] { // Networks outside of VPCs don't have a vpcid to filter on.
+		if network["vpcid"] == id {
+			networks++
+		}
+	}
+	if networks > 0 {
+		return nil, &apiError{code: 530, text: fmt.Sprintf("Can't delete VPC %s as it has %d network(s)", id, networks)}
+	}
+	for _, ip := range s.resources[KindPublicIPAddress] {
+		if ip["vpcid"] == id {
+			releaseIP(ip)
+		}
+	}
+	var aclListIDs []string
+	for _, list := range s.resources[KindNetworkACLList] {
+		if list["vpcid"] == id {
+			aclListIDs = append(aclListIDs, list.str("id"))
+		}
+	}
+	for _, aclListID := range aclListIDs {
+		s.removeNetworkACLList(aclListID)
+	}
+	s.remove(KindVPC, id)
+	s.removeTags(id)
+	return success, nil
+}
+
+func createNetworkACLList(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "name", "vpcid"); err != nil {
+		return nil, err
+	}
+	vpc := s.get(KindVPC, params.Get("vpcid"))
+	if vpc == nil {
+		return nil, notFound(params.Get("vpcid"))
+	}
+	list := s.add(KindNetworkACLList, Resource{
+		"name":        params.Get("name"),
+		"description": params.Get("description"),
+		"vpcid":       vpc["id"],
+		"vpcname":     vpc["name"],
+	})
+	return &asyncResult{key: KindNetworkACLList, obj: list}, nil
+}
+
+func deleteNetworkACLList(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "id"); err != nil {
+		return nil, err
+	}
+	id := params.Get("id")
+	list := s.get(KindNetworkACLList, id)
+	if list == nil {
+		return nil, notFound(id)
+	}
+	if list.str("vpcid") == "" {
+		return nil, invalidParameter("Default ACL cannot be removed")
+	}
+	for _, network := range s.resources[KindNetwork] {
+		if network["aclid"] == id {
+			return nil, invalidParameter("ACL is still associated with network %s", network["name"])
+		}
+	}
+	s.removeNetworkACLList(id)
+	return success, nil
+}
+
+// removeNetworkACLList removes a network ACL list along with its items.
+func (s *Server) removeNetworkACLList(id string) {
+	for _, item := range s.filter(KindNetworkACL, url.Values{"aclid": {id}}) {
+		s.remove(KindNetworkACL, item.str("id"))
+	}
+	s.remove(KindNetworkACLList, id)
+	s.removeTags(id)
+}
+
+func replaceNetworkACLList(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "aclid", "networkid"); err != nil {
+		return nil, err
+	}
+	list := s.get(KindNetworkACLList, params.Get("aclid"))
+	if list == nil {
+		return nil, notFound(params.Get("aclid"))
+	}
+	network := s.get(KindNetwork, params.Get("networkid"))
+	if network == nil {
+		return nil, notFound(params.Get("networkid"))
+	}
+	if network.str("vpcid") == "" {
+		return nil, invalidParameter("Network %s is not part of a VPC", network["name"])
+	}
+	if vpcID := list.str("vpcid"); vpcID != "" && vpcID != network.str("vpcid") {
+		return nil, invalidParameter("Network ACL list %s does not belong to the VPC of network %s", list["name"], network["name"])
+	}
+	network["aclid"] = list["id"]
+	network["aclname"] = list["name"]
+	return success, nil
+}
+
+func createNetworkACL(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "protocol", "aclid"); err != nil {
+		return nil, err
+	}
+	list := s.get(KindNetworkACLList, params.Get("aclid"))
+	if list == nil {
+		return nil, notFound(params.Get("aclid"))
+	}
+	if list.str("vpcid") == "" {
+		return nil, invalidParameter("Default ACL cannot be modified")
+	}
+
+	items := s.filter(KindNetworkACL, url.Values{"aclid": {list.str("id")}})
+	number := 1
+	for _, item := range items {
+		number = max(number, item["number"].(int)+1)
+	}
+	if n := params.Get("number"); n != "" {
+		var err error
+		if number, err = strconv.Atoi(n); err != nil {
+			return nil, invalidParameter("Invalid number %s", n)
+		}
+		for _, item := range items {
+			if item["number"] == number {
+				return nil, invalidParameter("ACL item with number %d already exists in ACL %s", number, list["name"])
+			}
+		}
+	}
+
+	action, trafficType, cidrList := params.Get("action"), params.Get("traffictype"), params.Get("cidrlist")
+	if action == "" {
+		action = "Allow"
+	}
+	if trafficType == "" {
+		trafficType = "Ingress"
+	}
+	if cidrList == "" {
+		cidrList = "0.0.0.0/0"
+	}
+	item := s.add(KindNetworkACL, Resource{
+		"aclid":       list["id"],
+		"aclname":     list["name"],
+		"number":      number,
+		"action":      action,
+		"traffictype": trafficType,
+		"protocol":    params.Get("protocol"),
+		"startport":   params.Get("startport"),
+		"endport":     params.Get("endport"),
+		"cidrlist":    cidrList,
+		"reason":      params.Get("reason"),
+		"state":       "Active",
+	})
+	return &asyncResult{key: KindNetworkACL, obj: item}, nil
+}
+
+func associateIPAddress(s *Server, caller Resource, params url.Values) (interface{}, error) {
+	networkID, vpcID := params.Get("networkid"), params.Get("vpcid")
+	if networkID == "" && vpcID == "" {
+		return nil, invalidParameter("One of the parameters networkid or vpcid is required")
+	}
+	if networkID != "" && s.get(KindNetwork, networkID) == nil {
+		return nil, notFound(networkID)
+	}
+	if vpcID != "" && s.get(KindVPC, vpcID) == nil {
+		return nil, notFound(vpcID)
+	}
+
+	var ip Resource
+	for _, candidate := range s.resources[KindPublicIPAddress] {
+		if candidate["forvirtualnetwork"] != true {
+			continue
+		}
+		if addr := params.Get("ipaddress"); addr != "" {
+			if candidate["ipaddress"] == addr {
+				ip = candidate
+				break
+			}
+		} else if candidate["state"] == "Free" {
+			ip = candidate
+			break
+		}
+	}
+	if ip == nil {
+		return nil, &apiError{code: 533, text: "Insufficient address capacity"}
+	}
+	if ip["state"] != "Free" {
+		return nil, invalidParameter("Unable to associate IP address %s: it is already allocated", ip["ipaddress"])
+	}
+
+	owned(caller, params, ip)
+	ip["state"] = "Allocated"
+	ip["allocated"] = time.Now().UTC().Format("2006-01-02T15:04:05-0700")
+	ip["associatednetworkid"] = networkID
+	ip["vpcid"] = vpcID
+	if network := s.get(KindNetwork, networkID); network != nil {
+		ip["associatednetworkname"] = network["name"]
+	}
+	return &asyncResult{key: "ipaddress", obj: ip}, nil
+}
+
+func disassociateIPAddress(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "id"); err != nil {
+		return nil, err
+	}
+	ip := s.get(KindPublicIPAddress, params.Get("id"))
+	if ip == nil {
+		return nil, notFound(params.Get("id"))
+	}
+	if ip["issourcenat"] == true {
+		return nil, invalidParameter("IP address id=%s is source nat ip address, can't disassociate it", ip["id"])
+	}
+	for _, rule := range s.filter(KindLoadBalancerRule, url.Values{"publicipid": {ip.str("id")}}) {
+		s.removeLoadBalancerRule(rule.str("id"))
+	}
+	releaseIP(ip)
+	s.removeTags(ip.str("id"))
+	return success, nil
+}
+
+// listPublicIPAddresses lists public IPs. Like CloudStack it only lists allocated addresses unless
+// allocatedonly=false is passed.
+func listPublicIPAddresses(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	allocatedOnly := params.Get("allocatedonly") != "false"
+	var ips []Resource
+	for _, ip := range s.filter(KindPublicIPAddress, params) {
+		if !allocatedOnly || ip["state"] != "Free" {
+			ips = append(ips, ip)
+		}
+	}
+	return listResponse(KindPublicIPAddress, ips), nil
+}
+
+func releaseIP(ip Resource) {
+	for _, field := range []string{"allocated", "associatednetworkid", "associatednetworkname", "vpcid", "account", "domainid", "domain", "projectid"} {
+		delete(ip, field)
+	}
+	ip["state"] = "Free"
+}
+
+func createLoadBalancerRule(s *Server, caller Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "algorithm", "name", "privateport", "publicport", "publicipid"); err != nil {
+		return nil, err
+	}
+	ip := s.get(KindPublicIPAddress, params.Get("publicipid"))
+	if ip == nil {
+		return nil, notFound(params.Get("publicipid"))
+	}
+	if ip["state"] != "Allocated" {
+		return nil, invalidParameter("Unable to create load balancer rule; IP address %s is not allocated", ip["ipaddress"])
+	}
+	networkID := params.Get("networkid")
+	if networkID == "" {
+		networkID = ip.str("associatednetworkid")
+	}
+	for _, rule := range s.filter(KindLoadBalancerRule, url.Values{"publicipid": {ip.str("id")}}) {
+		if rule["publicport"] == params.Get("publicport") {
+			return nil, &apiError{code: 537, text: fmt.Sprintf(
+				"The range specified, %s-%s, conflicts with rule %s which has %s-%s",
+				params.Get("publicport"), params.Get("publicport"), rule["id"], rule["publicport"], rule["publicport"])}
+		}
+	}
+	protocol := params.Get("protocol")
+	if protocol == "" {
+		protocol = "tcp"
+	}
+	rule := s.add(KindLoadBalancerRule, owned(caller, params, Resource{
+		"name":        params.Get("name"),
+		"description": params.Get("description"),
+		"algorithm":   params.Get("algorithm"),
+		"publicipid":  ip["id"],
+		"publicip":    ip["ipaddress"],
+		"publicport":  params.Get("publicport"),
+		"privateport": params.Get("privateport"),
+		"networkid":   networkID,
+		"protocol":    protocol,
+		"cidrlist":    params.Get("cidrlist"),
+		"zoneid":      ip["zoneid"],
+		"zonename":    ip["zonename"],
+		"state":       "Active",
+	}))
+	// Like CloudStack, open the public port to all sources unless asked not to. VPCs are controlled by network ACLs.
+	if network := s.get(KindNetwork, networkID); params.Get("openfirewall") != "false" &&
+		(network == nil || network.str("vpcid") == "") {
+		port, _ := strconv.Atoi(params.Get("publicport"))
+		s.add(KindFirewallRule, owned(caller, params, Resource{
+			"protocol":      protocol,
+			"startport":     port,
+			"endport":       port,
+			"cidrlist":      "0.0.0.0/0",
+			"ipaddressid":   ip["id"],
+			"ipaddress":     ip["ipaddress"],
+			"networkid":     networkID,
+			"relatedruleid": rule["id"],
+			"state":         "Active",
+		}))
+	}
+	return &asyncResult{key: "loadbalancer", obj: rule}, nil
+}
+
+func deleteLoadBalancerRule(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "id"); err != nil {
+		return nil, err
+	}
+	if s.get(KindLoadBalancerRule, params.Get("id")) == nil {
+		return nil, notFound(params.Get("id"))
+	}
+	s.removeLoadBalancerRule(params.Get("id"))
+	return success, nil
+}
+
+// removeLoadBalancerRule removes a load balancer rule along with its instance assignments and policies.
+func (s *Server) removeLoadBalancerRule(id string) {
+	s.remove(KindLoadBalancerRule, id)
+	s.removeTags(id)
+	delete(s.lbInstances, id)
+	var related []string
+	for _, rule := range s.resources[KindFirewallRule] {
+		if rule["relatedruleid"] == id {
+			related = append(related, rule.str("id"))
+		}
+	}
+	for _, ruleID := range related {
+		s.remove(KindFirewallRule, ruleID)
+		s.removeTags(ruleID)
+	}
+	for _, kind := range []string{KindLBHealthCheckPolicy, KindLBStickinessPolicy} {
+		for _, policy := range s.filter(kind, url.Values{"lbruleid": {id}}) {
+			s.remove(kind, policy.str("id"))
+		}
+	}
+}
+
+func updateLoadBalancerRule(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "id"); err != nil {
+		return nil, err
+	}
+	rule := s.get(KindLoadBalancerRule, params.Get("id"))
+	if rule == nil {
+		return nil, notFound(params.Get("id"))
+	}
+	for _, field := range []string{"algorithm", "name", "description"} {
+		if value := params.Get(field); value != "" {
+			rule[field] = value
+		}
+	}
+	return &asyncResult{key: "loadbalancer", obj: rule}, nil
+}
+
+func createLBHealthCheckPolicy(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "lbruleid"); err != nil {
+		return nil, err
+	}
+	if s.get(KindLoadBalancerRule, params.Get("lbruleid")) == nil {
+		return nil, notFound(params.Get("lbruleid"))
+	}
+	if len(s.filter(KindLBHealthCheckPolicy, url.Values{"lbruleid": {params.Get("lbruleid")}})) > 0 {
+		return nil, invalidParameter("HealthCheck policy already exists on LB rule %s", params.Get("lbruleid"))
+	}
+	intParam := func(name string, fallback int) int {
+		if v, err := strconv.Atoi(params.Get(name)); err == nil {
+			return v
+		}
+		return fallback
+	}
+	pingPath := params.Get("pingpath")
+	if pingPath == "" {
+		pingPath = "/"
+	}
+	policy := s.add(KindLBHealthCheckPolicy, Resource{
+		"lbruleid":                params.Get("lbruleid"),
+		"pingpath":                pingPath,
+		"healthcheckinterval":     intParam("intervaltime", 5),
+		"responsetime":            intParam("responsetimeout", 2),
+		"healthcheckthresshold":   intParam("healthythreshold", 2),
+		"unhealthcheckthresshold": intParam("unhealthythreshold", 10),
+		"state":                   "Active",
+	})
+	return &asyncResult{key: "healthcheckpolicies", obj: Resource{
+		"lbruleid":          params.Get("lbruleid"),
+		"healthcheckpolicy": []Resource{policy},
+	}}, nil
+}
+
+func createLBStickinessPolicy(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "lbruleid", "methodname", "name"); err != nil {
+		return nil, err
+	}
+	if s.get(KindLoadBalancerRule, params.Get("lbruleid")) == nil {
+		return nil, notFound(params.Get("lbruleid"))
+	}
+	if len(s.filter(KindLBStickinessPolicy, url.Values{"lbruleid": {params.Get("lbruleid")}})) > 0 {
+		return nil, invalidParameter("Stickiness policy already exists on LB rule %s", params.Get("lbruleid"))
+	}
+	policyParams := map[string]string{}
+	for _, p := range indexedParams(params, "param") {
+		policyParams[p["key"]] = p["value"]
+	}
+	policy := s.add(KindLBStickinessPolicy, Resource{
+		"lbruleid":   params.Get("lbruleid"),
+		"name":       params.Get("name"),
+		"methodname": params.Get("methodname"),
+		"params":     policyParams,
+		"state":      "Active",
+	})
+	return &asyncResult{key: "stickinesspolicies", obj: Resource{
+		"lbruleid":         params.Get("lbruleid"),
+		"name":             params.Get("name"),
+		"stickinesspolicy": []Resource{policy},
+	}}, nil
+}
+
+// listLBPolicies returns a handler listing the policies of kind of a load balancer rule. CloudStack groups them
+// under the rule, reporting the policies under key.
+func listLBPolicies(kind, key string) handler {
+	return func(s *Server, _ Resource, params url.Values) (interface{}, error) {
+		ruleFilter, policyFilter := url.Values{}, url.Values{}
+		if ruleID := params.Get("lbruleid"); ruleID != "" {
+			ruleFilter.Set("id", ruleID)
+		}
+		if id := params.Get("id"); id != "" {
+			policyFilter.Set("id", id)
+		}
+		var groups []Resource
+		for _, rule := range s.filter(KindLoadBalancerRule, ruleFilter) {
+			policyFilter.Set("lbruleid", rule.str("id"))
+			if policies := s.filter(kind, policyFilter); len(policies) > 0 {
+				groups = append(groups, Resource{"lbruleid": rule["id"], key: policies})
+			}
+		}
+		return listResponse(kind, groups), nil
+	}
+}
+
+func assignToLoadBalancerRule(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	rule, vmIDs, err := s.loadBalancerRuleAndVMs(params)
+	if err != nil {
+		return nil, err
+	}
+	for _, vmID := range vmIDs {
+		if !contains(s.lbInstances[rule.str("id")], vmID) {
+			s.lbInstances[rule.str("id")] = append(s.lbInstances[rule.str("id")], vmID)
+		}
+	}
+	return success, nil
+}
+
+func removeFromLoadBalancerRule(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	rule, vmIDs, err := s.loadBalancerRuleAndVMs(params)
+	if err != nil {
+		return nil, err
+	}
+	s.lbInstances[rule.str("id")] = without(s.lbInstances[rule.str("id")], vmIDs...)
+	return success, nil
+}
+
+func (s *Server) loadBalancerRuleAndVMs(params url.Values) (Resource, []string, error) {
+	if err := required(params, "id"); err != nil {
+		return nil, nil, err
+	}
+	rule := s.get(KindLoadBalancerRule, params.Get("id"))
+	if rule == nil {
+		return nil, nil, notFound(params.Get("id"))
+	}
+	vmIDs := splitList(params.Get("virtualmachineids"))
+	for _, vmID := range vmIDs {
+		if s.get(KindVirtualMachine, vmID) == nil {
+			return nil, nil, notFound(vmID)
+		}
+	}
+	return rule, vmIDs, nil
+}
+
+func listLoadBalancerRuleInstances(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "id"); err != nil {
+		return nil, err
+	}
+	if s.get(KindLoadBalancerRule, params.Get("id")) == nil {
+		return nil, notFound(params.Get("id"))
+	}
+	var vms []Resource
+	for _, vmID := range s.lbInstances[params.Get("id")] {
+		if vm := s.get(KindVirtualMachine, vmID); vm != nil {
+			vms = append(vms, vm)
+		}
+	}
+	return listResponse("loadbalancerruleinstance", vms), nil
+}
+
+// createFirewallRule returns a handler creating ingress, egress or routing firewall rules of kind, which
+// are reported under key.
+func createFirewallRule(kind, key string) handler {
+	return func(s *Server, caller Resource, params url.Values) (interface{}, error) {
+		if err := required(params, "protocol"); err != nil {
+			return nil, err
+		}
+		rule := Resource{
+			"protocol": strings.ToLower(params.Get("protocol")),
+			"cidrlist": params.Get("cidrlist"),
+			"state":    "Active",
+		}
+		if kind == KindFirewallRule {
+			if err := required(params, "ipaddressid"); err != nil {
+				return nil, err
+			}
+			ip := s.get(KindPublicIPAddress, params.Get("ipaddressid"))
+			if ip == nil {
+				return nil, notFound(params.Get("ipaddressid"))
+			}
+			rule["ipaddressid"] = ip["id"]
+			rule["ipaddress"] = ip["ipaddress"]
+			rule["networkid"] = ip["associatednetworkid"]
+		} else {
+			if err := required(params, "networkid"); err != nil {
+				return nil, err
+			}
+			if s.get(KindNetwork, params.Get("networkid")) == nil {
+				return nil, notFound(params.Get("networkid"))
+			}
+			rule["networkid"] = params.Get("networkid")
+		}
+		if kind == KindIPv6FirewallRule {
+			rule["traffictype"] = params.Get("traffictype")
+		}
+		for _, field := range []string{"startport", "endport", "icmptype", "icmpcode"} {
+			if v := params.Get(field); v != "" {
+				n, err := strconv.Atoi(v)
+				if err != nil {
+					return nil, invalidParameter("Invalid value %s for parameter %s", v, field)
+				}
+				rule[field] = n
+			}
+		}
+		if rule["startport"] != nil && rule["endport"] == nil {
+			rule["endport"] = rule["startport"]
+		}
+
+		for _, existing := range s.resources[kind] {
+			if existing["networkid"] == rule["networkid"] && existing["ipaddressid"] == rule["ipaddressid"] &&
+				existing["protocol"] == rule["protocol"] && existing["cidrlist"] == rule["cidrlist"] &&
+				existing["startport"] == rule["startport"] && existing["endport"] == rule["endport"] &&
+				existing["icmptype"] == rule["icmptype"] && existing["icmpcode"] == rule["icmpcode"] {
+				return nil, &apiError{code: 537, text: fmt.Sprintf("New rule conflicts with existing rule id=%s", existing["id"])}
+			}
+		}
+
+		return &asyncResult{key: key, obj: s.add(kind, owned(caller, params, rule))}, nil
+	}
+}
+
+func createAffinityGroup(s *Server, caller Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "name", "type"); err != nil {
+		return nil, err
+	}
+	if !contains(s.affinityGroupTypes(), params.Get("type")) {
+		return nil, invalidParameter("Unable to create affinity group, invalid affinity group type %s", params.Get("type"))
+	}
+	for _, group := range s.resources[KindAffinityGroup] {
+		if strings.EqualFold(group.str("name"), params.Get("name")) && group["account"] == caller["account"] &&
+			group["domainid"] == caller["domainid"] {
+			return nil, invalidParameter(
+				"Unable to create affinity group, a group with name %s already exists.", params.Get("name"))
+		}
+	}
+	group := s.add(KindAffinityGroup, owned(caller, params, Resource{
+		"name":              params.Get("name"),
+		"type":              params.Get("type"),
+		"description":       params.Get("description"),
+		"virtualmachineIds": []string{},
+	}))
+	return &asyncResult{key: "affinitygroup", obj: group}, nil
+}
+
+func listAffinityGroupTypes(s *Server, _ Resource, _ url.Values) (interface{}, error) {
+	var types []Resource
+	for _, t := range s.affinityGroupTypes() {
+		types = append(types, Resource{"type": t})
+	}
+	return listResponse("affinitygrouptype", types), nil
+}
+
+// affinityGroupTypes returns the host affinity group types of the server's CloudStack version. The non-strict types
+// were added in 4.18.
+func (s *Server) affinityGroupTypes() []string {
+	types := []string{"host affinity", "host anti-affinity"}
+	var major, minor int
+	if _, err := fmt.Sscanf(fmt.Sprint(s.capabilities["cloudstackversion"]), "%d.%d", &major, &minor); err != nil {
+		return types
+	}
+	if major > 4 || (major == 4 && minor >= 18) {
+		types = append(types, "non-strict host affinity", "non-strict host anti-affinity")
+	}
+	return types
+}
+
+func deleteAffinityGroup(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	group := s.get(KindAffinityGroup, params.Get("id"))
+	if group == nil && params.Get("name") != "" {
+		group = s.findByName(KindAffinityGroup, params.Get("name"))
+	}
+	if group == nil {
+		return nil, invalidParameter("Unable to find affinity group %s%s", params.Get("id"), params.Get("name"))
+	}
+	for _, vm := range s.resources[KindVirtualMachine] {
+		s.setVMAffinityGroups(vm, without(affinityGroupIDs(vm), group.str("id")))
+	}
+	s.remove(KindAffinityGroup, group.str("id"))
+	s.removeTags(group.str("id"))
+	return success, nil
+}
+
+func updateVMAffinityGroup(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "id"); err != nil {
+		return nil, err
+	}
+	vm := s.get(KindVirtualMachine, params.Get("id"))
+	if vm == nil {
+		return nil, notFound(params.Get("id"))
+	}
+	if vm["state"] != "Stopped" {
+		return nil, invalidParameter(
+			"Unable to apply affinity groups to vm %s, vm must be in Stopped state but is in %s state", vm["name"], vm["state"])
+	}
+	groupIDs, err := s.resolveAffinityGroups(params)
+	if err != nil {
+		return nil, err
+	}
+	s.setVMAffinityGroups(vm, groupIDs)
+	return &asyncResult{key: "virtualmachine", obj: vm}, nil
+}
+
+// resolveAffinityGroups returns the IDs of the groups passed as affinitygroupids or affinitygroupnames.
+func (s *Server) resolveAffinityGroups(params url.Values) ([]string, error) {
+	groupIDs := splitList(params.Get("affinitygroupids"))
+	for _, id := range groupIDs {
+		if s.get(KindAffinityGroup, id) == nil {
+			return nil, notFound(id)
+		}
+	}
+	for _, name := range splitList(params.Get("affinitygroupnames")) {
+		group := s.findByName(KindAffinityGroup, name)
+		if group == nil {
+			return nil, invalidParameter("Unable to find affinity group by name %s", name)
+		}
+		groupIDs = append(groupIDs, group.str("id"))
+	}
+	return groupIDs, nil
+}
+
+// setVMAffinityGroups sets the affinity groups of a VM and keeps the groups' member lists in sync.
+func (s *Server) setVMAffinityGroups(vm Resource, groupIDs []string) {
+	groups := []Resource{}
+	for _, group := range s.resources[KindAffinityGroup] {
+		members, _ := group["virtualmachineIds"].([]string)
+		members = without(members, vm.str("id"))
+		if contains(groupIDs, group.str("id")) {
+			members = append(members, vm.str("id"))
+			groups = append(groups, Resource{"id": group["id"], "name": group["name"], "type": group["type"]})
+		}
+		group["virtualmachineIds"] = members
+	}
+	vm["affinitygroup"] = groups
+}
+
+func affinityGroupIDs(vm Resource) []string {
+	var ids []string
+	groups, _ := vm["affinitygroup"].([]Resource)
+	for _, group := range groups {
+		ids = append(ids, group.str("id"))
+	}
+	return ids
+}
+
+func createTags(s *Server, caller Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "resourceids", "resourcetype"); err != nil {
+		return nil, err
+	}
+	tags := indexedParams(params, "tags")
+	if len(tags) == 0 {
+		return nil, invalidParameter("Unable to execute API command createtags due to missing parameter tags")
+	}
+	for _, resourceID := range splitList(params.Get("resourceids")) {
+		for _, tag := range tags {
+			existing := s.filter(KindTag, url.Values{
+				"resourceid": {resourceID}, "resourcetype": {params.Get("resourcetype")}, "key": {tag["key"]}})
+			if len(existing) > 0 {
+				return nil, invalidParameter("tag %s already on %s with id %s", tag["key"], params.Get("resourcetype"), resourceID)
+			}
+			s.add(KindTag, owned(caller, params, Resource{
+				"key":          tag["key"],
+				"value":        tag["value"],
+				"resourceid":   resourceID,
+				"resourcetype": params.Get("resourcetype"),
+			}))
+		}
+	}
+	return success, nil
+}
+
+func deleteTags(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "resourceids", "resourcetype"); err != nil {
+		return nil, err
+	}
+	tags := indexedParams(params, "tags")
+	for _, resourceID := range splitList(params.Get("resourceids")) {
+		for _, existing := range s.filter(KindTag, url.Values{
+			"resourceid": {resourceID}, "resourcetype": {params.Get("resourcetype")}}) {
+			matched := len(tags) == 0
+			for _, tag := range tags {
+				if tag["key"] == existing["key"] && (tag["value"] == "" || tag["value"] == existing["value"]) {
+					matched = true
+				}
+			}
+			if matched {
+				s.remove(KindTag, existing.str("id"))
+			}
+		}
+	}
+	return success, nil
+}
+
+// removeTags removes all tags of a deleted resource.
+func (s *Server) removeTags(resourceID string) {
+	for _, tag := range s.filter(KindTag, url.Values{"resourceid": {resourceID}}) {
+		s.remove(KindTag, tag.str("id"))
+	}
+}
+
+func deployVirtualMachine(s *Server, caller Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "serviceofferingid", "templateid", "zoneid"); err != nil {
+		return nil, err
+	}
+	offering := s.get(KindServiceOffering, params.Get("serviceofferingid"))
+	if offering == nil {
+		return nil, notFound(params.Get("serviceofferingid"))
+	}
+	template := s.get(KindTemplate, params.Get("templateid"))
+	if template == nil {
+		return nil, notFound(params.Get("templateid"))
+	}
+	zone := s.get(KindZone, params.Get("zoneid"))
+	if zone == nil {
+		return nil, notFound(params.Get("zoneid"))
+	}
+	var diskOffering Resource
+	if id := params.Get("diskofferingid"); id != "" {
+		if diskOffering = s.get(KindDiskOffering, id); diskOffering == nil {
+			return nil, notFound(id)
+		}
+		if diskOffering["iscustomized"] == true && params.Get("size") == "" {
+			return nil, invalidParameter("Disk offering %s requires size parameter.", diskOffering["name"])
+		}
+	}
+	rootDiskSize, _ := template["size"].(int64)
+	var rootDiskOffering Resource
+	if id := params.Get("overridediskofferingid"); id != "" {
+		if rootDiskOffering = s.get(KindDiskOffering, id); rootDiskOffering == nil {
+			return nil, notFound(id)
+		}
+	}
+	if v := params.Get("rootdisksize"); v != "" {
+		size, _ := strconv.ParseInt(v, 10, 64)
+		if size<<30 < rootDiskSize {
+			return nil, invalidParameter("Root disk size should be greater than or equal to the template size")
+		}
+		rootDiskSize = size << 30
+	}
+	host, err := s.selectHost(zone, offering, params)
+	if err != nil {
+		return nil, err
+	}
+	groupIDs, err := s.resolveAffinityGroups(params)
+	if err != nil {
+		return nil, err
+	}
+
+	// Networks come either as a plain list of IDs or as a list of network ID and IP pairs.
+	requested := indexedParams(params, "iptonetworklist")
+	for _, id := range splitList(params.Get("networkids")) {
+		requested = append(requested, map[string]string{"networkid": id})
+	}
+	name := params.Get("name")
+	var nics, reservedIPs []Resource
+	for i, req := range requested {
+		network := s.get(KindNetwork, req["networkid"])
+		if network == nil {
+			return nil, notFound(req["networkid"])
+		}
+		for _, vm := range s.resources[KindVirtualMachine] {
+			if name != "" && vm["name"] == name && vmInNetwork(vm, network.str("id")) {
+				return nil, invalidParameter(
+					"The vm with hostName %s already exists in the network domain of network %s", name, network["name"])
+			}
+		}
+		ip := req["ip"]
+		if ip == "" {
+			ip = s.nextNetworkIP(network)
+		} else if s.ipInUse(network.str("id"), ip) {
+			return nil, &apiError{code: 533, text: fmt.Sprintf(
+				"Unable to deploy vm: IP address %s is already in use in network %s", ip, network["name"])}
+		}
+		if ip == "" {
+			return nil, &apiError{code: 533, text: fmt.Sprintf("Insufficient address capacity in network %s", network["name"])}
+		}
+		reservedIPs = append(reservedIPs, s.sharedNetworkIP(network.str("id"), ip))
+		nic := Resource{
+			"id":          newID(),
+			"networkid":   network["id"],
+			"networkname": network["name"],
+			"ipaddress":   ip,
+			"gateway":     network["gateway"],
+			"netmask":     network["netmask"],
+			"isdefault":   i == 0,
+			"type":        network["type"],
+			"traffictype": "Guest",
+		}
+		if ip6 := req["ipv6"]; ip6 != "" {
+			if network.str("ip6cidr") == "" {
+				return nil, invalidParameter("Network %s doesn't support IPv6", network["name"])
+			}
+			nic["ip6address"] = ip6
+			nic["ip6gateway"] = network["ip6gateway"]
+			nic["ip6cidr"] = network["ip6cidr"]
+		}
+		nics = append(nics, nic)
+		if network["state"] == "Allocated" {
+			network["state"] = "Implemented"
+		}
+	}
+
+	id := newID()
+	if name == "" {
+		name = "VM-" + id
+	}
+	displayName := params.Get("displayname")
+	if displayName == "" {
+		displayName = name
+	}
+	vm := owned(caller, params, Resource{
+		"id":                  id,
+		"name":                name,
+		"displayname":         displayName,
+		"state":               "Running",
+		"zoneid":              zone["id"],
+		"zonename":            zone["name"],
+		"templateid":          template["id"],
+		"templatename":        template["name"],
+		"serviceofferingid":   offering["id"],
+		"serviceofferingname": offering["name"],
+		"hostid":              host["id"],
+		"hostname":            host["name"],
+		"cpunumber":           offering["cpunumber"],
+		"cpuspeed":            offering["cpuspeed"],
+		"memory":              offering["memory"],
+		"hypervisor":          "Simulator",
+		"keypair":             params.Get("keypair"),
+		"userdata":            params.Get("userdata"),
+		"details":             detailsParam(params),
+		"nic":                 nics,
+		"created":             time.Now().UTC().Format("2006-01-02T15:04:05-0700"),
+	})
+	if diskOffering != nil {
+		vm["diskofferingid"] = diskOffering["id"]
+		vm["diskofferingname"] = diskOffering["name"]
+	}
+	s.add(KindVirtualMachine, vm)
+	s.setVMAffinityGroups(vm, groupIDs)
+	for _, ip := range reservedIPs {
+		if ip != nil {
+			ip["state"] = "Allocated"
+			ip["virtualmachineid"] = id
+		}
+	}
+
+	rootVolume := s.add(KindVolume, owned(caller, params, Resource{
+		"name":             "ROOT-" + id,
+		"type":             "ROOT",
+		"virtualmachineid": id,
+		"vmname":           name,
+		"zoneid":           zone["id"],
+		"size":             rootDiskSize,
+		"deviceid":         int64(0),
+		"state":            "Ready",
+	}))
+	if rootDiskOffering != nil {
+		rootVolume["diskofferingid"] = rootDiskOffering["id"]
+	}
+	if diskOffering != nil {
+		size, _ := diskOffering["disksize"].(int64)
+		if v := params.Get("size"); v != "" {
+			size, _ = strconv.ParseInt(v, 10, 64)
+		}
+		s.add(KindVolume, owned(caller, params, Resource{
+			"name":             "DATA-" + id,
+			"type":             "DATADISK",
+			"virtualmachineid": id,
+			"vmname":           name,
+			"zoneid":           zone["id"],
+			"diskofferingid":   diskOffering["id"],
+			"size":             size << 30,
+			"deviceid":         int64(1),
+			"state":            "Ready",
+		}))
+	}
+
+	return &asyncResult{key: "virtualmachine", obj: vm}, nil
+}
+
+// selectHost returns the first host of the zone with room for a VM with the offering, in the pod, cluster and host
+// the VM is pinned to, if any. Hosts without a memorytotal have unlimited capacity.
+func (s *Server) selectHost(zone, offering Resource, params url.Values) (Resource, error) {
+	filter := url.Values{"zoneid": {zone.str("id")}}
+	for param, key := range map[string]string{"podid": "podid", "clusterid": "clusterid", "hostid": "id"} {
+		if v := params.Get(param); v != "" {
+			filter.Set(key, v)
+		}
+	}
+	cpu, memory := number(offering["cpunumber"])*number(offering["cpuspeed"]), number(offering["memory"])<<20
+	for _, host := range s.filter(KindHost, filter) {
+		if host["memorytotal"] == nil {
+			return host, nil
+		}
+		cpuUsed, memoryUsed := s.hostUsage(host)
+		if cpuUsed+cpu <= number(host["cpunumber"])*number(host["cpuspeed"]) &&
+			memoryUsed+memory <= number(host["memorytotal"]) {
+			return host, nil
+		}
+	}
+	return nil, &apiError{code: 533, text: fmt.Sprintf("Unable to create a deployment for VM in zone %s", zone["name"])}
+}
+
+// hostUsage returns the CPU in MHz and the memory in bytes the running VMs of a host use.
+func (s *Server) hostUsage(host Resource) (cpu, memory int64) {
+	for _, vm := range s.filter(KindVirtualMachine, url.Values{"hostid": {host.str("id")}}) {
+		if vm["state"] == "Running" {
+			cpu += number(vm["cpunumber"]) * number(vm["cpuspeed"])
+			memory += number(vm["memory"]) << 20
+		}
+	}
+	return cpu, memory
+}
+
+// listCapacity reports the CPU and memory capacity of zones, summed over their hosts. Like in CloudStack, only
+// admins may list capacity.
+func listCapacity(s *Server, caller Resource, params url.Values) (interface{}, error) {
+	if caller["accounttype"] != 1 {
+		return nil, &apiError{code: 432, text: "The given command:listCapacity does not exist or it is not available for user"}
+	}
+	zones := s.resources[KindZone]
+	if id := params.Get("zoneid"); id != "" {
+		zones = s.filter(KindZone, url.Values{"id": {id}})
+	}
+
+	var capacities []Resource
+	for _, zone := range zones {
+		var cpuTotal, cpuUsed, memoryTotal, memoryUsed int64
+		for _, host := range s.filter(KindHost, url.Values{"zoneid": {zone.str("id")}}) {
+			cpu, memory := s.hostUsage(host)
+			cpuTotal += number(host["cpunumber"]) * number(host["cpuspeed"])
+			memoryTotal += number(host["memorytotal"])
+			cpuUsed += cpu
+			memoryUsed += memory
+		}
+		for capacityType, usage := range map[int][2]int64{0: {memoryTotal, memoryUsed}, 1: {cpuTotal, cpuUsed}} {
+			if t := params.Get("type"); t != "" && t != strconv.Itoa(capacityType) {
+				continue
+			}
+			capacities = append(capacities, Resource{
+				"zoneid":            zone["id"],
+				"zonename":          zone["name"],
+				"type":              capacityType,
+				"capacitytotal":     usage[0],
+				"capacityused":      usage[1],
+				"capacityallocated": usage[1],
+			})
+		}
+	}
+	sort.Slice(capacities, func(i, j int) bool { return capacities[i]["type"].(int) < capacities[j]["type"].(int) })
+	return listResponse("capacity", capacities), nil
+}
+
+func createVolume(s *Server, caller Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "name", "diskofferingid", "zoneid"); err != nil {
+		return nil, err
+	}
+	diskOffering := s.get(KindDiskOffering, params.Get("diskofferingid"))
+	if diskOffering == nil {
+		return nil, notFound(params.Get("diskofferingid"))
+	}
+	size, _ := diskOffering["disksize"].(int64)
+	if diskOffering["iscustomized"] == true {
+		if params.Get("size") == "" {
+			return nil, invalidParameter("Size is required for a customized disk offering")
+		}
+		size, _ = strconv.ParseInt(params.Get("size"), 10, 64)
+	}
+	volume := s.add(KindVolume, owned(caller, params, Resource{
+		"name":           params.Get("name"),
+		"type":           "DATADISK",
+		"zoneid":         params.Get("zoneid"),
+		"diskofferingid": diskOffering["id"],
+		"size":           size << 30,
+		"state":          "Allocated",
+	}))
+	for _, key := range []string{"miniops", "maxiops"} {
+		if v := params.Get(key); v != "" {
+			volume[key], _ = strconv.ParseInt(v, 10, 64)
+		}
+	}
+	return &asyncResult{key: "volume", obj: volume}, nil
+}
+
+// attachVolume attaches a data disk volume. Like CloudStack, it reserves device ID 3 for the CD-ROM drive.
+func attachVolume(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "id", "virtualmachineid"); err != nil {
+		return nil, err
+	}
+	volume := s.get(KindVolume, params.Get("id"))
+	if volume == nil {
+		return nil, notFound(params.Get("id"))
+	}
+	vm := s.get(KindVirtualMachine, params.Get("virtualmachineid"))
+	if vm == nil {
+		return nil, notFound(params.Get("virtualmachineid"))
+	}
+	if volume["virtualmachineid"] != nil {
+		return nil, invalidParameter("Volume %s is already attached to a VM", volume.str("id"))
+	}
+	if state := vm.str("state"); state != "Running" && state != "Stopped" {
+		return nil, invalidParameter("Volumes can only be attached to running or stopped VMs, VM %s is %s",
+			vm.str("id"), state)
+	}
+
+	used := map[int64]bool{}
+	for _, attached := range s.resources[KindVolume] {
+		if attached["virtualmachineid"] == vm["id"] {
+			deviceID, _ := attached["deviceid"].(int64)
+			used[deviceID] = true
+		}
+	}
+	deviceID := int64(1)
+	if v := params.Get("deviceid"); v != "" {
+		deviceID, _ = strconv.ParseInt(v, 10, 64)
+		if deviceID == 3 {
+			return nil, invalidParameter("deviceId 3 is reserved for CD-ROM")
+		} else if used[deviceID] {
+			return nil, invalidParameter("deviceId %d is used by VM %s", deviceID, vm.str("name"))
+		}
+	} else {
+		for used[deviceID] || deviceID == 3 {
+			deviceID++
+		}
+	}
+	volume["virtualmachineid"] = vm["id"]
+	volume["vmname"] = vm["name"]
+	volume["deviceid"] = deviceID
+	volume["state"] = "Ready"
+	return &asyncResult{key: "volume", obj: volume}, nil
+}
+
+func deleteVolume(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "id"); err != nil {
+		return nil, err
+	}
+	volume := s.get(KindVolume, params.Get("id"))
+	if volume == nil {
+		return nil, notFound(params.Get("id"))
+	}
+	if volume["virtualmachineid"] != nil {
+		return nil, invalidParameter("Please specify a volume that is not attached to any VM.")
+	}
+	s.remove(KindVolume, volume.str("id"))
+	s.removeTags(volume.str("id"))
+	return Resource{"success": true}, nil
+}
+
+func destroyVirtualMachine(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "id"); err != nil {
+		return nil, err
+	}
+	id := params.Get("id")
+	vm := s.get(KindVirtualMachine, id)
+	if vm == nil {
+		return nil, notFound(id)
+	}
+	for _, volumeID := range splitList(params.Get("volumeids")) {
+		s.remove(KindVolume, volumeID)
+		s.removeTags(volumeID)
+	}
+
+	if params.Get("expunge") != "true" {
+		vm["state"] = "Destroyed"
+		return &asyncResult{key: "virtualmachine", obj: vm}, nil
+	}
+
+	result := vm.copy()
+	result["state"] = "Expunging"
+	s.setVMAffinityGroups(vm, nil)
+	for ruleID, members := range s.lbInstances {
+		s.lbInstances[ruleID] = without(members, id)
+	}
+	for clusterID, members := range s.cksMembers {
+		s.cksMembers[clusterID] = without(members, id)
+	}
+	for _, ip := range s.resources[KindPublicIPAddress] {
+		if ip["virtualmachineid"] == id {
+			delete(ip, "virtualmachineid")
+			ip["state"] = "Free"
+		}
+	}
+	for _, volume := range s.filter(KindVolume, url.Values{"virtualmachineid": {id}}) {
+		if volume["type"] == "ROOT" {
+			s.remove(KindVolume, volume.str("id"))
+		} else {
+			delete(volume, "virtualmachineid")
+			delete(volume, "vmname")
+		}
+	}
+	s.remove(KindVirtualMachine, id)
+	s.removeTags(id)
+	return &asyncResult{key: "virtualmachine", obj: result}, nil
+}
+
+// setVirtualMachineState returns a handler that moves a VM into the given state.
+func setVirtualMachineState(state string) handler {
+	return func(s *Server, _ Resource, params url.Values) (interface{}, error) {
+		if err := required(params, "id"); err != nil {
+			return nil, err
+		}
+		vm := s.get(KindVirtualMachine, params.Get("id"))
+		if vm == nil {
+			return nil, notFound(params.Get("id"))
+		}
+		if vm["state"] == "Destroyed" {
+			return nil, invalidParameter("Unable to change the state of vm %s as it is Destroyed", vm["name"])
+		}
+		vm["state"] = state
+		return &asyncResult{key: "virtualmachine", obj: vm}, nil
+	}
+}
+
+// scaleVirtualMachine changes the service offering of a running or stopped VM.
+func scaleVirtualMachine(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "id", "serviceofferingid"); err != nil {
+		return nil, err
+	}
+	vm := s.get(KindVirtualMachine, params.Get("id"))
+	if vm == nil {
+		return nil, notFound(params.Get("id"))
+	}
+	offering := s.get(KindServiceOffering, params.Get("serviceofferingid"))
+	if offering == nil {
+		return nil, notFound(params.Get("serviceofferingid"))
+	}
+	if vm["state"] != "Running" && vm["state"] != "Stopped" {
+		return nil, invalidParameter("Unable to scale vm %s in state %s", vm["name"], vm["state"])
+	}
+	for _, key := range []string{"cpunumber", "cpuspeed", "memory"} {
+		vm[key] = offering[key]
+	}
+	vm["serviceofferingid"] = offering["id"]
+	vm["serviceofferingname"] = offering["name"]
+	return &asyncResult{key: "virtualmachine", obj: vm}, nil
+}
+
+// listVirtualMachines serves listVirtualMachines and listVirtualMachinesMetrics. The networkid filter
+// matches any of a VM's NICs.
+func listVirtualMachines(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	networkID := params.Get("networkid")
+	params.Del("networkid")
+	var vms []Resource
+	for _, vm := range s.filter(KindVirtualMachine, params) {
+		if networkID == "" || vmInNetwork(vm, networkID) {
+			vms = append(vms, vm)
+		}
+	}
+	return listResponse(KindVirtualMachine, vms), nil
+}
+
+func vmInNetwork(vm Resource, networkID string) bool {
+	nics, _ := vm["nic"].([]Resource)
+	for _, nic := range nics {
+		if nic["networkid"] == networkID {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) ipInUse(networkID, ip string) bool {
+	for _, vm := range s.resources[KindVirtualMachine] {
+		nics, _ := vm["nic"].([]Resource)
+		for _, nic := range nics {
+			if nic["networkid"] == networkID && nic["ipaddress"] == ip {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sharedNetworkIP returns the address record of ip in a shared network, if the network's addresses are
+// tracked as (non-virtual) public IP addresses.
+func (s *Server) sharedNetworkIP(networkID, ip string) Resource {
+	for _, r := range s.resources[KindPublicIPAddress] {
+		if r["networkid"] == networkID && r["ipaddress"] == ip {
+			return r
+		}
+	}
+	return nil
+}
+
+// nextNetworkIP hands out the next free address of a network. Shared networks with tracked addresses
+// hand out their free addresses, other networks the next unused address of their CIDR, skipping the gateway.
+func (s *Server) nextNetworkIP(network Resource) string {
+	if network["type"] == "Shared" {
+		for _, r := range s.resources[KindPublicIPAddress] {
+			if r["networkid"] == network["id"] && r["state"] == "Free" {
+				return r.str("ipaddress")
+			}
+		}
+	}
+	_, cidr, err := net.ParseCIDR(network.str("cidr"))
+	if err != nil || cidr.IP.To4() == nil {
+		return ""
+	}
+	base := binary.BigEndian.Uint32(cidr.IP.To4())
+	for {
+		s.nextIP[network.str("id")]++
+		ip := make(net.IP, 4)
+		binary.BigEndian.PutUint32(ip, base+uint32(s.nextIP[network.str("id")])+1)
+		if !cidr.Contains(ip) {
+			return ""
+		}
+		if ip.String() != network.str("gateway") && !s.ipInUse(network.str("id"), ip.String()) {
+			return ip.String()
+		}
+	}
+}
+
+func createKubernetesCluster(s *Server, caller Resource, params url.Values) (interface{}, error) {
+	if err := required(params, "name", "zoneid"); err != nil {
+		return nil, err
+	}
+	zone := s.get(KindZone, params.Get("zoneid"))
+	if zone == nil {
+		return nil, notFound(params.Get("zoneid"))
+	}
+	clusterType := params.Get("clustertype")
+	if clusterType == "" {
+		clusterType = "CloudManaged"
+	}
+	if clusterType == "CloudManaged" {
+		if err := required(params, "kubernetesversionid", "serviceofferingid", "size"); err != nil {
+			return nil, err
+		}
+	}
+	cluster := s.add(KindKubernetesCluster, owned(caller, params, Resource{
+		"name":        params.Get("name"),
+		"description": params.Get("description"),
+		"clustertype": clusterType,
+		"zoneid":      zone["id"],
+		"zonename":    zone["name"],
+		"networkid":   params.Get("networkid"),
+		"state":       "Running",
+	}))
+	return &asyncResult{key: KindKubernetesCluster, obj: cluster}, nil
+}
+
+func deleteKubernetesCluster(s *Server, caller Resource, params url.Values) (interface{}, error) {
+	if _, err := deleteResource(KindKubernetesCluster)(s, caller, params); err != nil {
+		return nil, err
+	}
+	delete(s.cksMembers, params.Get("id"))
+	return success, nil
+}
+
+// listKubernetesClusters lists clusters along with the VMs added to them.
+func listKubernetesClusters(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	var clusters []Resource
+	for _, cluster := range s.filter(KindKubernetesCluster, params) {
+		cluster = cluster.copy()
+		vms := []Resource{}
+		for _, vmID := range s.cksMembers[cluster.str("id")] {
+			if vm := s.get(KindVirtualMachine, vmID); vm != nil {
+				vms = append(vms, vm)
+			}
+		}
+		cluster["virtualmachines"] = vms
+		cluster["size"] = len(vms)
+		clusters = append(clusters, cluster)
+	}
+	return listResponse(KindKubernetesCluster, clusters), nil
+}
+
+func addVirtualMachinesToKubernetesCluster(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	cluster, vmIDs, err := s.kubernetesClusterAndVMs(params)
+	if err != nil {
+		return nil, err
+	}
+	for _, vmID := range vmIDs {
+		if !contains(s.cksMembers[cluster.str("id")], vmID) {
+			s.cksMembers[cluster.str("id")] = append(s.cksMembers[cluster.str("id")], vmID)
+		}
+	}
+	return Resource{"success": true}, nil
+}
+
+func removeVirtualMachinesFromKubernetesCluster(s *Server, _ Resource, params url.Values) (interface{}, error) {
+	cluster, vmIDs, err := s.kubernetesClusterAndVMs(params)
+	if err != nil {
+		return nil, err
+	}
+	s.cksMembers[cluster.str("id")] = without(s.cksMembers[cluster.str("id")], vmIDs...)
+	return Resource{"success": true}, nil
+}
+
+func (s *Server) kubernetesClusterAndVMs(params url.Values) (Resource, []string, error) {
+	if err := required(params, "id", "virtualmachineids"); err != nil {
+		return nil, nil, err
+	}
+	cluster := s.get(KindKubernetesCluster, params.Get("id"))
+	if cluster == nil {
+		return nil, nil, notFound(params.Get("id"))
+	}
+	if cluster["clustertype"] != "ExternalManaged" {
+		return nil, nil, invalidParameter("VMs can only be added to or removed from ExternalManaged clusters")
+	}
+	vmIDs := splitList(params.Get("virtualmachineids"))
+	for _, vmID := range vmIDs {
+		if s.get(KindVirtualMachine, vmID) == nil {
+			return nil, nil, notFound(vmID)
+		}
+	}
+	return cluster, vmIDs, nil
+}
+
+// owned sets the owner of a new resource to the project passed in the request, or the calling account.
+func owned(caller Resource, params url.Values, r Resource) Resource {
+	r["account"] = caller["account"]
+	r["domainid"] = caller["domainid"]
+	r["domain"] = caller["domain"]
+	if projectID := params.Get("projectid"); projectID != "" {
+		r["projectid"] = projectID
+	}
+	return r
+}
+
+// required checks that all named parameters are present.
+func required(params url.Values, names ...string) error {
+	for _, name := range names {
+		if params.Get(name) == "" {
+			return invalidParameter("Unable to execute API command %s due to missing parameter %s",
+				strings.ToLower(params.Get("command")), name)
+		}
+	}
+	return nil
+}
+
+var indexedParamRegexp = regexp.MustCompile(`^([a-z]+)\[(\d+)\]\.(.+)$`)
+
+// indexedParams decodes list-of-map parameters such as tags[0].key=k&tags[0].value=v.
+func indexedParams(params url.Values, name string) []map[string]string {
+	byIndex := map[int]map[string]string{}
+	for key := range params {
+		m := indexedParamRegexp.FindStringSubmatch(key)
+		if m == nil || m[1] != name {
+			continue
+		}
+		i, _ := strconv.Atoi(m[2])
+		if byIndex[i] == nil {
+			byIndex[i] = map[string]string{}
+		}
+		byIndex[i][m[3]] = params.Get(key)
+	}
+	indices := make([]int, 0, len(byIndex))
+	for i := range byIndex {
+		indices = append(indices, i)
+	}
+	sort.Ints(indices)
+	out := make([]map[string]string, 0, len(indices))
+	for _, i := range indices {
+		out = append(out, byIndex[i])
+	}
+	return out
+}
+
+// detailsParam decodes the details[0].key=value map parameter of deployVirtualMachine.
+func detailsParam(params url.Values) map[string]string {
+	details := map[string]string{}
+	for _, m := range indexedParams(params, "details") {
+		for k, v := range m {
+			details[k] = v
+		}
+	}
+	return details
+}
+
+func splitList(s string) []string {
+	if s == "" {
+		return nil
+	}
+	return strings.Split(s, ",")
+}
+
+// number converts a numeric field of a resource, as seeded or set through Update, to an int64.
+func number(v interface{}) int64 {
+	switch n := v.(type) {
+	case int:
+		return int64(n)
+	case int32:
+		return int64(n)
+	case int64:
+		return n
+	case float64:
+		return int64(n)
+	}
+	return 0
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+	return false
+}
+
+func without(list []string, remove ...string) []string {
+	out := []string{}
+	for _, item := range list {
+		if !contains(remove, item) {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -0,0 +1,369 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakecloudstack
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Names of the resources every new Server is seeded with.
+const (
+	RootDomainName              = "ROOT"
+	AdminAccountName            = "admin"
+	AdminUserName               = "admin"
+	ZoneName                    = "zone1"
+	PodName                     = "pod1"
+	SANClusterName              = "san"
+	NVMeClusterName             = "nvme"
+	SharedNetworkName           = "SharedGuestNet1"
+	ServiceOfferingName         = "Small Instance"
+	MediumServiceOfferingName   = "Medium Instance"
+	DiskOfferingName            = "Small"
+	CustomDiskOfferingName      = "Custom"
+	NVMeDiskOfferingName        = "NVMe"
+	NVMeStorageTag              = "nvme"
+	TemplateName                = "kube-v1.30.0-ubuntu-2204"
+	IsolatedNetworkOfferingName = "DefaultIsolatedNetworkOfferingWithSourceNatService"
+	VPCNetworkOfferingName      = "DefaultIsolatedNetworkOfferingForVpcNetworks"
+	VPCOfferingName             = "Default VPC offering"
+
+	// Capacity of each seeded host. Memory is in bytes.
+	HostCPUNumber = 32
+	HostCPUSpeed  = 2000
+	HostMemory    = int64(256) << 30
+
+	// PublicIPRange is the /24 the seeded public IP addresses are taken from.
+	PublicIPRange = "203.0.113"
+)
+
+const unlimited = "Unlimited"
+
+func (s *Server) seed() {
+	s.add(KindDomain, Resource{
+		"name":            RootDomainName,
+		"path":            RootDomainName,
+		"level":           0,
+		"state":           "Active",
+		"cpuavailable":    unlimited,
+		"memoryavailable": unlimited,
+		"vmavailable":     unlimited,
+	})
+	admin := s.addAccount(RootDomainName, AdminAccountName, AdminUserName, 1)
+	s.APIKey, s.SecretKey = admin.str("apikey"), admin.str("secretkey")
+
+	zone := s.add(KindZone, Resource{
+		"name":            ZoneName,
+		"networktype":     "Advanced",
+		"allocationstate": "Enabled",
+	})
+	zoneID := zone.str("id")
+
+	// Each host cluster has a single host, named after its cluster.
+	pod := s.add(KindPod, Resource{
+		"name":            PodName,
+		"zoneid":          zoneID,
+		"zonename":        ZoneName,
+		"allocationstate": "Enabled",
+	})
+	for _, name := range []string{SANClusterName, NVMeClusterName} {
+		cluster := s.add(KindCluster, Resource{
+			"name":            name,
+			"podid":           pod["id"],
+			"podname":         pod["name"],
+			"zoneid":          zoneID,
+			"zonename":        ZoneName,
+			"hypervisortype":  "Simulator",
+			"allocationstate": "Enabled",
+		})
+		s.add(KindHost, Resource{
+			"name":        name + "-host1",
+			"type":        "Routing",
+			"state":       "Up",
+			"clusterid":   cluster["id"],
+			"clustername": cluster["name"],
+			"podid":       pod["id"],
+			"podname":     pod["name"],
+			"zoneid":      zoneID,
+			"zonename":    ZoneName,
+			"hypervisor":  "Simulator",
+			"cpunumber":   HostCPUNumber,
+			"cpuspeed":    HostCPUSpeed,
+			"memorytotal": HostMemory,
+		})
+	}
+
+	s.add(KindServiceOffering, Resource{
+		"name":         ServiceOfferingName,
+		"displaytext":  ServiceOfferingName,
+		"cpunumber":    2,
+		"cpuspeed":     1000,
+		"memory":       2048,
+		"iscustomized": false,
+		"state":        "Active",
+	})
+	s.add(KindServiceOffering, Resource{
+		"name":         MediumServiceOfferingName,
+		"displaytext":  MediumServiceOfferingName,
+		"cpunumber":    4,
+		"cpuspeed":     1000,
+		"memory":       8192,
+		"iscustomized": false,
+		"state":        "Active",
+	})
+	s.add(KindDiskOffering, Resource{
+		"name":         DiskOfferingName,
+		"displaytext":  DiskOfferingName,
+		"disksize":     int64(5),
+		"iscustomized": false,
+		"state":        "Active",
+	})
+	s.add(KindDiskOffering, Resource{
+		"name":         CustomDiskOfferingName,
+		"displaytext":  CustomDiskOfferingName,
+		"disksize":     int64(0),
+		"iscustomized": true,
+		"state":        "Active",
+	})
+	s.add(KindDiskOffering, Resource{
+		"name":         NVMeDiskOfferingName,
+		"displaytext":  NVMeDiskOfferingName,
+		"disksize":     int64(0),
+		"iscustomized": true,
+		"tags":         NVMeStorageTag,
+		"state":        "Active",
+	})
+	s.add(KindTemplate, Resource{
+		"name":         TemplateName,
+		"displaytext":  TemplateName,
+		"zoneid":       zoneID,
+		"zonename":     ZoneName,
+		"isready":      true,
+		"ispublic":     true,
+		"isfeatured":   true,
+		"templatetype": "USER",
+		"size":         int64(8589934592),
+	})
+
+	s.add(KindNetworkOffering, Resource{
+		"name":                IsolatedNetworkOfferingName,
+		"displaytext":         "Offering for Isolated networks with Source Nat service enabled",
+		"guestiptype":         "Isolated",
+		"forvpc":              false,
+		"isdefault":           true,
+		"state":               "Enabled",
+		"traffictype":         "Guest",
+		"networkrate":         200,
+		"egressdefaultpolicy": false,
+	})
+	s.add(KindNetworkOffering, Resource{
+		"name":                VPCNetworkOfferingName,
+		"displaytext":         "Offering for Isolated VPC networks with Source Nat service enabled",
+		"guestiptype":         "Isolated",
+		"forvpc":              true,
+		"isdefault":           true,
+		"state":               "Enabled",
+		"traffictype":         "Guest",
+		"networkrate":         200,
+		"egressdefaultpolicy": false,
+	})
+	s.add(KindVPCOffering, Resource{
+		"name":        VPCOfferingName,
+		"displaytext": VPCOfferingName,
+		"isdefault":   true,
+		"state":       "Enabled",
+	})
+
+	s.add(KindNetworkACLList, Resource{
+		"name":        "default_allow",
+		"description": "Default Network ACL Allow All",
+	})
+	s.add(KindNetworkACLList, Resource{
+		"name":        "default_deny",
+		"description": "Default Network ACL Deny All",
+	})
+
+	shared := s.add(KindNetwork, Resource{
+		"name":        SharedNetworkName,
+		"displaytext": SharedNetworkName,
+		"type":        "Shared",
+		"zoneid":      zoneID,
+		"zonename":    ZoneName,
+		"cidr":        "10.0.0.0/24",
+		"gateway":     "10.0.0.1",
+		"netmask":     "255.255.255.0",
+		"state":       "Setup",
+	})
+
+	// Addresses for isolated networks and VPCs come first, so they are picked before shared network
+	// addresses when listing without a network filter.
+	for i := 10; i < 20; i++ {
+		s.add(KindPublicIPAddress, Resource{
+			"ipaddress":         fmt.Sprintf("%s.%d", PublicIPRange, i),
+			"zoneid":            zoneID,
+			"zonename":          ZoneName,
+			"forvirtualnetwork": true,
+			"issourcenat":       false,
+			"isstaticnat":       false,
+			"state":             "Free",
+		})
+	}
+	for i := 10; i < 20; i++ {
+		s.add(KindPublicIPAddress, Resource{
+			"ipaddress":         fmt.Sprintf("10.0.0.%d", i),
+			"networkid":         shared["id"],
+			"zoneid":            zoneID,
+			"zonename":          ZoneName,
+			"forvirtualnetwork": false,
+			"issourcenat":       false,
+			"isstaticnat":       false,
+			"state":             "Free",
+		})
+	}
+}
+
+// AddZone creates another zone with a single pod, cluster and host, all named after the zone, e.g. "zone2-pod1".
+// The host has the same capacity as the seeded ones. The new zone is returned.
+func (s *Server) AddZone(name string) Resource {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	zone := s.add(KindZone, Resource{
+		"name":            name,
+		"networktype":     "Advanced",
+		"allocationstate": "Enabled",
+	})
+	pod := s.add(KindPod, Resource{
+		"name":            name + "-pod1",
+		"zoneid":          zone["id"],
+		"zonename":        name,
+		"allocationstate": "Enabled",
+	})
+	cluster := s.add(KindCluster, Resource{
+		"name":            name + "-cluster1",
+		"podid":           pod["id"],
+		"podname":         pod["name"],
+		"zoneid":          zone["id"],
+		"zonename":        name,
+		"hypervisortype":  "Simulator",
+		"allocationstate": "Enabled",
+	})
+	s.add(KindHost, Resource{
+		"name":        name + "-host1",
+		"type":        "Routing",
+		"state":       "Up",
+		"clusterid":   cluster["id"],
+		"clustername": cluster["name"],
+		"podid":       pod["id"],
+		"podname":     pod["name"],
+		"zoneid":      zone["id"],
+		"zonename":    name,
+		"hypervisor":  "Simulator",
+		"cpunumber":   HostCPUNumber,
+		"cpuspeed":    HostCPUSpeed,
+		"memorytotal": HostMemory,
+	})
+	return zone.copy()
+}
+
+// AddDomain creates a domain below an existing one. The path is given from the ROOT domain, e.g.
+// "ROOT/tenant/team". The new domain is returned.
+func (s *Server) AddDomain(path string) (Resource, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	parentPath := path[:max(strings.LastIndex(path, "/"), 0)]
+	parent := s.domainByPath(parentPath)
+	if parent == nil {
+		return nil, fmt.Errorf("parent domain %q not found", parentPath)
+	}
+	return s.add(KindDomain, Resource{
+		"name":            path[strings.LastIndex(path, "/")+1:],
+		"path":            path,
+		"level":           parent["level"].(int) + 1,
+		"parentdomainid":  parent["id"],
+		"state":           "Active",
+		"cpuavailable":    unlimited,
+		"memoryavailable": unlimited,
+		"vmavailable":     unlimited,
+	}).copy(), nil
+}
+
+// AddAccount creates a user account with an API key pair in the domain at path and returns its user,
+// including the "apikey" and "secretkey" to sign requests with.
+func (s *Server) AddAccount(domainPath, account, username string) (Resource, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.domainByPath(domainPath) == nil {
+		return nil, fmt.Errorf("domain %q not found", domainPath)
+	}
+	return s.addAccount(domainPath, account, username, 0).copy(), nil
+}
+
+// AddProject creates a project owned by the given account.
+func (s *Server) AddProject(domainPath, account, name string) (Resource, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	domain := s.domainByPath(domainPath)
+	if domain == nil {
+		return nil, fmt.Errorf("domain %q not found", domainPath)
+	}
+	return s.add(KindProject, Resource{
+		"name":            name,
+		"displaytext":     name,
+		"account":         account,
+		"domainid":        domain["id"],
+		"domain":          domain["name"],
+		"state":           "Active",
+		"cpuavailable":    unlimited,
+		"memoryavailable": unlimited,
+		"vmavailable":     unlimited,
+	}).copy(), nil
+}
+
+func (s *Server) addAccount(domainPath, account, username string, accountType int) Resource {
+	domain := s.domainByPath(domainPath)
+	acc := s.add(KindAccount, Resource{
+		"name":            account,
+		"accounttype":     accountType,
+		"domainid":        domain["id"],
+		"domain":          domain["name"],
+		"domainpath":      domain["path"],
+		"state":           "enabled",
+		"cpuavailable":    unlimited,
+		"memoryavailable": unlimited,
+		"vmavailable":     unlimited,
+	})
+	return s.add(KindUser, Resource{
+		"username":    username,
+		"account":     account,
+		"accountid":   acc["id"],
+		"accounttype": accountType,
+		"domainid":    domain["id"],
+		"domain":      domain["name"],
+		"state":       "enabled",
+		"apikey":      strings.ReplaceAll(newID()+newID(), "-", ""),
+		"secretkey":   strings.ReplaceAll(newID()+newID(), "-", ""),
+	})
+}
+
+func (s *Server) domainByPath(path string) Resource {
+	for _, d := range s.resources[KindDomain] {
+		if strings.EqualFold(d.str("path"), path) {
+			return d
+		}
+	}
+	return nil
+}
@@ -0,0 +1,366 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package fakecloudstack provides an in-process, stateful simulator of the Apache CloudStack API.
+//
+// The simulator speaks the same wire protocol as a management server: requests must be signed with a
+// registered API key and secret, responses are wrapped in "<command>response" envelopes and
+// asynchronous commands return a job ID that is resolved through queryAsyncJobResult. This lets the
+// real cloudstack-go client, and therefore the provider's cloud.Client, run full reconcile flows
+// without access to a live CloudStack.
+package fakecloudstack
+
+import (
+	"crypto/hmac"
+	"crypto/sha1" // #nosec G505 -- CloudStack request signatures are defined as HMAC-SHA1.
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"gopkg.in/yaml.v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
+)
+
+// Resource is a single CloudStack object as it is rendered in API responses. Keys are the lowercase JSON
+// field names used by the CloudStack API (and cloudstack-go), e.g. "id", "name" or "zoneid".
+type Resource map[string]interface{}
+
+// Resource kinds, named after the JSON key CloudStack uses for them in list responses.
+const (
+	KindAccount             = "account"
+	KindAffinityGroup       = "affinitygroup"
+	KindDiskOffering        = "diskoffering"
+	KindCluster             = "cluster"
+	KindDomain              = "domain"
+	KindEgressFirewallRule  = "egressfirewallrule"
+	KindFirewallRule        = "firewallrule"
+	KindHost                = "host"
+	KindIPv6FirewallRule    = "ipv6firewallrule"
+	KindKubernetesCluster   = "kubernetescluster"
+	KindLBHealthCheckPolicy = "lbhealthcheckpolicy"
+	KindLBStickinessPolicy  = "lbstickinesspolicy"
+	KindLoadBalancerRule    = "loadbalancerrule"
+	KindNetwork             = "network"
+	KindNetworkACL          = "networkacl"
+	KindNetworkACLList      = "networkacllist"
+	KindNetworkOffering     = "networkoffering"
+	KindPod                 = "pod"
+	KindProject             = "project"
+	KindPublicIPAddress     = "publicipaddress"
+	KindRoutingFirewallRule = "routingfirewallrule"
+	KindServiceOffering     = "serviceoffering"
+	KindTag                 = "tag"
+	KindTemplate            = "template"
+	KindUser                = "user"
+	KindVirtualMachine      = "virtualmachine"
+	KindVolume              = "volume"
+	KindVPC                 = "vpc"
+	KindVPCOffering         = "vpcoffering"
+	KindZone                = "zone"
+)
+
+// Server is a fake CloudStack management server listening on a local port.
+type Server struct {
+	// URL is the API endpoint, suitable for use as a cloud.Config APIUrl.
+	URL string
+	// APIKey and SecretKey are the credentials of the seeded admin user.
+	APIKey    string
+	SecretKey string
+
+	httpServer *httptest.Server
+
+	mu           sync.Mutex
+	resources    map[string][]Resource
+	jobs         map[string]*asyncJob
+	lbInstances  map[string][]string
+	cksMembers   map[string][]string
+	failures     map[string][]string
+	jobFailures  map[string][]string
+	calls        map[string]int
+	jobPolls     int
+	capabilities Resource
+	nextIP       map[string]int
+}
+
+// asyncJob is a job created by an asynchronous command.
+type asyncJob struct {
+	id           string
+	command      string
+	result       json.RawMessage
+	pendingPolls int
+	failed       bool
+}
+
+// NewServer starts a fake CloudStack seeded with a ROOT domain, an admin account with API keys and a
+// single zone with default offerings and a template. Callers must Close the server when done.
+func NewServer() *Server {
+	s := &Server{
+		resources:   map[string][]Resource{},
+		jobs:        map[string]*asyncJob{},
+		lbInstances: map[string][]string{},
+		cksMembers:  map[string][]string{},
+		failures:    map[string][]string{},
+		jobFailures: map[string][]string{},
+		calls:       map[string]int{},
+		nextIP:      map[string]int{},
+		capabilities: Resource{
+			"allowuserexpungerecovervm":                    true,
+			"apilimitinterval":                             1,
+			"apilimitmax":                                  0,
+			"cloudstackversion":                            "4.20.0.0",
+			"kubernetesclusterexperimentalfeaturesenabled": false,
+			"kubernetesserviceenabled":                     true,
+		},
+	}
+	s.seed()
+
+	s.httpServer = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	s.URL = s.httpServer.URL + "/client/api"
+
+	return s
+}
+
+// Close shuts the server down.
+func (s *Server) Close() {
+	s.httpServer.Close()
+}
+
+// Config returns a cloud.Config pointing at the server with the admin user's credentials.
+func (s *Server) Config() cloud.Config {
+	return cloud.Config{
+		APIUrl:    s.URL,
+		APIKey:    s.APIKey,
+		SecretKey: s.SecretKey,
+		VerifySSL: "false",
+	}
+}
+
+// EndpointSecret returns an ACS endpoint secret, as referenced by a CloudStackFailureDomain, which points
+// at the server with the admin user's credentials.
+func (s *Server) EndpointSecret(name, namespace string) *corev1.Secret {
+	conf := s.Config()
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Data: map[string][]byte{
+			"api-url":    []byte(conf.APIUrl),
+			"api-key":    []byte(conf.APIKey),
+			"secret-key": []byte(conf.SecretKey),
+			"verify-ssl": []byte(conf.VerifySSL),
+		},
+	}
+}
+
+// CloudConfig renders the server's credentials in the cloud-config format read by cloud.NewClientFromYamlPath.
+func (s *Server) CloudConfig(secretName string) ([]byte, error) {
+	return yaml.Marshal(cloud.SecretConfig{
+		APIVersion: "v1",
+		Kind:       "Secret",
+		Type:       "Opaque",
+		Metadata:   map[string]string{"name": secretName},
+		StringData: s.Config(),
+	})
+}
+
+// FailNext makes the next call of command fail with the given error text. Calls queue up, so invoking
+// FailNext twice fails the next two calls.
+func (s *Server) FailNext(command string, errorText string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	command = strings.ToLower(command)
+	s.failures[command] = append(s.failures[command], errorText)
+}
+
+// FailNextJob makes the job of the next call of the asynchronous command fail with the given error text once
+// it's done. The call itself succeeds and its effects apply. Calls queue up like those of FailNext.
+func (s *Server) FailNextJob(command string, errorText string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	command = strings.ToLower(command)
+	s.jobFailures[command] = append(s.jobFailures[command], errorText)
+}
+
+// SetAsyncJobPolls sets how many times queryAsyncJobResult reports a newly created job as pending before
+// reporting its result. The default of zero completes jobs immediately.
+func (s *Server) SetAsyncJobPolls(polls int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.jobPolls = polls
+}
+
+// SetCapability overrides a field of the listCapabilities response.
+func (s *Server) SetCapability(key string, value interface{}) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.capabilities[key] = value
+}
+
+// Calls returns the number of signed requests received for command.
+func (s *Server) Calls(command string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.calls[strings.ToLower(command)]
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	params := r.Form
+	command := params.Get("command")
+	responseKey := strings.ToLower(command) + "response"
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	caller, err := s.authenticate(params)
+	if err != nil {
+		writeResponse(w, responseKey, err)
+		return
+	}
+	s.calls[strings.ToLower(command)]++
+
+	if queued := s.failures[strings.ToLower(command)]; len(queued) > 0 {
+		s.failures[strings.ToLower(command)] = queued[1:]
+		writeResponse(w, responseKey, &apiError{code: 530, text: queued[0]})
+		return
+	}
+
+	h, found := handlers[strings.ToLower(command)]
+	if !found {
+		writeResponse(w, responseKey, &apiError{
+			code: 432,
+			text: fmt.Sprintf("The given command:%s does not exist or it is not available for user", command),
+		})
+		return
+	}
+
+	body, err := h(s, caller, params)
+	if err != nil {
+		writeResponse(w, responseKey, err)
+		return
+	}
+	if result, ok := body.(*asyncResult); ok {
+		body = s.newJob(command, result)
+	}
+	writeResponse(w, responseKey, body)
+}
+
+// authenticate verifies the request signature and expiry and returns the calling user.
+func (s *Server) authenticate(params url.Values) (Resource, error) {
+	unauthorized := &apiError{code: 401, text: "unable to verify user credentials and/or request signature"}
+
+	signature := params.Get("signature")
+	if signature == "" {
+		return nil, unauthorized
+	}
+	if expires := params.Get("expires"); expires != "" {
+		if expiry, err := time.Parse(time.RFC3339, expires); err != nil || time.Now().After(expiry) {
+			return nil, unauthorized
+		}
+	}
+
+	var caller Resource
+	for _, u := range s.resources[KindUser] {
+		if u["apikey"] != nil && u["apikey"] == params.Get("apiKey") {
+			caller = u
+			break
+		}
+	}
+	if caller == nil {
+		return nil, unauthorized
+	}
+
+	signed := url.Values{}
+	for k, v := range params {
+		if k != "signature" {
+			signed[k] = v
+		}
+	}
+	mac := hmac.New(sha1.New, []byte(caller["secretkey"].(string)))
+	mac.Write([]byte(strings.ToLower(cloudstack.EncodeValues(signed))))
+	if !hmac.Equal([]byte(signature), []byte(base64.StdEncoding.EncodeToString(mac.Sum(nil)))) {
+		return nil, unauthorized
+	}
+
+	return caller, nil
+}
+
+// newJob records the result of an asynchronous command and returns the command's immediate response.
+func (s *Server) newJob(command string, result *asyncResult) Resource {
+	job := &asyncJob{id: newID(), command: command, pendingPolls: s.jobPolls}
+	if queued := s.jobFailures[strings.ToLower(command)]; len(queued) > 0 {
+		s.jobFailures[strings.ToLower(command)] = queued[1:]
+		job.failed = true
+		job.result, _ = json.Marshal(map[string]interface{}{"errorcode": 530, "errortext": queued[0]})
+	} else if result.key == "" {
+		job.result, _ = json.Marshal(result.obj)
+	} else {
+		job.result, _ = json.Marshal(map[string]interface{}{result.key: result.obj})
+	}
+	s.jobs[job.id] = job
+
+	resp := Resource{"jobid": job.id}
+	if r, ok := result.obj.(Resource); ok && r["id"] != nil {
+		resp["id"] = r["id"]
+	}
+	return resp
+}
+
+func writeResponse(w http.ResponseWriter, responseKey string, body interface{}) {
+	status := http.StatusOK
+	if err, ok := body.(error); ok {
+		apiErr, ok := err.(*apiError)
+		if !ok {
+			apiErr = &apiError{code: 530, text: err.Error()}
+		}
+		status = apiErr.code
+		body = Resource{"uuidList": []string{}, "errorcode": apiErr.code, "cserrorcode": 9999, "errortext": apiErr.text}
+	}
+
+	w.Header().Set("Content-Type", "application/json;charset=utf-8")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{responseKey: body})
+}
+
+// apiError is an error reported by the API with an HTTP status code.
+type apiError struct {
+	code int
+	text string
+}
+
+func (e *apiError) Error() string {
+	return e.text
+}
+
+// invalidParameter returns the error CloudStack reports for a bad or missing parameter value.
+func invalidParameter(format string, args ...interface{}) error {
+	return &apiError{code: 431, text: fmt.Sprintf(format, args...)}
+}
+
+// notFound returns the error CloudStack reports when a referenced entity does not exist.
+func notFound(id string) error {
+	return &apiError{code: 431, text: fmt.Sprintf("Unable to find uuid for id %s", id)}
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakecloudstack_test
+
+import (
+	"github.com/apache/cloudstack-go/v2/cloudstack"
+	"github.com/onsi/ginkgo/v2"
+	gomega "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+	infrav1 "sigs.k8s.io/cluster-api-provider-cloudstack/api/v1beta3"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/pkg/cloud"
+	"sigs.k8s.io/cluster-api-provider-cloudstack/test/fakecloudstack"
+)
+
+var _ = ginkgo.Describe("Fake CloudStack server", func() {
+	var (
+		server    *fakecloudstack.Server
+		client    cloud.Client
+		csCluster *infrav1.CloudStackCluster
+		fd        *infrav1.CloudStackFailureDomain
+		csMachine *infrav1.CloudStackMachine
+		machine   *clusterv1.Machine
+	)
+
+	ginkgo.BeforeEach(func() {
+		server = fakecloudstack.NewServer()
+		var err error
+		client, err = cloud.NewClientFromConf(server.Config(), nil, "")
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+
+		csCluster = &infrav1.CloudStackCluster{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-cluster", Namespace: "default", UID: uuid.NewUUID()},
+		}
+		fd = &infrav1.CloudStackFailureDomain{Spec: infrav1.CloudStackFailureDomainSpec{
+			Name: "fd1",
+			Zone: infrav1.CloudStackZoneSpec{
+				Name:    fakecloudstack.ZoneName,
+				Network: infrav1.Network{Name: "test-cluster-net", Type: cloud.NetworkTypeIsolated},
+			},
+		}}
+		csMachine = &infrav1.CloudStackMachine{
+			ObjectMeta: metav1.ObjectMeta{Name: "test-machine", Namespace: "default"},
+			Spec: infrav1.CloudStackMachineSpec{
+				Offering: infrav1.CloudStackResourceIdentifier{Name: fakecloudstack.ServiceOfferingName},
+				Template: infrav1.CloudStackResourceIdentifier{Name: fakecloudstack.TemplateName},
+			},
+		}
+		machine = &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Name: "test-machine", Namespace: "default"}}
+	})
+
+	ginkgo.AfterEach(func() {
+		server.Close()
+	})
+
+	ginkgo.It("rejects requests that are not signed with a registered secret", func() {
+		conf := server.Config()
+		conf.SecretKey = "wrong"
+		_, err := cloud.NewClientFromConf(conf, nil, "")
+		gomega.Ω(err).Should(gomega.MatchError(gomega.ContainSubstring("unable to verify user credentials")))
+	})
+
+	ginkgo.It("refuses to delete a network that still has VMs", func() {
+		shared, _ := server.Find(fakecloudstack.KindNetwork, fakecloudstack.SharedNetworkName)
+		gomega.Ω(client.ResolveZone(&fd.Spec.Zone)).Should(gomega.Succeed())
+		fd.Spec.Zone.Network = infrav1.Network{ID: shared["id"].(string), Name: fakecloudstack.SharedNetworkName}
+		gomega.Ω(client.GetOrCreateVMInstance(csMachine, machine, csCluster, fd, nil, "")).Should(gomega.Succeed())
+		gomega.Ω(csMachine.Status.Addresses[0].Address).Should(gomega.HavePrefix("10.0.0."))
+
+		gomega.Ω(client.DeleteNetwork(fd.Spec.Zone.Network)).Should(
+			gomega.MatchError(gomega.ContainSubstring("not all user vms are expunged")))
+	})
+
+	ginkgo.It("fails the next call of a command on request", func() {
+		gomega.Ω(client.ResolveZone(&fd.Spec.Zone)).Should(gomega.Succeed())
+		server.FailNext("deployVirtualMachine", "Insufficient capacity")
+		gomega.Ω(client.GetOrCreateVMInstance(csMachine, machine, csCluster, fd, nil, "")).Should(
+			gomega.MatchError(gomega.ContainSubstring("Insufficient capacity")))
+		gomega.Ω(client.GetOrCreateVMInstance(csMachine, machine, csCluster, fd, nil, "")).Should(gomega.Succeed())
+	})
+
+	ginkgo.It("reports asynchronous jobs as pending until they have been polled", func() {
+		server.SetAsyncJobPolls(1)
+		cs := cloudstack.NewAsyncClient(server.URL, server.APIKey, server.SecretKey, false)
+		resp, err := cs.AffinityGroup.CreateAffinityGroup(cs.AffinityGroup.NewCreateAffinityGroupParams("polled", "host affinity"))
+		gomega.Ω(err).ShouldNot(gomega.HaveOccurred())
+		gomega.Ω(resp.Name).Should(gomega.Equal("polled"))
+		gomega.Ω(server.Calls("queryAsyncJobResult")).Should(gomega.Equal(2))
+	})
+})
@@ -0,0 +1,201 @@
+/*
+Copyright 2025 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package fakecloudstack
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/util/uuid"
+)
+
+// listIgnoredParams are request parameters that never filter list results.
+var listIgnoredParams = map[string]bool{
+	"apikey":           true,
+	"command":          true,
+	"response":         true,
+	"signature":        true,
+	"signatureversion": true,
+	"expires":          true,
+	"listall":          true,
+	"isrecursive":      true,
+	"page":             true,
+	"pagesize":         true,
+	"keyword":          true,
+	"details":          true,
+	"templatefilter":   true,
+	"allocatedonly":    true,
+}
+
+// Add stores a resource of the given kind, generating an ID if it has none, and returns a copy of it.
+func (s *Server) Add(kind string, r Resource) Resource {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.add(kind, r).copy()
+}
+
+// Get returns a copy of the resource of the given kind with the given ID.
+func (s *Server) Get(kind, id string) (Resource, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.get(kind, id)
+	return r.copy(), r != nil
+}
+
+// List returns copies of all resources of the given kind.
+func (s *Server) List(kind string) []Resource {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var out []Resource
+	for _, r := range s.resources[kind] {
+		out = append(out, r.copy())
+	}
+	return out
+}
+
+// Find returns a copy of the first resource of the given kind with the given name.
+func (s *Server) Find(kind, name string) (Resource, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.findByName(kind, name)
+	return r.copy(), r != nil
+}
+
+// Update merges fields into the resource of the given kind with the given ID. This is how tests simulate
+// changes made outside of the provider, e.g. a VM crashing into the Error state.
+func (s *Server) Update(kind, id string, fields Resource) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r := s.get(kind, id)
+	if r == nil {
+		return fmt.Errorf("%s %s not found", kind, id)
+	}
+	for k, v := range fields {
+		r[k] = v
+	}
+	return nil
+}
+
+// Delete removes the resource of the given kind with the given ID.
+func (s *Server) Delete(kind, id string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.remove(kind, id)
+}
+
+func (s *Server) add(kind string, r Resource) Resource {
+	if id, _ := r["id"].(string); id == "" {
+		r["id"] = newID()
+	}
+	s.resources[kind] = append(s.resources[kind], r)
+	return r
+}
+
+func (s *Server) get(kind, id string) Resource {
+	if id == "" {
+		return nil
+	}
+	for _, r := range s.resources[kind] {
+		if r["id"] == id {
+			return r
+		}
+	}
+	return nil
+}
+
+func (s *Server) findByName(kind, name string) Resource {
+	for _, r := range s.resources[kind] {
+		if n, _ := r["name"].(string); strings.EqualFold(n, name) {
+			return r
+		}
+	}
+	return nil
+}
+
+func (s *Server) remove(kind, id string) {
+	kept := s.resources[kind][:0]
+	for _, r := range s.resources[kind] {
+		if r["id"] != id {
+			kept = append(kept, r)
+		}
+	}
+	s.resources[kind] = kept
+}
+
+// filter returns the resources of kind matching the list parameters. A parameter filters only if the
+// resource has a field of the same name, which covers the id, name, zoneid, networkid, etc. filters the
+// provider uses. The keyword parameter matches a substring of the name.
+func (s *Server) filter(kind string, params url.Values) []Resource {
+	var out []Resource
+	for _, r := range s.resources[kind] {
+		if matches(r, params) {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
+func matches(r Resource, params url.Values) bool {
+	for key := range params {
+		value := params.Get(key)
+		key = strings.ToLower(key)
+		if key == "keyword" {
+			name, _ := r["name"].(string)
+			if !strings.Contains(strings.ToLower(name), strings.ToLower(value)) {
+				return false
+			}
+			continue
+		}
+		if listIgnoredParams[key] {
+			continue
+		}
+		if field, found := r[key]; found && !strings.EqualFold(fmt.Sprint(field), value) {
+			return false
+		}
+	}
+	return true
+}
+
+// listResponse renders resources as a list response. CloudStack omits the count and list entirely when
+// nothing matched.
+func listResponse(kind string, resources []Resource) Resource {
+	if len(resources) == 0 {
+		return Resource{}
+	}
+	return Resource{"count": len(resources), kind: resources}
+}
+
+func (r Resource) copy() Resource {
+	if r == nil {
+		return nil
+	}
+	out := make(Resource, len(r))
+	for k, v := range r {
+		out[k] = v
+	}
+	return out
+}
+
+func (r Resource) str(key string) string {
+	s, _ := r[key].(string)
+	return s
+}
+
+func newID() string {
+	return string(uuid.NewUUID())
+}